@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+var (
+	fmtWrite        bool
+	fmtCheck        bool
+	fmtIndent       string
+	fmtMaxLineWidth int
+)
+
+var errUnformattedFiles = errors.New("some files are not formatted")
+
+// fmtCmd represents the fmt command
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [files or directories...]",
+	Short: "Formats GraphQL files",
+	Long: `fmt formats GraphQL schemas and operations while keeping their comments.
+Directories are searched recursively for files with the extensions .graphql, .graphqls and .gql.
+By default the formatted files are written to stdout.`,
+	Example: `graphql-go-tools fmt ./schema.graphql
+graphql-go-tools fmt -w ./graphql
+graphql-go-tools fmt --check ./graphql`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fmtWrite && fmtCheck {
+			return errors.New("either --write or --check can be set, not both")
+		}
+
+		files, err := graphqlFiles(args)
+		if err != nil {
+			return err
+		}
+
+		options := astprinter.FormatOptions{
+			Indent:       fmtIndent,
+			MaxLineWidth: fmtMaxLineWidth,
+		}
+
+		unformatted := false
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			formatted, err := formatGraphQL(content, options)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			switch {
+			case fmtCheck:
+				if !bytes.Equal(content, formatted) {
+					unformatted = true
+					fmt.Fprintln(cmd.OutOrStdout(), file)
+				}
+			case fmtWrite:
+				if bytes.Equal(content, formatted) {
+					continue
+				}
+				info, err := os.Stat(file)
+				if err != nil {
+					return err
+				}
+				if err = os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+					return err
+				}
+			default:
+				if _, err = cmd.OutOrStdout().Write(formatted); err != nil {
+					return err
+				}
+			}
+		}
+
+		if unformatted {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return errUnformattedFiles
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "write the formatted files in place")
+	fmtCmd.Flags().BoolVarP(&fmtCheck, "check", "c", false, "list files which are not formatted and fail if there are any")
+	fmtCmd.Flags().StringVar(&fmtIndent, "indent", astprinter.DefaultFormatIndent, "indentation per nesting level")
+	fmtCmd.Flags().IntVar(&fmtMaxLineWidth, "max-line-width", astprinter.DefaultFormatMaxLineWidth, "line width up to which arguments are printed on a single line")
+}
+
+func formatGraphQL(content []byte, options astprinter.FormatOptions) ([]byte, error) {
+	doc := ast.NewSmallDocument()
+	doc.Input.ResetInputBytes(content)
+	report := operationreport.Report{}
+	astparser.NewParser().ParseWithComments(doc, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	out := &bytes.Buffer{}
+	err := astprinter.Format(doc, options, out)
+	return out.Bytes(), err
+}
+
+// graphqlFiles returns the given files and the GraphQL files in the given directories
+func graphqlFiles(paths []string) (files []string, err error) {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".graphql", ".graphqls", ".gql":
+				if !entry.IsDir() {
+					files = append(files, file)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
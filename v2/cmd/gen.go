@@ -1,36 +1,115 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/codegen"
+)
+
+var (
+	genSchema      string
+	genOperations  []string
+	genPackageName string
+	genOutFile     string
+	genScalarTypes []string
 )
 
 // genCmd represents the gen command
 var genCmd = &cobra.Command{
 	Use:   "gen",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("gen called")
+	Short: "Generates a typed go client from GraphQL operations",
+	Long: `gen generates a typed go client for the named operations of the given files and directories.
+For every operation it generates response structs which respect aliases, fragments and nullability,
+a variables struct and a function which executes the operation with a graphqlclient.Executor,
+either over http or directly against an in-process execution engine.`,
+	Example: `graphql-go-tools gen -s ./schema.graphql -o ./operations -p client --scalar DateTime=time.Time --outFile ./client/client.go`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := parseGraphQLFile(genSchema)
+		if err != nil {
+			return err
+		}
+		if err = asttransform.MergeDefinitionWithBaseSchema(schema); err != nil {
+			return err
+		}
+
+		files, err := graphqlFiles(genOperations)
+		if err != nil {
+			return err
+		}
+		operations := bytes.Buffer{}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			operations.Write(content)
+			operations.WriteString("\n")
+		}
+		operationsDocument, report := astparser.ParseGraphqlDocumentBytes(operations.Bytes())
+		if report.HasErrors() {
+			return report
+		}
+
+		scalarTypes, err := parseScalarTypes(genScalarTypes)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer
+		if genOutFile == "" {
+			out = os.Stdout
+		} else {
+			o, err := os.Create(genOutFile)
+			if err != nil {
+				return err
+			}
+			defer o.Close()
+			out = o
+		}
+
+		gen := codegen.NewClientGen(schema, &operationsDocument, codegen.ClientConfig{
+			PackageName: genPackageName,
+			ScalarTypes: scalarTypes,
+		})
+		_, err = gen.Generate(out)
+		return err
 	},
 }
 
+// parseScalarTypes parses mappings of custom scalars to qualified go types, e.g. DateTime=time.Time
+func parseScalarTypes(mappings []string) (map[string]string, error) {
+	scalarTypes := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		scalar, goType, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid scalar mapping %q, expected Scalar=package.Type", mapping)
+		}
+		scalarTypes[scalar] = goType
+	}
+	return scalarTypes, nil
+}
+
 func init() {
 	rootCmd.AddCommand(genCmd)
 
-	// Here you will define your flags and configuration settings.
+	genCmd.Flags().StringVarP(&genSchema, "schema", "s", "", "schema is the GraphQL schema the operations are validated against (required)")
+	_ = genCmd.MarkFlagRequired("schema")
+
+	genCmd.Flags().StringSliceVarP(&genOperations, "operations", "o", nil, "operations are the files and directories containing the named operations and their fragments (required)")
+	_ = genCmd.MarkFlagRequired("operations")
+
+	genCmd.Flags().StringVarP(&genPackageName, "packageName", "p", "", "packageName is the package for the generated code (required)")
+	_ = genCmd.MarkFlagRequired("packageName")
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// genCmd.PersistentFlags().String("foo", "", "A help for foo")
+	genCmd.Flags().StringVar(&genOutFile, "outFile", "", "outFile is a flag to redirect the output directly into a file (optional)")
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// genCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	genCmd.Flags().StringSliceVar(&genScalarTypes, "scalar", nil, "scalar maps a custom scalar to a qualified go type, e.g. DateTime=time.Time, other custom scalars are decoded as json.RawMessage (optional)")
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lsp"
+)
+
+var (
+	lspSchemaFiles       []string
+	lspIntrospectionFile string
+	lspFederation        bool
+)
+
+// lspCmd represents the lsp command
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Runs a GraphQL language server over stdio",
+	Long: `lsp runs a language server for GraphQL operations which communicates over stdin and stdout.
+It provides diagnostics, completion, hover information, go-to-definition and formatting for editors supporting the Language Server Protocol.
+The schema is loaded from SDL files, from the JSON response of an introspection query or composed from the SDLs of federated subgraphs.`,
+	Example: `graphql-go-tools lsp -s ./schema.graphql
+graphql-go-tools lsp -i ./introspection.json
+graphql-go-tools lsp --federation -s ./accounts.graphql -s ./reviews.graphql`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			schema *lsp.Schema
+			err    error
+		)
+		switch {
+		case lspIntrospectionFile != "" && len(lspSchemaFiles) != 0:
+			return errors.New("either schema files or an introspection file must be set, not both")
+		case lspIntrospectionFile != "":
+			schema, err = lsp.NewSchemaFromIntrospectionFile(lspIntrospectionFile)
+		case lspFederation:
+			schema, err = lsp.NewFederatedSchemaFromSDLFiles(lspSchemaFiles...)
+		default:
+			schema, err = lsp.NewSchemaFromSDLFiles(lspSchemaFiles...)
+		}
+		if err != nil {
+			return err
+		}
+
+		return lsp.NewServer(schema).Serve(os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+
+	lspCmd.Flags().StringSliceVarP(&lspSchemaFiles, "schema", "s", nil, "SDL files of the schema, can be repeated")
+	lspCmd.Flags().StringVarP(&lspIntrospectionFile, "introspection", "i", "", "JSON file with the response of an introspection query")
+	lspCmd.Flags().BoolVar(&lspFederation, "federation", false, "compose the schema files as federated subgraphs")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/codegen"
+)
+
+var (
+	resolversSchema      string
+	resolversPackageName string
+	resolversOutFile     string
+	resolversScalarTypes []string
+)
+
+// resolversCmd represents the resolvers command
+var resolversCmd = &cobra.Command{
+	Use:   "resolvers",
+	Short: "Generates go resolver scaffolding from a schema",
+	Long: `resolvers generates model structs, resolver interfaces and a data source adapter for a schema.
+Implementing the resolver interfaces is all it takes to mount a go package into the execution engine
+as an in-process data source, without running a separate GraphQL server.`,
+	Example: `graphql-go-tools gen resolvers -s ./schema.graphql -p starwars --scalar DateTime=time.Time --outFile ./starwars/generated.go`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := parseGraphQLFile(resolversSchema)
+		if err != nil {
+			return err
+		}
+
+		scalarTypes, err := parseScalarTypes(resolversScalarTypes)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer
+		if resolversOutFile == "" {
+			out = os.Stdout
+		} else {
+			o, err := os.Create(resolversOutFile)
+			if err != nil {
+				return err
+			}
+			defer o.Close()
+			out = o
+		}
+
+		gen := codegen.NewResolverGen(schema, codegen.ResolverConfig{
+			PackageName: resolversPackageName,
+			ScalarTypes: scalarTypes,
+		})
+		_, err = gen.Generate(out)
+		return err
+	},
+}
+
+func init() {
+	genCmd.AddCommand(resolversCmd)
+
+	resolversCmd.Flags().StringVarP(&resolversSchema, "schema", "s", "", "schema is the GraphQL schema to generate the resolvers for (required)")
+	_ = resolversCmd.MarkFlagRequired("schema")
+
+	resolversCmd.Flags().StringVarP(&resolversPackageName, "packageName", "p", "", "packageName is the package for the generated code (required)")
+	_ = resolversCmd.MarkFlagRequired("packageName")
+
+	resolversCmd.Flags().StringVar(&resolversOutFile, "outFile", "", "outFile is a flag to redirect the output directly into a file (optional)")
+
+	resolversCmd.Flags().StringSliceVar(&resolversScalarTypes, "scalar", nil, "scalar maps a custom scalar to a qualified go type, e.g. DateTime=time.Time, other custom scalars are json.RawMessage (optional)")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/schemadiff"
+)
+
+var (
+	schemadiffOperations      []string
+	schemadiffUsageFile       string
+	schemadiffFailOnDangerous bool
+)
+
+var errBreakingChanges = errors.New("the schema has breaking changes")
+
+// schemadiffCmd represents the schemadiff command
+var schemadiffCmd = &cobra.Command{
+	Use:   "schemadiff <old schema> <new schema>",
+	Short: "Reports the changes between two schemas",
+	Long: `schemadiff compares two GraphQL schemas and classifies every change as breaking, dangerous or safe.
+It fails if there are breaking changes, which makes it usable as a CI check for schema changes.
+With recorded client operations or schema usage, breaking changes are only reported for schema coordinates the clients use.
+The usage file contains a JSON array of schema usage infos as returned by plan.GetSchemaUsageInfo.`,
+	Example: `graphql-go-tools schemadiff ./old.graphql ./new.graphql
+graphql-go-tools schemadiff ./old.graphql ./new.graphql --operations ./operations
+graphql-go-tools schemadiff ./old.graphql ./new.graphql --usage ./usage.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldSchema, err := parseGraphQLFile(args[0])
+		if err != nil {
+			return err
+		}
+		newSchema, err := parseGraphQLFile(args[1])
+		if err != nil {
+			return err
+		}
+
+		var options []schemadiff.Option
+		if len(schemadiffOperations) != 0 || schemadiffUsageFile != "" {
+			usage, err := loadSchemaUsage(args[0])
+			if err != nil {
+				return err
+			}
+			options = append(options, schemadiff.WithUsage(usage))
+		}
+
+		diff := schemadiff.Compare(oldSchema, newSchema, options...)
+		for _, change := range diff.Changes {
+			message := change.Message
+			if change.Unused {
+				message += " (not used by clients)"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-9s %-35s %s\n", change.Criticality, change.Type, message)
+		}
+
+		if diff.HasBreakingChanges() || (schemadiffFailOnDangerous && len(diff.Filter(schemadiff.CriticalityDangerous)) != 0) {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return errBreakingChanges
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemadiffCmd)
+
+	schemadiffCmd.Flags().StringSliceVarP(&schemadiffOperations, "operations", "o", nil, "files or directories with client operations, can be repeated")
+	schemadiffCmd.Flags().StringVarP(&schemadiffUsageFile, "usage", "u", "", "JSON file with the schema usage of executed operations")
+	schemadiffCmd.Flags().BoolVar(&schemadiffFailOnDangerous, "fail-on-dangerous", false, "fail on dangerous changes as well")
+}
+
+func parseGraphQLFile(file string) (*ast.Document, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, report := astparser.ParseGraphqlDocumentBytes(content)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("%s: %w", file, report)
+	}
+	return &doc, nil
+}
+
+// loadSchemaUsage collects the usage from the operations and the usage file,
+// operations are walked against the old schema as they have been written for it
+func loadSchemaUsage(oldSchemaFile string) (*schemadiff.Usage, error) {
+	usage := schemadiff.NewUsage()
+
+	if len(schemadiffOperations) != 0 {
+		definition, err := parseGraphQLFile(oldSchemaFile)
+		if err != nil {
+			return nil, err
+		}
+		if err = asttransform.MergeDefinitionWithBaseSchema(definition); err != nil {
+			return nil, err
+		}
+
+		files, err := graphqlFiles(schemadiffOperations)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			operation, err := parseGraphQLFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if err = usage.AddOperation(operation, definition); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+
+	if schemadiffUsageFile != "" {
+		content, err := os.ReadFile(schemadiffUsageFile)
+		if err != nil {
+			return nil, err
+		}
+		var infos []plan.SchemaUsageInfo
+		if err = json.Unmarshal(content, &infos); err != nil {
+			return nil, fmt.Errorf("%s: %w", schemadiffUsageFile, err)
+		}
+		for i := range infos {
+			usage.AddSchemaUsageInfo(&infos[i])
+		}
+	}
+
+	return usage, nil
+}
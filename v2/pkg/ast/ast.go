@@ -50,6 +50,8 @@ type Document struct {
 	VariableDefinitions          []VariableDefinition
 	FragmentDefinitions          []FragmentDefinition
 	BooleanValues                [2]BooleanValue
+	Comments                     []Comment
+	NodeComments                 map[Node]NodeComments
 	Refs                         [][8]int
 	RefIndex                     int
 	Index                        Index
@@ -158,6 +160,10 @@ func (d *Document) Reset() {
 	d.OperationDefinitions = d.OperationDefinitions[:0]
 	d.VariableDefinitions = d.VariableDefinitions[:0]
 	d.FragmentDefinitions = d.FragmentDefinitions[:0]
+	d.Comments = d.Comments[:0]
+	for node := range d.NodeComments {
+		delete(d.NodeComments, node)
+	}
 
 	d.RefIndex = -1
 	d.Index.Reset()
@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+)
+
+// Comment is a single comment line
+// example:
+//
+//	# this is a comment
+type Comment struct {
+	Content  ByteSliceReference // e.g. # this is a comment
+	Position position.Position
+}
+
+// NodeComments are the comments attached to a node.
+// Comments are only attached when parsing with comments, see astparser.Parser.ParseWithComments.
+// example:
+//
+//	type Query {
+//		# leading comment
+//		user: User # trailing comment
+//		# after comment, the node is the last one in its block
+//	}
+type NodeComments struct {
+	Leading  []int // Comments on the lines before the node
+	Trailing []int // Comment on the same line after the node
+	After    []int // Comments on the lines after the last node of a block or document
+}
+
+func (d *Document) CommentString(ref int) string {
+	return d.Input.ByteSliceString(d.Comments[ref].Content)
+}
+
+// NodeHasComments reports whether any comments are attached to the node
+func (d *Document) NodeHasComments(node Node) bool {
+	comments, ok := d.NodeComments[node]
+	return ok && (len(comments.Leading) != 0 || len(comments.Trailing) != 0 || len(comments.After) != 0)
+}
+
+// AddNodeComments appends comments to the comments of a node
+func (d *Document) AddNodeComments(node Node, comments NodeComments) {
+	if d.NodeComments == nil {
+		d.NodeComments = map[Node]NodeComments{}
+	}
+	existing := d.NodeComments[node]
+	existing.Leading = append(existing.Leading, comments.Leading...)
+	existing.Trailing = append(existing.Trailing, comments.Trailing...)
+	existing.After = append(existing.After, comments.After...)
+	d.NodeComments[node] = existing
+}
@@ -53,6 +53,15 @@ func (d *Document) EnumTypeDefinitionHasEnumValueDefinition(ref int) bool {
 	return d.EnumTypeDefinitions[ref].HasEnumValuesDefinition
 }
 
+func (d *Document) EnumTypeDefinitionEnumValueDefinitionByName(enumTypeDef int, valueName ByteSlice) int {
+	for _, i := range d.EnumTypeDefinitions[enumTypeDef].EnumValuesDefinition.Refs {
+		if bytes.Equal(valueName, d.EnumValueDefinitionNameBytes(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (d *Document) EnumTypeDefinitionContainsEnumValue(enumTypeDef int, valueName ByteSlice) bool {
 	for _, i := range d.EnumTypeDefinitions[enumTypeDef].EnumValuesDefinition.Refs {
 		if bytes.Equal(valueName, d.EnumValueDefinitionNameBytes(i)) {
@@ -7,6 +7,9 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
 )
 
+// OneOfDirectiveName is the name of the directive which marks input objects, where exactly one field must be set.
+const OneOfDirectiveName = "oneOf"
+
 type InputObjectTypeDefinition struct {
 	Description              Description        // optional, describes the input type
 	InputLiteral             position.Position  // input
@@ -36,6 +39,11 @@ func (d *Document) InputObjectTypeDefinitionDescriptionString(ref int) string {
 	return unsafebytes.BytesToString(d.InputObjectTypeDefinitionDescriptionBytes(ref))
 }
 
+// InputObjectTypeDefinitionIsOneOf returns true if the input object is annotated with @oneOf.
+func (d *Document) InputObjectTypeDefinitionIsOneOf(ref int) bool {
+	return d.InputObjectTypeDefinitions[ref].Directives.HasDirectiveByName(d, OneOfDirectiveName)
+}
+
 func (d *Document) InputObjectTypeDefinitionInputValueDefinitionDefaultValueString(inputObjectTypeDefinitionName, inputValueDefinitionName string) string {
 	defaultValue := d.InputObjectTypeDefinitionInputValueDefinitionDefaultValue(inputObjectTypeDefinitionName, inputValueDefinitionName)
 	if defaultValue.Kind != ValueKindString {
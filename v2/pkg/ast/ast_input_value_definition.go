@@ -78,17 +78,34 @@ func (d *Document) InputValueDefinitionHasDirective(ref int, directiveName ByteS
 	return false
 }
 
+func (d *Document) InputValueDefinitionDirectiveByName(inputValueDefinition int, directiveName ByteSlice) (ref int, exists bool) {
+	for _, i := range d.InputValueDefinitions[inputValueDefinition].Directives.Refs {
+		if bytes.Equal(directiveName, d.DirectiveNameBytes(i)) {
+			return i, true
+		}
+	}
+	return
+}
+
 func (d *Document) AddInputValueDefinition(inputValueDefinition InputValueDefinition) (ref int) {
 	d.InputValueDefinitions = append(d.InputValueDefinitions, inputValueDefinition)
 	return len(d.InputValueDefinitions) - 1
 }
 
 func (d *Document) ImportInputValueDefinition(name, description string, typeRef int, defaultValue DefaultValue) (ref int) {
+	return d.ImportInputValueDefinitionWithDirectives(name, description, typeRef, defaultValue, nil)
+}
+
+func (d *Document) ImportInputValueDefinitionWithDirectives(name, description string, typeRef int, defaultValue DefaultValue, directiveRefs []int) (ref int) {
 	inputValueDef := InputValueDefinition{
-		Description:  d.ImportDescription(description),
-		Name:         d.Input.AppendInputString(name),
-		Type:         typeRef,
-		DefaultValue: defaultValue,
+		Description:   d.ImportDescription(description),
+		Name:          d.Input.AppendInputString(name),
+		Type:          typeRef,
+		DefaultValue:  defaultValue,
+		HasDirectives: len(directiveRefs) > 0,
+		Directives: DirectiveList{
+			Refs: directiveRefs,
+		},
 	}
 
 	return d.AddInputValueDefinition(inputValueDef)
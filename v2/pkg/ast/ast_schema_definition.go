@@ -42,10 +42,22 @@ func (d *Document) AddSchemaDefinitionRootNode(schemaDefinition SchemaDefinition
 	d.RootNodes = append([]Node{schemaNode}, d.RootNodes...)
 }
 
+func (d *Document) SchemaDefinitionDescriptionString(ref int) string {
+	if !d.SchemaDefinitions[ref].Description.IsDefined {
+		return ""
+	}
+	return d.Input.ByteSliceString(d.SchemaDefinitions[ref].Description.Content)
+}
+
 func (d *Document) ImportSchemaDefinition(queryTypeName, mutationTypeName, subscriptionTypeName string) {
+	d.ImportSchemaDefinitionWithDescription("", queryTypeName, mutationTypeName, subscriptionTypeName)
+}
+
+func (d *Document) ImportSchemaDefinitionWithDescription(description, queryTypeName, mutationTypeName, subscriptionTypeName string) {
 	rootOperationTypeRefs := d.ImportRootOperationTypeDefinitions(queryTypeName, mutationTypeName, subscriptionTypeName)
 
 	schemaDefinition := SchemaDefinition{
+		Description: d.ImportDescription(description),
 		RootOperationTypeDefinitions: RootOperationTypeDefinitionList{
 			Refs: rootOperationTypeRefs,
 		},
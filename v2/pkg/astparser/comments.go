@@ -0,0 +1,201 @@
+package astparser
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/keyword"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/token"
+)
+
+// leadingComments are the comments in front of a node, after is the index of the last token before the node
+type leadingComments struct {
+	refs  []int
+	after int
+}
+
+// innerComment is a comment within a node, token is the index of the comment token
+type innerComment struct {
+	ref   int
+	token int
+}
+
+// takeLeadingComments returns all comments in front of the next token which are not attached to a node yet
+func (p *Parser) takeLeadingComments() leadingComments {
+	if !p.parseComments {
+		return leadingComments{}
+	}
+	p.collectComments()
+	leading := leadingComments{
+		refs:  p.pendingComments,
+		after: p.tokenizer.currentToken,
+	}
+	p.pendingComments = nil
+	return leading
+}
+
+// attachComments attaches the leading comments, the comments within the node
+// and the comment on the same line after the last read token to the node
+func (p *Parser) attachComments(node ast.Node, leading leadingComments) {
+	if !p.parseComments {
+		return
+	}
+	p.collectInnerComments()
+	refs := append(leading.refs, p.takeInnerComments(leading.after)...)
+	trailing := p.takeTrailingComment()
+	if len(refs) == 0 && len(trailing) == 0 {
+		return
+	}
+	p.document.AddNodeComments(node, ast.NodeComments{
+		Leading:  refs,
+		Trailing: trailing,
+	})
+}
+
+// attachAfterComments attaches all comments in front of the next token to the node,
+// it's used for comments between the last node of a block and the closing brace or the end of the document
+func (p *Parser) attachAfterComments(node ast.Node) {
+	if !p.parseComments {
+		return
+	}
+	p.collectComments()
+	if len(p.pendingComments) == 0 {
+		return
+	}
+	p.document.AddNodeComments(node, ast.NodeComments{
+		After: p.pendingComments,
+	})
+	p.pendingComments = nil
+}
+
+// restoreLeadingComments puts leading comments back in front of the pending comments,
+// so that they get attached to the next node if no node was parsed
+func (p *Parser) restoreLeadingComments(leading leadingComments) {
+	if len(leading.refs) == 0 {
+		return
+	}
+	p.pendingComments = append(leading.refs, p.pendingComments...)
+}
+
+// collectComments adds all comment tokens up to the next token to the pending comments,
+// comments in front of the last read token are within a node and added to the inner comments
+func (p *Parser) collectComments() {
+	i := p.commentToken
+	for ; i < p.tokenizer.maxTokens; i++ {
+		tok := p.tokenizer.tokens[i]
+		if tok.Keyword != keyword.COMMENT {
+			if i > p.tokenizer.currentToken {
+				break
+			}
+			continue
+		}
+		if i < p.tokenizer.currentToken {
+			p.addInnerComments(tok, i)
+			continue
+		}
+		p.pendingComments = append(p.pendingComments, p.addComments(tok)...)
+	}
+	p.commentToken = i
+}
+
+// collectInnerComments adds all comment tokens in front of the last read token to the inner comments
+func (p *Parser) collectInnerComments() {
+	for ; p.commentToken < p.tokenizer.currentToken; p.commentToken++ {
+		tok := p.tokenizer.tokens[p.commentToken]
+		if tok.Keyword == keyword.COMMENT {
+			p.addInnerComments(tok, p.commentToken)
+		}
+	}
+}
+
+func (p *Parser) addInnerComments(tok token.Token, tokenIndex int) {
+	for _, ref := range p.addComments(tok) {
+		p.innerComments = append(p.innerComments, innerComment{ref: ref, token: tokenIndex})
+	}
+}
+
+// takeInnerComments returns the inner comments after the token with the index after,
+// comments of nested nodes are already taken when the enclosing node takes its comments
+func (p *Parser) takeInnerComments(after int) (refs []int) {
+	remaining := p.innerComments[:0]
+	for _, comment := range p.innerComments {
+		if comment.token > after {
+			refs = append(refs, comment.ref)
+			continue
+		}
+		remaining = append(remaining, comment)
+	}
+	p.innerComments = remaining
+	return refs
+}
+
+// takeTrailingComment returns the first line of a comment which starts on the line of the last read token
+func (p *Parser) takeTrailingComment() []int {
+	next := p.tokenizer.currentToken + 1
+	if p.tokenizer.currentToken < 0 || next < p.commentToken || next >= p.tokenizer.maxTokens {
+		return nil
+	}
+	tok := p.tokenizer.tokens[next]
+	if tok.Keyword != keyword.COMMENT || tok.TextPosition.LineStart != p.tokenizer.tokens[p.tokenizer.currentToken].TextPosition.LineEnd {
+		return nil
+	}
+	p.commentToken = next + 1
+	comments := p.addComments(tok)
+	p.pendingComments = append(p.pendingComments, comments[1:]...)
+	return comments[:1]
+}
+
+// addComments adds a Comment for each line of a comment token, the lexer combines consecutive comment lines into one token
+func (p *Parser) addComments(tok token.Token) (refs []int) {
+	content := p.document.Input.ByteSlice(tok.Literal)
+	line := tok.TextPosition.LineStart
+	lineStart := 0
+	char := tok.TextPosition.CharStart
+
+	for i := 0; i <= len(content); i++ {
+		if i < len(content) && content[i] != '\n' {
+			continue
+		}
+
+		start, end := lineStart, i
+		for start < end && (content[start] == ' ' || content[start] == '\t') {
+			start++
+			char++
+		}
+		for end > start && (content[end-1] == '\r' || content[end-1] == ' ' || content[end-1] == '\t') {
+			end--
+		}
+		if start < end {
+			p.document.Comments = append(p.document.Comments, ast.Comment{
+				Content: ast.ByteSliceReference{
+					Start: tok.Literal.Start + uint32(start),
+					End:   tok.Literal.Start + uint32(end),
+				},
+				Position: position.Position{
+					LineStart: line,
+					LineEnd:   line,
+					CharStart: char,
+					CharEnd:   char + uint32(end-start),
+				},
+			})
+			refs = append(refs, len(p.document.Comments)-1)
+		}
+
+		line++
+		lineStart = i + 1
+		char = 1
+	}
+	return refs
+}
+
+// selectionNode returns the node of a selection, comments are attached to the field or fragment instead of the selection
+func (p *Parser) selectionNode(ref int) ast.Node {
+	selection := p.document.Selections[ref]
+	switch selection.Kind {
+	case ast.SelectionKindFragmentSpread:
+		return ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: selection.Ref}
+	case ast.SelectionKindInlineFragment:
+		return ast.Node{Kind: ast.NodeKindInlineFragment, Ref: selection.Ref}
+	default:
+		return ast.Node{Kind: ast.NodeKindField, Ref: selection.Ref}
+	}
+}
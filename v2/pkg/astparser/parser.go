@@ -36,6 +36,40 @@ func ParseGraphqlDocumentBytes(input []byte) (ast.Document, operationreport.Repo
 	return doc, report
 }
 
+// ParseGraphqlDocumentStringWithMaxTokens parses a raw GraphQL document like ParseGraphqlDocumentString
+// but rejects documents with more than maxTokens tokens before parsing, see Parser.SetMaxTokens.
+func ParseGraphqlDocumentStringWithMaxTokens(input string, maxTokens int) (ast.Document, operationreport.Report) {
+	parser := NewParser()
+	parser.SetMaxTokens(maxTokens)
+	doc := *ast.NewSmallDocument()
+	doc.Input.ResetInputString(input)
+	report := operationreport.Report{}
+	parser.Parse(&doc, &report)
+	return doc, report
+}
+
+// ParseGraphqlDocumentStringWithComments parses a raw GraphQL document like ParseGraphqlDocumentString
+// and attaches comments to the nodes, see Parser.ParseWithComments.
+func ParseGraphqlDocumentStringWithComments(input string) (ast.Document, operationreport.Report) {
+	parser := NewParser()
+	doc := *ast.NewSmallDocument()
+	doc.Input.ResetInputString(input)
+	report := operationreport.Report{}
+	parser.ParseWithComments(&doc, &report)
+	return doc, report
+}
+
+// ParseGraphqlDocumentStringWithRecovery parses a raw GraphQL document like ParseGraphqlDocumentString
+// but doesn't stop at the first syntax error, see Parser.ParseWithRecovery.
+func ParseGraphqlDocumentStringWithRecovery(input string) (ast.Document, operationreport.Report) {
+	parser := NewParser()
+	doc := *ast.NewSmallDocument()
+	doc.Input.ResetInputString(input)
+	report := operationreport.Report{}
+	parser.ParseWithRecovery(&doc, &report)
+	return doc, report
+}
+
 // Parser takes a raw input and turns it into an AST
 // use NewParser() to create a parser
 // Don't create new parsers in the hot path, re-use them.
@@ -45,6 +79,20 @@ type Parser struct {
 	tokenizer            *Tokenizer
 	shouldIndex          bool
 	reportInternalErrors bool
+	// recover is true while parsing with ParseWithRecovery
+	recover bool
+	// failed is set in recovery mode when the current node has a syntax error,
+	// it gets reset once the parser has skipped to a point where it can continue
+	failed bool
+	// parseComments is true while parsing with ParseWithComments
+	parseComments bool
+	// commentToken is the index of the next token to look for comments
+	commentToken int
+	// pendingComments are comments which are not attached to a node yet
+	pendingComments []int
+	// innerComments are comments within a node which can't have comments itself, e.g. between arguments,
+	// they are attached to the enclosing node
+	innerComments []innerComment
 }
 
 // NewParser returns a new parser with all values properly initialized
@@ -56,6 +104,13 @@ func NewParser() *Parser {
 	}
 }
 
+// SetMaxTokens limits the number of tokens of the parsed documents, comments are not counted.
+// Documents with more tokens are rejected before parsing, which protects from expensive oversized documents.
+// 0 disables the limit, which is the default.
+func (p *Parser) SetMaxTokens(maxTokens int) {
+	p.tokenizer.tokenLimit = maxTokens
+}
+
 // PrepareImport prepares the Parser for importing new Nodes into an AST without directly parsing the content
 func (p *Parser) PrepareImport(document *ast.Document, report *operationreport.Report) {
 	p.document = document
@@ -67,20 +122,74 @@ func (p *Parser) PrepareImport(document *ast.Document, report *operationreport.R
 func (p *Parser) Parse(document *ast.Document, report *operationreport.Report) {
 	p.document = document
 	p.report = report
-	p.tokenize()
-	p.parse()
+	p.recover = false
+	p.failed = false
+	p.parseComments = false
+	if p.tokenize() {
+		p.parse()
+	}
 }
 
-func (p *Parser) tokenize() {
+// ParseWithRecovery parses all input in a Document.Input into the Document without stopping at the first syntax error.
+// All syntax errors are collected in the report while the parser keeps producing a best-effort Document:
+// missing names are replaced by empty placeholder names, unclosed selection sets are closed at the end of the input
+// and invalid tokens are skipped until the next selection or definition.
+// Placeholders keep the position of the token where the missing node was expected.
+// This mode is intended for editor tooling, e.g. to provide completions for incomplete operations.
+func (p *Parser) ParseWithRecovery(document *ast.Document, report *operationreport.Report) {
+	p.document = document
+	p.report = report
+	p.recover = true
+	p.failed = false
+	p.parseComments = false
+	if p.tokenize() {
+		p.parse()
+	}
+	p.recover = false
+}
+
+// ParseWithComments parses all input in a Document.Input into the Document and attaches comments to the nodes.
+// Comments are attached to root nodes, field definitions, input value definitions, enum value definitions and selections,
+// see ast.NodeComments. Other comments, e.g. between arguments, are attached as leading comments to the enclosing node.
+// Comments are ignored by the regular printer, use astprinter.Format to print a Document including its comments.
+func (p *Parser) ParseWithComments(document *ast.Document, report *operationreport.Report) {
+	p.document = document
+	p.report = report
+	p.recover = false
+	p.failed = false
+	p.parseComments = true
+	p.commentToken = 0
+	p.pendingComments = nil
+	p.innerComments = nil
+	if p.tokenize() {
+		p.parse()
+	}
+	p.parseComments = false
+}
+
+// tokenize returns false if the input exceeds the token limit, the error is added to the report
+func (p *Parser) tokenize() bool {
 	p.tokenizer.Tokenize(&p.document.Input)
+	if p.tokenizer.tokenLimitExceeded {
+		p.report.AddExternalError(operationreport.ErrDocumentTokenLimitExceeded(p.tokenizer.tokenLimit))
+		return false
+	}
+	return true
 }
 
 func (p *Parser) parse() {
 	for {
+		start := p.tokenizer.currentToken
+		rootNodes := len(p.document.RootNodes)
+		leading := p.takeLeadingComments()
 		key, literalReference := p.peekLiteral()
 
 		switch key {
 		case keyword.EOF:
+			p.restoreLeadingComments(leading)
+			if rootNodes != 0 {
+				p.attachAfterComments(p.document.RootNodes[rootNodes-1])
+			}
 			p.read()
 			return
 		case keyword.LBRACE:
@@ -119,12 +228,119 @@ func (p *Parser) parse() {
 			p.errUnexpectedToken(p.read(), keyword.EOF, keyword.LBRACE, keyword.COMMENT, keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT)
 		}
 
-		if p.report.HasErrors() {
+		if len(p.document.RootNodes) > rootNodes {
+			p.attachComments(p.document.RootNodes[len(p.document.RootNodes)-1], leading)
+		} else {
+			p.restoreLeadingComments(leading)
+		}
+		// inner comments of a root node which couldn't be parsed are dropped
+		p.innerComments = p.innerComments[:0]
+
+		if p.recover {
+			p.recoverDefinition(start)
+			continue
+		}
+
+		if p.hasErrors() {
 			return
 		}
 	}
 }
 
+// hasErrors reports whether parsing of the current node has to stop.
+// In recovery mode only errors of the current node count, errors of previous nodes have already been recovered from.
+func (p *Parser) hasErrors() bool {
+	if p.recover {
+		return p.failed
+	}
+	return p.report.HasErrors()
+}
+
+// recoverDefinition skips all tokens of a failed definition up to the start of the next definition
+func (p *Parser) recoverDefinition(start int) {
+	if !p.failed {
+		return
+	}
+	if p.tokenizer.currentToken == start {
+		// make sure the parser doesn't get stuck on a token it can't parse
+		p.read()
+	}
+	depth := 0
+	for {
+		key, literal := p.peekLiteral()
+		switch key {
+		case keyword.EOF:
+			p.failed = false
+			return
+		case keyword.LBRACE:
+			if depth == 0 && p.isDefinitionStart(p.tokenizer.currentToken) {
+				p.failed = false
+				return
+			}
+			depth++
+		case keyword.RBRACE:
+			if depth > 0 {
+				depth--
+			}
+		case keyword.IDENT:
+			if depth == 0 && p.isDefinitionKeyword(p.identKeywordSliceRef(literal)) {
+				p.failed = false
+				return
+			}
+		}
+		p.read()
+	}
+}
+
+// isDefinitionStart reports whether an anonymous operation can start after the token with the index current.
+// This is the case when the previous token closes a node, so that '{' can't be the body of a definition.
+func (p *Parser) isDefinitionStart(current int) bool {
+	if current < 0 {
+		return true
+	}
+	return p.tokenizer.tokens[current].Keyword == keyword.RBRACE
+}
+
+func (p *Parser) isDefinitionKeyword(key identkeyword.IdentKeyword) bool {
+	switch key {
+	case identkeyword.ENUM, identkeyword.TYPE, identkeyword.UNION, identkeyword.QUERY, identkeyword.MUTATION,
+		identkeyword.SUBSCRIPTION, identkeyword.INPUT, identkeyword.EXTEND, identkeyword.SCHEMA, identkeyword.SCALAR,
+		identkeyword.FRAGMENT, identkeyword.INTERFACE, identkeyword.DIRECTIVE:
+		return true
+	default:
+		return false
+	}
+}
+
+// recoverSelection skips all tokens of a failed selection up to the next selection or the end of the selection set
+func (p *Parser) recoverSelection() {
+	if !p.failed {
+		return
+	}
+	depth := 0
+	for {
+		switch p.peek() {
+		case keyword.EOF:
+			// keep failed, so that unclosed selection sets don't report the end of the input again
+			return
+		case keyword.LBRACE:
+			depth++
+		case keyword.RBRACE:
+			if depth == 0 {
+				p.failed = false
+				return
+			}
+			depth--
+		case keyword.IDENT, keyword.SPREAD:
+			if depth == 0 {
+				p.failed = false
+				return
+			}
+		}
+		p.read()
+	}
+}
+
 func (p *Parser) identKeywordToken(token token.Token) identkeyword.IdentKeyword {
 	return identkeyword.KeywordFromLiteral(p.document.Input.ByteSlice(token.Literal))
 }
@@ -135,9 +351,10 @@ func (p *Parser) identKeywordSliceRef(ref ast.ByteSliceReference) identkeyword.I
 
 func (p *Parser) errUnexpectedIdentKey(unexpected token.Token, unexpectedKey identkeyword.IdentKeyword, expectedKeywords ...identkeyword.IdentKeyword) {
 
-	if p.report.HasErrors() {
+	if p.hasErrors() {
 		return
 	}
+	p.failed = p.recover
 
 	p.report.AddExternalError(operationreport.ExternalError{
 		Message: fmt.Sprintf("unexpected literal - got: %s want one of: %v", unexpectedKey, expectedKeywords),
@@ -182,9 +399,10 @@ func (p *Parser) errUnexpectedIdentKey(unexpected token.Token, unexpectedKey ide
 
 func (p *Parser) errUnexpectedToken(unexpected token.Token, expectedKeywords ...keyword.Keyword) {
 
-	if p.report.HasErrors() {
+	if p.hasErrors() {
 		return
 	}
+	p.failed = p.recover
 
 	p.report.AddExternalError(operationreport.ExternalError{
 		Message: fmt.Sprintf("unexpected token - got: %s want one of: %v", unexpected.Keyword, expectedKeywords),
@@ -304,7 +522,7 @@ func (p *Parser) parseRootOperationTypeDefinitionList(list *ast.RootOperationTyp
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -364,7 +582,7 @@ func (p *Parser) parseDirectiveList() (list ast.DirectiveList) {
 
 		list.Refs = append(list.Refs, ref)
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -375,6 +593,7 @@ func (p *Parser) parseDirectiveList() (list ast.DirectiveList) {
 func (p *Parser) parseArgumentList() (list ast.ArgumentList) {
 
 	bracketOpen := p.mustRead(keyword.LPAREN)
+	list.LPAREN = bracketOpen.TextPosition
 
 Loop:
 	for {
@@ -406,14 +625,12 @@ Loop:
 
 		list.Refs = append(list.Refs, ref)
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
 
 	bracketClose := p.mustRead(keyword.RPAREN)
-
-	list.LPAREN = bracketOpen.TextPosition
 	list.RPAREN = bracketClose.TextPosition
 
 	return
@@ -485,7 +702,7 @@ func (p *Parser) parseObjectValue() (ref int, pos position.Position) {
 			return ast.InvalidRef, position.Position{}
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return ast.InvalidRef, position.Position{}
 		}
 	}
@@ -525,7 +742,7 @@ func (p *Parser) parseValueList() int {
 			list.Refs = append(list.Refs, ref)
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return ast.InvalidRef
 		}
 	}
@@ -766,7 +983,7 @@ func (p *Parser) parseImplementsInterfaces() (list ast.TypeList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -784,10 +1001,15 @@ func (p *Parser) parseFieldDefinitionList() (list ast.FieldDefinitionList) {
 
 		switch next {
 		case keyword.RBRACE:
+			if len(list.Refs) != 0 {
+				p.attachAfterComments(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: list.Refs[len(list.Refs)-1]})
+			}
 			list.RBRACE = p.read().TextPosition
 			return
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
+			leading := p.takeLeadingComments()
 			ref := p.parseFieldDefinition()
+			p.attachComments(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}, leading)
 			if !refsInitialized {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 				refsInitialized = true
@@ -798,7 +1020,7 @@ func (p *Parser) parseFieldDefinitionList() (list ast.FieldDefinitionList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -898,10 +1120,15 @@ func (p *Parser) parseInputValueDefinitionList(closingKeyword keyword.Keyword) (
 		next := p.peek()
 		switch next {
 		case closingKeyword:
+			if len(list.Refs) != 0 {
+				p.attachAfterComments(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: list.Refs[len(list.Refs)-1]})
+			}
 			list.RPAREN = p.read().TextPosition
 			return
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
+			leading := p.takeLeadingComments()
 			ref := p.parseInputValueDefinition()
+			p.attachComments(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}, leading)
 			if cap(list.Refs) == 0 {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
@@ -911,7 +1138,7 @@ func (p *Parser) parseInputValueDefinitionList(closingKeyword keyword.Keyword) (
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -1101,7 +1328,7 @@ func (p *Parser) parseUnionMemberTypes() (list ast.TypeList) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -1142,12 +1369,17 @@ func (p *Parser) parseEnumValueDefinitionList() (list ast.EnumValueDefinitionLis
 		next := p.peek()
 		switch next {
 		case keyword.STRING, keyword.BLOCKSTRING, keyword.IDENT:
+			leading := p.takeLeadingComments()
 			ref := p.parseEnumValueDefinition()
+			p.attachComments(ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}, leading)
 			if cap(list.Refs) == 0 {
 				list.Refs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
 			list.Refs = append(list.Refs, ref)
 		case keyword.RBRACE:
+			if len(list.Refs) != 0 {
+				p.attachAfterComments(ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: list.Refs[len(list.Refs)-1]})
+			}
 			list.RBRACE = p.read().TextPosition
 			return
 		default:
@@ -1155,7 +1387,7 @@ func (p *Parser) parseEnumValueDefinitionList() (list ast.EnumValueDefinitionLis
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -1268,7 +1500,7 @@ func (p *Parser) parseDirectiveLocations(locations *ast.DirectiveLocations) {
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
@@ -1283,29 +1515,48 @@ func (p *Parser) parseSelectionSet() (int, bool) {
 	set.LBrace = lbraceToken.TextPosition
 
 	for {
-		switch p.peek() {
+		next := p.peek()
+		if p.recover && next == keyword.EOF {
+			// an unclosed selection set gets closed at the end of the input
+			next = keyword.RBRACE
+		}
+
+		switch next {
 		case keyword.RBRACE:
-			rbraceToken := p.read()
+			if len(set.SelectionRefs) != 0 {
+				p.attachAfterComments(p.selectionNode(set.SelectionRefs[len(set.SelectionRefs)-1]))
+			}
+			rbraceToken := p.mustRead(keyword.RBRACE)
 			set.RBrace = rbraceToken.TextPosition
 
-			if len(set.SelectionRefs) == 0 {
+			if len(set.SelectionRefs) == 0 && !p.recover {
 				return 0, false
 			}
 
+			// in recovery mode empty selection sets are kept as placeholders, so that their position is known
 			p.document.SelectionSets = append(p.document.SelectionSets, set)
-			return len(p.document.SelectionSets) - 1, true
+			return len(p.document.SelectionSets) - 1, len(set.SelectionRefs) != 0
 
 		case keyword.IDENT, keyword.SPREAD:
 			if cap(set.SelectionRefs) == 0 {
 				set.SelectionRefs = p.document.Refs[p.document.NextRefIndex()][:0]
 			}
+			leading := p.takeLeadingComments()
 			ref := p.parseSelection()
+			if ref != ast.InvalidRef {
+				p.attachComments(p.selectionNode(ref), leading)
+			}
 			set.SelectionRefs = append(set.SelectionRefs, ref)
 		default:
 			p.errUnexpectedToken(p.read(), keyword.RBRACE, keyword.IDENT, keyword.SPREAD)
 		}
 
-		if p.report.HasErrors() {
+		if p.recover {
+			p.recoverSelection()
+			continue
+		}
+
+		if p.hasErrors() {
 			return ast.InvalidRef, false
 		}
 	}
@@ -1350,6 +1601,12 @@ func (p *Parser) parseFragmentSelection(spread position.Position) int {
 			selection.Ref = p.parseFragmentSpread(spread)
 		}
 	default:
+		if p.recover {
+			// the fragment name is missing, parseFragmentSpread adds a placeholder
+			selection.Kind = ast.SelectionKindFragmentSpread
+			selection.Ref = p.parseFragmentSpread(spread)
+			break
+		}
 		nextToken := p.read()
 		p.errUnexpectedToken(nextToken, keyword.IDENT)
 	}
@@ -1519,7 +1776,7 @@ func (p *Parser) parseVariableDefinitionList() (list ast.VariableDefinitionList)
 			return
 		}
 
-		if p.report.HasErrors() {
+		if p.hasErrors() {
 			return
 		}
 	}
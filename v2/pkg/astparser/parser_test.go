@@ -2320,6 +2320,164 @@ func TestErrorReport(t *testing.T) {
 	})
 }
 
+func TestParser_ParseWithRecovery(t *testing.T) {
+	errorMessages := func(report operationreport.Report) (messages []string) {
+		for _, err := range report.ExternalErrors {
+			messages = append(messages, fmt.Sprintf("%s %d:%d", err.Message, err.Locations[0].Line, err.Locations[0].Column))
+		}
+		return messages
+	}
+
+	selectionNames := func(doc *ast.Document, set int) (names []string) {
+		for _, ref := range doc.SelectionSets[set].SelectionRefs {
+			selection := doc.Selections[ref]
+			switch selection.Kind {
+			case ast.SelectionKindField:
+				names = append(names, doc.FieldNameString(selection.Ref))
+			case ast.SelectionKindFragmentSpread:
+				names = append(names, "..."+doc.FragmentSpreadNameString(selection.Ref))
+			case ast.SelectionKindInlineFragment:
+				names = append(names, "... on "+doc.InlineFragmentTypeConditionNameString(selection.Ref))
+			}
+		}
+		return names
+	}
+
+	t.Run("valid document", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`query Q { user { name } }`)
+		require.False(t, report.HasErrors())
+		require.Len(t, doc.OperationDefinitions, 1)
+		assert.Equal(t, []string{"user"}, selectionNames(&doc, doc.OperationDefinitions[0].SelectionSet))
+	})
+
+	t.Run("unclosed selection sets", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery("{\n  user {\n    name\n    ")
+		assert.Equal(t, []string{"unexpected token - got: EOF want one of: [RBRACE] 3:9"}, errorMessages(report))
+
+		require.Len(t, doc.OperationDefinitions, 1)
+		operation := doc.OperationDefinitions[0]
+		assert.True(t, operation.HasSelections)
+		assert.Equal(t, []string{"user"}, selectionNames(&doc, operation.SelectionSet))
+
+		user := doc.Selections[doc.SelectionSets[operation.SelectionSet].SelectionRefs[0]].Ref
+		assert.True(t, doc.Fields[user].HasSelections)
+		assert.Equal(t, []string{"name"}, selectionNames(&doc, doc.Fields[user].SelectionSet))
+	})
+
+	t.Run("empty selection set is kept as placeholder", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`{ user { } }`)
+		assert.False(t, report.HasErrors())
+
+		user := doc.Selections[doc.SelectionSets[doc.OperationDefinitions[0].SelectionSet].SelectionRefs[0]].Ref
+		assert.False(t, doc.Fields[user].HasSelections)
+		set := doc.SelectionSets[doc.Fields[user].SelectionSet]
+		assert.Equal(t, position.Position{LineStart: 1, LineEnd: 1, CharStart: 8, CharEnd: 9}, set.LBrace)
+		assert.Equal(t, position.Position{LineStart: 1, LineEnd: 1, CharStart: 10, CharEnd: 11}, set.RBrace)
+	})
+
+	t.Run("missing selection set of operation", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`query Q`)
+		assert.Equal(t, []string{"unexpected token - got: EOF want one of: [LBRACE] 1:8"}, errorMessages(report))
+
+		require.Len(t, doc.OperationDefinitions, 1)
+		operation := doc.OperationDefinitions[0]
+		assert.Equal(t, "Q", doc.OperationDefinitionNameString(0))
+		assert.False(t, operation.HasSelections)
+		assert.Equal(t, position.Position{LineStart: 1, LineEnd: 1, CharStart: 8, CharEnd: 8}, doc.SelectionSets[operation.SelectionSet].LBrace)
+	})
+
+	t.Run("placeholder names", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`{ a: } { ... }`)
+		assert.Equal(t, []string{
+			"unexpected token - got: RBRACE want one of: [IDENT] 1:6",
+			"unexpected token - got: RBRACE want one of: [IDENT] 1:14",
+		}, errorMessages(report))
+
+		require.Len(t, doc.OperationDefinitions, 2)
+		assert.Equal(t, []string{""}, selectionNames(&doc, doc.OperationDefinitions[0].SelectionSet))
+		field := doc.Selections[doc.SelectionSets[doc.OperationDefinitions[0].SelectionSet].SelectionRefs[0]].Ref
+		assert.Equal(t, "a", doc.FieldAliasString(field))
+		assert.Equal(t, []string{"..."}, selectionNames(&doc, doc.OperationDefinitions[1].SelectionSet))
+	})
+
+	t.Run("skips invalid selections", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`{ a(x: ) { b } 1 2 c ... on User { d } e(: 1) f }`)
+		assert.Equal(t, []string{
+			"unexpected token - got: RPAREN want one of: [] 1:8",
+			"unexpected token - got: INTEGER want one of: [RBRACE IDENT SPREAD] 1:16",
+			"unexpected token - got: COLON want one of: [RPAREN] 1:42",
+		}, errorMessages(report))
+
+		require.Len(t, doc.OperationDefinitions, 1)
+		assert.Equal(t, []string{"a", "c", "... on User", "e", "f"}, selectionNames(&doc, doc.OperationDefinitions[0].SelectionSet))
+	})
+
+	t.Run("continues with next definition", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithRecovery(`
+			type Query { user(id: ): User }
+			type User { name: String }
+			query Q { user { name } }
+			fragment F on { name }
+			{ user { name } }
+		`)
+		assert.Equal(t, []string{
+			"unexpected token - got: RPAREN want one of: [IDENT LBRACK] 2:26",
+			"unexpected token - got: LBRACE want one of: [IDENT] 5:18",
+		}, errorMessages(report))
+
+		require.Len(t, doc.ObjectTypeDefinitions, 2)
+		assert.Equal(t, "User", doc.ObjectTypeDefinitionNameString(1))
+		require.Len(t, doc.OperationDefinitions, 2)
+		assert.Equal(t, "Q", doc.OperationDefinitionNameString(0))
+		assert.Equal(t, []string{"user"}, selectionNames(&doc, doc.OperationDefinitions[1].SelectionSet))
+		require.Len(t, doc.FragmentDefinitions, 1)
+		assert.Equal(t, "F", doc.FragmentDefinitionNameString(0))
+	})
+
+	t.Run("parser can be reused without recovery", func(t *testing.T) {
+		parser := NewParser()
+		doc := ast.NewSmallDocument()
+		doc.Input.ResetInputString(`{ a: } { b }`)
+		report := operationreport.Report{}
+		parser.ParseWithRecovery(doc, &report)
+		assert.Len(t, report.ExternalErrors, 1)
+		assert.Len(t, doc.OperationDefinitions, 2)
+
+		doc.Reset()
+		doc.Input.ResetInputString(`{ a: } { b }`)
+		report.Reset()
+		parser.Parse(doc, &report)
+		assert.Len(t, report.ExternalErrors, 1)
+		assert.Len(t, doc.OperationDefinitions, 1)
+	})
+}
+
+func TestParser_SetMaxTokens(t *testing.T) {
+	// 8 tokens: query Hero { hero { name } }
+	const query = `query Hero { hero { name } }`
+
+	t.Run("within the limit", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithMaxTokens(query, 8)
+		require.False(t, report.HasErrors(), report.Error())
+		assert.Len(t, doc.OperationDefinitions, 1)
+	})
+	t.Run("exceeding the limit", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithMaxTokens(query, 7)
+		require.True(t, report.HasErrors())
+		require.Len(t, report.ExternalErrors, 1)
+		assert.Equal(t, "Document contains more than 7 tokens, parsing aborted.", report.ExternalErrors[0].Message)
+		assert.Len(t, doc.OperationDefinitions, 0)
+	})
+	t.Run("comments are not counted", func(t *testing.T) {
+		_, report := ParseGraphqlDocumentStringWithMaxTokens("# the hero\n# and its name\n"+query, 8)
+		assert.False(t, report.HasErrors(), report.Error())
+	})
+	t.Run("no limit", func(t *testing.T) {
+		_, report := ParseGraphqlDocumentStringWithMaxTokens(query, 0)
+		assert.False(t, report.HasErrors(), report.Error())
+	})
+}
+
 func TestParseStarwars(t *testing.T) {
 
 	starWarsSchema, err := os.ReadFile("./testdata/starwars.schema.graphql")
@@ -2739,3 +2897,128 @@ fragment TypeRef on __Type {
     }
   }
 }`)
+
+func TestParser_ParseWithComments(t *testing.T) {
+	comments := func(doc *ast.Document, refs []int) (out []string) {
+		for _, ref := range refs {
+			out = append(out, doc.CommentString(ref))
+		}
+		return out
+	}
+
+	t.Run("root nodes", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithComments("# header\n#   second\n\"desc\" type Query { a: Int } # trailing\n# between\nscalar Date\n# end")
+		require.False(t, report.HasErrors())
+		require.Len(t, doc.Comments, 5)
+		assert.Equal(t, uint32(2), doc.Comments[1].Position.LineStart)
+		assert.Equal(t, uint32(1), doc.Comments[1].Position.CharStart)
+
+		query := doc.NodeComments[doc.RootNodes[0]]
+		assert.Equal(t, []string{"# header", "#   second"}, comments(&doc, query.Leading))
+		assert.Equal(t, []string{"# trailing"}, comments(&doc, query.Trailing))
+
+		date := doc.NodeComments[doc.RootNodes[1]]
+		assert.Equal(t, []string{"# between"}, comments(&doc, date.Leading))
+		assert.Equal(t, []string{"# end"}, comments(&doc, date.After))
+	})
+
+	t.Run("definitions", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithComments(`
+			type Query {
+				# user
+				user(
+					id: ID! # id
+				): User # user trailing
+				# last
+			}
+			enum E {
+				A # a
+				# b
+				B
+			}`)
+		require.False(t, report.HasErrors())
+
+		user := doc.NodeComments[ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: 0}]
+		assert.Equal(t, []string{"# user"}, comments(&doc, user.Leading))
+		assert.Equal(t, []string{"# user trailing"}, comments(&doc, user.Trailing))
+		assert.Equal(t, []string{"# last"}, comments(&doc, user.After))
+
+		id := doc.NodeComments[ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: 0}]
+		assert.Equal(t, []string{"# id"}, comments(&doc, id.Trailing))
+
+		a := doc.NodeComments[ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: 0}]
+		assert.Equal(t, []string{"# a"}, comments(&doc, a.Trailing))
+		b := doc.NodeComments[ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: 1}]
+		assert.Equal(t, []string{"# b"}, comments(&doc, b.Leading))
+	})
+
+	t.Run("selections", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithComments(`
+			query Q {
+				# user
+				user { id } # user trailing
+				...F # spread
+				... on Query { a }
+				# last
+			}`)
+		require.False(t, report.HasErrors())
+
+		user := doc.NodeComments[ast.Node{Kind: ast.NodeKindField, Ref: 1}]
+		assert.Equal(t, []string{"# user"}, comments(&doc, user.Leading))
+		assert.Equal(t, []string{"# user trailing"}, comments(&doc, user.Trailing))
+
+		spread := doc.NodeComments[ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: 0}]
+		assert.Equal(t, []string{"# spread"}, comments(&doc, spread.Trailing))
+
+		inlineFragment := doc.NodeComments[ast.Node{Kind: ast.NodeKindInlineFragment, Ref: 0}]
+		assert.Equal(t, []string{"# last"}, comments(&doc, inlineFragment.After))
+	})
+
+	t.Run("comments within nodes without comments", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithComments(`
+			union U =
+				# a
+				A
+				# b
+				| B
+			type Query { a: Int }
+			query Q(
+				# variable
+				$a: Int
+			) {
+				user(filter: {
+					# value
+					a: $a
+				}) { id }
+				other
+			}`)
+		require.False(t, report.HasErrors())
+
+		union := doc.NodeComments[doc.RootNodes[0]]
+		assert.Equal(t, []string{"# a", "# b"}, comments(&doc, union.Leading))
+		assert.False(t, doc.NodeHasComments(doc.RootNodes[1]))
+		assert.False(t, doc.NodeHasComments(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: 0}))
+
+		operation := doc.NodeComments[doc.RootNodes[2]]
+		assert.Equal(t, []string{"# variable"}, comments(&doc, operation.Leading))
+
+		user := doc.NodeComments[ast.Node{Kind: ast.NodeKindField, Ref: 1}]
+		assert.Equal(t, []string{"# value"}, comments(&doc, user.Leading))
+		assert.False(t, doc.NodeHasComments(ast.Node{Kind: ast.NodeKindField, Ref: 0}))
+		assert.False(t, doc.NodeHasComments(ast.Node{Kind: ast.NodeKindField, Ref: 2}))
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentStringWithComments("# a\n# b\n")
+		require.False(t, report.HasErrors())
+		assert.Len(t, doc.RootNodes, 0)
+		assert.Equal(t, []string{"# a", "# b"}, comments(&doc, []int{0, 1}))
+	})
+
+	t.Run("parse ignores comments", func(t *testing.T) {
+		doc, report := ParseGraphqlDocumentString("# comment\ntype Query { a: Int }")
+		require.False(t, report.HasErrors())
+		assert.Len(t, doc.Comments, 0)
+		assert.Len(t, doc.NodeComments, 0)
+	})
+}
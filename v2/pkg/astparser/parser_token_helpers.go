@@ -45,6 +45,9 @@ func (p *Parser) peekEqualsIdentKey(identKey identkeyword.IdentKeyword) bool {
 }
 
 func (p *Parser) mustRead(key keyword.Keyword) (next token.Token) {
+	if p.recover && p.peek() != key {
+		return p.missingToken(key)
+	}
 	next = p.read()
 	if next.Keyword != key {
 		p.errUnexpectedToken(next, key)
@@ -53,6 +56,9 @@ func (p *Parser) mustRead(key keyword.Keyword) (next token.Token) {
 }
 
 func (p *Parser) mustReadIdentKey(key identkeyword.IdentKeyword) (next token.Token) {
+	if p.recover && p.peek() != keyword.IDENT {
+		return p.missingToken(keyword.IDENT)
+	}
 	next = p.read()
 	if next.Keyword != keyword.IDENT {
 		p.errUnexpectedToken(next, keyword.IDENT)
@@ -65,6 +71,9 @@ func (p *Parser) mustReadIdentKey(key identkeyword.IdentKeyword) (next token.Tok
 }
 
 func (p *Parser) mustReadExceptIdentKey(key identkeyword.IdentKeyword) (next token.Token) {
+	if p.recover && p.peek() != keyword.IDENT {
+		return p.missingToken(keyword.IDENT)
+	}
 	next = p.read()
 	if next.Keyword != keyword.IDENT {
 		p.errUnexpectedToken(next, keyword.IDENT)
@@ -88,3 +97,29 @@ func (p *Parser) mustReadOneOf(keys ...identkeyword.IdentKeyword) (token.Token,
 	p.errUnexpectedToken(next)
 	return next, identKey
 }
+
+// missingToken reports the next token as unexpected without reading it and returns a placeholder token instead.
+// It's used in recovery mode, so that the unexpected token can still be parsed, e.g. a closing brace.
+// The placeholder has an empty literal and is positioned at the start of the unexpected token
+// or at the end of the last token if the input has ended.
+func (p *Parser) missingToken(key keyword.Keyword) token.Token {
+	unexpected := p.tokenizer.Peek()
+	if unexpected.Keyword == keyword.EOF && p.tokenizer.currentToken >= 0 {
+		last := p.tokenizer.tokens[p.tokenizer.currentToken]
+		unexpected.Literal.Start = last.Literal.End
+		unexpected.TextPosition.LineStart = last.TextPosition.LineEnd
+		unexpected.TextPosition.CharStart = last.TextPosition.CharEnd
+	}
+	p.errUnexpectedToken(unexpected, key)
+
+	placeholder := token.Token{
+		Keyword: key,
+	}
+	placeholder.Literal.Start = unexpected.Literal.Start
+	placeholder.Literal.End = unexpected.Literal.Start
+	placeholder.TextPosition.LineStart = unexpected.TextPosition.LineStart
+	placeholder.TextPosition.LineEnd = unexpected.TextPosition.LineStart
+	placeholder.TextPosition.CharStart = unexpected.TextPosition.CharStart
+	placeholder.TextPosition.CharEnd = unexpected.TextPosition.CharStart
+	return placeholder
+}
@@ -14,6 +14,10 @@ type Tokenizer struct {
 	maxTokens    int
 	currentToken int
 	skipComments bool
+	// tokenLimit is the maximum number of tokens excluding comments, 0 means no limit
+	tokenLimit int
+	// tokenLimitExceeded is true if Tokenize stopped because the input has more tokens than tokenLimit
+	tokenLimitExceeded bool
 }
 
 // NewTokenizer returns a new tokenizer
@@ -28,7 +32,9 @@ func NewTokenizer() *Tokenizer {
 func (t *Tokenizer) Tokenize(input *ast.Input) {
 	t.lexer.SetInput(input)
 	t.tokens = t.tokens[:0]
+	t.tokenLimitExceeded = false
 
+	count := 0
 	for {
 		next := t.lexer.Read()
 		if next.Keyword == keyword.EOF {
@@ -36,6 +42,15 @@ func (t *Tokenizer) Tokenize(input *ast.Input) {
 			t.currentToken = -1
 			return
 		}
+		if next.Keyword != keyword.COMMENT {
+			count++
+			if t.tokenLimit > 0 && count > t.tokenLimit {
+				t.tokenLimitExceeded = true
+				t.maxTokens = len(t.tokens)
+				t.currentToken = -1
+				return
+			}
+		}
 		t.tokens = append(t.tokens, next)
 	}
 }
@@ -587,6 +587,20 @@ func (p *printVisitor) EnterInterfaceTypeDefinition(ref int) {
 	p.write(p.document.InterfaceTypeDefinitionNameBytes(ref))
 	p.write(literal.SPACE)
 
+	if len(p.document.InterfaceTypeDefinitions[ref].ImplementsInterfaces.Refs) != 0 {
+		p.write(literal.IMPLEMENTS)
+		p.write(literal.SPACE)
+		for i, j := range p.document.InterfaceTypeDefinitions[ref].ImplementsInterfaces.Refs {
+			if i != 0 {
+				p.write(literal.SPACE)
+				p.write(literal.AND)
+				p.write(literal.SPACE)
+			}
+			p.must(p.document.PrintType(j, p.out))
+		}
+		p.write(literal.SPACE)
+	}
+
 	p.inputValueDefinitionOpener = literal.LPAREN
 	p.inputValueDefinitionCloser = literal.RPAREN
 }
@@ -994,6 +1008,11 @@ func (p *printVisitor) LeaveDirectiveLocation(location ast.DirectiveLocation) {
 }
 
 func (p *printVisitor) EnterSchemaDefinition(ref int) {
+	if p.document.SchemaDefinitions[ref].Description.IsDefined {
+		p.must(p.document.PrintDescription(p.document.SchemaDefinitions[ref].Description, nil, 0, p.out))
+		p.write(literal.LINETERMINATOR)
+	}
+
 	p.write(literal.SCHEMA)
 	p.write(literal.SPACE)
 }
@@ -201,6 +201,13 @@ vary: [String]! = []) on QUERY directive @include(if: Boolean!) repeatable on FI
 					subscription: Subscription
 				}`, `schema {query: Query mutation: Mutation subscription: Subscription}`)
 	})
+	t.Run("schema definition with description", func(t *testing.T) {
+		run(t, `
+				"The schema."
+				schema {
+					query: Query
+				}`, "\"The schema.\"\nschema {query: Query}")
+	})
 	t.Run("schema extension", func(t *testing.T) {
 		run(t, `
 				extend schema @foo {
@@ -246,6 +253,12 @@ vary: [String]! = []) on QUERY directive @include(if: Boolean!) repeatable on FI
 					field2: Boolean
 				}`, `interface Foo {field: String field2: Boolean}`)
 	})
+	t.Run("interface type definition implementing interfaces", func(t *testing.T) {
+		run(t, `
+				interface Foo implements Bar & Baz {
+					field: String
+				}`, `interface Foo implements Bar & Baz {field: String}`)
+	})
 	t.Run("interface type extension", func(t *testing.T) {
 		run(t, `
 				extend interface Foo @foo {
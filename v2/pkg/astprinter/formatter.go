@@ -0,0 +1,670 @@
+package astprinter
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
+)
+
+const (
+	DefaultFormatIndent       = "  "
+	DefaultFormatMaxLineWidth = 80
+)
+
+// FormatOptions configures the formatter
+type FormatOptions struct {
+	// Indent is used once per nesting level, defaults to two spaces
+	Indent string
+	// MaxLineWidth is the width up to which arguments are printed on a single line, defaults to 80
+	MaxLineWidth int
+}
+
+// Format prints a document in a canonical layout including its comments.
+// Unlike Print, it doesn't need a walker and keeps the comments attached by astparser.Parser.ParseWithComments.
+// Arguments, arguments definitions and variable definitions are printed on a single line
+// if they fit into FormatOptions.MaxLineWidth, otherwise each of them is printed on its own line.
+// Formatting a formatted document again results in the same output.
+func Format(document *ast.Document, options FormatOptions, out io.Writer) error {
+	if options.Indent == "" {
+		options.Indent = DefaultFormatIndent
+	}
+	if options.MaxLineWidth <= 0 {
+		options.MaxLineWidth = DefaultFormatMaxLineWidth
+	}
+	f := formatter{
+		document: document,
+		indent:   []byte(options.Indent),
+		maxWidth: options.MaxLineWidth,
+	}
+	f.printDocument()
+	_, err := out.Write(f.buf.Bytes())
+	return err
+}
+
+// FormatString is the same as Format but returns a string instead of writing to an io.Writer
+func FormatString(document *ast.Document, options FormatOptions) (string, error) {
+	buff := &bytes.Buffer{}
+	err := Format(document, options, buff)
+	return buff.String(), err
+}
+
+type formatter struct {
+	document  *ast.Document
+	buf       bytes.Buffer
+	indent    []byte
+	maxWidth  int
+	depth     int
+	lineStart int
+}
+
+func (f *formatter) write(data []byte) {
+	f.buf.Write(data)
+}
+
+func (f *formatter) writeString(data string) {
+	f.buf.WriteString(data)
+}
+
+func (f *formatter) newline() {
+	f.buf.Write(literal.LINETERMINATOR)
+	f.lineStart = f.buf.Len()
+}
+
+func (f *formatter) writeIndent() {
+	for i := 0; i < f.depth; i++ {
+		f.buf.Write(f.indent)
+	}
+}
+
+func (f *formatter) column() int {
+	return f.buf.Len() - f.lineStart
+}
+
+// wrap prints a node on a single line if possible, reserve is the width of the content which follows on the same line
+func (f *formatter) wrap(canInline bool, reserve int, print func(multiline bool)) {
+	if canInline {
+		start, lineStart := f.buf.Len(), f.lineStart
+		print(false)
+		if f.lineStart == lineStart && f.column()+reserve <= f.maxWidth {
+			return
+		}
+		f.buf.Truncate(start)
+		f.lineStart = lineStart
+	}
+	print(true)
+}
+
+func (f *formatter) printDocument() {
+	if len(f.document.RootNodes) == 0 {
+		for i := range f.document.Comments {
+			f.write(f.document.Input.ByteSlice(f.document.Comments[i].Content))
+			f.newline()
+		}
+		return
+	}
+
+	for i, node := range f.document.RootNodes {
+		if i != 0 {
+			f.newline()
+		}
+		f.printRootNode(node)
+	}
+}
+
+func (f *formatter) printRootNode(node ast.Node) {
+	d := f.document
+	switch node.Kind {
+	case ast.NodeKindSchemaDefinition:
+		f.printItem(node, d.SchemaDefinitions[node.Ref].Description, func() {
+			f.printSchema("schema", d.SchemaDefinitions[node.Ref])
+		})
+	case ast.NodeKindSchemaExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printSchema("extend schema", d.SchemaExtensions[node.Ref].SchemaDefinition)
+		})
+	case ast.NodeKindObjectTypeDefinition:
+		f.printItem(node, d.ObjectTypeDefinitions[node.Ref].Description, func() {
+			f.printObjectType("type", d.ObjectTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindObjectTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printObjectType("extend type", d.ObjectTypeExtensions[node.Ref].ObjectTypeDefinition)
+		})
+	case ast.NodeKindInterfaceTypeDefinition:
+		f.printItem(node, d.InterfaceTypeDefinitions[node.Ref].Description, func() {
+			f.printInterfaceType("interface", d.InterfaceTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindInterfaceTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printInterfaceType("extend interface", d.InterfaceTypeExtensions[node.Ref].InterfaceTypeDefinition)
+		})
+	case ast.NodeKindScalarTypeDefinition:
+		f.printItem(node, d.ScalarTypeDefinitions[node.Ref].Description, func() {
+			f.printScalarType("scalar", d.ScalarTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindScalarTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printScalarType("extend scalar", d.ScalarTypeExtensions[node.Ref].ScalarTypeDefinition)
+		})
+	case ast.NodeKindUnionTypeDefinition:
+		f.printItem(node, d.UnionTypeDefinitions[node.Ref].Description, func() {
+			f.printUnionType("union", d.UnionTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindUnionTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printUnionType("extend union", d.UnionTypeExtensions[node.Ref].UnionTypeDefinition)
+		})
+	case ast.NodeKindEnumTypeDefinition:
+		f.printItem(node, d.EnumTypeDefinitions[node.Ref].Description, func() {
+			f.printEnumType("enum", d.EnumTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindEnumTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printEnumType("extend enum", d.EnumTypeExtensions[node.Ref].EnumTypeDefinition)
+		})
+	case ast.NodeKindInputObjectTypeDefinition:
+		f.printItem(node, d.InputObjectTypeDefinitions[node.Ref].Description, func() {
+			f.printInputObjectType("input", d.InputObjectTypeDefinitions[node.Ref])
+		})
+	case ast.NodeKindInputObjectTypeExtension:
+		f.printItem(node, ast.Description{}, func() {
+			f.printInputObjectType("extend input", d.InputObjectTypeExtensions[node.Ref].InputObjectTypeDefinition)
+		})
+	case ast.NodeKindDirectiveDefinition:
+		f.printItem(node, d.DirectiveDefinitions[node.Ref].Description, func() {
+			f.printDirectiveDefinition(node.Ref)
+		})
+	case ast.NodeKindOperationDefinition:
+		f.printItem(node, ast.Description{}, func() {
+			f.printOperationDefinition(node.Ref)
+		})
+	case ast.NodeKindFragmentDefinition:
+		f.printItem(node, ast.Description{}, func() {
+			f.printFragmentDefinition(node.Ref)
+		})
+	}
+}
+
+// printItem prints a node on its own lines including its description and comments
+func (f *formatter) printItem(node ast.Node, description ast.Description, print func()) {
+	comments := f.document.NodeComments[node]
+	f.printComments(comments.Leading)
+	if description.IsDefined {
+		f.writeIndent()
+		f.printDescription(description)
+		f.newline()
+	}
+	f.writeIndent()
+	print()
+	for _, ref := range comments.Trailing {
+		f.write(literal.SPACE)
+		f.write(f.document.Input.ByteSlice(f.document.Comments[ref].Content))
+	}
+	f.newline()
+	f.printComments(comments.After)
+}
+
+func (f *formatter) printComments(refs []int) {
+	for _, ref := range refs {
+		f.writeIndent()
+		f.write(f.document.Input.ByteSlice(f.document.Comments[ref].Content))
+		f.newline()
+	}
+}
+
+// printDescription prints block strings with the common indentation of the original lines removed
+func (f *formatter) printDescription(description ast.Description) {
+	content := f.document.Input.ByteSliceString(description.Content)
+	if !description.IsBlockString {
+		f.writeString(`"`)
+		f.writeString(content)
+		f.writeString(`"`)
+		return
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if indent := len(line) - len(trimmed); commonIndent == -1 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+
+	f.writeString(`"""`)
+	f.newline()
+	for i, line := range lines {
+		if i != 0 && commonIndent > 0 && len(line) >= commonIndent {
+			line = line[commonIndent:]
+		}
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			f.writeIndent()
+			f.writeString(line)
+		}
+		f.newline()
+	}
+	f.writeIndent()
+	f.writeString(`"""`)
+}
+
+func (f *formatter) printName(name ast.ByteSliceReference) {
+	f.write(f.document.Input.ByteSlice(name))
+}
+
+func (f *formatter) printType(ref int) {
+	_ = f.document.PrintType(ref, &f.buf)
+}
+
+func (f *formatter) printValue(value ast.Value) {
+	_ = f.document.PrintValue(value, &f.buf)
+}
+
+func (f *formatter) printDirectives(hasDirectives bool, directives ast.DirectiveList) {
+	if !hasDirectives {
+		return
+	}
+	for _, ref := range directives.Refs {
+		f.write(literal.SPACE)
+		_ = f.document.PrintDirective(ref, &f.buf)
+	}
+}
+
+func (f *formatter) printImplementsInterfaces(interfaces ast.TypeList) {
+	if len(interfaces.Refs) == 0 {
+		return
+	}
+	f.writeString(" implements ")
+	for i, ref := range interfaces.Refs {
+		if i != 0 {
+			f.writeString(" & ")
+		}
+		f.printType(ref)
+	}
+}
+
+func (f *formatter) printSchema(prefix string, schema ast.SchemaDefinition) {
+	f.writeString(prefix)
+	f.printDirectives(schema.HasDirectives, schema.Directives)
+	if len(schema.RootOperationTypeDefinitions.Refs) == 0 {
+		return
+	}
+	f.writeString(" {")
+	f.newline()
+	f.depth++
+	for _, ref := range schema.RootOperationTypeDefinitions.Refs {
+		rootOperationType := f.document.RootOperationTypeDefinitions[ref]
+		f.writeIndent()
+		f.writeString(rootOperationType.OperationType.Name())
+		f.writeString(": ")
+		f.printName(rootOperationType.NamedType.Name)
+		f.newline()
+	}
+	f.depth--
+	f.writeIndent()
+	f.writeString("}")
+}
+
+func (f *formatter) printObjectType(prefix string, objectType ast.ObjectTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(objectType.Name)
+	f.printImplementsInterfaces(objectType.ImplementsInterfaces)
+	f.printDirectives(objectType.HasDirectives, objectType.Directives)
+	if objectType.HasFieldDefinitions {
+		f.printFieldDefinitions(objectType.FieldsDefinition.Refs)
+	}
+}
+
+func (f *formatter) printInterfaceType(prefix string, interfaceType ast.InterfaceTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(interfaceType.Name)
+	f.printImplementsInterfaces(interfaceType.ImplementsInterfaces)
+	f.printDirectives(interfaceType.HasDirectives, interfaceType.Directives)
+	if interfaceType.HasFieldDefinitions {
+		f.printFieldDefinitions(interfaceType.FieldsDefinition.Refs)
+	}
+}
+
+func (f *formatter) printScalarType(prefix string, scalarType ast.ScalarTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(scalarType.Name)
+	f.printDirectives(scalarType.HasDirectives, scalarType.Directives)
+}
+
+func (f *formatter) printUnionType(prefix string, unionType ast.UnionTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(unionType.Name)
+	f.printDirectives(unionType.HasDirectives, unionType.Directives)
+	if !unionType.HasUnionMemberTypes {
+		return
+	}
+	f.writeString(" = ")
+	for i, ref := range unionType.UnionMemberTypes.Refs {
+		if i != 0 {
+			f.writeString(" | ")
+		}
+		f.printType(ref)
+	}
+}
+
+func (f *formatter) printEnumType(prefix string, enumType ast.EnumTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(enumType.Name)
+	f.printDirectives(enumType.HasDirectives, enumType.Directives)
+	if !enumType.HasEnumValuesDefinition || len(enumType.EnumValuesDefinition.Refs) == 0 {
+		return
+	}
+	f.writeString(" {")
+	f.newline()
+	f.depth++
+	for _, ref := range enumType.EnumValuesDefinition.Refs {
+		enumValue := f.document.EnumValueDefinitions[ref]
+		f.printItem(ast.Node{Kind: ast.NodeKindEnumValueDefinition, Ref: ref}, enumValue.Description, func() {
+			f.printName(enumValue.EnumValue)
+			f.printDirectives(enumValue.HasDirectives, enumValue.Directives)
+		})
+	}
+	f.depth--
+	f.writeIndent()
+	f.writeString("}")
+}
+
+func (f *formatter) printInputObjectType(prefix string, inputObjectType ast.InputObjectTypeDefinition) {
+	f.writeString(prefix)
+	f.write(literal.SPACE)
+	f.printName(inputObjectType.Name)
+	f.printDirectives(inputObjectType.HasDirectives, inputObjectType.Directives)
+	if !inputObjectType.HasInputFieldsDefinition || len(inputObjectType.InputFieldsDefinition.Refs) == 0 {
+		return
+	}
+	f.writeString(" {")
+	f.newline()
+	f.depth++
+	for _, ref := range inputObjectType.InputFieldsDefinition.Refs {
+		f.printItem(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}, f.document.InputValueDefinitions[ref].Description, func() {
+			f.printInputValueDefinition(ref)
+		})
+	}
+	f.depth--
+	f.writeIndent()
+	f.writeString("}")
+}
+
+func (f *formatter) printDirectiveDefinition(ref int) {
+	directiveDefinition := f.document.DirectiveDefinitions[ref]
+	f.writeString("directive @")
+	f.printName(directiveDefinition.Name)
+	if directiveDefinition.HasArgumentsDefinitions {
+		f.printArgumentsDefinition(directiveDefinition.ArgumentsDefinition.Refs, 0)
+	}
+	if directiveDefinition.Repeatable.IsRepeatable {
+		f.writeString(" repeatable")
+	}
+	f.writeString(" on ")
+	iter := directiveDefinition.DirectiveLocations.Iterable()
+	first := true
+	for iter.Next() {
+		if !first {
+			f.writeString(" | ")
+		}
+		first = false
+		f.write(iter.Value().LiteralBytes())
+	}
+}
+
+func (f *formatter) printFieldDefinitions(refs []int) {
+	if len(refs) == 0 {
+		return
+	}
+	f.writeString(" {")
+	f.newline()
+	f.depth++
+	for _, ref := range refs {
+		fieldDefinition := f.document.FieldDefinitions[ref]
+		f.printItem(ast.Node{Kind: ast.NodeKindFieldDefinition, Ref: ref}, fieldDefinition.Description, func() {
+			f.printName(fieldDefinition.Name)
+			if fieldDefinition.HasArgumentsDefinitions {
+				f.printArgumentsDefinition(fieldDefinition.ArgumentsDefinition.Refs, f.fieldDefinitionSuffixWidth(ref))
+			}
+			f.writeString(": ")
+			f.printType(fieldDefinition.Type)
+			f.printDirectives(fieldDefinition.HasDirectives, fieldDefinition.Directives)
+		})
+	}
+	f.depth--
+	f.writeIndent()
+	f.writeString("}")
+}
+
+// fieldDefinitionSuffixWidth returns the width of the type and directives of a field definition
+func (f *formatter) fieldDefinitionSuffixWidth(ref int) int {
+	suffix := formatter{document: f.document}
+	suffix.writeString(": ")
+	suffix.printType(f.document.FieldDefinitions[ref].Type)
+	suffix.printDirectives(f.document.FieldDefinitions[ref].HasDirectives, f.document.FieldDefinitions[ref].Directives)
+	return suffix.buf.Len()
+}
+
+// printArgumentsDefinition prints input value definitions on a single line
+// unless they are too long or have descriptions or comments
+func (f *formatter) printArgumentsDefinition(refs []int, reserve int) {
+	canInline := true
+	for _, ref := range refs {
+		if f.document.InputValueDefinitions[ref].Description.IsDefined ||
+			f.document.NodeHasComments(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}) {
+			canInline = false
+			break
+		}
+	}
+
+	f.wrap(canInline, reserve, func(multiline bool) {
+		f.writeString("(")
+		if !multiline {
+			for i, ref := range refs {
+				if i != 0 {
+					f.writeString(", ")
+				}
+				f.printInputValueDefinition(ref)
+			}
+			f.writeString(")")
+			return
+		}
+		f.newline()
+		f.depth++
+		for _, ref := range refs {
+			f.printItem(ast.Node{Kind: ast.NodeKindInputValueDefinition, Ref: ref}, f.document.InputValueDefinitions[ref].Description, func() {
+				f.printInputValueDefinition(ref)
+			})
+		}
+		f.depth--
+		f.writeIndent()
+		f.writeString(")")
+	})
+}
+
+func (f *formatter) printInputValueDefinition(ref int) {
+	inputValueDefinition := f.document.InputValueDefinitions[ref]
+	f.printName(inputValueDefinition.Name)
+	f.writeString(": ")
+	f.printType(inputValueDefinition.Type)
+	if inputValueDefinition.DefaultValue.IsDefined {
+		f.writeString(" = ")
+		f.printValue(inputValueDefinition.DefaultValue.Value)
+	}
+	f.printDirectives(inputValueDefinition.HasDirectives, inputValueDefinition.Directives)
+}
+
+func (f *formatter) printOperationDefinition(ref int) {
+	operation := f.document.OperationDefinitions[ref]
+	isShorthand := operation.OperationType == ast.OperationTypeQuery && operation.Name.Length() == 0 &&
+		!operation.HasVariableDefinitions && !operation.HasDirectives
+	if isShorthand {
+		f.printSelectionSet(operation.SelectionSet)
+		return
+	}
+
+	f.writeString(operation.OperationType.Name())
+	if operation.Name.Length() != 0 {
+		f.write(literal.SPACE)
+		f.printName(operation.Name)
+	}
+	if operation.HasVariableDefinitions && len(operation.VariableDefinitions.Refs) != 0 {
+		f.printVariableDefinitions(operation.VariableDefinitions.Refs)
+	}
+	f.printDirectives(operation.HasDirectives, operation.Directives)
+	f.write(literal.SPACE)
+	f.printSelectionSet(operation.SelectionSet)
+}
+
+func (f *formatter) printVariableDefinitions(refs []int) {
+	f.wrap(true, len(" {"), func(multiline bool) {
+		f.writeString("(")
+		if multiline {
+			f.newline()
+			f.depth++
+		}
+		for i, ref := range refs {
+			if multiline {
+				f.writeIndent()
+			} else if i != 0 {
+				f.writeString(", ")
+			}
+			variableDefinition := f.document.VariableDefinitions[ref]
+			f.writeString("$")
+			f.write(f.document.VariableDefinitionNameBytes(ref))
+			f.writeString(": ")
+			f.printType(variableDefinition.Type)
+			if variableDefinition.DefaultValue.IsDefined {
+				f.writeString(" = ")
+				f.printValue(variableDefinition.DefaultValue.Value)
+			}
+			f.printDirectives(variableDefinition.HasDirectives, variableDefinition.Directives)
+			if multiline {
+				f.newline()
+			}
+		}
+		if multiline {
+			f.depth--
+			f.writeIndent()
+		}
+		f.writeString(")")
+	})
+}
+
+func (f *formatter) printFragmentDefinition(ref int) {
+	fragment := f.document.FragmentDefinitions[ref]
+	f.writeString("fragment ")
+	f.printName(fragment.Name)
+	f.writeString(" on ")
+	f.printType(fragment.TypeCondition.Type)
+	f.printDirectives(fragment.HasDirectives, fragment.Directives)
+	f.write(literal.SPACE)
+	f.printSelectionSet(fragment.SelectionSet)
+}
+
+func (f *formatter) printSelectionSet(ref int) {
+	selections := f.document.SelectionSets[ref].SelectionRefs
+	if len(selections) == 0 {
+		f.writeString("{}")
+		return
+	}
+	f.writeString("{")
+	f.newline()
+	f.depth++
+	for _, selectionRef := range selections {
+		selection := f.document.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			f.printItem(ast.Node{Kind: ast.NodeKindField, Ref: selection.Ref}, ast.Description{}, func() {
+				f.printField(selection.Ref)
+			})
+		case ast.SelectionKindFragmentSpread:
+			f.printItem(ast.Node{Kind: ast.NodeKindFragmentSpread, Ref: selection.Ref}, ast.Description{}, func() {
+				fragmentSpread := f.document.FragmentSpreads[selection.Ref]
+				f.writeString("...")
+				f.printName(fragmentSpread.FragmentName)
+				f.printDirectives(fragmentSpread.HasDirectives, fragmentSpread.Directives)
+			})
+		case ast.SelectionKindInlineFragment:
+			f.printItem(ast.Node{Kind: ast.NodeKindInlineFragment, Ref: selection.Ref}, ast.Description{}, func() {
+				f.printInlineFragment(selection.Ref)
+			})
+		}
+	}
+	f.depth--
+	f.writeIndent()
+	f.writeString("}")
+}
+
+func (f *formatter) printField(ref int) {
+	field := f.document.Fields[ref]
+	if field.Alias.IsDefined {
+		f.printName(field.Alias.Name)
+		f.writeString(": ")
+	}
+	f.printName(field.Name)
+	if field.HasArguments && len(field.Arguments.Refs) != 0 {
+		reserve := 0
+		if field.HasSelections {
+			reserve = len(" {")
+		}
+		f.printArguments(field.Arguments.Refs, reserve)
+	}
+	f.printDirectives(field.HasDirectives, field.Directives)
+	if field.HasSelections {
+		f.write(literal.SPACE)
+		f.printSelectionSet(field.SelectionSet)
+	}
+}
+
+// printArguments prints the arguments of a field on a single line if they fit, otherwise one argument per line
+func (f *formatter) printArguments(refs []int, reserve int) {
+	f.wrap(true, reserve, func(multiline bool) {
+		f.writeString("(")
+		if multiline {
+			f.newline()
+			f.depth++
+		}
+		for i, ref := range refs {
+			if multiline {
+				f.writeIndent()
+			} else if i != 0 {
+				f.writeString(", ")
+			}
+			f.printName(f.document.Arguments[ref].Name)
+			f.writeString(": ")
+			f.printValue(f.document.Arguments[ref].Value)
+			if multiline {
+				f.newline()
+			}
+		}
+		if multiline {
+			f.depth--
+			f.writeIndent()
+		}
+		f.writeString(")")
+	})
+}
+
+func (f *formatter) printInlineFragment(ref int) {
+	inlineFragment := f.document.InlineFragments[ref]
+	f.writeString("...")
+	if f.document.InlineFragmentHasTypeCondition(ref) {
+		f.writeString(" on ")
+		f.printType(inlineFragment.TypeCondition.Type)
+	}
+	f.printDirectives(inlineFragment.HasDirectives, inlineFragment.Directives)
+	f.write(literal.SPACE)
+	f.printSelectionSet(inlineFragment.SelectionSet)
+}
@@ -0,0 +1,156 @@
+package astprinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+)
+
+func TestFormat(t *testing.T) {
+	run := func(t *testing.T, input, expectedOutput string, options FormatOptions) {
+		t.Helper()
+
+		doc, report := astparser.ParseGraphqlDocumentStringWithComments(input)
+		require.False(t, report.HasErrors(), report.Error())
+
+		out, err := FormatString(&doc, options)
+		require.NoError(t, err)
+		assert.Equal(t, expectedOutput, out)
+
+		formatted, report := astparser.ParseGraphqlDocumentStringWithComments(out)
+		require.False(t, report.HasErrors(), report.Error())
+		again, err := FormatString(&formatted, options)
+		require.NoError(t, err)
+		assert.Equal(t, out, again, "formatting must be stable")
+	}
+
+	t.Run("schema with comments", func(t *testing.T) {
+		run(t, `
+# header
+schema { query: Query }
+"""
+    The query type
+    with fields:
+      indented
+"""
+type Query implements Node @key(fields: "id") { # opening
+	# user by id
+	user("the id" id: ID! # id
+	): User # trailing
+	# last field
+}
+enum Role { ADMIN # admin
+USER }
+# before input
+input Filter { role: Role = ADMIN, tags: [String] }
+union Result = User | Admin
+scalar Date @specifiedBy(url: "https://example.com")
+directive @auth(role: Role) repeatable on OBJECT | FIELD_DEFINITION
+extend type Query { me: User }
+# end of file`, `# header
+schema {
+  query: Query
+}
+
+"""
+The query type
+with fields:
+  indented
+"""
+type Query implements Node @key(fields: "id") {
+  # opening
+  # user by id
+  user(
+    "the id"
+    id: ID! # id
+  ): User # trailing
+  # last field
+}
+
+enum Role {
+  ADMIN # admin
+  USER
+}
+
+# before input
+input Filter {
+  role: Role = ADMIN
+  tags: [String]
+}
+
+union Result = User | Admin
+
+scalar Date @specifiedBy(url: "https://example.com")
+
+directive @auth(role: Role) repeatable on OBJECT | FIELD_DEFINITION
+
+extend type Query {
+  me: User
+}
+# end of file
+`, FormatOptions{})
+	})
+
+	t.Run("operations with comments", func(t *testing.T) {
+		run(t, `
+query Q($id: ID!, $first: Int = 10) @cached {
+	# user
+	u: user(id: $id) { ...UserFields # spread
+		... on Admin @include(if: true) { role } }
+}
+fragment UserFields on User { id name }
+{ me { id } } # shorthand`, `query Q($id: ID!, $first: Int = 10) @cached {
+  # user
+  u: user(id: $id) {
+    ...UserFields # spread
+    ... on Admin @include(if: true) {
+      role
+    }
+  }
+}
+
+fragment UserFields on User {
+  id
+  name
+}
+
+{
+  me {
+    id
+  }
+} # shorthand
+`, FormatOptions{})
+	})
+
+	t.Run("wraps long arguments", func(t *testing.T) {
+		run(t, `
+type Query { users(first: Int, after: String, filter: Filter): [User!]! }
+query Q($first: Int, $after: String) { users(first: $first, after: $after) { id } }`, `type Query {
+	users(
+		first: Int
+		after: String
+		filter: Filter
+	): [User!]!
+}
+
+query Q(
+	$first: Int
+	$after: String
+) {
+	users(
+		first: $first
+		after: $after
+	) {
+		id
+	}
+}
+`, FormatOptions{Indent: "\t", MaxLineWidth: 30})
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		run(t, "  # a\n\n# b", "# a\n# b\n", FormatOptions{})
+	})
+}
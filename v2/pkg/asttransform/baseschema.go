@@ -164,7 +164,14 @@ directive @deprecated(
     [Markdown](https://daringfireball.net/projects/markdown/).
     """
     reason: String = "No longer supported"
-) on FIELD_DEFINITION | ENUM_VALUE
+) on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE
+"Indicates exactly one field must be supplied and this field must not be 'null'."
+directive @oneOf on INPUT_OBJECT
+"Exposes a URL that specifies the behavior of this scalar."
+directive @specifiedBy(
+    "The URL that specifies the behavior of this scalar."
+    url: String!
+) on SCALAR
 
 """
 A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.
@@ -177,7 +184,7 @@ type __Directive {
     name: String!
     description: String
     locations: [__DirectiveLocation!]!
-    args: [__InputValue!]!
+    args(includeDeprecated: Boolean = false): [__InputValue!]!
     isRepeatable: Boolean!
 }
 
@@ -242,7 +249,7 @@ a name, potentially a list of arguments, and a return type.
 type __Field {
     name: String!
     description: String
-    args: [__InputValue!]!
+    args(includeDeprecated: Boolean = false): [__InputValue!]!
     type: __Type!
     isDeprecated: Boolean!
     deprecationReason: String
@@ -258,6 +265,8 @@ type __InputValue {
     type: __Type!
     "A GraphQL-formatted string representing the default value for this input value."
     defaultValue: String
+    isDeprecated: Boolean!
+    deprecationReason: String
 }
 
 """
@@ -266,6 +275,7 @@ available types and directives on the server, as well as the entry points for
 query, mutation, and subscription operations.
 """
 type __Schema {
+    description: String
     "A list of all types supported by this server."
     types: [__Type!]!
     "The type that query operations will be rooted at."
@@ -292,12 +302,14 @@ type __Type {
     kind: __TypeKind!
     name: String
     description: String
+    specifiedByURL: String
     fields(includeDeprecated: Boolean = false): [__Field!]
     interfaces: [__Type!]
     possibleTypes: [__Type!]
     enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
-    inputFields: [__InputValue!]
+    inputFields(includeDeprecated: Boolean = false): [__InputValue!]
     ofType: __Type
+    isOneOf: Boolean
 }
 
 "An enum describing what kind of type a given '__Type' is."
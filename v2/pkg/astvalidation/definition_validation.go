@@ -18,7 +18,14 @@ func DefaultDefinitionValidator() *DefinitionValidator {
 		RequireDefinedTypesForExtensions(),
 		ImplementTransitiveInterfaces(),
 		ImplementingTypesAreSupersets(),
+		ImplementingFieldsHaveCompatibleArguments(),
 		DirectivesAreUniquePerLocation(),
+		OneOfInputObjects(),
+		InputAndOutputTypes(),
+		NoCircularNonNullInputObjects(),
+		ValidDirectiveDefinitions(),
+		RootOperationTypesAreObjectTypes(),
+		ReservedNames(),
 	)
 }
 
@@ -0,0 +1,232 @@
+package astvalidation
+
+import (
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const defaultDeprecationReason = "No longer supported"
+
+// DeprecationKind is the kind of schema element which is deprecated
+type DeprecationKind string
+
+const (
+	DeprecationKindField      DeprecationKind = "field"
+	DeprecationKindArgument   DeprecationKind = "argument"
+	DeprecationKindEnumValue  DeprecationKind = "enum value"
+	DeprecationKindInputField DeprecationKind = "input field"
+)
+
+// Deprecation is the use of a deprecated schema element in an operation
+type Deprecation struct {
+	Kind DeprecationKind
+	// Coordinate is the schema coordinate of the element, e.g. User.name, Query.user(id:), Role.ADMIN or UserInput.name
+	Coordinate string
+	Reason     string
+	// Position is the position of the element in the operation,
+	// for values of variables it's the position of the variable definition
+	Position position.Position
+}
+
+// ExternalError returns the deprecation as error, it's reported like this by the strict mode of DeprecatedUsage
+func (d Deprecation) ExternalError() operationreport.ExternalError {
+	return operationreport.ErrDeprecatedUsage(string(d.Kind), d.Coordinate, d.Reason, d.Position)
+}
+
+// DeprecatedUsage reports the use of deprecated fields, arguments, enum values and input fields,
+// including enum values and input fields in the values of variables.
+// Every use is passed to the function of WithDeprecationReport, with WithStrictDeprecation it's an error.
+func DeprecatedUsage(options ...Option) Rule {
+	opts := applyOptions(options)
+	return func(walker *astvisitor.Walker) {
+		visitor := deprecatedUsageVisitor{
+			Walker: walker,
+			report: opts.deprecationReport,
+			strict: opts.strictDeprecation,
+		}
+		walker.RegisterEnterDocumentVisitor(&visitor)
+		walker.RegisterEnterFieldVisitor(&visitor)
+		walker.RegisterEnterArgumentVisitor(&visitor)
+		walker.RegisterEnterVariableDefinitionVisitor(&visitor)
+	}
+}
+
+type deprecatedUsageVisitor struct {
+	*astvisitor.Walker
+	operation, definition *ast.Document
+	report                func(deprecation Deprecation)
+	strict                bool
+	fieldCoordinate       string
+}
+
+func (v *deprecatedUsageVisitor) EnterDocument(operation, definition *ast.Document) {
+	v.operation = operation
+	v.definition = definition
+}
+
+func (v *deprecatedUsageVisitor) EnterField(ref int) {
+	definition, exists := v.FieldDefinition(ref)
+	if !exists {
+		v.SkipNode()
+		return
+	}
+
+	v.fieldCoordinate = v.definition.NodeNameString(v.EnclosingTypeDefinition) + "." + v.operation.FieldNameString(ref)
+	if directiveRef, deprecated := v.definition.FieldDefinitionDirectiveByName(definition, literal.DEPRECATED); deprecated {
+		v.addDeprecation(DeprecationKindField, v.fieldCoordinate, directiveRef, v.operation.Fields[ref].Position)
+	}
+}
+
+func (v *deprecatedUsageVisitor) EnterArgument(ref int) {
+	definition, exists := v.ArgumentInputValueDefinition(ref)
+	if !exists {
+		return
+	}
+
+	var parentCoordinate string
+	switch v.Ancestor().Kind {
+	case ast.NodeKindField:
+		parentCoordinate = v.fieldCoordinate
+	case ast.NodeKindDirective:
+		parentCoordinate = "@" + v.operation.DirectiveNameString(v.Ancestor().Ref)
+	default:
+		return
+	}
+
+	if directiveRef, deprecated := v.definition.InputValueDefinitionDirectiveByName(definition, literal.DEPRECATED); deprecated {
+		coordinate := parentCoordinate + "(" + v.operation.ArgumentNameString(ref) + ":)"
+		v.addDeprecation(DeprecationKindArgument, coordinate, directiveRef, v.operation.Arguments[ref].Position)
+	}
+
+	v.valueDeprecations(v.operation.ArgumentValue(ref), v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(definition)))
+}
+
+func (v *deprecatedUsageVisitor) EnterVariableDefinition(ref int) {
+	variableDefinition := v.operation.VariableDefinitions[ref]
+	typeName := v.operation.ResolveTypeNameString(variableDefinition.Type)
+
+	if variableDefinition.DefaultValue.IsDefined {
+		v.valueDeprecations(variableDefinition.DefaultValue.Value, typeName)
+	}
+
+	if len(v.operation.Input.Variables) == 0 {
+		return
+	}
+	value, valueType, _, err := jsonparser.Get(v.operation.Input.Variables, v.operation.VariableDefinitionNameString(ref))
+	if err != nil {
+		return
+	}
+	v.jsonValueDeprecations(value, valueType, typeName, variableDefinition.VariableValue.Position)
+}
+
+// valueDeprecations reports deprecated enum values and input fields of a literal value of the named type
+func (v *deprecatedUsageVisitor) valueDeprecations(value ast.Value, typeName string) {
+	node, exists := v.definition.Index.FirstNodeByNameStr(typeName)
+	if !exists {
+		return
+	}
+
+	switch value.Kind {
+	case ast.ValueKindList:
+		for _, ref := range v.operation.ListValues[value.Ref].Refs {
+			v.valueDeprecations(v.operation.Value(ref), typeName)
+		}
+	case ast.ValueKindEnum:
+		if node.Kind == ast.NodeKindEnumTypeDefinition {
+			v.enumValueDeprecation(node.Ref, typeName, v.operation.EnumValueNameBytes(value.Ref), value.Position)
+		}
+	case ast.ValueKindObject:
+		if node.Kind != ast.NodeKindInputObjectTypeDefinition {
+			return
+		}
+		for _, ref := range v.operation.ObjectValues[value.Ref].Refs {
+			inputFieldTypeName, ok := v.inputFieldDeprecation(node.Ref, typeName, v.operation.ObjectFieldNameBytes(ref), v.operation.ObjectFields[ref].Position)
+			if ok {
+				v.valueDeprecations(v.operation.ObjectFieldValue(ref), inputFieldTypeName)
+			}
+		}
+	}
+}
+
+// jsonValueDeprecations reports deprecated enum values and input fields of a variable value of the named type
+func (v *deprecatedUsageVisitor) jsonValueDeprecations(value []byte, valueType jsonparser.ValueType, typeName string, position position.Position) {
+	node, exists := v.definition.Index.FirstNodeByNameStr(typeName)
+	if !exists {
+		return
+	}
+
+	switch valueType {
+	case jsonparser.Array:
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, _ int, _ error) {
+			v.jsonValueDeprecations(item, itemType, typeName, position)
+		})
+	case jsonparser.String:
+		if node.Kind == ast.NodeKindEnumTypeDefinition {
+			v.enumValueDeprecation(node.Ref, typeName, value, position)
+		}
+	case jsonparser.Object:
+		if node.Kind != ast.NodeKindInputObjectTypeDefinition {
+			return
+		}
+		_ = jsonparser.ObjectEach(value, func(key []byte, fieldValue []byte, fieldValueType jsonparser.ValueType, _ int) error {
+			inputFieldTypeName, ok := v.inputFieldDeprecation(node.Ref, typeName, key, position)
+			if ok {
+				v.jsonValueDeprecations(fieldValue, fieldValueType, inputFieldTypeName, position)
+			}
+			return nil
+		})
+	}
+}
+
+func (v *deprecatedUsageVisitor) enumValueDeprecation(enumTypeDefinition int, typeName string, valueName ast.ByteSlice, position position.Position) {
+	enumValueDefinition := v.definition.EnumTypeDefinitionEnumValueDefinitionByName(enumTypeDefinition, valueName)
+	if enumValueDefinition == ast.InvalidRef {
+		return
+	}
+	if directiveRef, deprecated := v.definition.EnumValueDefinitionDirectiveByName(enumValueDefinition, literal.DEPRECATED); deprecated {
+		v.addDeprecation(DeprecationKindEnumValue, typeName+"."+string(valueName), directiveRef, position)
+	}
+}
+
+// inputFieldDeprecation reports the input field if it's deprecated and returns the name of its type
+func (v *deprecatedUsageVisitor) inputFieldDeprecation(inputObjectTypeDefinition int, typeName string, fieldName ast.ByteSlice, position position.Position) (string, bool) {
+	inputValueDefinition := v.definition.InputObjectTypeDefinitionInputValueDefinitionByName(inputObjectTypeDefinition, fieldName)
+	if inputValueDefinition == ast.InvalidRef {
+		return "", false
+	}
+	if directiveRef, deprecated := v.definition.InputValueDefinitionDirectiveByName(inputValueDefinition, literal.DEPRECATED); deprecated {
+		v.addDeprecation(DeprecationKindInputField, typeName+"."+string(fieldName), directiveRef, position)
+	}
+	return v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(inputValueDefinition)), true
+}
+
+func (v *deprecatedUsageVisitor) addDeprecation(kind DeprecationKind, coordinate string, directiveRef int, position position.Position) {
+	deprecation := Deprecation{
+		Kind:       kind,
+		Coordinate: coordinate,
+		Reason:     v.deprecationReason(directiveRef),
+		Position:   position,
+	}
+	if v.report != nil {
+		v.report(deprecation)
+	}
+	if v.strict {
+		v.Report.AddExternalError(deprecation.ExternalError())
+	}
+}
+
+func (v *deprecatedUsageVisitor) deprecationReason(directiveRef int) string {
+	value, exists := v.definition.DirectiveArgumentValueByName(directiveRef, literal.REASON)
+	if exists && value.Kind == ast.ValueKindString {
+		return v.definition.StringValueContentString(value.Ref)
+	}
+	if reason := v.definition.DirectiveDefinitionArgumentDefaultValueString(string(literal.DEPRECATED), string(literal.REASON)); reason != "" {
+		return reason
+	}
+	return defaultDeprecationReason
+}
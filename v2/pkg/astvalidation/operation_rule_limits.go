@@ -0,0 +1,253 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// OperationLimits are the maximums enforced by the limit rules, a limit of 0 is disabled
+type OperationLimits struct {
+	// MaxDepth is the maximum nesting of fields, root fields have a depth of 1, see MaxDepth
+	MaxDepth int
+	// MaxAliases is the maximum number of aliased fields of an operation, see MaxAliases
+	MaxAliases int
+	// MaxRootFields is the maximum number of root fields of an operation, see MaxRootFields
+	MaxRootFields int
+	// MaxDirectivesPerField is the maximum number of directives on a single field, see MaxDirectivesPerField
+	MaxDirectivesPerField int
+}
+
+// rules returns the rules of all enabled limits
+func (l OperationLimits) rules() []Rule {
+	var rules []Rule
+	if l.MaxDepth > 0 {
+		rules = append(rules, MaxDepth(l.MaxDepth))
+	}
+	if l.MaxAliases > 0 {
+		rules = append(rules, MaxAliases(l.MaxAliases))
+	}
+	if l.MaxRootFields > 0 {
+		rules = append(rules, MaxRootFields(l.MaxRootFields))
+	}
+	if l.MaxDirectivesPerField > 0 {
+		rules = append(rules, MaxDirectivesPerField(l.MaxDirectivesPerField))
+	}
+	return rules
+}
+
+// NewOperationLimitsValidator returns an OperationValidator with the rules of the enabled limits only.
+// It's meant to validate operations before normalization, which inlines fragments and multiplies their fields.
+func NewOperationLimitsValidator(limits OperationLimits) *OperationValidator {
+	return NewOperationValidator(limits.rules())
+}
+
+// MaxDepth validates that the fields of an operation are not nested deeper than maxDepth.
+// Root fields have a depth of 1, fragments count with the depth of the fields they contain.
+func MaxDepth(maxDepth int) Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := operationLimitsVisitor{
+			Walker: walker,
+			limit:  maxDepth,
+		}
+		visitor.check = func(ref int) {
+			if depth := visitor.selectionSetDepth(visitor.operation.OperationDefinitions[ref].SelectionSet); depth > maxDepth {
+				visitor.Report.AddExternalError(operationreport.ErrMaxDepthExceeded(visitor.operation.OperationDefinitionNameString(ref), depth, maxDepth, visitor.operationPosition(ref)))
+			}
+		}
+		walker.RegisterEnterDocumentVisitor(&visitor)
+		walker.RegisterEnterOperationVisitor(&visitor)
+	}
+}
+
+// MaxAliases validates that an operation contains at most maxAliases aliased fields.
+// Aliases in fragments are counted for every spread of the fragment.
+func MaxAliases(maxAliases int) Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := operationLimitsVisitor{
+			Walker: walker,
+			limit:  maxAliases,
+		}
+		visitor.check = func(ref int) {
+			if aliases := visitor.selectionSetAliases(visitor.operation.OperationDefinitions[ref].SelectionSet); aliases > maxAliases {
+				visitor.Report.AddExternalError(operationreport.ErrMaxAliasesExceeded(visitor.operation.OperationDefinitionNameString(ref), aliases, maxAliases, visitor.operationPosition(ref)))
+			}
+		}
+		walker.RegisterEnterDocumentVisitor(&visitor)
+		walker.RegisterEnterOperationVisitor(&visitor)
+	}
+}
+
+// MaxRootFields validates that an operation selects at most maxRootFields root fields,
+// including the root fields selected by fragments.
+func MaxRootFields(maxRootFields int) Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := operationLimitsVisitor{
+			Walker: walker,
+			limit:  maxRootFields,
+		}
+		visitor.check = func(ref int) {
+			if rootFields := visitor.selectionSetFields(visitor.operation.OperationDefinitions[ref].SelectionSet); rootFields > maxRootFields {
+				visitor.Report.AddExternalError(operationreport.ErrMaxRootFieldsExceeded(visitor.operation.OperationDefinitionNameString(ref), rootFields, maxRootFields, visitor.operationPosition(ref)))
+			}
+		}
+		walker.RegisterEnterDocumentVisitor(&visitor)
+		walker.RegisterEnterOperationVisitor(&visitor)
+	}
+}
+
+// MaxDirectivesPerField validates that no field has more than maxDirectives directives
+func MaxDirectivesPerField(maxDirectives int) Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := maxDirectivesPerFieldVisitor{
+			Walker:        walker,
+			maxDirectives: maxDirectives,
+		}
+		walker.RegisterEnterDocumentVisitor(&visitor)
+		walker.RegisterEnterFieldVisitor(&visitor)
+	}
+}
+
+// operationLimitsVisitor computes a value of each operation, e.g. its depth, and checks it against the limit.
+// The values of fragments are computed once per fragment, counting stops as soon as the limit is exceeded,
+// so the reported value of an invalid operation is a lower bound.
+type operationLimitsVisitor struct {
+	*astvisitor.Walker
+	operation *ast.Document
+	limit     int
+	check     func(ref int)
+	// fragments are the names of the fragments which are currently visited, to stop at cyclic fragment spreads
+	fragments map[string]struct{}
+	// fragmentValues are the computed values of fragments by name
+	fragmentValues map[string]int
+}
+
+func (v *operationLimitsVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.operation = operation
+	v.fragments = map[string]struct{}{}
+	v.fragmentValues = map[string]int{}
+}
+
+func (v *operationLimitsVisitor) EnterOperationDefinition(ref int) {
+	if v.operation.OperationDefinitions[ref].HasSelections {
+		v.check(ref)
+	}
+	v.SkipNode()
+}
+
+func (v *operationLimitsVisitor) operationPosition(ref int) position.Position {
+	operationDefinition := v.operation.OperationDefinitions[ref]
+	if operationDefinition.OperationTypeLiteral.LineStart != 0 {
+		return operationDefinition.OperationTypeLiteral
+	}
+	return v.operation.SelectionSets[operationDefinition.SelectionSet].LBrace
+}
+
+// eachSelection calls visitField for every field and visitFragment for every fragment spread of the selection set,
+// including the selections of inline fragments. It stops once a visit func returns false.
+func (v *operationLimitsVisitor) eachSelection(selectionSet int, visitField func(field int) bool, visitFragment func(fragment int) bool) bool {
+	for _, selectionRef := range v.operation.SelectionSets[selectionSet].SelectionRefs {
+		selection := v.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if !visitField(selection.Ref) {
+				return false
+			}
+		case ast.SelectionKindInlineFragment:
+			if inlineFragmentSelectionSet, ok := v.operation.InlineFragmentSelectionSet(selection.Ref); ok {
+				if !v.eachSelection(inlineFragmentSelectionSet, visitField, visitFragment) {
+					return false
+				}
+			}
+		case ast.SelectionKindFragmentSpread:
+			fragment, exists := v.operation.FragmentDefinitionRef(v.operation.FragmentSpreadNameBytes(selection.Ref))
+			if !exists {
+				continue
+			}
+			if !visitFragment(fragment) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fragmentValue returns the value of a fragment computed by compute from its selection set,
+// the value of a cyclic fragment spread is 0
+func (v *operationLimitsVisitor) fragmentValue(fragment int, compute func(selectionSet int) int) int {
+	name := v.operation.FragmentDefinitionNameString(fragment)
+	if value, ok := v.fragmentValues[name]; ok {
+		return value
+	}
+	if _, visiting := v.fragments[name]; visiting {
+		return 0
+	}
+	v.fragments[name] = struct{}{}
+	value := compute(v.operation.FragmentDefinitions[fragment].SelectionSet)
+	delete(v.fragments, name)
+	v.fragmentValues[name] = value
+	return value
+}
+
+func (v *operationLimitsVisitor) selectionSetDepth(selectionSet int) (depth int) {
+	v.eachSelection(selectionSet, func(field int) bool {
+		fieldDepth := 1
+		if fieldSelectionSet, ok := v.operation.FieldSelectionSet(field); ok {
+			fieldDepth += v.selectionSetDepth(fieldSelectionSet)
+		}
+		if fieldDepth > depth {
+			depth = fieldDepth
+		}
+		return depth <= v.limit
+	}, func(fragment int) bool {
+		if fragmentDepth := v.fragmentValue(fragment, v.selectionSetDepth); fragmentDepth > depth {
+			depth = fragmentDepth
+		}
+		return depth <= v.limit
+	})
+	return depth
+}
+
+func (v *operationLimitsVisitor) selectionSetAliases(selectionSet int) (aliases int) {
+	v.eachSelection(selectionSet, func(field int) bool {
+		if v.operation.FieldAliasIsDefined(field) {
+			aliases++
+		}
+		if fieldSelectionSet, ok := v.operation.FieldSelectionSet(field); ok && aliases <= v.limit {
+			aliases += v.selectionSetAliases(fieldSelectionSet)
+		}
+		return aliases <= v.limit
+	}, func(fragment int) bool {
+		aliases += v.fragmentValue(fragment, v.selectionSetAliases)
+		return aliases <= v.limit
+	})
+	return aliases
+}
+
+func (v *operationLimitsVisitor) selectionSetFields(selectionSet int) (fields int) {
+	v.eachSelection(selectionSet, func(_ int) bool {
+		fields++
+		return fields <= v.limit
+	}, func(fragment int) bool {
+		fields += v.fragmentValue(fragment, v.selectionSetFields)
+		return fields <= v.limit
+	})
+	return fields
+}
+
+type maxDirectivesPerFieldVisitor struct {
+	*astvisitor.Walker
+	operation     *ast.Document
+	maxDirectives int
+}
+
+func (v *maxDirectivesPerFieldVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.operation = operation
+}
+
+func (v *maxDirectivesPerFieldVisitor) EnterField(ref int) {
+	if directives := len(v.operation.FieldDirectives(ref)); directives > v.maxDirectives {
+		v.Report.AddExternalError(operationreport.ErrMaxDirectivesPerFieldExceeded(v.operation.FieldAliasOrNameBytes(ref), directives, v.maxDirectives, v.operation.Fields[ref].Position))
+	}
+}
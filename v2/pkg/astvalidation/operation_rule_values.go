@@ -2,19 +2,28 @@ package astvalidation
 
 import (
 	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/buger/jsonparser"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astimport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
-// Values validates if values are used properly
-func Values() Rule {
+// Values validates if values are used properly.
+// Values of scalars registered with WithScalarRegistry are validated with their ParseValue func,
+// including the values of variables of the operation.
+func Values(options ...Option) Rule {
+	opts := applyOptions(options)
 	return func(walker *astvisitor.Walker) {
 		visitor := valuesVisitor{
-			Walker: walker,
+			Walker:  walker,
+			scalars: opts.scalars,
 		}
 		walker.RegisterEnterDocumentVisitor(&visitor)
 		walker.RegisterEnterArgumentVisitor(&visitor)
@@ -26,6 +35,7 @@ type valuesVisitor struct {
 	*astvisitor.Walker
 	operation, definition *ast.Document
 	importer              astimport.Importer
+	scalars               *scalar.ScalarRegistry
 }
 
 func (v *valuesVisitor) EnterDocument(operation, definition *ast.Document) {
@@ -34,6 +44,8 @@ func (v *valuesVisitor) EnterDocument(operation, definition *ast.Document) {
 }
 
 func (v *valuesVisitor) EnterVariableDefinition(ref int) {
+	v.variableValueSatisfiesScalars(ref)
+
 	if !v.operation.VariableDefinitionHasDefaultValue(ref) {
 		return // variable has no default value, deep type check not required
 	}
@@ -313,10 +325,122 @@ func (v *valuesVisitor) valueSatisfiesScalar(value ast.Value, definitionTypeRef
 	case bytes.Equal(scalarName, literal.STRING):
 		return v.valueSatisfiesScalarString(value, definitionTypeRef, true)
 	default:
+		if registered, valid := v.valueSatisfiesRegisteredScalar(value, scalarName); registered {
+			return valid
+		}
 		return v.valueSatisfiesScalarString(value, definitionTypeRef, false)
 	}
 }
 
+func (v *valuesVisitor) valueSatisfiesRegisteredScalar(value ast.Value, scalarName ast.ByteSlice) (registered, valid bool) {
+	if v.scalars == nil {
+		return false, false
+	}
+	if _, registered = v.scalars.Scalar(string(scalarName)); !registered {
+		return false, false
+	}
+
+	jsonValue, err := v.operation.ValueToJSON(value)
+	if err != nil {
+		// the value contains variables, their values are validated separately
+		return true, true
+	}
+
+	_, err = v.scalars.ParseValue(string(scalarName), jsonValue)
+	if err == nil {
+		return true, true
+	}
+
+	printedValue, ok := v.printOperationValue(value)
+	if !ok {
+		return true, false
+	}
+
+	v.Report.AddExternalError(operationreport.ErrInvalidScalarValue(printedValue, scalarName, scalarErrorReason(err), value.Position))
+	return true, false
+}
+
+// variableValueSatisfiesScalars validates the values of registered scalars in the value of a variable
+func (v *valuesVisitor) variableValueSatisfiesScalars(variableDefinitionRef int) {
+	if v.scalars == nil || len(v.operation.Input.Variables) == 0 {
+		return
+	}
+
+	variableName := v.operation.VariableDefinitionNameString(variableDefinitionRef)
+	value, valueType, _, err := jsonparser.Get(v.operation.Input.Variables, variableName)
+	if err != nil {
+		return
+	}
+
+	v.jsonValueSatisfiesScalars(v.operation, v.operation.VariableDefinitions[variableDefinitionRef].Type, value, valueType, variableDefinitionRef, variableName)
+}
+
+func (v *valuesVisitor) jsonValueSatisfiesScalars(document *ast.Document, typeRef int, value []byte, valueType jsonparser.ValueType, variableDefinitionRef int, valuePath string) {
+	if valueType == jsonparser.Null {
+		return
+	}
+
+	switch document.Types[typeRef].TypeKind {
+	case ast.TypeKindNonNull:
+		v.jsonValueSatisfiesScalars(document, document.Types[typeRef].OfType, value, valueType, variableDefinitionRef, valuePath)
+	case ast.TypeKindList:
+		if valueType != jsonparser.Array {
+			// a single value is coerced into a list
+			v.jsonValueSatisfiesScalars(document, document.Types[typeRef].OfType, value, valueType, variableDefinitionRef, valuePath)
+			return
+		}
+		index := 0
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemType jsonparser.ValueType, _ int, _ error) {
+			itemPath := valuePath + "[" + strconv.Itoa(index) + "]"
+			v.jsonValueSatisfiesScalars(document, document.Types[typeRef].OfType, item, itemType, variableDefinitionRef, itemPath)
+			index++
+		})
+	case ast.TypeKindNamed:
+		typeName := document.TypeNameBytes(typeRef)
+		node, exists := v.definition.Index.FirstNodeByNameBytes(typeName)
+		if !exists {
+			return
+		}
+		switch node.Kind {
+		case ast.NodeKindScalarTypeDefinition:
+			if valueType == jsonparser.String {
+				value = []byte(`"` + string(value) + `"`)
+			}
+			registered, err := v.scalars.ParseValue(string(typeName), value)
+			if !registered || err == nil {
+				return
+			}
+			variableName := v.operation.VariableDefinitionNameBytes(variableDefinitionRef)
+			var fieldPath string
+			if len(valuePath) > len(variableName) {
+				fieldPath = valuePath
+			}
+			v.Report.AddExternalError(operationreport.ErrInvalidScalarVariableValue(variableName, value, fieldPath, string(typeName), scalarErrorReason(err),
+				v.operation.VariableDefinitions[variableDefinitionRef].VariableValue.Position))
+		case ast.NodeKindInputObjectTypeDefinition:
+			if valueType != jsonparser.Object {
+				return
+			}
+			for _, inputValueDefinition := range v.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
+				fieldName := v.definition.InputValueDefinitionNameString(inputValueDefinition)
+				fieldValue, fieldValueType, _, err := jsonparser.Get(value, fieldName)
+				if err != nil {
+					continue
+				}
+				v.jsonValueSatisfiesScalars(v.definition, v.definition.InputValueDefinitions[inputValueDefinition].Type, fieldValue, fieldValueType, variableDefinitionRef, valuePath+"."+fieldName)
+			}
+		}
+	}
+}
+
+// scalarErrorReason returns the message of a ParseValue error, scalar.ErrInvalidValue has no reason
+func scalarErrorReason(err error) string {
+	if errors.Is(err, scalar.ErrInvalidValue) {
+		return ""
+	}
+	return err.Error()
+}
+
 func (v *valuesVisitor) valueSatisfiesScalarID(value ast.Value, definitionTypeRef int) bool {
 	if value.Kind == ast.ValueKindString || value.Kind == ast.ValueKindInteger {
 		return true
@@ -448,6 +572,37 @@ func (v *valuesVisitor) valueSatisfiesInputObjectTypeDefinition(value ast.Value,
 		return false
 	}
 
+	if v.definition.InputObjectTypeDefinitionIsOneOf(inputObjectTypeDefinition) {
+		return v.objectValueSatisfiesOneOf(value, inputObjectTypeDefinition)
+	}
+
+	return true
+}
+
+// objectValueSatisfiesOneOf checks that exactly one field of a @oneOf input object is set to a non-null value.
+// Variables used as field value must be of a non-null type, so that the field can't become null at execution.
+func (v *valuesVisitor) objectValueSatisfiesOneOf(objectValue ast.Value, inputObjectTypeDefinition int) bool {
+	objectName := v.definition.InputObjectTypeDefinitionNameBytes(inputObjectTypeDefinition)
+	fieldRefs := v.operation.ObjectValues[objectValue.Ref].Refs
+
+	if len(fieldRefs) != 1 {
+		v.Report.AddExternalError(operationreport.ErrOneOfInputObjectFieldCount(objectName, v.operation.ObjectValues[objectValue.Ref].LBRACE))
+		return false
+	}
+
+	field := v.operation.ObjectField(fieldRefs[0])
+	switch field.Value.Kind {
+	case ast.ValueKindNull:
+		v.Report.AddExternalError(operationreport.ErrOneOfInputObjectNullField(objectName, v.operation.ObjectFieldNameBytes(fieldRefs[0]), field.Position))
+		return false
+	case ast.ValueKindVariable:
+		_, variableTypeRef, _, ok := v.operationVariableType(field.Value.Ref)
+		if ok && !v.operation.TypeIsNonNull(variableTypeRef) {
+			v.Report.AddExternalError(operationreport.ErrOneOfInputObjectNullableVariable(v.operation.VariableValueNameBytes(field.Value.Ref), objectName, field.Position))
+			return false
+		}
+	}
+
 	return true
 }
 
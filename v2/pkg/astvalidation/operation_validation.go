@@ -5,10 +5,57 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
+type operationValidatorOptions struct {
+	scalars           *scalar.ScalarRegistry
+	deprecationReport func(deprecation Deprecation)
+	strictDeprecation bool
+	limits            OperationLimits
+}
+
+// Option configures the rules of the DefaultOperationValidator
+type Option func(options *operationValidatorOptions)
+
+// WithScalarRegistry validates values of custom scalars with the scalars of the registry
+func WithScalarRegistry(registry *scalar.ScalarRegistry) Option {
+	return func(options *operationValidatorOptions) {
+		options.scalars = registry
+	}
+}
+
+// WithDeprecationReport registers the DeprecatedUsage rule, report is called for every use of a deprecated element
+func WithDeprecationReport(report func(deprecation Deprecation)) Option {
+	return func(options *operationValidatorOptions) {
+		options.deprecationReport = report
+	}
+}
+
+// WithStrictDeprecation registers the DeprecatedUsage rule, operations using deprecated elements are invalid
+func WithStrictDeprecation() Option {
+	return func(options *operationValidatorOptions) {
+		options.strictDeprecation = true
+	}
+}
+
+// WithOperationLimits registers the rules of the enabled limits, see OperationLimits
+func WithOperationLimits(limits OperationLimits) Option {
+	return func(options *operationValidatorOptions) {
+		options.limits = limits
+	}
+}
+
+func applyOptions(opts []Option) operationValidatorOptions {
+	var applied operationValidatorOptions
+	for _, opt := range opts {
+		opt(&applied)
+	}
+	return applied
+}
+
 // DefaultOperationValidator returns a fully initialized OperationValidator with all default rules registered
-func DefaultOperationValidator() *OperationValidator {
+func DefaultOperationValidator(options ...Option) *OperationValidator {
 
 	validator := OperationValidator{
 		walker: astvisitor.NewWalker(48),
@@ -22,7 +69,7 @@ func DefaultOperationValidator() *OperationValidator {
 	validator.RegisterRule(FieldSelectionMerging())
 	validator.RegisterRule(KnownArguments())
 	validator.RegisterRule(ValidArguments())
-	validator.RegisterRule(Values())
+	validator.RegisterRule(Values(options...))
 	validator.RegisterRule(ArgumentUniqueness())
 	validator.RegisterRule(RequiredArguments())
 	validator.RegisterRule(Fragments())
@@ -34,6 +81,14 @@ func DefaultOperationValidator() *OperationValidator {
 	validator.RegisterRule(AllVariableUsesDefined())
 	validator.RegisterRule(AllVariablesUsed())
 
+	opts := applyOptions(options)
+	if opts.deprecationReport != nil || opts.strictDeprecation {
+		validator.RegisterRule(DeprecatedUsage(options...))
+	}
+	for _, rule := range opts.limits.rules() {
+		validator.RegisterRule(rule)
+	}
+
 	return &validator
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 type options struct {
@@ -20,6 +21,7 @@ type options struct {
 	expectNormalizationError    bool
 	expectValidationErrors      bool
 	expectedValidationErrorMsgs []string
+	variables                   string
 }
 
 type option func(options *options)
@@ -44,6 +46,12 @@ func withValidationErrors(errMsgs ...string) option {
 	}
 }
 
+func withVariables(variables string) option {
+	return func(options *options) {
+		options.variables = variables
+	}
+}
+
 func TestExecutionValidation(t *testing.T) {
 	must := func(err error) {
 		if report, ok := err.(operationreport.Report); ok {
@@ -79,6 +87,7 @@ func TestExecutionValidation(t *testing.T) {
 
 		definition := mustDocument(astparser.ParseGraphqlDocumentString(definitionInput))
 		operation := mustDocument(astparser.ParseGraphqlDocumentString(operationInput))
+		operation.Input.Variables = []byte(options.variables)
 		report := operationreport.Report{}
 
 		if !options.disableNormalization {
@@ -3181,6 +3190,93 @@ func TestExecutionValidation(t *testing.T) {
 						`, Values(), Invalid, withValidationErrors(`String cannot represent a non string value: 123`))
 			})
 		})
+		t.Run("5.6.5 OneOf Input Objects", func(t *testing.T) {
+			oneOfDefinition := `
+				schema { query: Query }
+				type Query { pet(by: PetBy!): String }
+				input PetBy @oneOf { id: ID name: String }
+				directive @oneOf on INPUT_OBJECT
+				scalar ID
+				scalar String`
+
+			t.Run("exactly one non-null field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ pet(by: { name: "Fido" }) }`, Values(), Valid)
+			})
+			t.Run("non-null variable as field value", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `query ($name: String!) { pet(by: { name: $name }) }`, Values(), Valid)
+			})
+			t.Run("variable as object value", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `query ($by: PetBy!) { pet(by: $by) }`, Values(), Valid)
+			})
+			t.Run("no field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ pet(by: {}) }`, Values(), Invalid,
+					withValidationErrors(`OneOf Input Object "PetBy" must specify exactly one key.`))
+			})
+			t.Run("more than one field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ pet(by: { id: "1", name: "Fido" }) }`, Values(), Invalid,
+					withValidationErrors(`OneOf Input Object "PetBy" must specify exactly one key.`))
+			})
+			t.Run("null field", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `{ pet(by: { name: null }) }`, Values(), Invalid,
+					withValidationErrors(`Field "PetBy.name" must be non-null.`))
+			})
+			t.Run("nullable variable as field value", func(t *testing.T) {
+				runWithDefinition(t, oneOfDefinition, `query ($name: String) { pet(by: { name: $name }) }`, Values(), Invalid,
+					withValidationErrors(`Variable "$name" must be non-nullable to be used for OneOf Input Object "PetBy".`))
+			})
+		})
+		t.Run("5.6.6 Custom Scalars", func(t *testing.T) {
+			scalarDefinition := `
+				schema { query: Query }
+				type Query { events(since: DateTime, filter: EventFilter, tags: [JSON]): String }
+				input EventFilter { until: DateTime! }
+				scalar DateTime
+				scalar JSON
+				scalar String`
+
+			scalars := scalar.NewDefaultScalarRegistry()
+
+			t.Run("valid literal", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(since: "2023-10-18T16:00:00Z") }`, Values(WithScalarRegistry(scalars)), Valid)
+			})
+			t.Run("complex literal of json scalar", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(tags: [123, "abc", {deep: [true]}]) }`, Values(WithScalarRegistry(scalars)), Valid)
+			})
+			t.Run("invalid literal", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(since: "yesterday") }`, Values(WithScalarRegistry(scalars)), Invalid,
+					withValidationErrors(`Expected value of type "DateTime", found "yesterday"; DateTime cannot represent an invalid date-time string: "yesterday"`))
+			})
+			t.Run("invalid literal in input object", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(filter: {until: 1}) }`, Values(WithScalarRegistry(scalars)), Invalid,
+					withValidationErrors(`Expected value of type "DateTime", found 1; DateTime cannot represent a non string value: 1`))
+			})
+			t.Run("invalid value without reason", func(t *testing.T) {
+				invalid := scalar.NewScalarRegistry()
+				invalid.Register("DateTime", scalar.Scalar{ParseValue: func(value []byte) error {
+					return scalar.ErrInvalidValue
+				}})
+				runWithDefinition(t, scalarDefinition, `{ events(since: "2023-10-18T16:00:00Z") }`, Values(WithScalarRegistry(invalid)), Invalid,
+					withValidationErrors(`Expected value of type "DateTime", found "2023-10-18T16:00:00Z".`))
+			})
+			t.Run("unregistered scalar requires string literal", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `{ events(since: 1) }`, Values(), Invalid,
+					withValidationErrors(`DateTime cannot represent value: 1`))
+			})
+			t.Run("valid variable value", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `query ($since: DateTime) { events(since: $since) }`, Values(WithScalarRegistry(scalars)), Valid,
+					withVariables(`{"since":"2023-10-18T16:00:00Z"}`))
+			})
+			t.Run("invalid variable value", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `query ($since: DateTime) { events(since: $since) }`, Values(WithScalarRegistry(scalars)), Invalid,
+					withVariables(`{"since":"yesterday"}`),
+					withValidationErrors(`Variable "$since" got invalid value "yesterday"; Expected type "DateTime". DateTime cannot represent an invalid date-time string: "yesterday"`))
+			})
+			t.Run("invalid nested variable value", func(t *testing.T) {
+				runWithDefinition(t, scalarDefinition, `query ($filter: EventFilter) { events(filter: $filter) }`, Values(WithScalarRegistry(scalars)), Invalid,
+					withVariables(`{"filter":{"until":"yesterday"}}`),
+					withValidationErrors(`Variable "$filter" got invalid value "yesterday" at "filter.until"; Expected type "DateTime".`))
+			})
+		})
 	})
 	t.Run("5.7 Directives", func(t *testing.T) {
 		t.Run("5.7.1 Directives Are Defined", func(t *testing.T) {
@@ -3876,6 +3972,156 @@ func TestExecutionValidation(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Deprecated Usage", func(t *testing.T) {
+		deprecationDefinition := `
+			schema { query: Query }
+			type Query {
+				user(id: ID, login: String @deprecated(reason: "Use id."), filter: UserFilter, role: Role): User
+				users(roles: [Role]): [User] @deprecated
+			}
+			type User {
+				name: String
+				fullName: String @deprecated(reason: "Use name.")
+			}
+			input UserFilter {
+				name: String
+				nickname: String @deprecated(reason: "Use name.")
+				role: Role
+			}
+			enum Role { ADMIN USER @deprecated(reason: "Use MEMBER.") MEMBER }
+			scalar ID
+			scalar String
+			directive @deprecated(reason: String = "No longer supported") on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE`
+
+		collect := func(deprecations *[]string) Option {
+			return WithDeprecationReport(func(deprecation Deprecation) {
+				*deprecations = append(*deprecations, fmt.Sprintf("%s %s: %s", deprecation.Kind, deprecation.Coordinate, deprecation.Reason))
+			})
+		}
+
+		t.Run("no deprecated elements", func(t *testing.T) {
+			var deprecations []string
+			runWithDefinition(t, deprecationDefinition, `{ user(id: "1", filter: {name: "a", role: ADMIN}) { name } }`, DeprecatedUsage(collect(&deprecations)), Valid)
+			assert.Nil(t, deprecations)
+		})
+		t.Run("deprecated fields are reported", func(t *testing.T) {
+			var deprecations []string
+			runWithDefinition(t, deprecationDefinition, `{ user(id: "1") { name fullName } users { name } }`, DeprecatedUsage(collect(&deprecations)), Valid)
+			assert.Equal(t, []string{
+				`field User.fullName: Use name.`,
+				`field Query.users: No longer supported`,
+			}, deprecations)
+		})
+		t.Run("deprecated arguments, input fields and enum values are reported", func(t *testing.T) {
+			var deprecations []string
+			runWithDefinition(t, deprecationDefinition, `{ user(login: "a", filter: {nickname: "b", role: USER}, role: USER) { name } users(roles: [ADMIN, USER]) { name } }`,
+				DeprecatedUsage(collect(&deprecations)), Valid)
+			assert.Equal(t, []string{
+				`argument Query.user(login:): Use id.`,
+				`input field UserFilter.nickname: Use name.`,
+				`enum value Role.USER: Use MEMBER.`,
+				`enum value Role.USER: Use MEMBER.`,
+				`field Query.users: No longer supported`,
+				`enum value Role.USER: Use MEMBER.`,
+			}, deprecations)
+		})
+		t.Run("deprecated values of variables are reported", func(t *testing.T) {
+			var deprecations []string
+			runWithDefinition(t, deprecationDefinition, `query ($filter: UserFilter, $role: Role = USER) { user(filter: $filter, role: $role) { name } }`,
+				DeprecatedUsage(collect(&deprecations)), Valid,
+				withVariables(`{"filter":{"name":"a","nickname":"b","role":"USER"}}`))
+			assert.Equal(t, []string{
+				`input field UserFilter.nickname: Use name.`,
+				`enum value Role.USER: Use MEMBER.`,
+				`enum value Role.USER: Use MEMBER.`,
+			}, deprecations)
+		})
+		t.Run("strict mode rejects deprecated elements", func(t *testing.T) {
+			runWithDefinition(t, deprecationDefinition, `{ user(login: "a") { fullName } }`, DeprecatedUsage(WithStrictDeprecation()), Invalid,
+				withValidationErrors(
+					`The argument "Query.user(login:)" is deprecated. Use id.`,
+					`The field "User.fullName" is deprecated. Use name.`,
+				))
+		})
+		t.Run("default validator", func(t *testing.T) {
+			definition := mustDocument(astparser.ParseGraphqlDocumentString(deprecationDefinition))
+			operation := mustDocument(astparser.ParseGraphqlDocumentString(`{ user(id: "1") { fullName } }`))
+
+			var deprecations []string
+			report := operationreport.Report{}
+			assert.Equal(t, Valid, DefaultOperationValidator(collect(&deprecations)).Validate(&operation, &definition, &report))
+			assert.Equal(t, []string{`field User.fullName: Use name.`}, deprecations)
+
+			report = operationreport.Report{}
+			assert.Equal(t, Invalid, DefaultOperationValidator(WithStrictDeprecation()).Validate(&operation, &definition, &report))
+			assert.Equal(t, Valid, DefaultOperationValidator().Validate(&operation, &definition, &operationreport.Report{}))
+		})
+	})
+	t.Run("Operation Limits", func(t *testing.T) {
+		t.Run("max depth", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `{ country(code: "DE") { name continent { name } } }`, MaxDepth(3), Valid)
+			runWithDefinition(t, countriesDefinition, `query Deep { country(code: "DE") { name continent { countries { name } } } }`, MaxDepth(3), Invalid,
+				withValidationErrors(`Operation "Deep" has a depth of 4 which exceeds the maximum depth of 3.`))
+		})
+		t.Run("max depth with fragments", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `
+				{ country(code: "DE") { ...ContinentFields } }
+				fragment ContinentFields on Country { continent { ... on Continent { countries { name } } } }`,
+				MaxDepth(3), Invalid, withDisableNormalization(),
+				withValidationErrors(`Anonymous operation has a depth of 4 which exceeds the maximum depth of 3.`))
+		})
+		t.Run("max aliases", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `{ de: country(code: "DE") { name } at: country(code: "AT") { name } }`, MaxAliases(2), Valid)
+			runWithDefinition(t, countriesDefinition, `
+				query Aliases { de: country(code: "DE") { ...Names } at: country(code: "AT") { ...Names } }
+				fragment Names on Country { countryName: name }`,
+				MaxAliases(3), Invalid, withDisableNormalization(),
+				withValidationErrors(`Operation "Aliases" has 4 aliases which exceeds the maximum of 3 aliases.`))
+		})
+		t.Run("max root fields", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `{ de: country(code: "DE") { name } at: country(code: "AT") { name } }`, MaxRootFields(2), Valid)
+			runWithDefinition(t, countriesDefinition, `
+				query Countries { de: country(code: "DE") { name } ... on Query { at: country(code: "AT") { name } } ...Swiss }
+				fragment Swiss on Query { ch: country(code: "CH") { name } }`,
+				MaxRootFields(2), Invalid, withDisableNormalization(),
+				withValidationErrors(`Operation "Countries" has 3 root fields which exceeds the maximum of 2 root fields.`))
+		})
+		t.Run("max directives per field", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `query ($a: Boolean!) { country(code: "DE") @include(if: $a) { name } }`, MaxDirectivesPerField(1), Valid, withVariables(`{"a":true}`))
+			runWithDefinition(t, countriesDefinition, `query ($a: Boolean!) { country(code: "DE") @include(if: $a) @skip(if: $a) { name } }`, MaxDirectivesPerField(1), Invalid,
+				withVariables(`{"a":true}`),
+				withValidationErrors(`Field "country" has 2 directives which exceeds the maximum of 1 directives per field.`))
+		})
+		t.Run("cyclic fragments", func(t *testing.T) {
+			runWithDefinition(t, countriesDefinition, `
+				{ country(code: "DE") { ...A } }
+				fragment A on Country { ...B }
+				fragment B on Country { ...A name }`,
+				MaxDepth(3), Valid, withDisableNormalization())
+		})
+		t.Run("nested fragment spreads are counted once per fragment", func(t *testing.T) {
+			// every fragment spreads the next one twice, walking all spreads would visit 2^26 fragments
+			operation := `{ country(code: "DE") { ...F0 } }`
+			for i := 0; i < 26; i++ {
+				operation += fmt.Sprintf("\nfragment F%d on Country { a%d: name ...F%d ...F%d }", i, i, i+1, i+1)
+			}
+			operation += "\nfragment F26 on Country { name }"
+
+			runWithDefinition(t, countriesDefinition, operation, MaxAliases(10), Invalid, withDisableNormalization(),
+				withValidationErrors(`Anonymous operation has 37 aliases which exceeds the maximum of 10 aliases.`))
+			runWithDefinition(t, countriesDefinition, operation, MaxAliases(1<<26-1), Valid, withDisableNormalization())
+			runWithDefinition(t, countriesDefinition, operation, MaxAliases(1<<26-2), Invalid, withDisableNormalization())
+			runWithDefinition(t, countriesDefinition, operation, MaxDepth(2), Valid, withDisableNormalization())
+		})
+		t.Run("default validator", func(t *testing.T) {
+			definition := mustDocument(astparser.ParseGraphqlDocumentString(countriesDefinition))
+			operation := mustDocument(astparser.ParseGraphqlDocumentString(`{ de: country(code: "DE") { name } at: country(code: "AT") { name } }`))
+
+			assert.Equal(t, Valid, DefaultOperationValidator(WithOperationLimits(OperationLimits{MaxDepth: 2})).Validate(&operation, &definition, &operationreport.Report{}))
+			assert.Equal(t, Invalid, DefaultOperationValidator(WithOperationLimits(OperationLimits{MaxRootFields: 1})).Validate(&operation, &definition, &operationreport.Report{}))
+			assert.Equal(t, Invalid, DefaultOperationValidator(WithOperationLimits(OperationLimits{MaxAliases: 1})).Validate(&operation, &definition, &operationreport.Report{}))
+		})
+	})
 }
 
 func TestValidationEdgeCases(t *testing.T) {
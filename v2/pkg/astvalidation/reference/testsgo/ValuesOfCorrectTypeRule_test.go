@@ -10,10 +10,18 @@ func TestValuesOfCorrectTypeRule(t *testing.T) {
 		return ExpectValidationErrors(t, ValuesOfCorrectTypeRule, queryStr)
 	}
 
+	ExpectErrorsWithSchema := func(t *testing.T, schema string, scalars Scalars, queryStr string) ResultCompare {
+		return ExpectValuesValidationErrorsWithScalars(t, schema, scalars, queryStr)
+	}
+
 	ExpectValid := func(t *testing.T, queryStr string) {
 		ExpectErrors(t, queryStr)([]Err{})
 	}
 
+	ExpectValidWithSchema := func(t *testing.T, schema string, scalars Scalars, queryStr string) {
+		ExpectErrorsWithSchema(t, schema, scalars, queryStr)([]Err{})
+	}
+
 	t.Run("Validate: Values of correct type", func(t *testing.T) {
 		t.Run("Valid values", func(t *testing.T) {
 			t.Run("Good int value", func(t *testing.T) {
@@ -967,6 +975,77 @@ func TestValuesOfCorrectTypeRule(t *testing.T) {
 				})
 			})
 
+			t.Run("reports original error for custom scalar which throws", func(t *testing.T) {
+				schema := BuildSchema(`
+        type Query {
+          invalidArg(arg: Invalid): String
+        }
+
+        scalar Invalid
+      `)
+
+				scalars := Scalars{
+					"Invalid": func(value string) error {
+						return ScalarError("Invalid scalar is always invalid: " + value)
+					},
+				}
+
+				ExpectErrorsWithSchema(t, schema, scalars, "{ invalidArg(arg: 123) }")([]Err{
+					{
+						message:   `Expected value of type "Invalid", found 123; Invalid scalar is always invalid: 123`,
+						locations: []Loc{{line: 1, column: 19}},
+					},
+				})
+			})
+
+			t.Run("reports error for custom scalar that returns undefined", func(t *testing.T) {
+				schema := BuildSchema(`
+        type Query {
+          invalidArg(arg: CustomScalar): String
+        }
+
+        scalar CustomScalar
+      `)
+
+				scalars := Scalars{
+					"CustomScalar": func(value string) error {
+						return ErrInvalidScalarValue
+					},
+				}
+
+				ExpectErrorsWithSchema(t, schema, scalars, "{ invalidArg(arg: 123) }")([]Err{
+					{
+						message:   `Expected value of type "CustomScalar", found 123.`,
+						locations: []Loc{{line: 1, column: 19}},
+					},
+				})
+			})
+
+			t.Run("allows custom scalar to accept complex literals", func(t *testing.T) {
+				schema := BuildSchema(`
+        type Query {
+          anyArg(arg: Any): String
+        }
+
+        scalar Any
+      `)
+
+				scalars := Scalars{
+					"Any": func(value string) error {
+						return nil
+					},
+				}
+
+				ExpectValidWithSchema(t, schema, scalars, `
+          {
+            test1: anyArg(arg: 123)
+            test2: anyArg(arg: "abc")
+            test3: anyArg(arg: [123, "abc"])
+            test4: anyArg(arg: {deep: [123, "abc"]})
+          }
+        `)
+			})
+
 		})
 
 		t.Run("Directive arguments", func(t *testing.T) {
@@ -1,6 +1,7 @@
 package testsgo
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 const (
@@ -167,6 +169,52 @@ func ExpectValidationErrorsWithSchema(t *testing.T, schema string, rule string,
 	return compareReportErrors(t, report)
 }
 
+// ScalarParseValue - validates the JSON value of a custom scalar
+type ScalarParseValue func(value string) error
+
+// Scalars - custom scalars by name
+type Scalars map[string]ScalarParseValue
+
+// ErrInvalidScalarValue - rejects a custom scalar value without a reason
+var ErrInvalidScalarValue = scalar.ErrInvalidValue
+
+// ScalarError - rejects a custom scalar value with a reason
+func ScalarError(reason string) error {
+	return errors.New(reason)
+}
+
+// ExpectValuesValidationErrorsWithScalars - is a helper to run values validation with registered custom scalars
+// returns ResultCompare function
+func ExpectValuesValidationErrorsWithScalars(t *testing.T, schema string, scalars Scalars, queryStr string) ResultCompare {
+	t.Helper()
+
+	op, opReport := astparser.ParseGraphqlDocumentString(queryStr)
+	def := prepareSchema(schema)
+
+	if opReport.HasErrors() {
+		t.Log("operation report has errors")
+		return compareReportErrors(t, opReport)
+	}
+
+	registry := scalar.NewScalarRegistry()
+	for name, parseValue := range scalars {
+		parseValue := parseValue
+		registry.Register(name, scalar.Scalar{
+			ParseValue: func(value []byte) error {
+				return parseValue(string(value))
+			},
+		})
+	}
+
+	report := operationreport.Report{}
+	validator := astvalidation.NewOperationValidator([]astvalidation.Rule{
+		astvalidation.Values(astvalidation.WithScalarRegistry(registry)),
+	})
+	validator.Validate(&op, &def, &report)
+
+	return compareReportErrors(t, report)
+}
+
 // ExpectValidationErrors - a wrapper for ExpectValidationErrorsWithSchema which uses default testSchema
 // returns ResultCompare function
 func ExpectValidationErrors(t *testing.T, rule string, queryStr string) ResultCompare {
@@ -0,0 +1,168 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// ImplementingFieldsHaveCompatibleArguments validates that a field which implements an interface field defines
+// all arguments of the interface field with the same type. Additional arguments must be optional.
+// Missing fields are reported by ImplementingTypesAreSupersets.
+func ImplementingFieldsHaveCompatibleArguments() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &implementingFieldsHaveCompatibleArgumentsVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterDocumentVisitor(visitor)
+		walker.RegisterEnterInterfaceTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInterfaceTypeExtensionVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterObjectTypeExtensionVisitor(visitor)
+	}
+}
+
+type implementingFieldsHaveCompatibleArgumentsVisitor struct {
+	*astvisitor.Walker
+	definition          *ast.Document
+	implementingTypes   []string
+	isImplementingTypes map[string]struct{}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.definition = operation
+	v.implementingTypes = v.implementingTypes[:0]
+	v.isImplementingTypes = make(map[string]struct{})
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) EnterInterfaceTypeDefinition(ref int) {
+	if len(v.definition.InterfaceTypeDefinitions[ref].ImplementsInterfaces.Refs) > 0 {
+		v.collectImplementingType(v.definition.InterfaceTypeDefinitionNameString(ref))
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) EnterInterfaceTypeExtension(ref int) {
+	if len(v.definition.InterfaceTypeExtensions[ref].ImplementsInterfaces.Refs) > 0 {
+		v.collectImplementingType(v.definition.InterfaceTypeExtensionNameString(ref))
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) EnterObjectTypeDefinition(ref int) {
+	if len(v.definition.ObjectTypeDefinitions[ref].ImplementsInterfaces.Refs) > 0 {
+		v.collectImplementingType(v.definition.ObjectTypeDefinitionNameString(ref))
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) EnterObjectTypeExtension(ref int) {
+	if len(v.definition.ObjectTypeExtensions[ref].ImplementsInterfaces.Refs) > 0 {
+		v.collectImplementingType(v.definition.ObjectTypeExtensionNameString(ref))
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) collectImplementingType(typeName string) {
+	if _, exists := v.isImplementingTypes[typeName]; exists {
+		return
+	}
+	v.isImplementingTypes[typeName] = struct{}{}
+	v.implementingTypes = append(v.implementingTypes, typeName)
+}
+
+// LeaveDocument compares the arguments of all fields of the collected types with the arguments of the
+// interface fields they implement. Definitions and extensions are merged by type name.
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) LeaveDocument(_, _ *ast.Document) {
+	for _, typeName := range v.implementingTypes {
+		fieldRefsByName := make(map[string]int)
+		for _, fieldRef := range v.fieldDefinitionRefs(typeName) {
+			fieldRefsByName[v.definition.FieldDefinitionNameString(fieldRef)] = fieldRef
+		}
+
+		for _, interfaceName := range v.implementedInterfaceNames(typeName) {
+			for _, interfaceFieldRef := range v.fieldDefinitionRefs(interfaceName) {
+				fieldName := v.definition.FieldDefinitionNameString(interfaceFieldRef)
+				fieldRef, exists := fieldRefsByName[fieldName]
+				if !exists {
+					continue
+				}
+				v.validateArguments(typeName, fieldName, fieldRef, interfaceName, interfaceFieldRef)
+			}
+		}
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) validateArguments(typeName, fieldName string, fieldRef int, interfaceName string, interfaceFieldRef int) {
+	argumentRefsByName := make(map[string]int)
+	for _, argumentRef := range v.definition.FieldDefinitionArgumentsDefinitions(fieldRef) {
+		argumentRefsByName[v.definition.InputValueDefinitionNameString(argumentRef)] = argumentRef
+	}
+
+	interfaceArgumentNames := make(map[string]struct{})
+	for _, interfaceArgumentRef := range v.definition.FieldDefinitionArgumentsDefinitions(interfaceFieldRef) {
+		argumentName := v.definition.InputValueDefinitionNameString(interfaceArgumentRef)
+		interfaceArgumentNames[argumentName] = struct{}{}
+
+		argumentRef, exists := argumentRefsByName[argumentName]
+		if !exists {
+			v.Report.AddExternalError(operationreport.ErrImplementingFieldDoesNotDefineArgument(typeName, fieldName, argumentName, interfaceName))
+			continue
+		}
+
+		argumentTypeRef := v.definition.InputValueDefinitionType(argumentRef)
+		interfaceArgumentTypeRef := v.definition.InputValueDefinitionType(interfaceArgumentRef)
+		if !v.definition.TypesAreEqualDeep(argumentTypeRef, interfaceArgumentTypeRef) {
+			argumentType, _ := v.definition.PrintTypeBytes(argumentTypeRef, nil)
+			interfaceArgumentType, _ := v.definition.PrintTypeBytes(interfaceArgumentTypeRef, nil)
+			v.Report.AddExternalError(operationreport.ErrImplementingFieldArgumentTypeMismatch(
+				typeName, fieldName, argumentName, string(argumentType), interfaceName, string(interfaceArgumentType),
+			))
+		}
+	}
+
+	for _, argumentRef := range v.definition.FieldDefinitionArgumentsDefinitions(fieldRef) {
+		argumentName := v.definition.InputValueDefinitionNameString(argumentRef)
+		if _, exists := interfaceArgumentNames[argumentName]; exists {
+			continue
+		}
+		if !v.definition.InputValueDefinitionArgumentIsOptional(argumentRef) {
+			v.Report.AddExternalError(operationreport.ErrImplementingFieldAdditionalArgumentMustBeOptional(typeName, fieldName, argumentName, interfaceName))
+		}
+	}
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) fieldDefinitionRefs(typeName string) (refs []int) {
+	nodes, _ := v.definition.Index.NodesByNameStr(typeName)
+	for _, node := range nodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition:
+			refs = append(refs, v.definition.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs...)
+		case ast.NodeKindObjectTypeExtension:
+			refs = append(refs, v.definition.ObjectTypeExtensions[node.Ref].FieldsDefinition.Refs...)
+		case ast.NodeKindInterfaceTypeDefinition:
+			refs = append(refs, v.definition.InterfaceTypeDefinitions[node.Ref].FieldsDefinition.Refs...)
+		case ast.NodeKindInterfaceTypeExtension:
+			refs = append(refs, v.definition.InterfaceTypeExtensions[node.Ref].FieldsDefinition.Refs...)
+		}
+	}
+	return refs
+}
+
+func (v *implementingFieldsHaveCompatibleArgumentsVisitor) implementedInterfaceNames(typeName string) (names []string) {
+	nodes, _ := v.definition.Index.NodesByNameStr(typeName)
+	for _, node := range nodes {
+		var interfaceRefs []int
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition:
+			interfaceRefs = v.definition.ObjectTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+		case ast.NodeKindObjectTypeExtension:
+			interfaceRefs = v.definition.ObjectTypeExtensions[node.Ref].ImplementsInterfaces.Refs
+		case ast.NodeKindInterfaceTypeDefinition:
+			interfaceRefs = v.definition.InterfaceTypeDefinitions[node.Ref].ImplementsInterfaces.Refs
+		case ast.NodeKindInterfaceTypeExtension:
+			interfaceRefs = v.definition.InterfaceTypeExtensions[node.Ref].ImplementsInterfaces.Refs
+		}
+		for _, interfaceRef := range interfaceRefs {
+			names = append(names, v.definition.TypeNameString(interfaceRef))
+		}
+	}
+	return names
+}
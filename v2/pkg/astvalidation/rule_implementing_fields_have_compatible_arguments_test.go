@@ -0,0 +1,86 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestImplementingFieldsHaveCompatibleArguments(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Equal and additional optional arguments are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						friends(first: Int!, after: String): [Node]
+					}
+
+					type User implements Node {
+						friends(first: Int!, after: String, filter: String, last: Int! = 10): [Node]
+					}
+
+					interface Named {
+						name(locale: String): String
+					}
+
+					extend type User implements Named {
+						name(locale: String): String
+					}
+				`, Valid, ImplementingFieldsHaveCompatibleArguments(),
+			)
+		})
+
+		t.Run("Missing argument is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						friends(first: Int): [Node]
+					}
+
+					type User implements Node {
+						friends: [Node]
+					}
+				`, Invalid, ImplementingFieldsHaveCompatibleArguments(),
+			)
+		})
+
+		t.Run("Argument of different type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						friends(first: Int): [Node]
+					}
+
+					type User implements Node {
+						friends(first: Int!): [Node]
+					}
+				`, Invalid, ImplementingFieldsHaveCompatibleArguments(),
+			)
+		})
+
+		t.Run("Additional required argument is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						friends: [Node]
+					}
+
+					interface Entity implements Node {
+						friends(first: Int!): [Node]
+					}
+				`, Invalid, ImplementingFieldsHaveCompatibleArguments(),
+			)
+		})
+
+		t.Run("Incompatible argument in type extension is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						friends(first: Int): [Node]
+					}
+
+					type User {
+						name: String
+					}
+
+					extend type User implements Node {
+						friends(first: String): [Node]
+					}
+				`, Invalid, ImplementingFieldsHaveCompatibleArguments(),
+			)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// InputAndOutputTypes validates that fields of objects and interfaces are of an output type and that
+// arguments and input object fields are of an input type. Undefined types are reported by KnownTypeNames.
+func InputAndOutputTypes() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &inputAndOutputTypesVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+		walker.RegisterEnterInputValueDefinitionVisitor(visitor)
+	}
+}
+
+type inputAndOutputTypesVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+}
+
+func (v *inputAndOutputTypesVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.definition = operation
+}
+
+func (v *inputAndOutputTypesVisitor) EnterFieldDefinition(ref int) {
+	typeName := v.definition.ResolveTypeNameString(v.definition.FieldDefinitionType(ref))
+	kind, exists := v.namedTypeKind(typeName)
+	if !exists {
+		return
+	}
+
+	switch kind {
+	case ast.NodeKindScalarTypeDefinition, ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition,
+		ast.NodeKindUnionTypeDefinition, ast.NodeKindEnumTypeDefinition:
+		return
+	}
+
+	v.Report.AddExternalError(operationreport.ErrFieldTypeMustBeOutputType(
+		v.ancestorName(len(v.Ancestors)-1),
+		v.definition.FieldDefinitionNameString(ref),
+		typeName,
+	))
+}
+
+func (v *inputAndOutputTypesVisitor) EnterInputValueDefinition(ref int) {
+	typeName := v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(ref))
+	kind, exists := v.namedTypeKind(typeName)
+	if !exists {
+		return
+	}
+
+	switch kind {
+	case ast.NodeKindScalarTypeDefinition, ast.NodeKindEnumTypeDefinition, ast.NodeKindInputObjectTypeDefinition:
+		return
+	}
+
+	v.Report.AddExternalError(operationreport.ErrInputValueTypeMustBeInputType(v.inputValueCoordinate(ref), typeName))
+}
+
+// namedTypeKind returns the kind of the definition of a named type. Extensions are treated like the
+// definition they extend, so that the kinds can be compared to definition kinds only.
+func (v *inputAndOutputTypesVisitor) namedTypeKind(typeName string) (kind ast.NodeKind, exists bool) {
+	node, exists := v.definition.Index.FirstNodeByNameStr(typeName)
+	if !exists {
+		return ast.NodeKindUnknown, false
+	}
+
+	switch node.Kind {
+	case ast.NodeKindScalarTypeExtension:
+		return ast.NodeKindScalarTypeDefinition, true
+	case ast.NodeKindObjectTypeExtension:
+		return ast.NodeKindObjectTypeDefinition, true
+	case ast.NodeKindInterfaceTypeExtension:
+		return ast.NodeKindInterfaceTypeDefinition, true
+	case ast.NodeKindUnionTypeExtension:
+		return ast.NodeKindUnionTypeDefinition, true
+	case ast.NodeKindEnumTypeExtension:
+		return ast.NodeKindEnumTypeDefinition, true
+	case ast.NodeKindInputObjectTypeExtension:
+		return ast.NodeKindInputObjectTypeDefinition, true
+	}
+
+	return node.Kind, true
+}
+
+// inputValueCoordinate returns the schema coordinate of an argument or input field, e.g. 'Query.user(id:)'.
+func (v *inputAndOutputTypesVisitor) inputValueCoordinate(ref int) string {
+	name := v.definition.InputValueDefinitionNameString(ref)
+	parent := v.Ancestors[len(v.Ancestors)-1]
+
+	switch parent.Kind {
+	case ast.NodeKindFieldDefinition:
+		return v.ancestorName(len(v.Ancestors)-2) + "." + v.definition.FieldDefinitionNameString(parent.Ref) + "(" + name + ":)"
+	case ast.NodeKindDirectiveDefinition:
+		return "@" + v.definition.DirectiveDefinitionNameString(parent.Ref) + "(" + name + ":)"
+	default:
+		return v.ancestorName(len(v.Ancestors)-1) + "." + name
+	}
+}
+
+func (v *inputAndOutputTypesVisitor) ancestorName(i int) string {
+	if i < 0 {
+		return ""
+	}
+
+	node := v.Ancestors[i]
+	switch node.Kind {
+	case ast.NodeKindInputObjectTypeExtension:
+		return v.definition.InputObjectTypeExtensionNameString(node.Ref)
+	default:
+		return v.definition.NodeNameString(node)
+	}
+}
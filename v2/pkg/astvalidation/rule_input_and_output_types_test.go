@@ -0,0 +1,102 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestInputAndOutputTypes(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Fields of output types and arguments of input types are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						user(filter: UserFilter, status: Status): User
+						search(text: String): [SearchResult!]!
+					}
+
+					union SearchResult = User
+
+					type User {
+						name: String
+						status: Status
+					}
+
+					input UserFilter {
+						status: Status
+						nested: [UserFilter!]
+					}
+
+					enum Status {
+						ACTIVE
+					}
+
+					directive @limit(filter: UserFilter) on FIELD_DEFINITION
+				`, Valid, InputAndOutputTypes(),
+			)
+		})
+
+		t.Run("Field of input object type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						filter: UserFilter
+					}
+
+					input UserFilter {
+						name: String
+					}
+				`, Invalid, InputAndOutputTypes(),
+			)
+		})
+
+		t.Run("Interface field of input object type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					interface Node {
+						filter: [UserFilter]
+					}
+
+					input UserFilter {
+						name: String
+					}
+				`, Invalid, InputAndOutputTypes(),
+			)
+		})
+
+		t.Run("Argument of object type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						user(user: User): User
+					}
+
+					type User {
+						name: String
+					}
+				`, Invalid, InputAndOutputTypes(),
+			)
+		})
+
+		t.Run("Input field of union type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input UserFilter {
+						result: SearchResult
+					}
+
+					union SearchResult = User
+
+					type User {
+						name: String
+					}
+				`, Invalid, InputAndOutputTypes(),
+			)
+		})
+
+		t.Run("Directive argument of interface type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @limit(node: Node) on FIELD_DEFINITION
+
+					interface Node {
+						id: ID
+					}
+				`, Invalid, InputAndOutputTypes(),
+			)
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package astvalidation
+
+import (
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// NoCircularNonNullInputObjects validates that input objects don't reference themselves through a series
+// of non-null fields, because such an input object could never be provided.
+func NoCircularNonNullInputObjects() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &noCircularNonNullInputObjectsVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+	}
+}
+
+type noCircularNonNullInputObjectsVisitor struct {
+	*astvisitor.Walker
+	definition               *ast.Document
+	visitedTypeNames         map[string]struct{}
+	fieldPath                []string
+	fieldPathIndexByTypeName map[string]int
+}
+
+func (v *noCircularNonNullInputObjectsVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.definition = operation
+	v.visitedTypeNames = make(map[string]struct{})
+	v.fieldPath = v.fieldPath[:0]
+	v.fieldPathIndexByTypeName = make(map[string]int)
+}
+
+func (v *noCircularNonNullInputObjectsVisitor) EnterInputObjectTypeDefinition(ref int) {
+	v.detectCycle(v.definition.InputObjectTypeDefinitionNameString(ref))
+}
+
+// detectCycle does a depth-first search along non-null input object fields. A type which is already part of
+// the current field path closes a cycle. Every type is only searched once, so that each cycle is reported once.
+func (v *noCircularNonNullInputObjectsVisitor) detectCycle(typeName string) {
+	if _, visited := v.visitedTypeNames[typeName]; visited {
+		return
+	}
+	v.visitedTypeNames[typeName] = struct{}{}
+	v.fieldPathIndexByTypeName[typeName] = len(v.fieldPath)
+
+	for _, fieldRef := range v.inputFieldRefs(typeName) {
+		fieldTypeRef := v.definition.InputValueDefinitionType(fieldRef)
+		if !v.definition.TypeIsNonNull(fieldTypeRef) {
+			continue
+		}
+		ofTypeRef := v.definition.Types[fieldTypeRef].OfType
+		if v.definition.Types[ofTypeRef].TypeKind != ast.TypeKindNamed {
+			continue
+		}
+		fieldTypeName := v.definition.TypeNameString(ofTypeRef)
+		if len(v.inputFieldRefs(fieldTypeName)) == 0 {
+			continue
+		}
+
+		v.fieldPath = append(v.fieldPath, v.definition.InputValueDefinitionNameString(fieldRef))
+		if cycleIndex, inPath := v.fieldPathIndexByTypeName[fieldTypeName]; inPath {
+			v.Report.AddExternalError(operationreport.ErrCircularNonNullInputObjectReference(
+				fieldTypeName,
+				strings.Join(v.fieldPath[cycleIndex:], "."),
+			))
+		} else {
+			v.detectCycle(fieldTypeName)
+		}
+		v.fieldPath = v.fieldPath[:len(v.fieldPath)-1]
+	}
+
+	delete(v.fieldPathIndexByTypeName, typeName)
+}
+
+// inputFieldRefs returns the fields of an input object including the fields of its extensions.
+func (v *noCircularNonNullInputObjectsVisitor) inputFieldRefs(typeName string) (refs []int) {
+	nodes, _ := v.definition.Index.NodesByNameStr(typeName)
+	for _, node := range nodes {
+		switch node.Kind {
+		case ast.NodeKindInputObjectTypeDefinition:
+			refs = append(refs, v.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs...)
+		case ast.NodeKindInputObjectTypeExtension:
+			refs = append(refs, v.definition.InputObjectTypeExtensions[node.Ref].InputFieldsDefinition.Refs...)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,64 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestNoCircularNonNullInputObjects(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Nullable and list references are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input Filter {
+						and: [Filter!]!
+						or: Filter
+						nested: NestedFilter!
+					}
+
+					input NestedFilter {
+						parent: Filter
+						name: String!
+					}
+				`, Valid, NoCircularNonNullInputObjects(),
+			)
+		})
+
+		t.Run("Non-null self reference is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input Filter {
+						not: Filter!
+					}
+				`, Invalid, NoCircularNonNullInputObjects(),
+			)
+		})
+
+		t.Run("Non-null reference through other input objects is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input A {
+						b: B!
+					}
+
+					input B {
+						c: C!
+					}
+
+					input C {
+						a: A!
+					}
+				`, Invalid, NoCircularNonNullInputObjects(),
+			)
+		})
+
+		t.Run("Non-null reference added by extension is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input A {
+						name: String
+					}
+
+					extend input A {
+						a: A!
+					}
+				`, Invalid, NoCircularNonNullInputObjects(),
+			)
+		})
+	})
+}
@@ -0,0 +1,64 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+type oneOfInputObjectsVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+}
+
+// OneOfInputObjects validates that all fields of input objects annotated with @oneOf are nullable
+// and have no default value.
+func OneOfInputObjects() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &oneOfInputObjectsVisitor{
+			Walker:     walker,
+			definition: nil,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeExtensionVisitor(visitor)
+	}
+}
+
+func (o *oneOfInputObjectsVisitor) EnterDocument(operation, _ *ast.Document) {
+	o.definition = operation
+}
+
+func (o *oneOfInputObjectsVisitor) EnterInputObjectTypeDefinition(ref int) {
+	if !o.definition.InputObjectTypeDefinitionIsOneOf(ref) {
+		return
+	}
+	o.validateInputFields(o.definition.InputObjectTypeDefinitionNameString(ref), o.definition.InputObjectTypeDefinitions[ref].InputFieldsDefinition.Refs)
+}
+
+func (o *oneOfInputObjectsVisitor) EnterInputObjectTypeExtension(ref int) {
+	extension := o.definition.InputObjectTypeExtensions[ref]
+	typeName := o.definition.InputObjectTypeExtensionNameString(ref)
+	isOneOf := extension.Directives.HasDirectiveByName(o.definition, ast.OneOfDirectiveName)
+	if !isOneOf {
+		node, exists := o.definition.Index.FirstNodeByNameStr(typeName)
+		isOneOf = exists && node.Kind == ast.NodeKindInputObjectTypeDefinition && o.definition.InputObjectTypeDefinitionIsOneOf(node.Ref)
+	}
+	if !isOneOf {
+		return
+	}
+	o.validateInputFields(typeName, extension.InputFieldsDefinition.Refs)
+}
+
+func (o *oneOfInputObjectsVisitor) validateInputFields(typeName string, inputValueDefinitionRefs []int) {
+	for _, ref := range inputValueDefinitionRefs {
+		fieldName := o.definition.InputValueDefinitionNameString(ref)
+		if o.definition.TypeIsNonNull(o.definition.InputValueDefinitionType(ref)) {
+			o.Report.AddExternalError(operationreport.ErrOneOfInputFieldMustBeNullable(typeName, fieldName))
+		}
+		if o.definition.InputValueDefinitionHasDefaultValue(ref) {
+			o.Report.AddExternalError(operationreport.ErrOneOfInputFieldMustNotHaveDefaultValue(typeName, fieldName))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestOneOfInputObjects(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("OneOf input object with nullable fields is valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input PetInput @oneOf {
+						cat: String
+						dog: String
+					}
+
+					extend input PetInput {
+						fish: String
+					}
+
+					input Filter {
+						name: String! = "bob"
+					}
+				`, Valid, OneOfInputObjects(),
+			)
+		})
+
+		t.Run("OneOf input object with non-null field is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input PetInput @oneOf {
+						cat: String!
+						dog: String
+					}
+				`, Invalid, OneOfInputObjects(),
+			)
+		})
+
+		t.Run("OneOf input object with default value is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input PetInput @oneOf {
+						cat: String = "garfield"
+						dog: String
+					}
+				`, Invalid, OneOfInputObjects(),
+			)
+		})
+
+		t.Run("Extension of oneOf input object with non-null field is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input PetInput @oneOf {
+						cat: String
+					}
+
+					extend input PetInput {
+						dog: String!
+					}
+				`, Invalid, OneOfInputObjects(),
+			)
+		})
+
+		t.Run("OneOf extension with non-null field is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input PetInput {
+						cat: String
+					}
+
+					extend input PetInput @oneOf {
+						dog: String!
+					}
+				`, Invalid, OneOfInputObjects(),
+			)
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package astvalidation
+
+import (
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// introspectionTypeNames and introspectionFieldNames are defined by the GraphQL introspection system
+// and are added to the schema by asttransform.MergeDefinitionWithBaseSchema.
+var (
+	introspectionTypeNames = map[string]struct{}{
+		"__Schema":            {},
+		"__Type":              {},
+		"__TypeKind":          {},
+		"__Field":             {},
+		"__InputValue":        {},
+		"__EnumValue":         {},
+		"__Directive":         {},
+		"__DirectiveLocation": {},
+	}
+	introspectionFieldNames = map[string]struct{}{
+		"__typename": {},
+		"__schema":   {},
+		"__type":     {},
+	}
+)
+
+// ReservedNames validates that types, fields, arguments, enum values and directives don't use names starting
+// with '__', as they are reserved for the introspection system.
+func ReservedNames() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &reservedNamesVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInterfaceTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterUnionTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterScalarTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterEnumTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterInputObjectTypeDefinitionVisitor(visitor)
+		walker.RegisterEnterDirectiveDefinitionVisitor(visitor)
+		walker.RegisterEnterFieldDefinitionVisitor(visitor)
+		walker.RegisterEnterInputValueDefinitionVisitor(visitor)
+		walker.RegisterEnterEnumValueDefinitionVisitor(visitor)
+	}
+}
+
+type reservedNamesVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+}
+
+func (r *reservedNamesVisitor) EnterDocument(operation, _ *ast.Document) {
+	r.definition = operation
+}
+
+func (r *reservedNamesVisitor) EnterObjectTypeDefinition(ref int) {
+	r.validateTypeName("type", r.definition.ObjectTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterInterfaceTypeDefinition(ref int) {
+	r.validateTypeName("interface", r.definition.InterfaceTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterUnionTypeDefinition(ref int) {
+	r.validateTypeName("union", r.definition.UnionTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterScalarTypeDefinition(ref int) {
+	r.validateTypeName("scalar", r.definition.ScalarTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterEnumTypeDefinition(ref int) {
+	r.validateTypeName("enum", r.definition.EnumTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterInputObjectTypeDefinition(ref int) {
+	r.validateTypeName("input", r.definition.InputObjectTypeDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterDirectiveDefinition(ref int) {
+	r.validateName("directive", r.definition.DirectiveDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) EnterFieldDefinition(ref int) {
+	fieldName := r.definition.FieldDefinitionNameString(ref)
+	if _, ok := introspectionFieldNames[fieldName]; ok {
+		return
+	}
+	r.validateName("field", fieldName)
+}
+
+func (r *reservedNamesVisitor) EnterInputValueDefinition(ref int) {
+	name := r.definition.InputValueDefinitionNameString(ref)
+	switch r.Ancestors[len(r.Ancestors)-1].Kind {
+	case ast.NodeKindInputObjectTypeDefinition, ast.NodeKindInputObjectTypeExtension:
+		r.validateName("input field", name)
+	default:
+		r.validateName("argument", name)
+	}
+}
+
+func (r *reservedNamesVisitor) EnterEnumValueDefinition(ref int) {
+	r.validateName("enum value", r.definition.EnumValueDefinitionNameString(ref))
+}
+
+func (r *reservedNamesVisitor) validateTypeName(definitionType, typeName string) {
+	if _, ok := introspectionTypeNames[typeName]; ok {
+		return
+	}
+	r.validateName(definitionType, typeName)
+}
+
+func (r *reservedNamesVisitor) validateName(definitionType, name string) {
+	if strings.HasPrefix(name, "__") {
+		r.Report.AddExternalError(operationreport.ErrNameIsReserved(definitionType, name))
+	}
+}
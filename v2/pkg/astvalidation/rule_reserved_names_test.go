@@ -0,0 +1,70 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestReservedNames(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Introspection types and fields are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						hello(_name: String): String
+					}
+				`, Valid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved type name is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type __User {
+						name: String
+					}
+				`, Invalid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved field name is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type User {
+						__name: String
+					}
+				`, Invalid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved argument name is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					type Query {
+						user(__id: ID): String
+					}
+				`, Invalid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved input field name is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					input UserFilter {
+						__name: String
+					}
+				`, Invalid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved enum value is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					enum Status {
+						__ACTIVE
+					}
+				`, Invalid, ReservedNames(),
+			)
+		})
+
+		t.Run("Reserved directive name is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @__auth on FIELD_DEFINITION
+				`, Invalid, ReservedNames(),
+			)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// RootOperationTypesAreObjectTypes validates that the root operation types of the schema definition and
+// its extensions are object types. Undefined types are reported by KnownTypeNames.
+func RootOperationTypesAreObjectTypes() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &rootOperationTypesAreObjectTypesVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterRootOperationTypeDefinitionVisitor(visitor)
+	}
+}
+
+type rootOperationTypesAreObjectTypesVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+}
+
+func (r *rootOperationTypesAreObjectTypesVisitor) EnterDocument(operation, _ *ast.Document) {
+	r.definition = operation
+}
+
+func (r *rootOperationTypesAreObjectTypesVisitor) EnterRootOperationTypeDefinition(ref int) {
+	rootOperationTypeDefinition := r.definition.RootOperationTypeDefinitions[ref]
+	typeName := r.definition.Input.ByteSliceString(rootOperationTypeDefinition.NamedType.Name)
+
+	nodes, exists := r.definition.Index.NodesByNameStr(typeName)
+	if !exists {
+		return
+	}
+
+	for _, node := range nodes {
+		if node.Kind == ast.NodeKindObjectTypeDefinition || node.Kind == ast.NodeKindObjectTypeExtension {
+			return
+		}
+	}
+
+	r.Report.AddExternalError(operationreport.ErrRootOperationTypeMustBeObjectType(
+		rootOperationTypeDefinition.OperationType.Name(),
+		typeName,
+	))
+}
@@ -0,0 +1,64 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestRootOperationTypesAreObjectTypes(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Object root operation types are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					schema {
+						query: MyQuery
+					}
+
+					extend schema {
+						mutation: MyMutation
+					}
+
+					type MyQuery {
+						hello: String
+					}
+
+					extend type MyMutation {
+						hello: String
+					}
+				`, Valid, RootOperationTypesAreObjectTypes(),
+			)
+		})
+
+		t.Run("Input object as query type is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					schema {
+						query: MyQuery
+					}
+
+					input MyQuery {
+						hello: String
+					}
+				`, Invalid, RootOperationTypesAreObjectTypes(),
+			)
+		})
+
+		t.Run("Interface as subscription type in schema extension is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					schema {
+						query: Query
+					}
+
+					extend schema {
+						subscription: Events
+					}
+
+					type Query {
+						hello: String
+					}
+
+					interface Events {
+						hello: String
+					}
+				`, Invalid, RootOperationTypesAreObjectTypes(),
+			)
+		})
+	})
+}
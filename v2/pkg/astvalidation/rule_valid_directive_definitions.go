@@ -0,0 +1,142 @@
+package astvalidation
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// ValidDirectiveDefinitions validates that the arguments of a directive definition are unique and that
+// a directive doesn't reference itself, neither directly on its arguments nor indirectly through the types
+// and directives used by its arguments.
+func ValidDirectiveDefinitions() Rule {
+	return func(walker *astvisitor.Walker) {
+		visitor := &validDirectiveDefinitionsVisitor{
+			Walker: walker,
+		}
+
+		walker.RegisterEnterDocumentVisitor(visitor)
+		walker.RegisterEnterDirectiveDefinitionVisitor(visitor)
+	}
+}
+
+type validDirectiveDefinitionsVisitor struct {
+	*astvisitor.Walker
+	definition *ast.Document
+	visited    map[string]struct{}
+}
+
+func (v *validDirectiveDefinitionsVisitor) EnterDocument(operation, _ *ast.Document) {
+	v.definition = operation
+	v.visited = make(map[string]struct{})
+}
+
+func (v *validDirectiveDefinitionsVisitor) EnterDirectiveDefinition(ref int) {
+	directiveName := v.definition.DirectiveDefinitionNameString(ref)
+	argumentRefs := v.definition.DirectiveDefinitions[ref].ArgumentsDefinition.Refs
+
+	argumentNames := make(map[string]struct{}, len(argumentRefs))
+	for _, argumentRef := range argumentRefs {
+		argumentName := v.definition.InputValueDefinitionNameString(argumentRef)
+		if _, exists := argumentNames[argumentName]; exists {
+			v.Report.AddExternalError(operationreport.ErrDirectiveDefinitionArgumentNameMustBeUnique(directiveName, argumentName))
+			continue
+		}
+		argumentNames[argumentName] = struct{}{}
+	}
+
+	for key := range v.visited {
+		delete(v.visited, key)
+	}
+	v.visited["@"+directiveName] = struct{}{}
+
+	if coordinate, references := v.inputValuesReferenceDirective(directiveName, "@"+directiveName, true, argumentRefs); references {
+		v.Report.AddExternalError(operationreport.ErrDirectiveDefinitionReferencesItself(directiveName, coordinate))
+	}
+}
+
+// inputValuesReferenceDirective returns the coordinate of the first usage of the directive within the input values.
+func (v *validDirectiveDefinitionsVisitor) inputValuesReferenceDirective(directiveName, parentCoordinate string, isArgument bool, inputValueRefs []int) (coordinate string, references bool) {
+	for _, ref := range inputValueRefs {
+		inputValueCoordinate := parentCoordinate + "." + v.definition.InputValueDefinitionNameString(ref)
+		if isArgument {
+			inputValueCoordinate = parentCoordinate + "(" + v.definition.InputValueDefinitionNameString(ref) + ":)"
+		}
+
+		if coordinate, references = v.directivesReferenceDirective(directiveName, inputValueCoordinate, v.definition.InputValueDefinitions[ref].Directives.Refs); references {
+			return coordinate, true
+		}
+
+		typeName := v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(ref))
+		if coordinate, references = v.typeReferencesDirective(directiveName, typeName); references {
+			return coordinate, true
+		}
+	}
+	return "", false
+}
+
+func (v *validDirectiveDefinitionsVisitor) directivesReferenceDirective(directiveName, coordinate string, directiveRefs []int) (string, bool) {
+	for _, directiveRef := range directiveRefs {
+		usedDirectiveName := v.definition.DirectiveNameString(directiveRef)
+		if usedDirectiveName == directiveName {
+			return coordinate, true
+		}
+
+		if _, visited := v.visited["@"+usedDirectiveName]; visited {
+			continue
+		}
+		v.visited["@"+usedDirectiveName] = struct{}{}
+
+		usedDirectiveRef, exists := v.definition.DirectiveDefinitionByName(usedDirectiveName)
+		if !exists {
+			continue
+		}
+		argumentRefs := v.definition.DirectiveDefinitions[usedDirectiveRef].ArgumentsDefinition.Refs
+		if coordinate, references := v.inputValuesReferenceDirective(directiveName, "@"+usedDirectiveName, true, argumentRefs); references {
+			return coordinate, true
+		}
+	}
+	return "", false
+}
+
+func (v *validDirectiveDefinitionsVisitor) typeReferencesDirective(directiveName, typeName string) (string, bool) {
+	if _, visited := v.visited[typeName]; visited {
+		return "", false
+	}
+	v.visited[typeName] = struct{}{}
+
+	nodes, _ := v.definition.Index.NodesByNameStr(typeName)
+	for _, node := range nodes {
+		if coordinate, references := v.directivesReferenceDirective(directiveName, typeName, v.definition.NodeDirectives(node)); references {
+			return coordinate, true
+		}
+
+		switch node.Kind {
+		case ast.NodeKindInputObjectTypeDefinition:
+			inputFieldRefs := v.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs
+			if coordinate, references := v.inputValuesReferenceDirective(directiveName, typeName, false, inputFieldRefs); references {
+				return coordinate, true
+			}
+		case ast.NodeKindInputObjectTypeExtension:
+			inputFieldRefs := v.definition.InputObjectTypeExtensions[node.Ref].InputFieldsDefinition.Refs
+			if coordinate, references := v.inputValuesReferenceDirective(directiveName, typeName, false, inputFieldRefs); references {
+				return coordinate, true
+			}
+		case ast.NodeKindEnumTypeDefinition:
+			for _, enumValueRef := range v.definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+				enumValueCoordinate := typeName + "." + v.definition.EnumValueDefinitionNameString(enumValueRef)
+				if coordinate, references := v.directivesReferenceDirective(directiveName, enumValueCoordinate, v.definition.EnumValueDefinitions[enumValueRef].Directives.Refs); references {
+					return coordinate, true
+				}
+			}
+		case ast.NodeKindEnumTypeExtension:
+			for _, enumValueRef := range v.definition.EnumTypeExtensions[node.Ref].EnumValuesDefinition.Refs {
+				enumValueCoordinate := typeName + "." + v.definition.EnumValueDefinitionNameString(enumValueRef)
+				if coordinate, references := v.directivesReferenceDirective(directiveName, enumValueCoordinate, v.definition.EnumValueDefinitions[enumValueRef].Directives.Refs); references {
+					return coordinate, true
+				}
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package astvalidation
+
+import (
+	"testing"
+)
+
+func TestValidDirectiveDefinitions(t *testing.T) {
+	t.Run("Definition", func(t *testing.T) {
+		t.Run("Directives with unique arguments are valid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(requires: Role = ADMIN, scopes: [String!]) on FIELD_DEFINITION
+					directive @tag(name: String! @auth) on ARGUMENT_DEFINITION
+
+					enum Role {
+						ADMIN @deprecated
+						USER
+					}
+				`, Valid, ValidDirectiveDefinitions(),
+			)
+		})
+
+		t.Run("Duplicated argument is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(requires: String, requires: String) on FIELD_DEFINITION
+				`, Invalid, ValidDirectiveDefinitions(),
+			)
+		})
+
+		t.Run("Directive used on its own argument is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(requires: String @auth) on FIELD_DEFINITION | ARGUMENT_DEFINITION
+				`, Invalid, ValidDirectiveDefinitions(),
+			)
+		})
+
+		t.Run("Directive used on the type of its argument is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(requires: Role) on FIELD_DEFINITION | ENUM_VALUE
+
+					enum Role {
+						ADMIN @auth
+					}
+				`, Invalid, ValidDirectiveDefinitions(),
+			)
+		})
+
+		t.Run("Directive referenced through input object fields is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(policy: Policy) on FIELD_DEFINITION | INPUT_FIELD_DEFINITION
+
+					input Policy {
+						nested: NestedPolicy
+					}
+
+					input NestedPolicy {
+						name: String @auth
+					}
+				`, Invalid, ValidDirectiveDefinitions(),
+			)
+		})
+
+		t.Run("Directive referenced through another directive is invalid", func(t *testing.T) {
+			runDefinitionValidation(t, `
+					directive @auth(requires: String @tag) on FIELD_DEFINITION | ARGUMENT_DEFINITION
+					directive @tag(name: String @auth) on ARGUMENT_DEFINITION
+				`, Invalid, ValidDirectiveDefinitions(),
+			)
+		})
+	})
+}
@@ -0,0 +1,562 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/iancoleman/strcase"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+)
+
+const (
+	graphqlClientPackage = "github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlclient"
+	typeNameFieldName    = "__typename"
+)
+
+// ClientConfig configures the generation of a typed client
+type ClientConfig struct {
+	PackageName string
+	// ScalarTypes maps custom scalars to qualified Go types, e.g. "DateTime": "time.Time".
+	// Custom scalars without a Go type are decoded as json.RawMessage.
+	ScalarTypes map[string]string
+}
+
+// ClientGen generates a typed Go client for the named operations of a document. For each operation it generates
+// a response struct which respects aliases, fragments and nullability, a variables struct if the operation has variables
+// and a function which executes the operation with a graphqlclient.Executor, e.g. over http or with an in-process engine.
+// Fields of fragments with a type condition other than the enclosing type are optional, as they are only part of the
+// response for some types. Selection sets of interfaces and unions always select __typename, so that the response
+// structs tell which fragments apply. Subscriptions are skipped, as the executors don't support them.
+type ClientGen struct {
+	schema     *ast.Document
+	operations *ast.Document
+	config     ClientConfig
+	file       *jen.File
+	// namedTypes are the enums and input objects used by the operations and whether they are generated,
+	// they are generated after the operations
+	namedTypes map[string]bool
+	// typeNames are the names of the declared Go types, names of response structs which are already taken get a
+	// numeric suffix, e.g. when an alias and a nested field result in the same name
+	typeNames map[string]struct{}
+}
+
+// NewClientGen returns a generator for the operations, the schema must be merged with the base schema,
+// see asttransform.MergeDefinitionWithBaseSchema.
+func NewClientGen(schema, operations *ast.Document, config ClientConfig) *ClientGen {
+	return &ClientGen{
+		schema:     schema,
+		operations: operations,
+		config:     config,
+	}
+}
+
+// Generate validates the operations against the schema and writes the client code to w
+func (c *ClientGen) Generate(w io.Writer) (int, error) {
+	c.file = jen.NewFile(c.config.PackageName)
+	c.file.PackageComment("Code generated by graphql-go-tools gen, DO NOT EDIT.")
+	c.namedTypes = map[string]bool{}
+	c.typeNames = map[string]struct{}{}
+
+	// enums and input objects keep their names, so they are reserved before any struct is named
+	for i := range c.schema.EnumTypeDefinitions {
+		c.typeNames[strcase.ToCamel(c.schema.EnumTypeDefinitionNameString(i))] = struct{}{}
+	}
+	for i := range c.schema.InputObjectTypeDefinitions {
+		c.typeNames[strcase.ToCamel(c.schema.InputObjectTypeDefinitionNameString(i))] = struct{}{}
+	}
+
+	c.addTypeNames()
+
+	for _, rootNode := range c.operations.RootNodes {
+		if rootNode.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if c.operations.OperationDefinitions[rootNode.Ref].OperationType == ast.OperationTypeSubscription {
+			continue
+		}
+		if err := c.generateOperation(rootNode.Ref); err != nil {
+			return 0, err
+		}
+	}
+
+	// input objects can use further named types, so they are generated until there are no pending types left
+	for pending := c.pendingNamedTypes(); len(pending) != 0; pending = c.pendingNamedTypes() {
+		for _, typeName := range pending {
+			c.namedTypes[typeName] = true
+			c.generateNamedType(typeName)
+		}
+	}
+
+	return fmt.Fprintf(w, "%#v", c.file)
+}
+
+func (c *ClientGen) generateOperation(ref int) error {
+	operationName := c.operations.OperationDefinitionNameString(ref)
+	if operationName == "" {
+		return fmt.Errorf("operations must be named to generate a client")
+	}
+
+	operation, err := c.operationString(ref)
+	if err != nil {
+		return err
+	}
+	if err = c.validateOperation(operationName, operation); err != nil {
+		return err
+	}
+
+	operationDefinition := c.operations.OperationDefinitions[ref]
+	rootTypeName, err := c.rootTypeName(operationDefinition.OperationType)
+	if err != nil {
+		return fmt.Errorf("operation %s: %w", operationName, err)
+	}
+
+	name := strcase.ToCamel(operationName)
+	responseName := c.uniqueTypeName(name + "Response")
+	variablesName := c.uniqueTypeName(name + "Variables")
+
+	c.file.Commentf("%sOperation is the document of the %s %s", name, operationDefinition.OperationType.Name(), operationName)
+	c.file.Const().Id(name + "Operation").Op("=").Lit(operation)
+
+	c.file.Commentf("%s is the data of the response of the %s %s", responseName, operationDefinition.OperationType.Name(), operationName)
+	if err = c.generateResponseStruct(responseName, rootTypeName, []int{operationDefinition.SelectionSet}); err != nil {
+		return fmt.Errorf("operation %s: %w", operationName, err)
+	}
+
+	hasVariables := operationDefinition.HasVariableDefinitions && len(operationDefinition.VariableDefinitions.Refs) != 0
+	if hasVariables {
+		c.file.Commentf("%s are the variables of the %s %s", variablesName, operationDefinition.OperationType.Name(), operationName)
+		c.generateVariablesStruct(variablesName, operationDefinition.VariableDefinitions.Refs)
+	}
+
+	params := []jen.Code{
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("executor").Qual(graphqlClientPackage, "Executor"),
+	}
+	request := jen.Dict{
+		jen.Id("Query"):         jen.Id(name + "Operation"),
+		jen.Id("OperationName"): jen.Lit(operationName),
+	}
+	if hasVariables {
+		params = append(params, jen.Id("variables").Id(variablesName))
+		request[jen.Id("Variables")] = jen.Id("variables")
+	}
+
+	c.file.Commentf("%s executes the %s %s", name, operationDefinition.OperationType.Name(), operationName)
+	c.file.Func().Id(name).Params(params...).Params(jen.Op("*").Id(responseName), jen.Error()).Block(
+		jen.Id("data").Op(":=").Op("&").Id(responseName).Values(),
+		jen.Id("err").Op(":=").Id("executor").Dot("Execute").Call(
+			jen.Id("ctx"),
+			jen.Qual(graphqlClientPackage, "Request").Values(request),
+			jen.Id("data"),
+		),
+		jen.Return(jen.Id("data"), jen.Id("err")),
+	)
+	return nil
+}
+
+// addTypeNames prepends a __typename selection to the selection sets of interfaces and unions of the operations
+// and fragments which don't select it
+func (c *ClientGen) addTypeNames() {
+	for _, rootNode := range c.operations.RootNodes {
+		switch rootNode.Kind {
+		case ast.NodeKindOperationDefinition:
+			operationDefinition := c.operations.OperationDefinitions[rootNode.Ref]
+			if rootTypeName, err := c.rootTypeName(operationDefinition.OperationType); err == nil {
+				c.addTypeNamesToSelectionSet(operationDefinition.SelectionSet, rootTypeName)
+			}
+		case ast.NodeKindFragmentDefinition:
+			typeCondition := c.operations.FragmentDefinitionTypeNameString(rootNode.Ref)
+			c.addTypeNamesToSelectionSet(c.operations.FragmentDefinitions[rootNode.Ref].SelectionSet, typeCondition)
+		}
+	}
+}
+
+// addTypeNamesToSelectionSet adds the __typename selections to the selection sets of the fields of the selection set,
+// unknown types and fields are skipped as they are reported by the validation of the operation
+func (c *ClientGen) addTypeNamesToSelectionSet(selectionSet int, typeName string) {
+	for _, selectionRef := range c.operations.SelectionSets[selectionSet].SelectionRefs {
+		selection := c.operations.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			fieldSelectionSet, ok := c.operations.FieldSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			enclosingType, ok := c.schema.Index.FirstNodeByNameStr(typeName)
+			if !ok {
+				continue
+			}
+			fieldDefinition, ok := c.schema.NodeFieldDefinitionByName(enclosingType, c.operations.FieldNameBytes(selection.Ref))
+			if !ok {
+				continue
+			}
+			fieldTypeName := c.schema.FieldDefinitionTypeNameString(fieldDefinition)
+			fieldType, ok := c.schema.Index.FirstNodeByNameStr(fieldTypeName)
+			if !ok {
+				continue
+			}
+			isAbstract := fieldType.Kind == ast.NodeKindInterfaceTypeDefinition || fieldType.Kind == ast.NodeKindUnionTypeDefinition
+			if hasTypeName, _ := c.operations.SelectionSetHasFieldSelectionWithExactName(fieldSelectionSet, []byte(typeNameFieldName)); isAbstract && !hasTypeName {
+				field := c.operations.AddField(ast.Field{
+					Name: c.operations.Input.AppendInputString(typeNameFieldName),
+				})
+				typeNameSelection := c.operations.AddSelectionToDocument(ast.Selection{
+					Ref:  field.Ref,
+					Kind: ast.SelectionKindField,
+				})
+				selectionRefs := c.operations.SelectionSets[fieldSelectionSet].SelectionRefs
+				c.operations.SelectionSets[fieldSelectionSet].SelectionRefs = append([]int{typeNameSelection}, selectionRefs...)
+			}
+			c.addTypeNamesToSelectionSet(fieldSelectionSet, fieldTypeName)
+		case ast.SelectionKindInlineFragment:
+			inlineFragmentSelectionSet, ok := c.operations.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			typeCondition := typeName
+			if c.operations.InlineFragmentHasTypeCondition(selection.Ref) {
+				typeCondition = c.operations.InlineFragmentTypeConditionNameString(selection.Ref)
+			}
+			c.addTypeNamesToSelectionSet(inlineFragmentSelectionSet, typeCondition)
+		}
+	}
+}
+
+// operationString prints the operation together with the fragments it uses
+func (c *ClientGen) operationString(ref int) (string, error) {
+	rootNodes := c.operations.RootNodes
+	defer func() {
+		c.operations.RootNodes = rootNodes
+	}()
+
+	c.operations.RootNodes = []ast.Node{{Kind: ast.NodeKindOperationDefinition, Ref: ref}}
+	fragments := map[string]struct{}{}
+	c.collectFragments(c.operations.OperationDefinitions[ref].SelectionSet, fragments)
+	for _, rootNode := range rootNodes {
+		if rootNode.Kind != ast.NodeKindFragmentDefinition {
+			continue
+		}
+		if _, ok := fragments[c.operations.FragmentDefinitionNameString(rootNode.Ref)]; ok {
+			c.operations.RootNodes = append(c.operations.RootNodes, rootNode)
+		}
+	}
+
+	return astprinter.PrintString(c.operations, c.schema)
+}
+
+func (c *ClientGen) collectFragments(selectionSet int, fragments map[string]struct{}) {
+	for _, selectionRef := range c.operations.SelectionSets[selectionSet].SelectionRefs {
+		selection := c.operations.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if fieldSelectionSet, ok := c.operations.FieldSelectionSet(selection.Ref); ok {
+				c.collectFragments(fieldSelectionSet, fragments)
+			}
+		case ast.SelectionKindInlineFragment:
+			if inlineFragmentSelectionSet, ok := c.operations.InlineFragmentSelectionSet(selection.Ref); ok {
+				c.collectFragments(inlineFragmentSelectionSet, fragments)
+			}
+		case ast.SelectionKindFragmentSpread:
+			name := c.operations.FragmentSpreadNameString(selection.Ref)
+			if _, ok := fragments[name]; ok {
+				continue
+			}
+			fragment, exists := c.operations.FragmentDefinitionRef(c.operations.FragmentSpreadNameBytes(selection.Ref))
+			if !exists {
+				continue
+			}
+			fragments[name] = struct{}{}
+			c.collectFragments(c.operations.FragmentDefinitions[fragment].SelectionSet, fragments)
+		}
+	}
+}
+
+func (c *ClientGen) validateOperation(operationName, operation string) error {
+	document, report := astparser.ParseGraphqlDocumentString(operation)
+	if !report.HasErrors() {
+		astnormalization.NormalizeOperation(&document, c.schema, &report)
+	}
+	if !report.HasErrors() {
+		astvalidation.DefaultOperationValidator().Validate(&document, c.schema, &report)
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("operation %s: %w", operationName, report)
+	}
+	return nil
+}
+
+func (c *ClientGen) rootTypeName(operationType ast.OperationType) (string, error) {
+	var typeName ast.ByteSlice
+	switch operationType {
+	case ast.OperationTypeQuery:
+		typeName = c.schema.Index.QueryTypeName
+	case ast.OperationTypeMutation:
+		typeName = c.schema.Index.MutationTypeName
+	case ast.OperationTypeSubscription:
+		typeName = c.schema.Index.SubscriptionTypeName
+	}
+	if len(typeName) == 0 {
+		return "", fmt.Errorf("the schema has no %s type", operationType.Name())
+	}
+	return string(typeName), nil
+}
+
+// responseField is a field of a response struct, the selections of all fields with the same response key are merged
+type responseField struct {
+	key       string
+	fieldName string
+	// typeName is the type of the selection set which contains the field
+	typeName      string
+	selectionSets []int
+	// conditional fields are only selected by fragments on other types than the enclosing type
+	conditional bool
+}
+
+// generateResponseStruct generates a struct for the fields of the selection sets and the structs of their selection sets
+func (c *ClientGen) generateResponseStruct(structName, typeName string, selectionSets []int) error {
+	var fields []*responseField
+	for _, selectionSet := range selectionSets {
+		c.collectFields(selectionSet, typeName, false, &fields, map[string]struct{}{})
+	}
+
+	structFields := make([]jen.Code, 0, len(fields))
+	var nestedStructs []func() error
+	for _, field := range fields {
+		if field.fieldName == typeNameFieldName {
+			structFields = append(structFields, jen.Id(strcase.ToCamel(field.key)).String().Tag(map[string]string{"json": field.key}))
+			continue
+		}
+
+		enclosingType, ok := c.schema.Index.FirstNodeByNameStr(field.typeName)
+		if !ok {
+			return fmt.Errorf("unknown type %s", field.typeName)
+		}
+		fieldDefinition, ok := c.schema.NodeFieldDefinitionByName(enclosingType, []byte(field.fieldName))
+		if !ok {
+			return fmt.Errorf("unknown field %s.%s", field.typeName, field.fieldName)
+		}
+
+		typeRef := c.schema.FieldDefinitions[fieldDefinition].Type
+		if field.conditional && c.schema.Types[typeRef].TypeKind == ast.TypeKindNonNull {
+			typeRef = c.schema.Types[typeRef].OfType
+		}
+
+		fieldStructName := ""
+		fieldTypeName := c.schema.ResolveTypeNameString(typeRef)
+		fieldSelectionSets := field.selectionSets
+		structFields = append(structFields, jen.Id(strcase.ToCamel(field.key)).Add(goType(c.schema, typeRef, true, func(typeName string) jen.Code {
+			if len(fieldSelectionSets) == 0 {
+				return c.namedGoType(typeName)
+			}
+			fieldStructName = c.uniqueTypeName(structName + strcase.ToCamel(field.key))
+			nestedStructs = append(nestedStructs, func() error {
+				return c.generateResponseStruct(fieldStructName, fieldTypeName, fieldSelectionSets)
+			})
+			return jen.Id(fieldStructName)
+		})).Tag(map[string]string{"json": field.key}))
+	}
+
+	c.file.Type().Id(structName).Struct(structFields...)
+	for _, nestedStruct := range nestedStructs {
+		if err := nestedStruct(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueTypeName returns name or, if it is taken, name with the first free numeric suffix and reserves it
+func (c *ClientGen) uniqueTypeName(name string) string {
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := c.typeNames[unique]; !taken {
+			break
+		}
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	c.typeNames[unique] = struct{}{}
+	return unique
+}
+
+func (c *ClientGen) collectFields(selectionSet int, typeName string, conditional bool, fields *[]*responseField, fragments map[string]struct{}) {
+	for _, selectionRef := range c.operations.SelectionSets[selectionSet].SelectionRefs {
+		selection := c.operations.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			c.addField(selection.Ref, typeName, conditional, fields)
+		case ast.SelectionKindInlineFragment:
+			inlineFragmentSelectionSet, ok := c.operations.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			typeCondition := typeName
+			if c.operations.InlineFragmentHasTypeCondition(selection.Ref) {
+				typeCondition = c.operations.InlineFragmentTypeConditionNameString(selection.Ref)
+			}
+			c.collectFields(inlineFragmentSelectionSet, typeCondition, conditional || typeCondition != typeName, fields, fragments)
+		case ast.SelectionKindFragmentSpread:
+			name := c.operations.FragmentSpreadNameString(selection.Ref)
+			if _, visiting := fragments[name]; visiting {
+				continue
+			}
+			fragment, exists := c.operations.FragmentDefinitionRef(c.operations.FragmentSpreadNameBytes(selection.Ref))
+			if !exists {
+				continue
+			}
+			typeCondition := c.operations.FragmentDefinitionTypeNameString(fragment)
+			fragments[name] = struct{}{}
+			c.collectFields(c.operations.FragmentDefinitions[fragment].SelectionSet, typeCondition, conditional || typeCondition != typeName, fields, fragments)
+			delete(fragments, name)
+		}
+	}
+}
+
+func (c *ClientGen) addField(ref int, typeName string, conditional bool, fields *[]*responseField) {
+	key := c.operations.FieldAliasOrNameString(ref)
+	var selectionSets []int
+	if fieldSelectionSet, ok := c.operations.FieldSelectionSet(ref); ok {
+		selectionSets = append(selectionSets, fieldSelectionSet)
+	}
+
+	for _, field := range *fields {
+		if field.key != key {
+			continue
+		}
+		field.selectionSets = append(field.selectionSets, selectionSets...)
+		field.conditional = field.conditional && conditional
+		if !conditional {
+			field.typeName = typeName
+		}
+		return
+	}
+
+	*fields = append(*fields, &responseField{
+		key:           key,
+		fieldName:     c.operations.FieldNameString(ref),
+		typeName:      typeName,
+		selectionSets: selectionSets,
+		conditional:   conditional,
+	})
+}
+
+func (c *ClientGen) generateVariablesStruct(structName string, variableDefinitions []int) {
+	structFields := make([]jen.Code, 0, len(variableDefinitions))
+	for _, ref := range variableDefinitions {
+		name := c.operations.VariableDefinitionNameString(ref)
+		typeRef := c.operations.VariableDefinitions[ref].Type
+		structFields = append(structFields, jen.Id(strcase.ToCamel(name)).Add(goType(c.operations, typeRef, true, c.namedGoType)).Tag(inputFieldTag(c.operations, name, typeRef)))
+	}
+	c.file.Type().Id(structName).Struct(structFields...)
+}
+
+// inputFieldTag omits nullable variables and input fields without a value, so that they are not set to null
+func inputFieldTag(doc *ast.Document, name string, typeRef int) map[string]string {
+	if doc.Types[typeRef].TypeKind == ast.TypeKindNonNull {
+		return map[string]string{"json": name}
+	}
+	return map[string]string{"json": name + ",omitempty"}
+}
+
+// goType renders a GraphQL type, nullable named types are pointers and lists are slices.
+// The Go type of named types is rendered by namedType.
+func goType(doc *ast.Document, typeRef int, nullable bool, namedType func(typeName string) jen.Code) *jen.Statement {
+	graphqlType := doc.Types[typeRef]
+	switch graphqlType.TypeKind {
+	case ast.TypeKindNonNull:
+		return goType(doc, graphqlType.OfType, false, namedType)
+	case ast.TypeKindList:
+		return jen.Index().Add(goType(doc, graphqlType.OfType, true, namedType))
+	default:
+		named := namedType(doc.TypeNameString(typeRef))
+		if nullable {
+			return jen.Op("*").Add(named)
+		}
+		return jen.Add(named)
+	}
+}
+
+// namedGoType returns the Go type of scalars, enums and input objects
+func (c *ClientGen) namedGoType(typeName string) jen.Code {
+	node, ok := c.schema.Index.FirstNodeByNameStr(typeName)
+	if ok && (node.Kind == ast.NodeKindEnumTypeDefinition || node.Kind == ast.NodeKindInputObjectTypeDefinition) {
+		if _, ok := c.namedTypes[typeName]; !ok {
+			c.namedTypes[typeName] = false
+		}
+		return jen.Id(strcase.ToCamel(typeName))
+	}
+	return scalarGoType(typeName, c.config.ScalarTypes)
+}
+
+// scalarGoType returns the Go type of a scalar, scalarTypes maps custom scalars to qualified Go types.
+// Custom scalars without a Go type are json.RawMessage.
+func scalarGoType(typeName string, scalarTypes map[string]string) jen.Code {
+	switch typeName {
+	case "String", "ID":
+		return jen.String()
+	case "Int":
+		return jen.Int64()
+	case "Float":
+		return jen.Float64()
+	case "Boolean":
+		return jen.Bool()
+	}
+
+	if goType, ok := scalarTypes[typeName]; ok {
+		if i := strings.LastIndex(goType, "."); i != -1 {
+			return jen.Qual(goType[:i], goType[i+1:])
+		}
+		return jen.Id(goType)
+	}
+	return jen.Qual("encoding/json", "RawMessage")
+}
+
+func (c *ClientGen) pendingNamedTypes() (pending []string) {
+	for typeName, generated := range c.namedTypes {
+		if !generated {
+			pending = append(pending, typeName)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// generateNamedType generates an enum or input object type
+func (c *ClientGen) generateNamedType(typeName string) {
+	generateInputType(c.file, c.schema, typeName, c.namedGoType)
+}
+
+// generateInputType generates an enum or input object type,
+// the Go types of the input fields are rendered by namedType
+func generateInputType(file *jen.File, schema *ast.Document, typeName string, namedType func(typeName string) jen.Code) {
+	node, _ := schema.Index.FirstNodeByNameStr(typeName)
+	goName := strcase.ToCamel(typeName)
+
+	switch node.Kind {
+	case ast.NodeKindEnumTypeDefinition:
+		file.Type().Id(goName).String()
+		values := schema.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs
+		file.Const().DefsFunc(func(group *jen.Group) {
+			for _, ref := range values {
+				value := schema.EnumValueDefinitionNameString(ref)
+				group.Id(goName + strcase.ToCamel(strings.ToLower(value))).Id(goName).Op("=").Lit(value)
+			}
+		})
+	case ast.NodeKindInputObjectTypeDefinition:
+		inputFields := schema.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs
+		structFields := make([]jen.Code, 0, len(inputFields))
+		for _, ref := range inputFields {
+			name := schema.InputValueDefinitionNameString(ref)
+			typeRef := schema.InputValueDefinitions[ref].Type
+			structFields = append(structFields, jen.Id(strcase.ToCamel(name)).Add(goType(schema, typeRef, true, namedType)).Tag(inputFieldTag(schema, name, typeRef)))
+		}
+		file.Type().Id(goName).Struct(structFields...)
+	}
+}
@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/internal/pkg/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/goldie"
+)
+
+func TestClientGen_Generate(t *testing.T) {
+	schemaBytes, err := os.ReadFile("../starwars/testdata/star_wars.graphql")
+	require.NoError(t, err)
+	schema := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(string(schemaBytes))
+
+	generate := func(t *testing.T, operations string, config ClientConfig) (string, error) {
+		t.Helper()
+		operationsDocument := unsafeparser.ParseGraphqlDocumentString(operations)
+		out := bytes.Buffer{}
+		_, err := NewClientGen(&schema, &operationsDocument, config).Generate(&out)
+		return out.String(), err
+	}
+
+	t.Run("operations of a directory", func(t *testing.T) {
+		files, err := filepath.Glob("./testdata/client/*.graphql")
+		require.NoError(t, err)
+
+		operations := bytes.Buffer{}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			require.NoError(t, err)
+			operations.Write(content)
+			operations.WriteString("\n")
+		}
+
+		out, err := generate(t, operations.String(), ClientConfig{PackageName: "starwars"})
+		require.NoError(t, err)
+
+		goldie.Assert(t, "Client", []byte(out))
+		if t.Failed() {
+			fixture, err := os.ReadFile("./fixtures/Client.golden")
+			require.NoError(t, err)
+
+			assert.Equal(t, string(fixture), out)
+		}
+	})
+
+	t.Run("clashing struct names compile", func(t *testing.T) {
+		out, err := generate(t, `
+			query Q {
+				hero { friends { name } }
+				heroFriends: hero { name }
+			}
+		`, ClientConfig{PackageName: "starwars"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "type QResponseHeroFriends struct")
+		assert.Contains(t, out, "type QResponseHeroFriends2 struct")
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "client.go", out, 0)
+		require.NoError(t, err)
+		config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+		_, err = config.Check("starwars", fset, []*ast.File{file}, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("anonymous operation", func(t *testing.T) {
+		_, err := generate(t, `{ hero { name } }`, ClientConfig{PackageName: "starwars"})
+		assert.EqualError(t, err, "operations must be named to generate a client")
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		_, err := generate(t, `query Hero { hero { unknown } }`, ClientConfig{PackageName: "starwars"})
+		assert.Error(t, err)
+	})
+}
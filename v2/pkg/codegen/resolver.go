@@ -0,0 +1,487 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/iancoleman/strcase"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+)
+
+const (
+	resolverDataSourcePackage = "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/resolver_datasource"
+	planPackage               = "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+// ResolverConfig configures the generation of resolver scaffolding
+type ResolverConfig struct {
+	PackageName string
+	// ScalarTypes maps custom scalars to qualified Go types, e.g. "DateTime": "time.Time".
+	// Custom scalars without a Go type are json.RawMessage.
+	ScalarTypes map[string]string
+}
+
+// ResolverGen generates the scaffolding to implement a schema with Go resolvers and to mount them into the engine
+// as an in-process data source. It generates
+//   - a model struct for every object type with the fields without arguments,
+//     interfaces and unions are Go interfaces which are implemented by the models of their object types
+//   - an args struct for every field with arguments, as well as the enums and input objects
+//   - a resolver interface for the query and mutation type with a method for every field
+//     and a resolver interface for every other object type with a method for every field with arguments
+//   - the Resolvers interface which returns the resolvers of all types
+//   - the DataSource adapter which implements resolve.DataSource and plan.PlannerFactory with the Resolvers
+//
+// Fields of the subscription type are not supported by the in-process data source, they get no resolvers
+// and are listed in the doc comment of the generated Resolvers interface instead.
+type ResolverGen struct {
+	schema     *ast.Document
+	definition *ast.Document
+	config     ResolverConfig
+	file       *jen.File
+	// objectTypes are the object types except the root operation types, in the order of the schema
+	objectTypes []string
+	rootTypes   []string
+	// resolverTypes are the types with a resolver interface
+	resolverTypes []string
+}
+
+// NewResolverGen returns a generator for the schema, the schema must not be merged with the base schema
+func NewResolverGen(schema *ast.Document, config ResolverConfig) *ResolverGen {
+	return &ResolverGen{
+		schema: schema,
+		config: config,
+	}
+}
+
+// Generate writes the resolver scaffolding of the schema to w
+func (r *ResolverGen) Generate(w io.Writer) (int, error) {
+	sdl, err := astprinter.PrintString(r.schema, nil)
+	if err != nil {
+		return 0, err
+	}
+	definition, report := astparser.ParseGraphqlDocumentString(sdl)
+	if report.HasErrors() {
+		return 0, report
+	}
+	if err = asttransform.MergeDefinitionWithBaseSchema(&definition); err != nil {
+		return 0, err
+	}
+	r.definition = &definition
+
+	r.file = jen.NewFile(r.config.PackageName)
+	r.file.PackageComment("Code generated by graphql-go-tools gen, DO NOT EDIT.")
+	r.collectTypes()
+
+	r.file.Comment("Schema is the schema the resolvers implement")
+	r.file.Const().Id("Schema").Op("=").Lit(sdl)
+
+	for _, typeName := range r.objectTypes {
+		r.generateModel(typeName)
+	}
+	for _, node := range r.definition.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+			typeName := node.NameString(r.definition)
+			if strings.HasPrefix(typeName, "__") {
+				continue
+			}
+			r.file.Commentf("%s is implemented by the models of the object types of the %s %s", strcase.ToCamel(typeName), r.abstractKindName(node.Kind), typeName)
+			r.file.Type().Id(strcase.ToCamel(typeName)).Interface(jen.Id("Is" + strcase.ToCamel(typeName)).Params())
+		case ast.NodeKindEnumTypeDefinition, ast.NodeKindInputObjectTypeDefinition:
+			typeName := node.NameString(r.definition)
+			if strings.HasPrefix(typeName, "__") {
+				continue
+			}
+			generateInputType(r.file, r.definition, typeName, r.inputGoType)
+		}
+	}
+
+	for _, typeName := range r.rootTypes {
+		r.generateResolver(typeName, true)
+	}
+	for _, typeName := range r.objectTypes {
+		if r.hasFieldsWithArguments(typeName) {
+			r.generateResolver(typeName, false)
+		}
+	}
+
+	r.generateResolvers()
+	r.generateDataSource()
+
+	return fmt.Fprintf(w, "%#v", r.file)
+}
+
+func (r *ResolverGen) abstractKindName(kind ast.NodeKind) string {
+	if kind == ast.NodeKindInterfaceTypeDefinition {
+		return "interface"
+	}
+	return "union"
+}
+
+// collectTypes collects the root operation types and the other object types in the order of the schema
+func (r *ResolverGen) collectTypes() {
+	rootTypeNames := map[string]bool{}
+	for _, typeName := range []ast.ByteSlice{r.definition.Index.QueryTypeName, r.definition.Index.MutationTypeName, r.definition.Index.SubscriptionTypeName} {
+		if len(typeName) != 0 {
+			rootTypeNames[string(typeName)] = true
+		}
+	}
+
+	for _, typeName := range []ast.ByteSlice{r.definition.Index.QueryTypeName, r.definition.Index.MutationTypeName} {
+		if len(typeName) == 0 {
+			continue
+		}
+		if _, ok := r.definition.Index.FirstNodeByNameBytes(typeName); ok {
+			r.rootTypes = append(r.rootTypes, string(typeName))
+		}
+	}
+
+	for _, node := range r.definition.RootNodes {
+		if node.Kind != ast.NodeKindObjectTypeDefinition {
+			continue
+		}
+		typeName := node.NameString(r.definition)
+		if rootTypeNames[typeName] || strings.HasPrefix(typeName, "__") {
+			continue
+		}
+		r.objectTypes = append(r.objectTypes, typeName)
+	}
+}
+
+// fields returns the field definitions of an object type without introspection fields
+func (r *ResolverGen) fields(typeName string) (fields []int) {
+	node, ok := r.definition.Index.FirstNodeByNameStr(typeName)
+	if !ok || node.Kind != ast.NodeKindObjectTypeDefinition {
+		return nil
+	}
+	for _, ref := range r.definition.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs {
+		if strings.HasPrefix(r.definition.FieldDefinitionNameString(ref), "__") {
+			continue
+		}
+		fields = append(fields, ref)
+	}
+	return fields
+}
+
+func (r *ResolverGen) hasArguments(fieldDefinition int) bool {
+	return r.definition.FieldDefinitions[fieldDefinition].HasArgumentsDefinitions &&
+		len(r.definition.FieldDefinitions[fieldDefinition].ArgumentsDefinition.Refs) != 0
+}
+
+func (r *ResolverGen) hasFieldsWithArguments(typeName string) bool {
+	for _, ref := range r.fields(typeName) {
+		if r.hasArguments(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateModel generates the model struct of an object type and the methods of its interfaces and unions
+func (r *ResolverGen) generateModel(typeName string) {
+	goName := strcase.ToCamel(typeName)
+	var structFields []jen.Code
+	for _, ref := range r.fields(typeName) {
+		if r.hasArguments(ref) {
+			continue
+		}
+		name := r.definition.FieldDefinitionNameString(ref)
+		structFields = append(structFields, jen.Id(strcase.ToCamel(name)).Add(r.outputGoType(r.definition.FieldDefinitions[ref].Type)).Tag(map[string]string{"json": name}))
+	}
+	r.file.Commentf("%s is the model of the object type %s", goName, typeName)
+	r.file.Type().Id(goName).Struct(structFields...)
+
+	node, _ := r.definition.Index.FirstNodeByNameStr(typeName)
+	for _, interfaceRef := range r.definition.ObjectTypeDefinitions[node.Ref].ImplementsInterfaces.Refs {
+		interfaceName := strcase.ToCamel(r.definition.TypeNameString(interfaceRef))
+		r.file.Func().Params(jen.Op("*").Id(goName)).Id("Is" + interfaceName).Params().Block()
+	}
+	for _, unionNode := range r.definition.RootNodes {
+		if unionNode.Kind != ast.NodeKindUnionTypeDefinition {
+			continue
+		}
+		memberTypeNames, _ := r.definition.UnionTypeDefinitionMemberTypeNames(unionNode.Ref)
+		for _, memberTypeName := range memberTypeNames {
+			if memberTypeName == typeName {
+				r.file.Func().Params(jen.Op("*").Id(goName)).Id("Is" + strcase.ToCamel(unionNode.NameString(r.definition))).Params().Block()
+			}
+		}
+	}
+}
+
+// generateResolver generates the resolver interface of a type and the args structs of its fields,
+// the resolver of root operation types has a method for every field, other resolvers for every field with arguments
+func (r *ResolverGen) generateResolver(typeName string, root bool) {
+	goName := strcase.ToCamel(typeName)
+	var methods []jen.Code
+	for _, ref := range r.fields(typeName) {
+		hasArguments := r.hasArguments(ref)
+		if !root && !hasArguments {
+			continue
+		}
+
+		fieldName := strcase.ToCamel(r.definition.FieldDefinitionNameString(ref))
+		params := []jen.Code{jen.Id("ctx").Qual("context", "Context")}
+		if !root {
+			params = append(params, jen.Id("obj").Op("*").Id(goName))
+		}
+		if hasArguments {
+			argsName := r.argsStructName(typeName, ref)
+			r.generateArgs(argsName, typeName, ref)
+			params = append(params, jen.Id("args").Id(argsName))
+		}
+		methods = append(methods, jen.Id(fieldName).Params(params...).Params(r.outputGoType(r.definition.FieldDefinitions[ref].Type), jen.Error()))
+	}
+
+	r.resolverTypes = append(r.resolverTypes, typeName)
+	r.file.Commentf("%sResolver resolves the fields of the type %s", goName, typeName)
+	r.file.Type().Id(goName + "Resolver").Interface(methods...)
+}
+
+func (r *ResolverGen) argsStructName(typeName string, fieldDefinition int) string {
+	return strcase.ToCamel(typeName) + strcase.ToCamel(r.definition.FieldDefinitionNameString(fieldDefinition)) + "Args"
+}
+
+func (r *ResolverGen) generateArgs(structName, typeName string, fieldDefinition int) {
+	arguments := r.definition.FieldDefinitions[fieldDefinition].ArgumentsDefinition.Refs
+	structFields := make([]jen.Code, 0, len(arguments))
+	for _, ref := range arguments {
+		name := r.definition.InputValueDefinitionNameString(ref)
+		typeRef := r.definition.InputValueDefinitions[ref].Type
+		structFields = append(structFields, jen.Id(strcase.ToCamel(name)).Add(goType(r.definition, typeRef, true, r.inputGoType)).Tag(inputFieldTag(r.definition, name, typeRef)))
+	}
+	r.file.Commentf("%s are the arguments of the field %s.%s", structName, typeName, r.definition.FieldDefinitionNameString(fieldDefinition))
+	r.file.Type().Id(structName).Struct(structFields...)
+}
+
+// generateResolvers generates the interface which returns the resolvers of all types
+func (r *ResolverGen) generateResolvers() {
+	methods := make([]jen.Code, 0, len(r.resolverTypes))
+	for _, typeName := range r.resolverTypes {
+		methods = append(methods, jen.Id(strcase.ToCamel(typeName)).Params().Id(strcase.ToCamel(typeName)+"Resolver"))
+	}
+	r.file.Comment("Resolvers returns the resolvers of the types of the schema")
+	if subscriptionFields := r.subscriptionFields(); len(subscriptionFields) != 0 {
+		r.file.Comment("")
+		r.file.Commentf("The fields of the subscription type %s are not supported and have no resolvers: %s",
+			r.definition.Index.SubscriptionTypeName, strings.Join(subscriptionFields, ", "))
+	}
+	r.file.Type().Id("Resolvers").Interface(methods...)
+}
+
+// subscriptionFields returns the names of the fields of the subscription type
+func (r *ResolverGen) subscriptionFields() (fieldNames []string) {
+	typeName := r.definition.Index.SubscriptionTypeName
+	if len(typeName) == 0 {
+		return nil
+	}
+	node, ok := r.definition.Index.FirstNodeByNameBytes(typeName)
+	if !ok || node.Kind != ast.NodeKindObjectTypeDefinition {
+		return nil
+	}
+	for _, ref := range r.definition.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs {
+		if name := r.definition.FieldDefinitionNameString(ref); !strings.HasPrefix(name, "__") {
+			fieldNames = append(fieldNames, name)
+		}
+	}
+	return fieldNames
+}
+
+// generateDataSource generates the adapter which mounts the Resolvers into the engine
+func (r *ResolverGen) generateDataSource() {
+	r.file.Comment("DataSource mounts Resolvers into the execution engine as an in-process data source,")
+	r.file.Comment("it implements resolve.DataSource and plan.PlannerFactory.")
+	r.file.Type().Id("DataSource").Struct(
+		jen.Id("resolvers").Id("Resolvers"),
+		jen.Id("source").Op("*").Qual(resolverDataSourcePackage, "Source"),
+	)
+
+	r.file.Func().Id("NewDataSource").Params(jen.Id("resolvers").Id("Resolvers")).Op("*").Id("DataSource").Block(
+		jen.Id("d").Op(":=").Op("&").Id("DataSource").Values(jen.Dict{jen.Id("resolvers"): jen.Id("resolvers")}),
+		jen.Id("d").Dot("source").Op("=").Qual(resolverDataSourcePackage, "NewSource").Call(jen.Id("d")),
+		jen.Return(jen.Id("d")),
+	)
+
+	r.file.Comment("Configuration returns the configuration to add the data source to an engine")
+	r.file.Func().Params(jen.Id("d").Op("*").Id("DataSource")).Id("Configuration").Params().Qual(planPackage, "DataSourceConfiguration").Block(
+		jen.Return(jen.Qual(planPackage, "DataSourceConfiguration").Values(jen.Dict{
+			jen.Id("RootNodes"):  r.typeFields(r.rootTypes),
+			jen.Id("ChildNodes"): r.typeFields(r.childNodeTypes()),
+			jen.Id("Factory"):    jen.Id("d"),
+			jen.Id("Custom"): jen.Qual(resolverDataSourcePackage, "ConfigJSON").Call(jen.Qual(resolverDataSourcePackage, "Configuration").Values(jen.Dict{
+				jen.Id("Schema"): jen.Id("Schema"),
+			})),
+		})),
+	)
+
+	r.file.Comment("Planner implements plan.PlannerFactory")
+	r.file.Func().Params(jen.Id("d").Op("*").Id("DataSource")).Id("Planner").Params(jen.Id("ctx").Qual("context", "Context")).Qual(planPackage, "DataSourcePlanner").Block(
+		jen.Return(jen.Qual(resolverDataSourcePackage, "NewPlanner").Call(jen.Id("d"))),
+	)
+
+	r.file.Comment("Load implements resolve.DataSource")
+	r.file.Func().Params(jen.Id("d").Op("*").Id("DataSource")).Id("Load").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("input").Index().Byte(),
+		jen.Id("w").Qual("io", "Writer"),
+	).Error().Block(
+		jen.Return(jen.Id("d").Dot("source").Dot("Load").Call(jen.Id("ctx"), jen.Id("input"), jen.Id("w"))),
+	)
+
+	r.generateResolveField()
+	r.generateTypeName()
+}
+
+// childNodeTypes are the object types and interfaces with fields
+func (r *ResolverGen) childNodeTypes() []string {
+	typeNames := append([]string{}, r.objectTypes...)
+	for _, node := range r.definition.RootNodes {
+		if node.Kind != ast.NodeKindInterfaceTypeDefinition {
+			continue
+		}
+		if typeName := node.NameString(r.definition); !strings.HasPrefix(typeName, "__") {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return typeNames
+}
+
+func (r *ResolverGen) typeFields(typeNames []string) jen.Code {
+	return jen.Index().Qual(planPackage, "TypeField").ValuesFunc(func(group *jen.Group) {
+		for _, typeName := range typeNames {
+			node, _ := r.definition.Index.FirstNodeByNameStr(typeName)
+			var fieldNames []jen.Code
+			for _, ref := range r.definition.NodeFieldDefinitions(node) {
+				if name := r.definition.FieldDefinitionNameString(ref); !strings.HasPrefix(name, "__") {
+					fieldNames = append(fieldNames, jen.Lit(name))
+				}
+			}
+			group.Values(jen.Dict{
+				jen.Id("TypeName"):   jen.Lit(typeName),
+				jen.Id("FieldNames"): jen.Index().String().Values(fieldNames...),
+			})
+		}
+	})
+}
+
+// generateResolveField generates the dispatch of fields to the models and the resolvers
+func (r *ResolverGen) generateResolveField() {
+	var cases []jen.Code
+	for _, typeName := range append(append([]string{}, r.rootTypes...), r.objectTypes...) {
+		root := r.isRootType(typeName)
+		var fieldCases []jen.Code
+		for _, ref := range r.fields(typeName) {
+			name := r.definition.FieldDefinitionNameString(ref)
+			goName := strcase.ToCamel(name)
+			hasArguments := r.hasArguments(ref)
+
+			if !root && !hasArguments {
+				fieldCases = append(fieldCases, jen.Case(jen.Lit(name)).Block(jen.Return(jen.Id("obj").Dot(goName), jen.Nil())))
+				continue
+			}
+
+			var body []jen.Code
+			args := []jen.Code{jen.Id("ctx")}
+			if !root {
+				args = append(args, jen.Id("obj"))
+			}
+			if hasArguments {
+				body = append(body,
+					jen.Var().Id("args").Id(r.argsStructName(typeName, ref)),
+					jen.If(jen.Err().Op(":=").Id("field").Dot("UnmarshalArguments").Call(jen.Op("&").Id("args")), jen.Err().Op("!=").Nil()).Block(
+						jen.Return(jen.Nil(), jen.Err()),
+					),
+				)
+				args = append(args, jen.Id("args"))
+			}
+			body = append(body, jen.Return(jen.Id("d").Dot("resolvers").Dot(strcase.ToCamel(typeName)).Call().Dot(goName).Call(args...)))
+			fieldCases = append(fieldCases, jen.Case(jen.Lit(name)).Block(body...))
+		}
+
+		var caseBody []jen.Code
+		if !root {
+			caseBody = append(caseBody, jen.Id("obj").Op(":=").Id("object").Assert(jen.Op("*").Id(strcase.ToCamel(typeName))))
+		}
+		caseBody = append(caseBody, jen.Switch(jen.Id("field").Dot("Name")).Block(fieldCases...))
+		cases = append(cases, jen.Case(jen.Lit(typeName)).Block(caseBody...))
+	}
+
+	r.file.Comment("ResolveField resolves the fields of the models with the resolvers, it implements resolver_datasource.Resolver")
+	r.file.Func().Params(jen.Id("d").Op("*").Id("DataSource")).Id("ResolveField").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("typeName").String(),
+		jen.Id("object").Interface(),
+		jen.Id("field").Op("*").Qual(resolverDataSourcePackage, "Field"),
+	).Params(jen.Interface(), jen.Error()).Block(
+		jen.Switch(jen.Id("typeName")).Block(cases...),
+		jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(jen.Lit("unknown field %s.%s"), jen.Id("typeName"), jen.Id("field").Dot("Name"))),
+	)
+}
+
+// generateTypeName generates the mapping of models to their object types
+func (r *ResolverGen) generateTypeName() {
+	cases := make([]jen.Code, 0, len(r.objectTypes))
+	for _, typeName := range r.objectTypes {
+		cases = append(cases, jen.Case(jen.Op("*").Id(strcase.ToCamel(typeName))).Block(jen.Return(jen.Lit(typeName), jen.Nil())))
+	}
+
+	r.file.Comment("TypeName returns the object type of a model, it implements resolver_datasource.Resolver")
+	r.file.Func().Params(jen.Id("d").Op("*").Id("DataSource")).Id("TypeName").Params(jen.Id("object").Interface()).Params(jen.String(), jen.Error()).Block(
+		jen.Switch(jen.Id("object").Assert(jen.Type())).Block(cases...),
+		jen.Return(jen.Lit(""), jen.Qual("fmt", "Errorf").Call(jen.Lit("unexpected model %T"), jen.Id("object"))),
+	)
+}
+
+func (r *ResolverGen) isRootType(typeName string) bool {
+	for _, rootType := range r.rootTypes {
+		if rootType == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// outputGoType renders the type of a field, models of object types are pointers, interfaces and unions are Go interfaces
+func (r *ResolverGen) outputGoType(typeRef int) *jen.Statement {
+	graphqlType := r.definition.Types[typeRef]
+	switch graphqlType.TypeKind {
+	case ast.TypeKindNonNull:
+		if r.definition.Types[graphqlType.OfType].TypeKind == ast.TypeKindNamed {
+			return jen.Add(r.namedOutputGoType(r.definition.TypeNameString(graphqlType.OfType), false))
+		}
+		return r.outputGoType(graphqlType.OfType)
+	case ast.TypeKindList:
+		return jen.Index().Add(r.outputGoType(graphqlType.OfType))
+	default:
+		return jen.Add(r.namedOutputGoType(r.definition.TypeNameString(typeRef), true))
+	}
+}
+
+func (r *ResolverGen) namedOutputGoType(typeName string, nullable bool) jen.Code {
+	node, _ := r.definition.Index.FirstNodeByNameStr(typeName)
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return jen.Op("*").Id(strcase.ToCamel(typeName))
+	case ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return jen.Id(strcase.ToCamel(typeName))
+	}
+	if nullable {
+		return jen.Op("*").Add(r.inputGoType(typeName))
+	}
+	return r.inputGoType(typeName)
+}
+
+// inputGoType returns the Go type of scalars, enums and input objects
+func (r *ResolverGen) inputGoType(typeName string) jen.Code {
+	node, ok := r.definition.Index.FirstNodeByNameStr(typeName)
+	if ok && (node.Kind == ast.NodeKindEnumTypeDefinition || node.Kind == ast.NodeKindInputObjectTypeDefinition) {
+		return jen.Id(strcase.ToCamel(typeName))
+	}
+	return scalarGoType(typeName, r.config.ScalarTypes)
+}
@@ -0,0 +1,32 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/internal/pkg/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/testing/goldie"
+)
+
+func TestResolverGen_Generate(t *testing.T) {
+	schema := unsafeparser.ParseGraphqlDocumentFile("./testdata/resolver/schema.graphql")
+
+	out := bytes.Buffer{}
+	_, err := NewResolverGen(&schema, ResolverConfig{
+		PackageName: "starwars",
+		ScalarTypes: map[string]string{"DateTime": "time.Time"},
+	}).Generate(&out)
+	require.NoError(t, err)
+
+	goldie.Assert(t, "Resolver", out.Bytes())
+	if t.Failed() {
+		fixture, err := os.ReadFile("./fixtures/Resolver.golden")
+		require.NoError(t, err)
+
+		assert.Equal(t, string(fixture), out.String())
+	}
+}
@@ -0,0 +1,507 @@
+// Package funcdatasource resolves fields in-process with Go functions registered per Type.field,
+// e.g. computed fields like formatting or currency conversion which don't justify a network round trip.
+package funcdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+// Input is the input of a Func
+type Input struct {
+	// Context is the context of the request, it's nil if the data source isn't loaded by the resolver
+	Context *resolve.Context
+	// Arguments are the arguments of the field as a JSON object, variables are already rendered
+	Arguments json.RawMessage
+	// Object contains the RequiredFields of the parent object, it's nil for fields without RequiredFields
+	Object json.RawMessage
+}
+
+// UnmarshalArguments decodes the arguments of the field into v, it's a no-op if the field has no arguments
+func (i *Input) UnmarshalArguments(v interface{}) error {
+	return unmarshalArguments(i.Arguments, v)
+}
+
+// BatchInput is the input of a BatchFunc
+type BatchInput struct {
+	// Context is the context of the request, it's nil if the data source isn't loaded by the resolver
+	Context *resolve.Context
+	// Arguments are the arguments of the field as a JSON object, they are the same for all objects
+	Arguments json.RawMessage
+	// Objects contain the RequiredFields of all parent objects the field is resolved for
+	Objects []json.RawMessage
+}
+
+// UnmarshalArguments decodes the arguments of the field into v, it's a no-op if the field has no arguments
+func (i *BatchInput) UnmarshalArguments(v interface{}) error {
+	return unmarshalArguments(i.Arguments, v)
+}
+
+func unmarshalArguments(arguments json.RawMessage, v interface{}) error {
+	if len(arguments) == 0 {
+		return nil
+	}
+	return json.Unmarshal(arguments, v)
+}
+
+// Func resolves a field, the returned value is encoded as JSON
+type Func func(ctx context.Context, input *Input) (interface{}, error)
+
+// BatchFunc resolves a field for all objects of a list at once,
+// it must return exactly one value per object in the order of the objects
+type BatchFunc func(ctx context.Context, input *BatchInput) ([]interface{}, error)
+
+// Field registers a function for the field FieldName of the type TypeName,
+// exactly one of Func and BatchFunc must be set
+type Field struct {
+	TypeName  string
+	FieldName string
+	// RequiredFields is a selection set of the fields of the parent object the function depends on, e.g. "price currency".
+	// The fields are added to the operation together with __typename and are resolved by the data source of the parent object first.
+	RequiredFields string
+	Func           Func
+	// BatchFunc is called once for all items of a list with the RequiredFields of every item,
+	// outside of lists or without RequiredFields it's called with a single object
+	BatchFunc BatchFunc
+}
+
+// Configuration describes the fields of a data source
+type Configuration struct {
+	Fields []FieldConfiguration `json:"fields"`
+}
+
+type FieldConfiguration struct {
+	TypeName       string `json:"type_name"`
+	FieldName      string `json:"field_name"`
+	RequiredFields string `json:"required_fields,omitempty"`
+	Batch          bool   `json:"batch,omitempty"`
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+type Factory struct {
+	fields []Field
+	source *Source
+}
+
+// NewFactory returns a factory for the fields, it returns an error if a field doesn't have exactly one of Func and BatchFunc
+func NewFactory(fields ...Field) (*Factory, error) {
+	source, err := NewSource(fields...)
+	if err != nil {
+		return nil, err
+	}
+	return &Factory{
+		fields: fields,
+		source: source,
+	}, nil
+}
+
+// DataSourceConfiguration returns the configuration of a data source which has all registered fields as root nodes.
+// Fields of the values returned by the functions must be added as ChildNodes if their type is composite.
+func (f *Factory) DataSourceConfiguration() plan.DataSourceConfiguration {
+	var (
+		rootNodes plan.TypeFields
+		requires  plan.FederationFieldConfigurations
+		config    Configuration
+	)
+
+	for _, field := range f.fields {
+		added := false
+		for i := range rootNodes {
+			if rootNodes[i].TypeName == field.TypeName {
+				rootNodes[i].FieldNames = append(rootNodes[i].FieldNames, field.FieldName)
+				added = true
+				break
+			}
+		}
+		if !added {
+			rootNodes = append(rootNodes, plan.TypeField{TypeName: field.TypeName, FieldNames: []string{field.FieldName}})
+		}
+
+		if field.RequiredFields != "" {
+			requires = append(requires, plan.FederationFieldConfiguration{
+				TypeName:     field.TypeName,
+				FieldName:    field.FieldName,
+				SelectionSet: field.RequiredFields,
+			})
+		}
+
+		config.Fields = append(config.Fields, FieldConfiguration{
+			TypeName:       field.TypeName,
+			FieldName:      field.FieldName,
+			RequiredFields: field.RequiredFields,
+			Batch:          field.BatchFunc != nil,
+		})
+	}
+
+	return plan.DataSourceConfiguration{
+		RootNodes: rootNodes,
+		Factory:   f,
+		Custom:    ConfigJSON(config),
+		FederationMetaData: plan.FederationMetaData{
+			Requires: requires,
+		},
+	}
+}
+
+func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return &Planner{
+		source: f.source,
+	}
+}
+
+type Planner struct {
+	visitor       *plan.Visitor
+	config        plan.DataSourceConfiguration
+	plannerConfig plan.DataSourcePlannerConfiguration
+	source        *Source
+	typeName      string
+	fieldName     string
+	responseKey   string
+	arguments     []byte
+	variables     resolve.Variables
+}
+
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(downstreamFieldRef int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+	}
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, dataSourcePlannerConfiguration plan.DataSourcePlannerConfiguration) error {
+	p.visitor = visitor
+	p.config = configuration
+	p.plannerConfig = dataSourcePlannerConfiguration
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	return nil
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.fieldName != "" {
+		// fields of the value returned by the function are selected from its JSON
+		return
+	}
+	typeName := p.visitor.Walker.EnclosingTypeDefinition.NameString(p.visitor.Definition)
+	fieldName := p.visitor.Operation.FieldNameString(ref)
+	if !p.config.HasRootNode(typeName, fieldName) {
+		// the field of the parent path of a nested planner
+		return
+	}
+	p.typeName = typeName
+	p.fieldName = fieldName
+	p.responseKey = p.visitor.Operation.FieldAliasOrNameString(ref)
+	p.arguments = p.renderArguments(ref)
+}
+
+// renderArguments renders the arguments of a field into a JSON object,
+// variables are rendered with context variables
+func (p *Planner) renderArguments(fieldRef int) []byte {
+	arguments := p.visitor.Operation.FieldArguments(fieldRef)
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	out := []byte{'{'}
+	for _, argRef := range arguments {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, p.visitor.Operation.ArgumentNameString(argRef))
+		out = append(out, ':')
+
+		value := p.visitor.Operation.ArgumentValue(argRef)
+		if value.Kind != ast.ValueKindVariable {
+			literal, err := p.visitor.Operation.ValueToJSON(value)
+			if err != nil {
+				p.visitor.Walker.StopWithInternalErr(err)
+				return nil
+			}
+			out = append(out, literal...)
+			continue
+		}
+
+		variableName := p.visitor.Operation.VariableValueNameBytes(value.Ref)
+		variableDefinition, exists := p.visitor.Operation.VariableDefinitionByNameAndOperation(p.visitor.Walker.Ancestors[0].Ref, variableName)
+		if !exists {
+			out = append(out, "null"...)
+			continue
+		}
+		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, p.visitor.Operation.VariableDefinitions[variableDefinition].Type, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
+		if err != nil {
+			p.visitor.Walker.StopWithInternalErr(err)
+			return nil
+		}
+		contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{string(variableName)},
+			Renderer: renderer,
+		})
+		out = append(out, contextVariableName...)
+	}
+	return append(out, '}')
+}
+
+// objectVariable renders the required fields of the parent objects,
+// the values are printed as they are resolved by the data source of the parent object
+func (p *Planner) objectVariable() (resolve.Variable, error) {
+	object := &resolve.Object{
+		Nullable: true,
+	}
+	for _, cfg := range p.plannerConfig.RequiredFields {
+		key, report := plan.RequiredFieldsFragment(cfg.TypeName, cfg.SelectionSet, false)
+		if report.HasErrors() {
+			return nil, report
+		}
+		selectionSet := key.FragmentDefinitions[0].SelectionSet
+		for _, fieldRef := range key.SelectionSetFieldSelections(selectionSet) {
+			name := key.FieldNameString(key.Selections[fieldRef].Ref)
+			if hasField(object, name) {
+				continue
+			}
+			object.Fields = append(object.Fields, &resolve.Field{
+				Name: []byte(name),
+				Value: &resolve.Scalar{
+					Path:     []string{name},
+					Nullable: true,
+				},
+			})
+		}
+	}
+	return resolve.NewResolvableObjectVariable(object), nil
+}
+
+func hasField(object *resolve.Object, name string) bool {
+	for _, field := range object.Fields {
+		if string(field.Name) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.fieldName == "" {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("func data source: no field planned"))
+		return resolve.FetchConfiguration{}
+	}
+
+	input := []byte(`{"type_name":`)
+	input = strconv.AppendQuote(input, p.typeName)
+	input = append(input, `,"field_name":`...)
+	input = strconv.AppendQuote(input, p.fieldName)
+	input = append(input, `,"response_key":`...)
+	input = strconv.AppendQuote(input, p.responseKey)
+	if p.arguments != nil {
+		input = append(input, `,"arguments":`...)
+		input = append(input, p.arguments...)
+	}
+
+	if !p.plannerConfig.HasRequiredFields() {
+		return resolve.FetchConfiguration{
+			Input:                         string(append(input, '}')),
+			Variables:                     p.variables,
+			DataSource:                    p.source,
+			DisallowSingleFlight:          true,
+			RequiresParallelListItemFetch: p.plannerConfig.PathType != plan.PlannerPathObject,
+			PostProcessing: resolve.PostProcessingConfiguration{
+				SelectResponseDataPath:   []string{"data"},
+				SelectResponseErrorsPath: []string{"errors"},
+			},
+		}
+	}
+
+	// the objects are rendered by an entity fetch, all items of a list are loaded with a single batch entity fetch
+	variable, err := p.objectVariable()
+	if err != nil {
+		p.visitor.Walker.StopWithInternalErr(err)
+		return resolve.FetchConfiguration{}
+	}
+	objects, _ := p.variables.AddVariable(variable)
+	input = append(input, `,"objects":[`...)
+	input = append(input, objects...)
+	input = append(input, `]}`...)
+
+	batch := p.plannerConfig.PathType != plan.PlannerPathObject
+	dataPath := []string{"data"}
+	if !batch {
+		dataPath = append(dataPath, "[0]")
+	}
+
+	return resolve.FetchConfiguration{
+		Input:                                 string(input),
+		Variables:                             p.variables,
+		DataSource:                            p.source,
+		DisallowSingleFlight:                  true,
+		RequiresSerialFetch:                   true,
+		RequiresEntityFetch:                   !batch,
+		RequiresEntityBatchFetch:              batch,
+		SetTemplateOutputToNullOnVariableNull: true,
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   dataPath,
+			SelectResponseErrorsPath: []string{"errors"},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	p.visitor.Walker.StopWithInternalErr(fmt.Errorf("func data source: subscriptions are not supported"))
+	return plan.SubscriptionConfiguration{}
+}
+
+// Source calls the function registered for the field of its input.
+// Without objects it responds with {"data":{"<response_key>":value}},
+// with objects it responds with {"data":[{"<response_key>":value},...]} in the order of the objects.
+// Errors of the functions are reported in "errors" and the field is null.
+type Source struct {
+	fields map[string]Field
+}
+
+// NewSource returns a source for the fields, it returns an error if a field doesn't have exactly one of Func and BatchFunc
+func NewSource(fields ...Field) (*Source, error) {
+	s := &Source{
+		fields: make(map[string]Field, len(fields)),
+	}
+	for _, field := range fields {
+		if (field.Func == nil) == (field.BatchFunc == nil) {
+			return nil, fmt.Errorf("func data source: %s.%s must have exactly one of Func and BatchFunc", field.TypeName, field.FieldName)
+		}
+		s.fields[field.TypeName+"."+field.FieldName] = field
+	}
+	return s, nil
+}
+
+type sourceInput struct {
+	TypeName    string            `json:"type_name"`
+	FieldName   string            `json:"field_name"`
+	ResponseKey string            `json:"response_key"`
+	Arguments   json.RawMessage   `json:"arguments,omitempty"`
+	Objects     []json.RawMessage `json:"objects,omitempty"`
+}
+
+type sourceError struct {
+	Message string `json:"message"`
+}
+
+type sourceResponse struct {
+	Data   interface{}   `json:"data"`
+	Errors []sourceError `json:"errors,omitempty"`
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) error {
+	var in sourceInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return fmt.Errorf("func data source: invalid input: %w", err)
+	}
+	field, ok := s.fields[in.TypeName+"."+in.FieldName]
+	if !ok {
+		return fmt.Errorf("func data source: no function registered for %s.%s", in.TypeName, in.FieldName)
+	}
+	requestContext, _ := resolve.GetContext(ctx)
+
+	var response sourceResponse
+	if in.Objects == nil {
+		values, err := s.call(ctx, requestContext, &field, in.Arguments, []json.RawMessage{nil})
+		if err != nil {
+			response.Errors = append(response.Errors, sourceError{Message: err.Error()})
+		}
+		response.Data = map[string]interface{}{in.ResponseKey: values[0]}
+	} else {
+		values, err := s.call(ctx, requestContext, &field, in.Arguments, in.Objects)
+		if err != nil {
+			response.Errors = append(response.Errors, sourceError{Message: err.Error()})
+		}
+		data := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			data = append(data, map[string]interface{}{in.ResponseKey: value})
+		}
+		response.Data = data
+	}
+
+	out, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// call resolves the field for all objects, values are nil if the function failed
+func (s *Source) call(ctx context.Context, requestContext *resolve.Context, field *Field, arguments json.RawMessage, objects []json.RawMessage) ([]interface{}, error) {
+	values := make([]interface{}, len(objects))
+
+	if field.BatchFunc != nil {
+		result, err := callBatchFunc(ctx, field, &BatchInput{
+			Context:   requestContext,
+			Arguments: arguments,
+			Objects:   objects,
+		})
+		if err != nil {
+			return values, err
+		}
+		if len(result) != len(objects) {
+			return values, fmt.Errorf("func data source: %s.%s returned %d values for %d objects", field.TypeName, field.FieldName, len(result), len(objects))
+		}
+		return result, nil
+	}
+
+	var firstErr error
+	for i := range objects {
+		value, err := callFunc(ctx, field, &Input{
+			Context:   requestContext,
+			Arguments: arguments,
+			Object:    objects[i],
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		values[i] = value
+	}
+	return values, firstErr
+}
+
+// callFunc calls the Func of the field, a panic of the function is returned as error
+func callFunc(ctx context.Context, field *Field, input *Input) (value interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = panicError(field, recovered)
+		}
+	}()
+	return field.Func(ctx, input)
+}
+
+// callBatchFunc calls the BatchFunc of the field, a panic of the function is returned as error
+func callBatchFunc(ctx context.Context, field *Field, input *BatchInput) (values []interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = panicError(field, recovered)
+		}
+	}()
+	return field.BatchFunc(ctx, input)
+}
+
+func panicError(field *Field, recovered interface{}) error {
+	return fmt.Errorf("func data source: %s.%s panicked: %v", field.TypeName, field.FieldName, recovered)
+}
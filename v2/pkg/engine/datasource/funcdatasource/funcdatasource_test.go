@@ -0,0 +1,243 @@
+package funcdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/staticdatasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const testSchema = `
+	schema { query: Query }
+
+	type Query {
+		products: [Product!]!
+		topProduct: Product!
+		greeting(name: String!): String!
+		locale: String
+		broken: String
+		brokenBatch: String
+	}
+
+	type Product {
+		name: String!
+		price: Float!
+		currency: String!
+		priceFormatted(decimals: Int): String!
+		priceInEUR: Float!
+	}
+`
+
+type product struct {
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+}
+
+func TestFuncDataSource(t *testing.T) {
+	schema, err := graphql.NewSchemaFromString(testSchema)
+	require.NoError(t, err)
+
+	var batchCalls int64
+	exchangeRates := map[string]float64{"EUR": 1, "USD": 0.5}
+
+	factory, err := NewFactory(
+		Field{
+			TypeName:       "Product",
+			FieldName:      "priceFormatted",
+			RequiredFields: "price currency",
+			Func: func(ctx context.Context, input *Input) (interface{}, error) {
+				var args struct {
+					Decimals *int `json:"decimals"`
+				}
+				if err := input.UnmarshalArguments(&args); err != nil {
+					return nil, err
+				}
+				decimals := 2
+				if args.Decimals != nil {
+					decimals = *args.Decimals
+				}
+				var p product
+				if err := json.Unmarshal(input.Object, &p); err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%.*f %s", decimals, p.Price, p.Currency), nil
+			},
+		},
+		Field{
+			TypeName:       "Product",
+			FieldName:      "priceInEUR",
+			RequiredFields: "price currency",
+			BatchFunc: func(ctx context.Context, input *BatchInput) ([]interface{}, error) {
+				atomic.AddInt64(&batchCalls, 1)
+				values := make([]interface{}, 0, len(input.Objects))
+				for _, object := range input.Objects {
+					var p product
+					if err := json.Unmarshal(object, &p); err != nil {
+						return nil, err
+					}
+					rate, ok := exchangeRates[p.Currency]
+					if !ok {
+						return nil, fmt.Errorf("unknown currency %s", p.Currency)
+					}
+					values = append(values, p.Price*rate)
+				}
+				return values, nil
+			},
+		},
+		Field{
+			TypeName:  "Query",
+			FieldName: "greeting",
+			Func: func(ctx context.Context, input *Input) (interface{}, error) {
+				var args struct {
+					Name string `json:"name"`
+				}
+				if err := input.UnmarshalArguments(&args); err != nil {
+					return nil, err
+				}
+				if args.Name == "" {
+					return nil, errors.New("name must not be empty")
+				}
+				return "Hello " + args.Name, nil
+			},
+		},
+		Field{
+			TypeName:  "Query",
+			FieldName: "locale",
+			Func: func(ctx context.Context, input *Input) (interface{}, error) {
+				return input.Context.Request.Header.Get("Accept-Language"), nil
+			},
+		},
+		Field{
+			TypeName:  "Query",
+			FieldName: "broken",
+			Func: func(ctx context.Context, input *Input) (interface{}, error) {
+				panic("boom")
+			},
+		},
+		Field{
+			TypeName:  "Query",
+			FieldName: "brokenBatch",
+			BatchFunc: func(ctx context.Context, input *BatchInput) ([]interface{}, error) {
+				panic("boom")
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"products"}},
+			},
+			ChildNodes: []plan.TypeField{
+				// required fields are planned together with __typename on the parent data source
+				{TypeName: "Product", FieldNames: []string{"name", "price", "currency", "__typename"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"products":[{"name":"Table","price":100,"currency":"EUR"},{"name":"Chair","price":30,"currency":"USD"}]}`,
+			}),
+		},
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"topProduct"}},
+			},
+			ChildNodes: []plan.TypeField{
+				// required fields are planned together with __typename on the parent data source
+				{TypeName: "Product", FieldNames: []string{"name", "price", "currency", "__typename"}},
+			},
+			Factory: &staticdatasource.Factory{},
+			Custom: staticdatasource.ConfigJSON(staticdatasource.Configuration{
+				Data: `{"topProduct":{"name":"Lamp","price":12.5,"currency":"USD"}}`,
+			}),
+		},
+		factory.DataSourceConfiguration(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query, variables string, header http.Header) string {
+		t.Helper()
+		request := graphql.Request{Query: query}
+		if variables != "" {
+			request.Variables = json.RawMessage(variables)
+		}
+		var options []graphql.ExecutionOptionsV2
+		if header != nil {
+			options = append(options, graphql.WithAdditionalHttpHeaders(header))
+		}
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &request, &resultWriter, options...))
+		return resultWriter.String()
+	}
+
+	t.Run("required fields of list items with arguments and aliases", func(t *testing.T) {
+		response := execute(t, `query ($decimals: Int) {
+			products {
+				name
+				priceFormatted
+				rounded: priceFormatted(decimals: $decimals)
+			}
+		}`, `{"decimals":0}`, nil)
+		assert.Equal(t, `{"data":{"products":[{"name":"Table","priceFormatted":"100.00 EUR","rounded":"100 EUR"},{"name":"Chair","priceFormatted":"30.00 USD","rounded":"30 USD"}]}}`, response)
+	})
+
+	t.Run("batch function is called once per list", func(t *testing.T) {
+		atomic.StoreInt64(&batchCalls, 0)
+		response := execute(t, `{ products { name priceInEUR } }`, "", nil)
+		assert.Equal(t, `{"data":{"products":[{"name":"Table","priceInEUR":100},{"name":"Chair","priceInEUR":15}]}}`, response)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&batchCalls))
+	})
+
+	t.Run("required fields of an object", func(t *testing.T) {
+		response := execute(t, `{ topProduct { priceFormatted(decimals: 1) priceInEUR } }`, "", nil)
+		assert.Equal(t, `{"data":{"topProduct":{"priceFormatted":"12.5 USD","priceInEUR":6.25}}}`, response)
+	})
+
+	t.Run("root fields", func(t *testing.T) {
+		response := execute(t, `query ($name: String!) { greeting(name: $name) locale }`, `{"name":"Gopher"}`, http.Header{"Accept-Language": []string{"de-DE"}})
+		assert.Equal(t, `{"data":{"greeting":"Hello Gopher","locale":"de-DE"}}`, response)
+	})
+
+	t.Run("function error", func(t *testing.T) {
+		response := execute(t, `{ greeting(name: "") }`, "", nil)
+		assert.Equal(t, `{"errors":[{"message":"name must not be empty"},{"message":"Cannot return null for non-nullable field Query.greeting.","path":["greeting"]}],"data":null}`, response)
+	})
+
+	t.Run("function panic", func(t *testing.T) {
+		response := execute(t, `{ broken brokenBatch }`, "", nil)
+		assert.Equal(t, `{"errors":[{"message":"func data source: Query.broken panicked: boom"},{"message":"func data source: Query.brokenBatch panicked: boom"}],"data":{"broken":null,"brokenBatch":null}}`, response)
+	})
+}
+
+func TestNewFactory(t *testing.T) {
+	_, err := NewFactory(Field{TypeName: "Query", FieldName: "greeting"})
+	assert.EqualError(t, err, "func data source: Query.greeting must have exactly one of Func and BatchFunc")
+
+	_, err = NewFactory(Field{
+		TypeName:  "Query",
+		FieldName: "greeting",
+		Func: func(ctx context.Context, input *Input) (interface{}, error) {
+			return "Hello", nil
+		},
+		BatchFunc: func(ctx context.Context, input *BatchInput) ([]interface{}, error) {
+			return []interface{}{"Hello"}, nil
+		},
+	})
+	assert.EqualError(t, err, "func data source: Query.greeting must have exactly one of Func and BatchFunc")
+}
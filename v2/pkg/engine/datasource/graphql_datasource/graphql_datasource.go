@@ -25,6 +25,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 )
@@ -225,7 +226,7 @@ func (p *Planner) addDirectiveToNode(directiveRef int, node ast.Node) {
 			variableType := p.visitor.Operation.VariableDefinitions[i].Type
 			typeName := p.visitor.Operation.ResolveTypeNameString(variableType)
 
-			renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, variableType)
+			renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, variableType, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
 			if err != nil {
 				continue
 			}
@@ -1083,7 +1084,7 @@ func (p *Planner) configureFieldArgumentSource(upstreamFieldRef, downstreamField
 	}
 
 	argumentType := p.visitor.Definition.InputValueDefinitionType(argumentDefinition)
-	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Definition, p.visitor.Definition, argumentType)
+	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Definition, p.visitor.Definition, argumentType, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
 	if err != nil {
 		return
 	}
@@ -1196,7 +1197,7 @@ func (p *Planner) addVariableDefinitionsRecursively(value ast.Value, sourcePath
 	contextVariable := &resolve.ContextVariable{
 		Path: append(sourcePath, variableNameStr),
 	}
-	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, variableDefinitionTypeRef)
+	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, variableDefinitionTypeRef, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
 	if err != nil {
 		return
 	}
@@ -1247,7 +1248,7 @@ func (p *Planner) configureObjectFieldSource(upstreamFieldRef, downstreamFieldRe
 	importedType := p.visitor.Importer.ImportTypeWithRename(argumentType, p.visitor.Definition, p.upstreamOperation, typeName)
 	p.upstreamOperation.AddVariableDefinitionToOperationDefinition(p.nodes[0].Ref, variableValue, importedType)
 
-	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Definition, p.visitor.Definition, argumentType)
+	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Definition, p.visitor.Definition, argumentType, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
 	if err != nil {
 		return
 	}
@@ -1639,6 +1640,8 @@ type Factory struct {
 	OnWsConnectionInitCallback *OnWsConnectionInitCallback
 	SubscriptionClient         *SubscriptionClient
 	Logger                     abstractlogger.Logger
+	// SubscriptionReconnect enables re-connecting and re-subscribing upstream graphql-transport-ws connections
+	SubscriptionReconnect *ReconnectOptions
 }
 
 func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
@@ -1650,6 +1653,9 @@ func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
 		if f.Logger != nil {
 			opts = append(opts, WithLogger(f.Logger))
 		}
+		if f.SubscriptionReconnect != nil {
+			opts = append(opts, WithReconnect(*f.SubscriptionReconnect))
+		}
 
 		f.SubscriptionClient = NewGraphQLSubscriptionClient(f.HTTPClient, f.StreamingClient, ctx, opts...)
 	} else if f.SubscriptionClient.engineCtx == nil {
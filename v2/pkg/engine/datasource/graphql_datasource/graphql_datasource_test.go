@@ -10223,3 +10223,107 @@ const petSchema = `
 		pets: [Pet!]!
 	}
 `
+
+func TestGraphQLDataSource_SubscriptionFilter(t *testing.T) {
+	factory := &Factory{
+		HTTPClient: http.DefaultClient,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("Subscription with filter", RunTest(`
+		type Subscription {
+			foo(bar: String): Int!
+ 		}
+`, `
+		subscription SubscriptionWithFilter {
+			foo(bar: "baz")
+		}
+	`, "SubscriptionWithFilter", &plan.SubscriptionResponsePlan{
+		Response: &resolve.GraphQLSubscription{
+			Trigger: resolve.GraphQLSubscriptionTrigger{
+				Input: []byte(`{"url":"wss://swapi.com/graphql","body":{"query":"subscription($a: String){foo(bar: $a)}","variables":{"a":$$0$$}}}`),
+				Variables: resolve.NewVariables(
+					&resolve.ContextVariable{
+						Path:     []string{"a"},
+						Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","null"]}`),
+					},
+				),
+				Source: &SubscriptionSource{
+					client: NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, ctx),
+				},
+				PostProcessing: DefaultPostProcessingConfiguration,
+			},
+			Filter: &resolve.SubscriptionFilter{
+				Not: &resolve.SubscriptionFilter{
+					In: &resolve.SubscriptionFieldFilter{
+						FieldPath: []string{"foo"},
+						Values: []resolve.InputTemplate{
+							{
+								Segments: []resolve.TemplateSegment{
+									{
+										SegmentType:        resolve.VariableSegmentType,
+										VariableKind:       resolve.ContextVariableKind,
+										VariableSourcePath: []string{"a"},
+										Renderer:           resolve.NewPlainVariableRendererWithValidation(`{"type":["string","null"]}`),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("foo"),
+							Value: &resolve.Integer{
+								Path:     []string{"foo"},
+								Nullable: false,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, plan.Configuration{
+		DataSources: []plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{
+						TypeName:   "Subscription",
+						FieldNames: []string{"foo"},
+					},
+				},
+				Custom: ConfigJson(Configuration{
+					Subscription: SubscriptionConfiguration{
+						URL: "wss://swapi.com/graphql",
+					},
+				}),
+				Factory: factory,
+			},
+		},
+		Fields: []plan.FieldConfiguration{
+			{
+				TypeName:  "Subscription",
+				FieldName: "foo",
+				Arguments: []plan.ArgumentConfiguration{
+					{
+						Name:       "bar",
+						SourceType: plan.FieldArgumentSource,
+					},
+				},
+				SubscriptionFilterCondition: &plan.SubscriptionFilterCondition{
+					Not: &plan.SubscriptionFilterCondition{
+						In: &plan.SubscriptionFieldCondition{
+							FieldPath: []string{"foo"},
+							Values:    []string{"{{ .arguments.bar }}"},
+						},
+					},
+				},
+			},
+		},
+		DisableResolveFieldPositions: true,
+	}))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"net/http"
 	"net/textproto"
 	"sync"
@@ -31,6 +32,7 @@ type SubscriptionClient struct {
 	handlersMu                 sync.Mutex
 	wsSubProtocol              string
 	onWsConnectionInitCallback *OnWsConnectionInitCallback
+	reconnect                  *ReconnectOptions
 
 	readTimeout time.Duration
 }
@@ -61,11 +63,61 @@ func WithOnWsConnectionInitCallback(callback *OnWsConnectionInitCallback) Option
 	}
 }
 
+// WithReconnect enables re-establishing graphql-transport-ws connections to the origin
+// when the underlying socket drops. All active subscriptions are re-subscribed on the new connection.
+func WithReconnect(reconnect ReconnectOptions) Options {
+	return func(options *opts) {
+		options.reconnect = &reconnect
+	}
+}
+
 type opts struct {
 	readTimeout                time.Duration
 	log                        abstractlogger.Logger
 	wsSubProtocol              string
 	onWsConnectionInitCallback *OnWsConnectionInitCallback
+	reconnect                  *ReconnectOptions
+}
+
+// ReconnectOptions configures how a dropped upstream WebSocket connection is re-established
+type ReconnectOptions struct {
+	// MaxAttempts is the maximum number of consecutive reconnection attempts, 0 means unlimited
+	MaxAttempts int
+	// InitialBackoff is the delay before the first reconnection attempt, defaults to 100ms
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay between attempts, defaults to 10s
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0-1) of the delay that is randomized to avoid thundering herds
+	Jitter float64
+	// NotifyGaps sends an error event to each subscriber after a successful reconnect
+	// so that clients know that events might have been missed
+	NotifyGaps bool
+}
+
+// backoff returns the delay before the given (zero based) reconnection attempt
+func (r *ReconnectOptions) backoff(attempt int) time.Duration {
+	initial, max := r.InitialBackoff, r.MaxBackoff
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+	delay := max
+	if attempt < 32 {
+		delay = initial << uint(attempt)
+		if delay <= 0 || delay > max {
+			delay = max
+		}
+	}
+	if r.Jitter > 0 {
+		jitter := r.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay
 }
 
 // GraphQLSubscriptionClientFactory abstracts the way of creating a new GraphQLSubscriptionClient.
@@ -102,6 +154,7 @@ func NewGraphQLSubscriptionClient(httpClient, streamingClient *http.Client, engi
 		},
 		wsSubProtocol:              op.wsSubProtocol,
 		onWsConnectionInitCallback: op.onWsConnectionInitCallback,
+		reconnect:                  op.reconnect,
 	}
 }
 
@@ -228,12 +281,35 @@ func (c *SubscriptionClient) generateHandlerIDHash(ctx *resolve.Context, options
 }
 
 func (c *SubscriptionClient) newWSConnectionHandler(reqCtx context.Context, options GraphQLSubscriptionOptions) (ConnectionHandler, error) {
+	conn, err := c.dial(reqCtx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.wsSubProtocol {
+	case ProtocolGraphQLWS:
+		return newGQLWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log), nil
+	case ProtocolGraphQLTWS:
+		handler := newGQLTWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log)
+		if c.reconnect != nil {
+			handler.enableReconnect(*c.reconnect, func(ctx context.Context) (*websocket.Conn, error) {
+				return c.dial(ctx, options)
+			})
+		}
+		return handler, nil
+	default:
+		return nil, fmt.Errorf("unknown protocol %s", conn.Subprotocol())
+	}
+}
+
+// dial opens a new WebSocket connection to the origin and performs the connection_init / connection_ack handshake
+func (c *SubscriptionClient) dial(ctx context.Context, options GraphQLSubscriptionOptions) (*websocket.Conn, error) {
 	subProtocols := []string{ProtocolGraphQLWS, ProtocolGraphQLTWS}
 	if c.wsSubProtocol != "" {
 		subProtocols = []string{c.wsSubProtocol}
 	}
 
-	conn, upgradeResponse, err := websocket.Dial(reqCtx, options.URL, &websocket.DialOptions{
+	conn, upgradeResponse, err := websocket.Dial(ctx, options.URL, &websocket.DialOptions{
 		HTTPClient:      c.httpClient,
 		HTTPHeader:      options.Header,
 		CompressionMode: websocket.CompressionDisabled,
@@ -249,13 +325,13 @@ func (c *SubscriptionClient) newWSConnectionHandler(reqCtx context.Context, opti
 		return nil, fmt.Errorf("upgrade unsuccessful")
 	}
 
-	connectionInitMessage, err := c.getConnectionInitMessage(reqCtx, options.URL, options.Header)
+	connectionInitMessage, err := c.getConnectionInitMessage(ctx, options.URL, options.Header)
 	if err != nil {
 		return nil, err
 	}
 
 	// init + ack
-	err = conn.Write(reqCtx, websocket.MessageText, connectionInitMessage)
+	err = conn.Write(ctx, websocket.MessageText, connectionInitMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -264,18 +340,11 @@ func (c *SubscriptionClient) newWSConnectionHandler(reqCtx context.Context, opti
 		c.wsSubProtocol = conn.Subprotocol()
 	}
 
-	if err := waitForAck(reqCtx, conn); err != nil {
+	if err := waitForAck(ctx, conn); err != nil {
 		return nil, err
 	}
 
-	switch c.wsSubProtocol {
-	case ProtocolGraphQLWS:
-		return newGQLWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log), nil
-	case ProtocolGraphQLTWS:
-		return newGQLTWSConnectionHandler(c.engineCtx, conn, c.readTimeout, c.log), nil
-	default:
-		return nil, fmt.Errorf("unknown protocol %s", conn.Subprotocol())
-	}
+	return conn, nil
 }
 
 func (c *SubscriptionClient) getConnectionInitMessage(ctx context.Context, url string, header http.Header) ([]byte, error) {
@@ -15,6 +15,7 @@ import (
 // gqlTWSConnectionHandler is responsible for handling a connection to an origin
 // it is responsible for managing all subscriptions using the underlying WebSocket connection
 // if all Subscriptions are complete or cancelled/unsubscribed the handler will terminate
+// if reconnect is enabled, a dropped connection is re-established and all subscriptions are re-subscribed
 type gqlTWSConnectionHandler struct {
 	conn               *websocket.Conn
 	ctx                context.Context
@@ -23,6 +24,8 @@ type gqlTWSConnectionHandler struct {
 	nextSubscriptionID int
 	subscriptions      map[string]Subscription
 	readTimeout        time.Duration
+	reconnect          *ReconnectOptions
+	dial               func(ctx context.Context) (*websocket.Conn, error)
 }
 
 func newGQLTWSConnectionHandler(ctx context.Context, conn *websocket.Conn, rt time.Duration, l log.Logger) *gqlTWSConnectionHandler {
@@ -37,6 +40,12 @@ func newGQLTWSConnectionHandler(ctx context.Context, conn *websocket.Conn, rt ti
 	}
 }
 
+// enableReconnect makes the handler re-establish the connection using dial when the socket drops
+func (h *gqlTWSConnectionHandler) enableReconnect(options ReconnectOptions, dial func(ctx context.Context) (*websocket.Conn, error)) {
+	h.reconnect = &options
+	h.dial = dial
+}
+
 func (h *gqlTWSConnectionHandler) SubscribeCH() chan<- Subscription {
 	return h.subscribeCh
 }
@@ -51,7 +60,7 @@ func (h *gqlTWSConnectionHandler) StartBlocking(sub Subscription) {
 	h.subscribe(sub)
 	dataCh := make(chan []byte)
 	errCh := make(chan error)
-	go h.readBlocking(readCtx, dataCh, errCh)
+	go h.readBlocking(readCtx, h.conn, dataCh, errCh)
 
 	for {
 		if h.ctx.Err() != nil || !h.hasActiveSubscriptions() {
@@ -65,6 +74,11 @@ func (h *gqlTWSConnectionHandler) StartBlocking(sub Subscription) {
 			h.subscribe(sub)
 		case err := <-errCh:
 			h.log.Error("gqlWSConnectionHandler.StartBlocking", log.Error(err))
+			if h.reconnect != nil && h.ctx.Err() == nil && h.reconnectAndResubscribe() {
+				// the previous read loop exited after reporting the error, start a new one for the new connection
+				go h.readBlocking(readCtx, h.conn, dataCh, errCh)
+				continue
+			}
 			h.broadcastErrorMessage(err)
 			return
 		case data := <-dataCh:
@@ -91,6 +105,89 @@ func (h *gqlTWSConnectionHandler) StartBlocking(sub Subscription) {
 	}
 }
 
+// reconnectAndResubscribe replaces the dropped connection with a new one, retrying with exponential backoff.
+// Subscriptions arriving while reconnecting are queued and subscribed together with all active subscriptions.
+// It returns false if the connection could not be re-established or no subscriptions are left.
+func (h *gqlTWSConnectionHandler) reconnectAndResubscribe() bool {
+	_ = h.conn.Close(websocket.StatusGoingAway, "")
+
+	for attempt := 0; h.reconnect.MaxAttempts == 0 || attempt < h.reconnect.MaxAttempts; attempt++ {
+		if !h.waitBeforeReconnect(h.reconnect.backoff(attempt)) {
+			return false
+		}
+		h.removeInactiveSubscriptions()
+		if len(h.subscriptions) == 0 {
+			return false
+		}
+
+		conn, err := h.dial(h.ctx)
+		if err != nil {
+			h.log.Error("gqlTWSConnectionHandler.reconnect",
+				log.Int("attempt", attempt+1),
+				log.Error(err),
+			)
+			continue
+		}
+		h.conn = conn
+
+		if !h.resubscribeAll() {
+			_ = h.conn.Close(websocket.StatusGoingAway, "")
+			continue
+		}
+		if h.reconnect.NotifyGaps {
+			h.broadcastMessage([]byte(reconnectedMessage))
+		}
+		return true
+	}
+
+	return false
+}
+
+// waitBeforeReconnect waits for the given delay while still accepting new subscriptions
+func (h *gqlTWSConnectionHandler) waitBeforeReconnect(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return false
+		case <-timer.C:
+			return true
+		case sub := <-h.subscribeCh:
+			h.nextSubscriptionID++
+			h.subscriptions[strconv.Itoa(h.nextSubscriptionID)] = sub
+		}
+	}
+}
+
+// resubscribeAll sends the subscribe message for every known subscription, keeping their ids
+func (h *gqlTWSConnectionHandler) resubscribeAll() bool {
+	for id, sub := range h.subscriptions {
+		graphQLBody, err := json.Marshal(sub.options.Body)
+		if err != nil {
+			h.log.Error("failed to marshal GraphQL body", log.Error(err))
+			continue
+		}
+		subscribeRequest := fmt.Sprintf(subscribeMessage, id, string(graphQLBody))
+		if err := h.conn.Write(h.ctx, websocket.MessageText, []byte(subscribeRequest)); err != nil {
+			h.log.Error("failed to write subscribe message", log.Error(err))
+			return false
+		}
+	}
+	return true
+}
+
+// removeInactiveSubscriptions drops subscriptions whose client went away without notifying the origin
+func (h *gqlTWSConnectionHandler) removeInactiveSubscriptions() {
+	for id, sub := range h.subscriptions {
+		if sub.ctx.Err() != nil {
+			close(sub.next)
+			delete(h.subscriptions, id)
+		}
+	}
+}
+
 func (h *gqlTWSConnectionHandler) unsubscribeAllAndCloseConn() {
 	for id := range h.subscriptions {
 		h.unsubscribe(id)
@@ -137,10 +234,14 @@ func (h *gqlTWSConnectionHandler) subscribe(sub Subscription) {
 
 func (h *gqlTWSConnectionHandler) broadcastErrorMessage(err error) {
 	errMsg := fmt.Sprintf(errorMessageTemplate, err)
+	h.broadcastMessage([]byte(errMsg))
+}
+
+func (h *gqlTWSConnectionHandler) broadcastMessage(msg []byte) {
 	for _, sub := range h.subscriptions {
 		ctx, cancel := context.WithTimeout(h.ctx, time.Second*5)
 		select {
-		case sub.next <- []byte(errMsg):
+		case sub.next <- msg:
 			cancel()
 			continue
 		case <-ctx.Done():
@@ -243,15 +344,17 @@ func (h *gqlTWSConnectionHandler) handleMessageTypeNext(data []byte) {
 // readBlocking is a dedicated loop running in a separate goroutine
 // because the library "nhooyr.io/websocket" doesn't allow reading with a context with Timeout
 // we'll block forever on reading until the context of the gqlTWSConnectionHandler stops
-func (h *gqlTWSConnectionHandler) readBlocking(ctx context.Context, dataCh chan []byte, errCh chan error) {
+func (h *gqlTWSConnectionHandler) readBlocking(ctx context.Context, conn *websocket.Conn, dataCh chan []byte, errCh chan error) {
 	for {
-		msgType, data, err := h.conn.Read(ctx)
+		msgType, data, err := conn.Read(ctx)
 		if ctx.Err() != nil {
-			errCh <- err
 			return
 		}
 		if err != nil {
-			errCh <- err
+			select {
+			case errCh <- err:
+			case <-ctx.Done():
+			}
 			return
 		}
 		if msgType != websocket.MessageText {
@@ -2,6 +2,8 @@ package graphql_datasource
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
 	"nhooyr.io/websocket"
 )
 
@@ -389,3 +392,106 @@ func TestWebsocketSubscriptionClient_GQLTWS_Upstream_Dies(t *testing.T) {
 		return len(client.handlers) == 0
 	}, time.Second, time.Millisecond, "client handlers not 0")
 }
+
+func TestWebsocketSubscriptionClient_GQLTWS_Reconnect(t *testing.T) {
+	engineCtx, engineCancel := context.WithCancel(context.Background())
+	defer engineCancel()
+
+	connections := atomic.NewInt64(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{ProtocolGraphQLTWS}})
+		assert.NoError(t, err)
+		connection := connections.Inc()
+
+		ctx := context.Background()
+		msgType, data, err := conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, websocket.MessageText, msgType)
+		assert.Equal(t, fmt.Sprintf(`{"type":"connection_init","payload":{"attempt":%d}}`, connection), string(data))
+
+		err = conn.Write(ctx, websocket.MessageText, []byte(`{"type":"connection_ack"}`))
+		assert.NoError(t, err)
+
+		msgType, data, err = conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, websocket.MessageText, msgType)
+		assert.Equal(t, `{"id":"1","type":"subscribe","payload":{"query":"subscription {messageAdded(roomName: \"room\"){text}}"}}`, string(data))
+
+		if connection == 1 {
+			err = conn.Write(ctx, websocket.MessageText, []byte(`{"id":"1","type":"next","payload":{"data":{"messageAdded":{"text":"first"}}}}`))
+			assert.NoError(t, err)
+			_ = conn.Close(websocket.StatusGoingAway, "deploy")
+			return
+		}
+
+		err = conn.Write(ctx, websocket.MessageText, []byte(`{"id":"1","type":"next","payload":{"data":{"messageAdded":{"text":"second"}}}}`))
+		assert.NoError(t, err)
+
+		msgType, data, err = conn.Read(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, websocket.MessageText, msgType)
+		assert.Equal(t, `{"id":"1","type":"complete"}`, string(data))
+		_, _, _ = conn.Read(ctx)
+	}))
+	defer server.Close()
+
+	initCalls := atomic.NewInt64(0)
+	var callback OnWsConnectionInitCallback = func(ctx context.Context, url string, header http.Header) (json.RawMessage, error) {
+		return json.RawMessage(fmt.Sprintf(`{"attempt":%d}`, initCalls.Inc())), nil
+	}
+
+	client := NewGraphQLSubscriptionClient(http.DefaultClient, http.DefaultClient, engineCtx,
+		WithReadTimeout(time.Millisecond),
+		WithLogger(logger()),
+		WithWSSubProtocol(ProtocolGraphQLTWS),
+		WithOnWsConnectionInitCallback(&callback),
+		WithReconnect(ReconnectOptions{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond * 10,
+			NotifyGaps:     true,
+		}),
+	)
+
+	ctx, clientCancel := context.WithCancel(context.Background())
+	defer clientCancel()
+
+	next := make(chan []byte)
+	err := client.Subscribe(resolve.NewContext(ctx), GraphQLSubscriptionOptions{
+		URL: server.URL,
+		Body: GraphQLBody{
+			Query: `subscription {messageAdded(roomName: "room"){text}}`,
+		},
+	}, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"data":{"messageAdded":{"text":"first"}}}`, string(<-next))
+	assert.Equal(t, reconnectedMessage, string(<-next))
+	assert.Equal(t, `{"data":{"messageAdded":{"text":"second"}}}`, string(<-next))
+	assert.Equal(t, int64(2), connections.Load())
+
+	clientCancel()
+	assert.Eventuallyf(t, func() bool {
+		client.handlersMu.Lock()
+		defer client.handlersMu.Unlock()
+		return len(client.handlers) == 0
+	}, time.Second, time.Millisecond, "client handlers not 0")
+}
+
+func TestReconnectOptionsBackoff(t *testing.T) {
+	options := ReconnectOptions{
+		InitialBackoff: time.Millisecond * 100,
+		MaxBackoff:     time.Second,
+	}
+	assert.Equal(t, time.Millisecond*100, options.backoff(0))
+	assert.Equal(t, time.Millisecond*200, options.backoff(1))
+	assert.Equal(t, time.Millisecond*800, options.backoff(3))
+	assert.Equal(t, time.Second, options.backoff(4))
+	assert.Equal(t, time.Second, options.backoff(100))
+
+	options.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := options.backoff(2)
+		assert.GreaterOrEqual(t, delay, time.Millisecond*200)
+		assert.LessOrEqual(t, delay, time.Millisecond*400)
+	}
+}
@@ -42,4 +42,5 @@ const (
 	internalError        = `{"errors":[{"message":"internal error"}]}`
 	connectionError      = `{"errors":[{"message":"connection error"}]}`
 	errorMessageTemplate = `{"errors":[{"message":"%s"}]}`
+	reconnectedMessage   = `{"errors":[{"message":"upstream connection was re-established, events might have been missed","extensions":{"code":"UPSTREAM_RECONNECTED"}}]}`
 )
@@ -0,0 +1,76 @@
+package grpc_datasource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Configuration maps the fields of the root types to RPCs
+type Configuration struct {
+	Fields []FieldConfiguration `json:"fields"`
+}
+
+// FieldConfiguration maps a field of a root type to an RPC.
+// The arguments of the field are the fields of the request message and the field returns the response message,
+// server-streaming RPCs must be mapped to fields of the subscription type.
+type FieldConfiguration struct {
+	TypeName  string `json:"type_name"`
+	FieldName string `json:"field_name"`
+	// Service is the full name of the service, e.g. "users.v1.UserService"
+	Service string `json:"service"`
+	// Method is the name of the RPC, e.g. "GetUser"
+	Method string `json:"method"`
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+func (c *Configuration) field(typeName, fieldName string) *FieldConfiguration {
+	for i := range c.Fields {
+		if c.Fields[i].TypeName == typeName && c.Fields[i].FieldName == fieldName {
+			return &c.Fields[i]
+		}
+	}
+	return nil
+}
+
+// LoadFileDescriptorSet loads a binary encoded FileDescriptorSet, e.g. the output of
+// protoc --include_imports --descriptor_set_out or buf build -o.
+// The set must contain all dependencies of its files.
+func LoadFileDescriptorSet(data []byte) (*protoregistry.Files, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("grpc: invalid file descriptor set: %w", err)
+	}
+	return protodesc.NewFiles(&set)
+}
+
+// findMethod looks up an RPC in the descriptors
+func findMethod(files *protoregistry.Files, service, method string) (protoreflect.MethodDescriptor, error) {
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("grpc: service %s not found: %w", service, err)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("grpc: %s is not a service", service)
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(method))
+	if methodDescriptor == nil {
+		return nil, fmt.Errorf("grpc: method %s of service %s not found", method, service)
+	}
+	return methodDescriptor, nil
+}
+
+// methodPath is the path of an RPC on the wire, e.g. "/users.v1.UserService/GetUser"
+func methodPath(service, method string) string {
+	return "/" + service + "/" + method
+}
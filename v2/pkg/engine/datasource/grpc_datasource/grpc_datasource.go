@@ -0,0 +1,269 @@
+// Package grpc_datasource resolves fields of the root types with RPCs of gRPC services.
+// The services are described by a FileDescriptorSet, requests and responses are translated from and to JSON
+// with dynamic messages, so no generated code is required. GenerateSchema generates the SDL and the configuration
+// of a data source from the descriptors, server-streaming RPCs are resolved as subscriptions.
+package grpc_datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+type Factory struct {
+	config Configuration
+	files  *protoregistry.Files
+	source *Source
+}
+
+// NewFactory creates the factory of a data source which calls the RPCs of config with conn,
+// files must contain the descriptors of all services of config
+func NewFactory(conn grpc.ClientConnInterface, files *protoregistry.Files, config Configuration) *Factory {
+	return &Factory{
+		config: config,
+		files:  files,
+		source: NewSource(conn, files),
+	}
+}
+
+// DataSourceConfiguration returns the configuration of a data source which has the fields of config as root nodes
+// and the fields of all messages returned by the RPCs as child nodes
+func (f *Factory) DataSourceConfiguration() plan.DataSourceConfiguration {
+	var (
+		rootNodes  plan.TypeFields
+		childNodes plan.TypeFields
+	)
+	seen := make(map[string]struct{})
+	for _, field := range f.config.Fields {
+		rootNodes = addTypeField(rootNodes, field.TypeName, field.FieldName)
+		method, err := findMethod(f.files, field.Service, field.Method)
+		if err != nil {
+			// the error is reported when the field is loaded
+			continue
+		}
+		childNodes = addMessageFields(childNodes, method.Output(), seen)
+	}
+
+	return plan.DataSourceConfiguration{
+		RootNodes:  rootNodes,
+		ChildNodes: childNodes,
+		Factory:    f,
+		Custom:     ConfigJSON(f.config),
+	}
+}
+
+func addTypeField(typeFields plan.TypeFields, typeName, fieldName string) plan.TypeFields {
+	for i := range typeFields {
+		if typeFields[i].TypeName == typeName {
+			typeFields[i].FieldNames = append(typeFields[i].FieldNames, fieldName)
+			return typeFields
+		}
+	}
+	return append(typeFields, plan.TypeField{TypeName: typeName, FieldNames: []string{fieldName}})
+}
+
+// addMessageFields adds the fields of the object type of a message and of all messages of its fields
+func addMessageFields(typeFields plan.TypeFields, message protoreflect.MessageDescriptor, seen map[string]struct{}) plan.TypeFields {
+	if isWellKnown(message) || len(fieldsOf(message)) == 0 {
+		return typeFields
+	}
+	name := typeName(message)
+	if _, ok := seen[name]; ok {
+		return typeFields
+	}
+	seen[name] = struct{}{}
+
+	fields := fieldsOf(message)
+	fieldNames := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		fieldNames = append(fieldNames, field.JSONName())
+	}
+	typeFields = append(typeFields, plan.TypeField{TypeName: name, FieldNames: append(fieldNames, typeNameFieldName)})
+
+	for _, field := range fields {
+		if field.Message() != nil {
+			typeFields = addMessageFields(typeFields, field.Message(), seen)
+		}
+	}
+	return typeFields
+}
+
+func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return &Planner{
+		source:       f.source,
+		rootFieldRef: ast.InvalidRef,
+	}
+}
+
+type Planner struct {
+	visitor      *plan.Visitor
+	config       Configuration
+	source       *Source
+	rootFieldRef int
+	field        *FieldConfiguration
+	responseKey  string
+	request      []byte
+	variables    resolve.Variables
+	// selections is the stack of the entered fields below the root field
+	selections []*fieldSelection
+	// disallowSingleFlight is set for mutations, their RPCs must be called once per field
+	disallowSingleFlight bool
+}
+
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(downstreamFieldRef int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+	}
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	p.visitor = visitor
+	p.rootFieldRef = ast.InvalidRef
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	visitor.Walker.RegisterLeaveFieldVisitor(p)
+	return json.Unmarshal(configuration.Custom, &p.config)
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.rootFieldRef != ast.InvalidRef {
+		// only the root field is mapped to an RPC, nested fields are selected from the response message
+		selection := &fieldSelection{
+			ResponseKey: p.visitor.Operation.FieldAliasOrNameString(ref),
+			FieldName:   p.visitor.Operation.FieldNameString(ref),
+		}
+		parent := p.selections[len(p.selections)-1]
+		parent.Fields = append(parent.Fields, selection)
+		p.selections = append(p.selections, selection)
+		return
+	}
+	typeName := p.visitor.Walker.EnclosingTypeDefinition.NameString(p.visitor.Definition)
+	fieldName := p.visitor.Operation.FieldNameString(ref)
+	p.field = p.config.field(typeName, fieldName)
+	if p.field == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("grpc: no RPC configured for %s.%s", typeName, fieldName))
+		return
+	}
+	p.rootFieldRef = ref
+	p.disallowSingleFlight = p.visitor.Operation.OperationDefinitions[p.visitor.Walker.Ancestors[0].Ref].OperationType == ast.OperationTypeMutation
+	p.responseKey = p.visitor.Operation.FieldAliasOrNameString(ref)
+	p.request = p.renderRequest(ref)
+	p.selections = append(p.selections[:0], &fieldSelection{})
+}
+
+func (p *Planner) LeaveField(ref int) {
+	if len(p.selections) > 1 {
+		p.selections = p.selections[:len(p.selections)-1]
+	}
+}
+
+// renderRequest renders the arguments of a field into the JSON of the request message,
+// variables are rendered with context variables
+func (p *Planner) renderRequest(fieldRef int) []byte {
+	out := []byte{'{'}
+	for _, argRef := range p.visitor.Operation.FieldArguments(fieldRef) {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, p.visitor.Operation.ArgumentNameString(argRef))
+		out = append(out, ':')
+
+		value := p.visitor.Operation.ArgumentValue(argRef)
+		if value.Kind != ast.ValueKindVariable {
+			literal, err := p.visitor.Operation.ValueToJSON(value)
+			if err != nil {
+				p.visitor.Walker.StopWithInternalErr(err)
+				return nil
+			}
+			out = append(out, literal...)
+			continue
+		}
+
+		variableName := p.visitor.Operation.VariableValueNameBytes(value.Ref)
+		variableDefinition, exists := p.visitor.Operation.VariableDefinitionByNameAndOperation(p.visitor.Walker.Ancestors[0].Ref, variableName)
+		if !exists {
+			out = append(out, "null"...)
+			continue
+		}
+		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, p.visitor.Operation.VariableDefinitions[variableDefinition].Type, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
+		if err != nil {
+			p.visitor.Walker.StopWithInternalErr(err)
+			return nil
+		}
+		contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{string(variableName)},
+			Renderer: renderer,
+		})
+		out = append(out, contextVariableName...)
+	}
+	return append(out, '}')
+}
+
+func (p *Planner) input() string {
+	input := []byte(`{"service":`)
+	input = strconv.AppendQuote(input, p.field.Service)
+	input = append(input, `,"method":`...)
+	input = strconv.AppendQuote(input, p.field.Method)
+	input = append(input, `,"response_key":`...)
+	input = strconv.AppendQuote(input, p.responseKey)
+	input = append(input, `,"request":`...)
+	input = append(input, p.request...)
+	if selection := p.selections[0]; len(selection.Fields) != 0 {
+		fields, _ := json.Marshal(selection.Fields)
+		input = append(input, `,"selection":`...)
+		input = append(input, fields...)
+	}
+	return string(append(input, '}'))
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.field == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("grpc: no RPC planned"))
+		return resolve.FetchConfiguration{}
+	}
+	return resolve.FetchConfiguration{
+		Input:                p.input(),
+		Variables:            p.variables,
+		DataSource:           p.source,
+		DisallowSingleFlight: p.disallowSingleFlight,
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   []string{"data"},
+			SelectResponseErrorsPath: []string{"errors"},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	if p.field == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("grpc: no RPC planned"))
+		return plan.SubscriptionConfiguration{}
+	}
+	return plan.SubscriptionConfiguration{
+		Input:      p.input(),
+		Variables:  p.variables,
+		DataSource: &SubscriptionSource{source: p.source},
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   []string{"data"},
+			SelectResponseErrorsPath: []string{"errors"},
+		},
+	}
+}
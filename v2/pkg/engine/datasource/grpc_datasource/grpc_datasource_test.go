@@ -0,0 +1,511 @@
+package grpc_datasource
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+// testFileDescriptorSet describes the following service:
+//
+//	package users.v1;
+//
+//	service UserService {
+//		rpc GetUser(GetUserRequest) returns (User);
+//		rpc ListUsers(ListUsersRequest) returns (ListUsersResponse);
+//		rpc CreateUser(CreateUserRequest) returns (User);
+//		rpc DeleteUsers(google.protobuf.Empty) returns (google.protobuf.Empty);
+//		rpc WatchUser(GetUserRequest) returns (stream User);
+//		rpc ImportUsers(stream CreateUserRequest) returns (ListUsersResponse);
+//	}
+//
+//	enum Role { ROLE_UNSPECIFIED = 0; ROLE_ADMIN = 1; ROLE_MEMBER = 2; }
+//
+//	message User {
+//		message Address { string city = 1; }
+//		string id = 1;
+//		string display_name = 2;
+//		Role role = 3;
+//		repeated string tags = 4;
+//		Address address = 5;
+//		int64 logins = 6;
+//		google.protobuf.Timestamp created_at = 7;
+//		map<string, string> labels = 8;
+//	}
+//
+//	message GetUserRequest { string id = 1; }
+//	message ListUsersRequest { Role role = 1; }
+//	message ListUsersResponse { repeated User users = 1; }
+//	message CreateUserRequest { string display_name = 1; Role role = 2; User.Address address = 3; }
+func testFileDescriptorSet() *descriptorpb.FileDescriptorSet {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	repeated := func(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+	method := func(name, input, output string, serverStreaming, clientStreaming bool) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(input),
+			OutputType:      proto.String(output),
+			ServerStreaming: proto.Bool(serverStreaming),
+			ClientStreaming: proto.Bool(clientStreaming),
+		}
+	}
+	const (
+		stringType  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		messageType = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		enumType    = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+	)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("users/v1/users.proto"),
+		Package:    proto.String("users.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto", "google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Role"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("ROLE_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("ROLE_ADMIN"), Number: proto.Int32(1)},
+				{Name: proto.String("ROLE_MEMBER"), Number: proto.Int32(2)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, stringType, ""),
+					field("display_name", 2, stringType, ""),
+					field("role", 3, enumType, ".users.v1.Role"),
+					repeated(field("tags", 4, stringType, "")),
+					field("address", 5, messageType, ".users.v1.User.Address"),
+					field("logins", 6, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+					field("created_at", 7, messageType, ".google.protobuf.Timestamp"),
+					repeated(field("labels", 8, messageType, ".users.v1.User.LabelsEntry")),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name:  proto.String("Address"),
+						Field: []*descriptorpb.FieldDescriptorProto{field("city", 1, stringType, "")},
+					},
+					{
+						Name:    proto.String("LabelsEntry"),
+						Field:   []*descriptorpb.FieldDescriptorProto{field("key", 1, stringType, ""), field("value", 2, stringType, "")},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+			{
+				Name:  proto.String("GetUserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("id", 1, stringType, "")},
+			},
+			{
+				Name:  proto.String("ListUsersRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("role", 1, enumType, ".users.v1.Role")},
+			},
+			{
+				Name:  proto.String("ListUsersResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{repeated(field("users", 1, messageType, ".users.v1.User"))},
+			},
+			{
+				Name: proto.String("CreateUserRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("display_name", 1, stringType, ""),
+					field("role", 2, enumType, ".users.v1.Role"),
+					field("address", 3, messageType, ".users.v1.User.Address"),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("GetUser", ".users.v1.GetUserRequest", ".users.v1.User", false, false),
+				method("ListUsers", ".users.v1.ListUsersRequest", ".users.v1.ListUsersResponse", false, false),
+				method("CreateUser", ".users.v1.CreateUserRequest", ".users.v1.User", false, false),
+				method("DeleteUsers", ".google.protobuf.Empty", ".google.protobuf.Empty", false, false),
+				method("WatchUser", ".users.v1.GetUserRequest", ".users.v1.User", true, false),
+				method("ImportUsers", ".users.v1.CreateUserRequest", ".users.v1.ListUsersResponse", false, true),
+			},
+		}},
+	}
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(emptypb.File_google_protobuf_empty_proto),
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			file,
+		},
+	}
+}
+
+func testFiles(t *testing.T) *protoregistry.Files {
+	data, err := proto.Marshal(testFileDescriptorSet())
+	require.NoError(t, err)
+	files, err := LoadFileDescriptorSet(data)
+	require.NoError(t, err)
+	return files
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, config, err := GenerateSchema(testFiles(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, `schema {
+  query: Query
+  mutation: Mutation
+  subscription: Subscription
+}
+
+type Query {
+  getUser(id: String): User
+  listUsers(role: Role): ListUsersResponse
+}
+
+type Mutation {
+  createUser(displayName: String, role: Role, address: User_AddressInput): User
+  deleteUsers: Boolean
+}
+
+type Subscription {
+  watchUser(id: String): User
+}
+
+type User {
+  id: String!
+  displayName: String!
+  role: Role!
+  tags: [String!]!
+  address: User_Address
+  logins: String!
+  createdAt: String
+}
+
+enum Role {
+  ROLE_UNSPECIFIED
+  ROLE_ADMIN
+  ROLE_MEMBER
+}
+
+type User_Address {
+  city: String!
+}
+
+type ListUsersResponse {
+  users: [User!]!
+}
+
+input User_AddressInput {
+  city: String
+}
+`, schema)
+
+	assert.Equal(t, Configuration{
+		Fields: []FieldConfiguration{
+			{TypeName: "Query", FieldName: "getUser", Service: "users.v1.UserService", Method: "GetUser"},
+			{TypeName: "Query", FieldName: "listUsers", Service: "users.v1.UserService", Method: "ListUsers"},
+			{TypeName: "Mutation", FieldName: "createUser", Service: "users.v1.UserService", Method: "CreateUser"},
+			{TypeName: "Mutation", FieldName: "deleteUsers", Service: "users.v1.UserService", Method: "DeleteUsers"},
+			{TypeName: "Subscription", FieldName: "watchUser", Service: "users.v1.UserService", Method: "WatchUser"},
+		},
+	}, config)
+
+	_, err = graphql.NewSchemaFromString(schema)
+	require.NoError(t, err)
+}
+
+func TestGenerateSchema_Messages(t *testing.T) {
+	generate := func(t *testing.T, files ...*descriptorpb.FileDescriptorProto) (string, error) {
+		t.Helper()
+		data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: files})
+		require.NoError(t, err)
+		registry, err := LoadFileDescriptorSet(data)
+		require.NoError(t, err)
+		schema, _, err := GenerateSchema(registry)
+		return schema, err
+	}
+	field := func(name string, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(1),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	t.Run("messages without fields are Boolean results and skipped as fields", func(t *testing.T) {
+		schema, err := generate(t, &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("items.proto"),
+			Package: proto.String("items"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("DeleteRequest"), Field: []*descriptorpb.FieldDescriptorProto{field("id", descriptorpb.FieldDescriptorProto_TYPE_STRING, "")}},
+				{Name: proto.String("DeleteResponse")},
+				{Name: proto.String("Options"), Field: []*descriptorpb.FieldDescriptorProto{field("response", descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".items.DeleteResponse")}},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("ItemService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("DeleteItem"), InputType: proto.String(".items.DeleteRequest"), OutputType: proto.String(".items.DeleteResponse")},
+					{Name: proto.String("UpdateItem"), InputType: proto.String(".items.Options"), OutputType: proto.String(".items.Options")},
+				},
+			}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `schema {
+  mutation: Mutation
+}
+
+type Mutation {
+  deleteItem(id: String): Boolean
+  updateItem: Boolean
+}
+`, schema)
+
+		_, err = graphql.NewSchemaFromString(schema)
+		require.NoError(t, err)
+	})
+
+	t.Run("messages with the same name in different packages", func(t *testing.T) {
+		user := func(pkg, fieldName string) *descriptorpb.FileDescriptorProto {
+			return &descriptorpb.FileDescriptorProto{
+				Name:        proto.String(pkg + ".proto"),
+				Package:     proto.String(pkg),
+				Syntax:      proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{field(fieldName, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")}}},
+			}
+		}
+		_, err := generate(t, user("a", "name"), user("b", "email"), &descriptorpb.FileDescriptorProto{
+			Name:       proto.String("accounts.proto"),
+			Package:    proto.String("accounts"),
+			Syntax:     proto.String("proto3"),
+			Dependency: []string{"a.proto", "b.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("AccountService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetUser"), InputType: proto.String(".a.User"), OutputType: proto.String(".a.User")},
+					{Name: proto.String("GetAccount"), InputType: proto.String(".b.User"), OutputType: proto.String(".b.User")},
+				},
+			}},
+		})
+		assert.EqualError(t, err, "grpc: type User is generated for a.User and b.User")
+	})
+}
+
+// userService implements the test service with dynamic messages
+type userService struct {
+	files *protoregistry.Files
+}
+
+func (s *userService) message(t *testing.T, name string, value string) *dynamicpb.Message {
+	descriptor, err := s.files.FindDescriptorByName(protoreflect.FullName(name))
+	require.NoError(t, err)
+	message := dynamicpb.NewMessage(descriptor.(protoreflect.MessageDescriptor))
+	require.NoError(t, protojson.Unmarshal([]byte(value), message))
+	return message
+}
+
+func (s *userService) serviceDesc(t *testing.T) *grpc.ServiceDesc {
+	users := map[string]string{
+		"1": `{"id":"1","displayName":"Ada","role":"ROLE_ADMIN","tags":["math"],"address":{"city":"London"},"logins":"9007199254740993","createdAt":"1843-07-01T00:00:00Z"}`,
+		"2": `{"id":"2","displayName":"Grace","role":"ROLE_MEMBER"}`,
+	}
+	request := func(dec func(interface{}) error, name string) (*dynamicpb.Message, error) {
+		message := s.message(t, name, `{}`)
+		return message, dec(message)
+	}
+	unary := func(name string, handler func(request *dynamicpb.Message) (interface{}, error)) grpc.MethodDesc {
+		return grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				descriptor, err := findMethod(s.files, "users.v1.UserService", name)
+				if err != nil {
+					return nil, err
+				}
+				in, err := request(dec, string(descriptor.Input().FullName()))
+				if err != nil {
+					return nil, err
+				}
+				return handler(in)
+			},
+		}
+	}
+	field := func(message *dynamicpb.Message, name string) protoreflect.Value {
+		return message.Get(message.Descriptor().Fields().ByName(protoreflect.Name(name)))
+	}
+
+	return &grpc.ServiceDesc{
+		ServiceName: "users.v1.UserService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			unary("GetUser", func(request *dynamicpb.Message) (interface{}, error) {
+				user, ok := users[field(request, "id").String()]
+				if !ok {
+					return nil, status.Error(codes.NotFound, "user not found")
+				}
+				return s.message(t, "users.v1.User", user), nil
+			}),
+			unary("ListUsers", func(request *dynamicpb.Message) (interface{}, error) {
+				if field(request, "role").Enum() == 1 {
+					return s.message(t, "users.v1.ListUsersResponse", `{"users":[`+users["1"]+`]}`), nil
+				}
+				return s.message(t, "users.v1.ListUsersResponse", `{"users":[`+users["1"]+`,`+users["2"]+`]}`), nil
+			}),
+			unary("CreateUser", func(request *dynamicpb.Message) (interface{}, error) {
+				user := s.message(t, "users.v1.User", `{"id":"3"}`)
+				for _, name := range []string{"display_name", "role", "address"} {
+					if request.Has(request.Descriptor().Fields().ByName(protoreflect.Name(name))) {
+						user.Set(user.Descriptor().Fields().ByName(protoreflect.Name(name)), field(request, name))
+					}
+				}
+				return user, nil
+			}),
+		},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "WatchUser",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				request := s.message(t, "users.v1.GetUserRequest", `{}`)
+				if err := stream.RecvMsg(request); err != nil {
+					return err
+				}
+				id := field(request, "id").String()
+				if _, ok := users[id]; !ok {
+					return status.Error(codes.NotFound, "user not found")
+				}
+				for _, name := range []string{"Ada", "Ada Lovelace"} {
+					user := s.message(t, "users.v1.User", users[id])
+					user.Set(user.Descriptor().Fields().ByName("display_name"), protoreflect.ValueOfString(name))
+					if err := stream.SendMsg(user); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}},
+	}
+}
+
+func TestGRPCDataSource(t *testing.T) {
+	files := testFiles(t)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	service := &userService{files: files}
+	server.RegisterService(service.serviceDesc(t), service)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	schemaSDL, config, err := GenerateSchema(files)
+	require.NoError(t, err)
+	schema, err := graphql.NewSchemaFromString(schemaSDL)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		NewFactory(conn, files, config).DataSourceConfiguration(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query, variables string) string {
+		t.Helper()
+		request := graphql.Request{Query: query}
+		if variables != "" {
+			request.Variables = json.RawMessage(variables)
+		}
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &request, &resultWriter))
+		return resultWriter.String()
+	}
+
+	t.Run("unary rpc with nested messages and aliases", func(t *testing.T) {
+		response := execute(t, `query ($id: String) {
+			ada: getUser(id: $id) { __typename id name: displayName role tags address { __typename city } logins createdAt }
+			grace: getUser(id: "2") { displayName tags address { city } createdAt }
+		}`, `{"id":"1"}`)
+		assert.Equal(t, `{"data":{"ada":{"__typename":"User","id":"1","name":"Ada","role":"ROLE_ADMIN","tags":["math"],"address":{"__typename":"User_Address","city":"London"},"logins":"9007199254740993","createdAt":"1843-07-01T00:00:00Z"},"grace":{"displayName":"Grace","tags":[],"address":null,"createdAt":null}}}`, response)
+	})
+
+	t.Run("enum argument and list of messages", func(t *testing.T) {
+		response := execute(t, `{ admins: listUsers(role: ROLE_ADMIN) { users { id } } all: listUsers { users { id } } }`, "")
+		assert.Equal(t, `{"data":{"admins":{"users":[{"id":"1"}]},"all":{"users":[{"id":"1"},{"id":"2"}]}}}`, response)
+	})
+
+	t.Run("mutation with input object", func(t *testing.T) {
+		response := execute(t, `mutation ($address: User_AddressInput) {
+			createUser(displayName: "Linus", role: ROLE_MEMBER, address: $address) { id displayName role address { city } }
+		}`, `{"address":{"city":"Helsinki"}}`)
+		assert.Equal(t, `{"data":{"createUser":{"id":"3","displayName":"Linus","role":"ROLE_MEMBER","address":{"city":"Helsinki"}}}}`, response)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		response := execute(t, `{ getUser(id: "42") { id } }`, "")
+		assert.Equal(t, `{"errors":[{"message":"user not found","extensions":{"code":"NotFound"}}],"data":{"getUser":null}}`, response)
+	})
+
+	t.Run("server-streaming rpc as subscription", func(t *testing.T) {
+		request := graphql.Request{Query: `subscription { watchUser(id: "1") { displayName } }`}
+		var messages []string
+		resultWriter := graphql.NewEngineResultWriter()
+		resultWriter.SetFlushCallback(func(data []byte) {
+			messages = append(messages, string(data))
+		})
+		require.NoError(t, engine.Execute(context.Background(), &request, &resultWriter))
+		assert.Equal(t, []string{
+			`{"data":{"watchUser":{"displayName":"Ada"}}}`,
+			`{"data":{"watchUser":{"displayName":"Ada Lovelace"}}}`,
+		}, messages)
+	})
+}
@@ -0,0 +1,382 @@
+package grpc_datasource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	queryTypeName        = "Query"
+	mutationTypeName     = "Mutation"
+	subscriptionTypeName = "Subscription"
+	typeNameFieldName    = "__typename"
+)
+
+// wellKnownScalars are the well-known types which are encoded as JSON scalars
+var wellKnownScalars = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp":   "String",
+	"google.protobuf.Duration":    "String",
+	"google.protobuf.FieldMask":   "String",
+	"google.protobuf.DoubleValue": "Float",
+	"google.protobuf.FloatValue":  "Float",
+	"google.protobuf.Int32Value":  "Int",
+	"google.protobuf.UInt32Value": "Float",
+	"google.protobuf.Int64Value":  "String",
+	"google.protobuf.UInt64Value": "String",
+	"google.protobuf.BoolValue":   "Boolean",
+	"google.protobuf.StringValue": "String",
+	"google.protobuf.BytesValue":  "String",
+}
+
+const emptyMessageName protoreflect.FullName = "google.protobuf.Empty"
+
+// GenerateSchema generates the SDL of the RPCs of services and the configuration which maps the root fields to them,
+// all services of files are generated if no service is given.
+//
+// Unary RPCs are fields of the query type if they are marked with idempotency_level = NO_SIDE_EFFECTS or their
+// name starts with Get or List, otherwise they are fields of the mutation type. Server-streaming RPCs are fields of
+// the subscription type, client-streaming RPCs are skipped. The field names are the method names in lowerCamelCase,
+// the arguments are the fields of the request message and the fields return the response message, RPCs returning
+// google.protobuf.Empty or another message without fields in the schema return Boolean.
+//
+// Messages are object types and input types with the suffix Input, nested messages and enums are prefixed with the
+// names of their parents, e.g. User_Address. Field names are the JSON names of the protobuf fields.
+// 64-bit integers are Strings as they are encoded as strings in JSON, unsigned 32-bit integers are Floats.
+// Map fields, well-known types which aren't encoded as JSON scalars, e.g. google.protobuf.Any, and messages without
+// fields in the schema are skipped. Messages and enums of different packages with the same name are an error.
+func GenerateSchema(files *protoregistry.Files, services ...string) (schema string, config Configuration, err error) {
+	if len(services) == 0 {
+		files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			for i := 0; i < file.Services().Len(); i++ {
+				services = append(services, string(file.Services().Get(i).FullName()))
+			}
+			return true
+		})
+		sort.Strings(services)
+	}
+
+	g := &schemaGenerator{
+		rootFields: make(map[string][]string),
+		fieldNames: make(map[string]string),
+		seen:       make(map[string]protoreflect.FullName),
+	}
+	for _, service := range services {
+		if err := g.service(files, service); err != nil {
+			return "", Configuration{}, err
+		}
+	}
+	if g.err != nil {
+		return "", Configuration{}, g.err
+	}
+	return g.print(), g.config, nil
+}
+
+type schemaGenerator struct {
+	config     Configuration
+	rootFields map[string][]string
+	// fieldNames are the RPCs of the generated root fields
+	fieldNames  map[string]string
+	definitions []string
+	// seen are the generated types with the messages or enums they are generated for
+	seen map[string]protoreflect.FullName
+	// err is the first name collision of generated types
+	err error
+}
+
+func (g *schemaGenerator) service(files *protoregistry.Files, service string) error {
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return fmt.Errorf("grpc: service %s not found: %w", service, err)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return fmt.Errorf("grpc: %s is not a service", service)
+	}
+
+	for i := 0; i < serviceDescriptor.Methods().Len(); i++ {
+		method := serviceDescriptor.Methods().Get(i)
+		typeName, ok := rootTypeName(method)
+		if !ok {
+			continue
+		}
+		fieldName := lowerCamelCase(string(method.Name()))
+		key := typeName + "." + fieldName
+		if rpc, exists := g.fieldNames[key]; exists {
+			return fmt.Errorf("grpc: %s is generated for %s and %s", key, rpc, method.FullName())
+		}
+		g.fieldNames[key] = string(method.FullName())
+
+		g.rootFields[typeName] = append(g.rootFields[typeName], g.rootField(fieldName, method))
+		g.config.Fields = append(g.config.Fields, FieldConfiguration{
+			TypeName:  typeName,
+			FieldName: fieldName,
+			Service:   service,
+			Method:    string(method.Name()),
+		})
+	}
+	return nil
+}
+
+// rootTypeName returns the root type of the field of an RPC, client-streaming RPCs aren't supported
+func rootTypeName(method protoreflect.MethodDescriptor) (string, bool) {
+	switch {
+	case method.IsStreamingClient():
+		return "", false
+	case method.IsStreamingServer():
+		return subscriptionTypeName, true
+	}
+	if options, ok := method.Options().(*descriptorpb.MethodOptions); ok && options.GetIdempotencyLevel() == descriptorpb.MethodOptions_NO_SIDE_EFFECTS {
+		return queryTypeName, true
+	}
+	name := string(method.Name())
+	if hasVerbPrefix(name, "Get") || hasVerbPrefix(name, "List") {
+		return queryTypeName, true
+	}
+	return mutationTypeName, true
+}
+
+func hasVerbPrefix(name, verb string) bool {
+	if !strings.HasPrefix(name, verb) {
+		return false
+	}
+	rest := name[len(verb):]
+	return rest == "" || unicode.IsUpper(rune(rest[0]))
+}
+
+func (g *schemaGenerator) rootField(fieldName string, method protoreflect.MethodDescriptor) string {
+	var arguments []string
+	for _, field := range fieldsOf(method.Input()) {
+		arguments = append(arguments, field.JSONName()+": "+g.inputType(field))
+	}
+
+	returnType := "Boolean"
+	if len(fieldsOf(method.Output())) != 0 {
+		returnType = g.object(method.Output())
+	}
+
+	if len(arguments) == 0 {
+		return fieldName + ": " + returnType
+	}
+	return fieldName + "(" + strings.Join(arguments, ", ") + "): " + returnType
+}
+
+// fieldsOf returns the fields of a message which are part of the schema
+func fieldsOf(message protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	return schemaFields(message, map[protoreflect.FullName]struct{}{})
+}
+
+// schemaFields returns the fields of a message which are part of the schema, fields of messages without fields in the
+// schema, e.g. google.protobuf.Empty, are skipped, visiting are the messages of the enclosing fields which are treated as having fields
+func schemaFields(message protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]struct{}) []protoreflect.FieldDescriptor {
+	visiting[message.FullName()] = struct{}{}
+	defer delete(visiting, message.FullName())
+
+	fields := make([]protoreflect.FieldDescriptor, 0, message.Fields().Len())
+	for i := 0; i < message.Fields().Len(); i++ {
+		field := message.Fields().Get(i)
+		if field.IsMap() {
+			continue
+		}
+		if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			if _, ok := wellKnownScalars[field.Message().FullName()]; !ok {
+				if isWellKnown(field.Message()) {
+					continue
+				}
+				if _, ok := visiting[field.Message().FullName()]; !ok && len(schemaFields(field.Message(), visiting)) == 0 {
+					continue
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func isWellKnown(message protoreflect.MessageDescriptor) bool {
+	return message.ParentFile().Package() == "google.protobuf"
+}
+
+// declare returns whether the type with the name has to be generated for the message or enum,
+// a type which is already generated for another message or enum is recorded as error
+func (g *schemaGenerator) declare(name string, descriptor protoreflect.Descriptor) bool {
+	fullName, ok := g.seen[name]
+	if !ok {
+		g.seen[name] = descriptor.FullName()
+		return true
+	}
+	if fullName != descriptor.FullName() && g.err == nil {
+		g.err = fmt.Errorf("grpc: type %s is generated for %s and %s", name, fullName, descriptor.FullName())
+	}
+	return false
+}
+
+// object renders the object type of a message and returns its name
+func (g *schemaGenerator) object(message protoreflect.MessageDescriptor) string {
+	name := typeName(message)
+	if !g.declare(name, message) {
+		return name
+	}
+	// the definition is added before the definitions of the types of its fields
+	index := len(g.definitions)
+	g.definitions = append(g.definitions, "")
+
+	var b strings.Builder
+	b.WriteString("type " + name + " {\n")
+	for _, field := range fieldsOf(message) {
+		b.WriteString("  " + field.JSONName() + ": " + g.outputType(field) + "\n")
+	}
+	b.WriteString("}")
+	g.definitions[index] = b.String()
+	return name
+}
+
+// input renders the input type of a message and returns its name
+func (g *schemaGenerator) input(message protoreflect.MessageDescriptor) string {
+	name := typeName(message) + "Input"
+	if !g.declare(name, message) {
+		return name
+	}
+	// the definition is added before the definitions of the types of its fields
+	index := len(g.definitions)
+	g.definitions = append(g.definitions, "")
+
+	var b strings.Builder
+	b.WriteString("input " + name + " {\n")
+	for _, field := range fieldsOf(message) {
+		b.WriteString("  " + field.JSONName() + ": " + g.inputType(field) + "\n")
+	}
+	b.WriteString("}")
+	g.definitions[index] = b.String()
+	return name
+}
+
+func (g *schemaGenerator) enum(enum protoreflect.EnumDescriptor) string {
+	name := typeName(enum)
+	if !g.declare(name, enum) {
+		return name
+	}
+	// the definition is added before the definitions of the types of its fields
+	index := len(g.definitions)
+	g.definitions = append(g.definitions, "")
+
+	var b strings.Builder
+	b.WriteString("enum " + name + " {\n")
+	for i := 0; i < enum.Values().Len(); i++ {
+		b.WriteString("  " + string(enum.Values().Get(i).Name()) + "\n")
+	}
+	b.WriteString("}")
+	g.definitions[index] = b.String()
+	return name
+}
+
+// outputType returns the type of the field of an object type.
+// Lists and fields without presence are non-null because they are always encoded.
+func (g *schemaGenerator) outputType(field protoreflect.FieldDescriptor) string {
+	var name string
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if scalar, ok := wellKnownScalars[field.Message().FullName()]; ok {
+			name = scalar
+		} else {
+			name = g.object(field.Message())
+		}
+	case protoreflect.EnumKind:
+		name = g.enum(field.Enum())
+	default:
+		name = scalarType(field.Kind())
+	}
+
+	if field.IsList() {
+		return "[" + name + "!]!"
+	}
+	if field.HasPresence() {
+		return name
+	}
+	return name + "!"
+}
+
+// inputType returns the type of an argument or the field of an input type,
+// all of them are optional as unset fields have default values
+func (g *schemaGenerator) inputType(field protoreflect.FieldDescriptor) string {
+	var name string
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if scalar, ok := wellKnownScalars[field.Message().FullName()]; ok {
+			name = scalar
+		} else {
+			name = g.input(field.Message())
+		}
+	case protoreflect.EnumKind:
+		name = g.enum(field.Enum())
+	default:
+		name = scalarType(field.Kind())
+	}
+
+	if field.IsList() {
+		return "[" + name + "!]"
+	}
+	return name
+}
+
+func scalarType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "Boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "Int"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "Float"
+	default:
+		// 64-bit integers, strings and bytes
+		return "String"
+	}
+}
+
+// typeName returns the name of the type of a message or enum, it's the name without the package
+// where the names of parent messages are joined with underscores
+func typeName(descriptor protoreflect.Descriptor) string {
+	name := strings.TrimPrefix(string(descriptor.FullName()), string(descriptor.ParentFile().Package())+".")
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+func lowerCamelCase(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func (g *schemaGenerator) print() string {
+	var b strings.Builder
+	b.WriteString("schema {\n")
+	for _, typeName := range []string{queryTypeName, mutationTypeName, subscriptionTypeName} {
+		if len(g.rootFields[typeName]) != 0 {
+			b.WriteString("  " + strings.ToLower(typeName) + ": " + typeName + "\n")
+		}
+	}
+	b.WriteString("}\n")
+
+	for _, typeName := range []string{queryTypeName, mutationTypeName, subscriptionTypeName} {
+		if len(g.rootFields[typeName]) == 0 {
+			continue
+		}
+		b.WriteString("\ntype " + typeName + " {\n")
+		for _, field := range g.rootFields[typeName] {
+			b.WriteString("  " + field + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, definition := range g.definitions {
+		b.WriteString("\n" + definition + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,248 @@
+package grpc_datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// Source calls the unary RPC of its input with the request message decoded from the JSON of the arguments.
+// It responds with {"data":{"<response_key>":message}} where message contains the selected fields of the response message
+// with their response keys, unset fields are included with their default values.
+// Failed RPCs are reported in "errors" with the status code as extension.
+type Source struct {
+	conn  grpc.ClientConnInterface
+	files *protoregistry.Files
+}
+
+func NewSource(conn grpc.ClientConnInterface, files *protoregistry.Files) *Source {
+	return &Source{
+		conn:  conn,
+		files: files,
+	}
+}
+
+type sourceInput struct {
+	Service     string          `json:"service"`
+	Method      string          `json:"method"`
+	ResponseKey string          `json:"response_key"`
+	Request     json.RawMessage `json:"request"`
+	// Selection are the fields selected from the response message, the response contains all fields without selection
+	Selection []*fieldSelection `json:"selection,omitempty"`
+}
+
+// fieldSelection selects a field of a message by its JSON name, it's written with the response key of the field
+type fieldSelection struct {
+	ResponseKey string            `json:"response_key"`
+	FieldName   string            `json:"field_name"`
+	Fields      []*fieldSelection `json:"fields,omitempty"`
+}
+
+type sourceError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type sourceResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []sourceError          `json:"errors,omitempty"`
+}
+
+var marshalOptions = protojson.MarshalOptions{
+	EmitUnpopulated: true,
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) error {
+	in, method, request, err := s.request(input)
+	if err != nil {
+		return err
+	}
+
+	response := dynamicpb.NewMessage(method.Output())
+	if err := s.conn.Invoke(ctx, methodPath(in.Service, in.Method), request, response); err != nil {
+		return s.writeError(w, in.ResponseKey, err)
+	}
+	return s.writeResponse(w, in, response)
+}
+
+// request decodes the input and the request message of the RPC
+func (s *Source) request(input []byte) (*sourceInput, protoreflect.MethodDescriptor, *dynamicpb.Message, error) {
+	var in sourceInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil, nil, nil, fmt.Errorf("grpc: invalid input: %w", err)
+	}
+	method, err := findMethod(s.files, in.Service, in.Method)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	request := dynamicpb.NewMessage(method.Input())
+	if len(in.Request) != 0 {
+		if err := protojson.Unmarshal(in.Request, request); err != nil {
+			return nil, nil, nil, fmt.Errorf("grpc: invalid request of %s.%s: %w", in.Service, in.Method, err)
+		}
+	}
+	return &in, method, request, nil
+}
+
+func (s *Source) writeResponse(w io.Writer, in *sourceInput, message *dynamicpb.Message) error {
+	value, err := messageValue(message)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(sourceResponse{
+		Data: map[string]interface{}{in.ResponseKey: selectFields(value, in.Selection)},
+	})
+}
+
+func (s *Source) writeError(w io.Writer, responseKey string, err error) error {
+	st := status.Convert(err)
+	return json.NewEncoder(w).Encode(sourceResponse{
+		Data: map[string]interface{}{responseKey: nil},
+		Errors: []sourceError{{
+			Message:    st.Message(),
+			Extensions: map[string]interface{}{"code": st.Code().String()},
+		}},
+	})
+}
+
+// messageValue returns the JSON value of a message, messages without fields in the schema,
+// e.g. google.protobuf.Empty, are true
+func messageValue(message *dynamicpb.Message) (interface{}, error) {
+	descriptor := message.Descriptor()
+	if len(fieldsOf(descriptor)) == 0 {
+		return true, nil
+	}
+	data, err := marshalOptions.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	addTypeNames(value, descriptor)
+	return value, nil
+}
+
+// addTypeNames adds __typename to the JSON objects of a message and all messages of its fields
+func addTypeNames(value interface{}, message protoreflect.MessageDescriptor) {
+	object, ok := value.(map[string]interface{})
+	if !ok || isWellKnown(message) {
+		return
+	}
+	object[typeNameFieldName] = typeName(message)
+	for _, field := range fieldsOf(message) {
+		if field.Message() == nil || field.IsMap() {
+			continue
+		}
+		if items, ok := object[field.JSONName()].([]interface{}); ok {
+			for _, item := range items {
+				addTypeNames(item, field.Message())
+			}
+			continue
+		}
+		addTypeNames(object[field.JSONName()], field.Message())
+	}
+}
+
+// selectFields returns the selected fields of the JSON value of a message with their response keys
+func selectFields(value interface{}, selection []*fieldSelection) interface{} {
+	if len(selection) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(selection))
+		for _, field := range selection {
+			object[field.ResponseKey] = selectFields(v[field.FieldName], field.Fields)
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i := range v {
+			items[i] = selectFields(v[i], selection)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// SubscriptionSource calls the server-streaming RPC of its input,
+// every response message is sent in the same format as the responses of Source
+type SubscriptionSource struct {
+	source *Source
+}
+
+func NewSubscriptionSource(conn grpc.ClientConnInterface, files *protoregistry.Files) *SubscriptionSource {
+	return &SubscriptionSource{
+		source: NewSource(conn, files),
+	}
+}
+
+func (s *SubscriptionSource) Start(ctx *resolve.Context, input []byte, next chan<- []byte) error {
+	in, method, request, err := s.source.request(input)
+	if err != nil {
+		return err
+	}
+	if !method.IsStreamingServer() || method.IsStreamingClient() {
+		return fmt.Errorf("grpc: %s.%s is not a server-streaming RPC", in.Service, in.Method)
+	}
+
+	stream, err := s.source.conn.NewStream(ctx.Context(), &grpc.StreamDesc{ServerStreams: true}, methodPath(in.Service, in.Method))
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(request); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(next)
+		for {
+			buf := &bytes.Buffer{}
+			response := dynamicpb.NewMessage(method.Output())
+			recvErr := stream.RecvMsg(response)
+			if errors.Is(recvErr, io.EOF) || (recvErr != nil && ctx.Context().Err() != nil) {
+				return
+			}
+			var err error
+			if recvErr != nil {
+				// the stream is closed after an error, it's delivered as the last message
+				err = s.source.writeError(buf, in.ResponseKey, recvErr)
+			} else {
+				err = s.source.writeResponse(buf, in, response)
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case next <- buf.Bytes():
+			case <-ctx.Context().Done():
+				return
+			}
+			if recvErr != nil {
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -41,24 +41,19 @@ func (f *IntrospectionConfigFactory) BuildFieldConfigurations() (planFields plan
 				},
 			},
 		},
-		{
-			TypeName:  "__Type",
-			FieldName: "fields",
-			Arguments: plan.ArgumentsConfigurations{
-				{
-					Name:       "includeDeprecated",
-					SourceType: plan.FieldArgumentSource,
-				},
-			},
-		},
-		{
-			TypeName:  "__Type",
-			FieldName: "enumValues",
-			Arguments: plan.ArgumentsConfigurations{
-				{
-					Name:       "includeDeprecated",
-					SourceType: plan.FieldArgumentSource,
-				},
+		includeDeprecatedFieldConfiguration("__Type", "fields"),
+		includeDeprecatedFieldConfiguration("__Type", "enumValues"),
+	}
+}
+
+func includeDeprecatedFieldConfiguration(typeName, fieldName string) plan.FieldConfiguration {
+	return plan.FieldConfiguration{
+		TypeName:  typeName,
+		FieldName: fieldName,
+		Arguments: plan.ArgumentsConfigurations{
+			{
+				Name:       "includeDeprecated",
+				SourceType: plan.FieldArgumentSource,
 			},
 		},
 	}
@@ -72,6 +67,23 @@ func (f *IntrospectionConfigFactory) BuildDataSourceConfigurations() []plan.Data
 	}
 }
 
+// the fields and enumValues are loaded by their own data sources to support the includeDeprecated argument,
+// the args and inputFields are filtered by the Source within the payload of the enclosing type
+var (
+	typeChildNode = plan.TypeField{
+		TypeName:   "__Type",
+		FieldNames: []string{"kind", "name", "description", "specifiedByURL", "interfaces", "possibleTypes", "inputFields", "ofType", "isOneOf"},
+	}
+	fieldChildNode = plan.TypeField{
+		TypeName:   "__Field",
+		FieldNames: []string{"name", "description", "args", "type", "isDeprecated", "deprecationReason"},
+	}
+	inputValueChildNode = plan.TypeField{
+		TypeName:   "__InputValue",
+		FieldNames: []string{"name", "description", "type", "defaultValue", "isDeprecated", "deprecationReason"},
+	}
+)
+
 func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() plan.DataSourceConfiguration {
 	return plan.DataSourceConfiguration{
 		RootNodes: []plan.TypeField{
@@ -83,20 +95,11 @@ func (f *IntrospectionConfigFactory) buildRootDataSourceConfiguration() plan.Dat
 		ChildNodes: []plan.TypeField{
 			{
 				TypeName:   "__Schema",
-				FieldNames: []string{"queryType", "mutationType", "subscriptionType", "types", "directives"},
-			},
-			{
-				TypeName:   "__Type",
-				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
-			},
-			{
-				TypeName:   "__Field",
-				FieldNames: []string{"name", "description", "args", "type", "isDeprecated", "deprecationReason"},
-			},
-			{
-				TypeName:   "__InputValue",
-				FieldNames: []string{"name", "description", "type", "defaultValue"},
+				FieldNames: []string{"description", "queryType", "mutationType", "subscriptionType", "types", "directives"},
 			},
+			typeChildNode,
+			fieldChildNode,
+			inputValueChildNode,
 			{
 				TypeName:   "__Directive",
 				FieldNames: []string{"name", "description", "locations", "args", "isRepeatable"},
@@ -116,18 +119,9 @@ func (f *IntrospectionConfigFactory) buildFieldsConfiguration() plan.DataSourceC
 			},
 		},
 		ChildNodes: []plan.TypeField{
-			{
-				TypeName:   "__Type",
-				FieldNames: []string{"kind", "name", "description", "interfaces", "possibleTypes", "inputFields", "ofType"},
-			},
-			{
-				TypeName:   "__Field",
-				FieldNames: []string{"name", "description", "args", "type", "isDeprecated", "deprecationReason"},
-			},
-			{
-				TypeName:   "__InputValue",
-				FieldNames: []string{"name", "description", "type", "defaultValue"},
-			},
+			typeChildNode,
+			fieldChildNode,
+			inputValueChildNode,
 		},
 		Factory: NewFactory(f.introspectionData),
 		Custom:  []byte("Introspection: __Type.fields"),
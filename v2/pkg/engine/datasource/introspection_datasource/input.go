@@ -15,10 +15,12 @@ const (
 )
 
 const (
-	schemaFieldName     = "__schema"
-	typeFieldName       = "__type"
-	fieldsFieldName     = "fields"
-	enumValuesFieldName = "enumValues"
+	schemaFieldName      = "__schema"
+	typeFieldName        = "__type"
+	fieldsFieldName      = "fields"
+	enumValuesFieldName  = "enumValues"
+	inputFieldsFieldName = "inputFields"
+	argsFieldName        = "args"
 )
 
 type introspectionInput struct {
@@ -26,19 +28,35 @@ type introspectionInput struct {
 	OnTypeName        *string     `json:"on_type_name"`
 	TypeName          *string     `json:"type_name"`
 	IncludeDeprecated bool        `json:"include_deprecated"`
+	// ArgsIncludeDeprecated and InputFieldsIncludeDeprecated hold the includeDeprecated argument of the
+	// args and inputFields selections within the loaded payload by their response keys
+	ArgsIncludeDeprecated        includeDeprecatedByKey `json:"args_include_deprecated"`
+	InputFieldsIncludeDeprecated includeDeprecatedByKey `json:"input_fields_include_deprecated"`
+}
+
+// includeDeprecatedArgument is the rendered includeDeprecated argument of an args or inputFields selection
+type includeDeprecatedArgument struct {
+	responseKey string
+	value       string
 }
 
 var (
-	lBrace                 = []byte("{")
-	rBrace                 = []byte("}")
-	comma                  = []byte(",")
-	requestTypeField       = []byte(`"request_type":`)
-	onTypeField            = []byte(`"on_type_name":"{{ .object.name }}"`)
-	typeNameField          = []byte(`"type_name":"{{ .arguments.name }}"`)
-	includeDeprecatedField = []byte(`"include_deprecated":{{ .arguments.includeDeprecated }}`)
+	lBrace                            = []byte("{")
+	rBrace                            = []byte("}")
+	comma                             = []byte(",")
+	colon                             = []byte(":")
+	quote                             = []byte(`"`)
+	requestTypeField                  = []byte(`"request_type":`)
+	onTypeField                       = []byte(`"on_type_name":"{{ .object.name }}"`)
+	typeNameField                     = []byte(`"type_name":"{{ .arguments.name }}"`)
+	includeDeprecatedField            = []byte(`"include_deprecated":{{ .arguments.includeDeprecated }}`)
+	argsIncludeDeprecatedField        = []byte(`"args_include_deprecated":`)
+	inputFieldsIncludeDeprecatedField = []byte(`"input_fields_include_deprecated":`)
 )
 
-func buildInput(fieldName string) string {
+// buildInput returns the input of the fetch for the field, args and inputFields are the
+// includeDeprecated arguments of the args and inputFields selections loaded by the fetch
+func buildInput(fieldName string, args, inputFields []includeDeprecatedArgument) string {
 	buf := &bytes.Buffer{}
 	buf.Write(lBrace)
 
@@ -57,6 +75,9 @@ func buildInput(fieldName string) string {
 		writeRequestTypeField(buf, SchemaRequestType)
 	}
 
+	writeIncludeDeprecatedArguments(buf, argsIncludeDeprecatedField, args)
+	writeIncludeDeprecatedArguments(buf, inputFieldsIncludeDeprecatedField, inputFields)
+
 	buf.Write(rBrace)
 
 	return buf.String()
@@ -73,3 +94,24 @@ func writeOnTypeFields(buf *bytes.Buffer) {
 	buf.Write(comma)
 	buf.Write(includeDeprecatedField)
 }
+
+func writeIncludeDeprecatedArguments(buf *bytes.Buffer, field []byte, arguments []includeDeprecatedArgument) {
+	if len(arguments) == 0 {
+		return
+	}
+
+	buf.Write(comma)
+	buf.Write(field)
+	buf.Write(lBrace)
+	for i, argument := range arguments {
+		if i > 0 {
+			buf.Write(comma)
+		}
+		buf.Write(quote)
+		buf.WriteString(argument.responseKey)
+		buf.Write(quote)
+		buf.Write(colon)
+		buf.WriteString(argument.value)
+	}
+	buf.Write(rBrace)
+}
@@ -9,18 +9,24 @@ import (
 )
 
 func TestBuildInput(t *testing.T) {
-	run := func(fieldName string, expectedJson string) func(t *testing.T) {
+	run := func(fieldName string, args, inputFields []includeDeprecatedArgument, expectedJson string) func(t *testing.T) {
 		t.Helper()
 		return func(t *testing.T) {
-			actualResult := buildInput(fieldName)
+			actualResult := buildInput(fieldName, args, inputFields)
 			assert.Equal(t, expectedJson, actualResult)
 		}
 	}
 
-	t.Run("schema introspection", run(schemaFieldName, `{"request_type":1}`))
-	t.Run("type introspection", run(typeFieldName, `{"request_type":2,"type_name":"{{ .arguments.name }}"}`))
-	t.Run("type fields", run(fieldsFieldName, `{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
-	t.Run("type enum values", run(enumValuesFieldName, `{"request_type":4,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("schema introspection", run(schemaFieldName, nil, nil, `{"request_type":1}`))
+	t.Run("type introspection", run(typeFieldName, nil, nil, `{"request_type":2,"type_name":"{{ .arguments.name }}"}`))
+	t.Run("type fields", run(fieldsFieldName, nil, nil, `{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("type enum values", run(enumValuesFieldName, nil, nil, `{"request_type":4,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }}}`))
+	t.Run("type fields with args", run(fieldsFieldName,
+		[]includeDeprecatedArgument{{responseKey: "args", value: "false"}, {responseKey: "allArgs", value: "$$0$$"}}, nil,
+		`{"request_type":3,"on_type_name":"{{ .object.name }}","include_deprecated":{{ .arguments.includeDeprecated }},"args_include_deprecated":{"args":false,"allArgs":$$0$$}}`))
+	t.Run("schema introspection with args and input fields", run(schemaFieldName,
+		[]includeDeprecatedArgument{{responseKey: "args", value: "true"}}, []includeDeprecatedArgument{{responseKey: "inputFields", value: "false"}},
+		`{"request_type":1,"args_include_deprecated":{"args":true},"input_fields_include_deprecated":{"inputFields":false}}`))
 }
 
 func TestUnmarshalIntrospectionInput(t *testing.T) {
@@ -39,4 +45,8 @@ func TestUnmarshalIntrospectionInput(t *testing.T) {
 	t.Run("type introspection", run(`{"request_type":2,"type_name":"Foo"}`, introspectionInput{RequestType: TypeRequestType, TypeName: &foo}))
 	t.Run("type fields", run(`{"request_type":3,"on_type_name":"Foo","include_deprecated":true}`, introspectionInput{RequestType: TypeFieldsRequestType, OnTypeName: &foo, IncludeDeprecated: true}))
 	t.Run("type enum values", run(`{"request_type":4,"on_type_name":"Foo","include_deprecated":false}`, introspectionInput{RequestType: TypeEnumValuesRequestType, OnTypeName: &foo, IncludeDeprecated: false}))
+	t.Run("type fields with args", run(`{"request_type":3,"on_type_name":"Foo","include_deprecated":false,"args_include_deprecated":{"args":false,"allArgs":true}}`,
+		introspectionInput{RequestType: TypeFieldsRequestType, OnTypeName: &foo, ArgsIncludeDeprecated: includeDeprecatedByKey{"args": false, "allArgs": true}}))
+	t.Run("type input fields", run(`{"request_type":2,"type_name":"Foo","input_fields_include_deprecated":{"inputFields":true}}`,
+		introspectionInput{RequestType: TypeRequestType, TypeName: &foo, InputFieldsIncludeDeprecated: includeDeprecatedByKey{"inputFields": true}}))
 }
@@ -2,6 +2,7 @@ package introspection_datasource
 
 import (
 	"errors"
+	"strconv"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
@@ -17,6 +18,9 @@ type Planner struct {
 	rootFielPath            string
 	dataSourceConfiguration plan.DataSourceConfiguration
 	isArrayItem             bool
+	variables               resolve.Variables
+	args                    []includeDeprecatedArgument
+	inputFields             []includeDeprecatedArgument
 }
 
 func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
@@ -52,11 +56,83 @@ func (p *Planner) EnterField(ref int) {
 		p.rootField = ref
 		p.rootFieldName = fieldName
 		p.rootFielPath = fieldAliasOrName
+	case argsFieldName:
+		if p.isLoadedInputValuesSelection(fieldName) {
+			p.args = append(p.args, p.includeDeprecatedArgument(ref))
+		}
+	case inputFieldsFieldName:
+		if p.isLoadedInputValuesSelection(fieldName) {
+			p.inputFields = append(p.inputFields, p.includeDeprecatedArgument(ref))
+		}
 	}
 }
 
+// isLoadedInputValuesSelection returns whether the args or inputFields selection selects the input values the Source
+// filters, which are the input fields of a loaded type, the args of loaded fields and the input fields of the types and
+// the args of the directives of the loaded schema. Nested selections, e.g. of the type of an input field, are skipped,
+// as they could have the same response key.
+func (p *Planner) isLoadedInputValuesSelection(fieldName string) bool {
+	var enclosingFields []string
+	withinRootField := false
+	for _, ancestor := range p.v.Walker.Ancestors {
+		if ancestor.Kind != ast.NodeKindField {
+			continue
+		}
+		if ancestor.Ref == p.rootField {
+			withinRootField = true
+			continue
+		}
+		if withinRootField {
+			enclosingFields = append(enclosingFields, p.v.Operation.FieldNameString(ancestor.Ref))
+		}
+	}
+	if !withinRootField {
+		return false
+	}
+
+	switch p.rootFieldName {
+	case typeFieldName:
+		return fieldName == inputFieldsFieldName && len(enclosingFields) == 0
+	case fieldsFieldName:
+		return fieldName == argsFieldName && len(enclosingFields) == 0
+	case schemaFieldName:
+		if len(enclosingFields) != 1 {
+			return false
+		}
+		return enclosingFields[0] == "types" && fieldName == inputFieldsFieldName ||
+			enclosingFields[0] == "directives" && fieldName == argsFieldName
+	}
+	return false
+}
+
+// includeDeprecatedArgument renders the includeDeprecated argument of an args or inputFields selection,
+// the Source filters the deprecated input values of the selection within the payload of the fetch
+func (p *Planner) includeDeprecatedArgument(ref int) includeDeprecatedArgument {
+	argument := includeDeprecatedArgument{
+		responseKey: p.v.Operation.FieldAliasOrNameString(ref),
+		value:       "false",
+	}
+
+	arg, ok := p.v.Operation.FieldArgument(ref, []byte("includeDeprecated"))
+	if !ok {
+		return argument
+	}
+
+	value := p.v.Operation.ArgumentValue(arg)
+	switch value.Kind {
+	case ast.ValueKindBoolean:
+		argument.value = strconv.FormatBool(bool(p.v.Operation.BooleanValue(value.Ref)))
+	case ast.ValueKindVariable:
+		argument.value, _ = p.variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{p.v.Operation.VariableValueNameString(value.Ref)},
+			Renderer: resolve.NewJSONVariableRenderer(),
+		})
+	}
+	return argument
+}
+
 func (p *Planner) configureInput() string {
-	return buildInput(p.rootFieldName)
+	return buildInput(p.rootFieldName, p.args, p.inputFields)
 }
 
 func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
@@ -76,6 +152,7 @@ func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
 
 	return resolve.FetchConfiguration{
 		Input:                         p.configureInput(),
+		Variables:                     p.variables,
 		RequiresParallelListItemFetch: requiresParallelListItemFetch,
 		DataSource: &Source{
 			introspectionData: p.introspectionData,
@@ -1,9 +1,11 @@
 package introspection_datasource
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"sort"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
 )
@@ -24,30 +26,62 @@ func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) (err error
 
 	switch req.RequestType {
 	case TypeRequestType:
-		return s.singleType(w, req.TypeName)
+		return s.singleType(w, req.TypeName, req.InputFieldsIncludeDeprecated)
 	case TypeEnumValuesRequestType:
 		return s.enumValuesForType(w, req.OnTypeName, req.IncludeDeprecated)
 	case TypeFieldsRequestType:
-		return s.fieldsForType(w, req.OnTypeName, req.IncludeDeprecated)
+		return s.fieldsForType(w, req.OnTypeName, req.IncludeDeprecated, req.ArgsIncludeDeprecated)
 	}
 
-	return json.NewEncoder(w).Encode(s.schemaWithoutTypeInfo())
+	return s.schemaWithoutTypeInfo(w, req.InputFieldsIncludeDeprecated, req.ArgsIncludeDeprecated)
 }
 
-func (s *Source) schemaWithoutTypeInfo() introspection.Schema {
+func (s *Source) schemaWithoutTypeInfo(w io.Writer, inputFields, args includeDeprecatedByKey) error {
 	types := make([]introspection.FullType, 0, len(s.introspectionData.Schema.Types))
 
 	for i := range s.introspectionData.Schema.Types {
-		types = append(types, s.typeWithoutFieldAndEnumValues(&s.introspectionData.Schema.Types[i]))
+		types = append(types, s.typeWithoutFieldAndEnumValues(&s.introspectionData.Schema.Types[i], inputFields))
 	}
 
-	return introspection.Schema{
+	directives := make([]introspection.Directive, 0, len(s.introspectionData.Schema.Directives))
+	for _, directive := range s.introspectionData.Schema.Directives {
+		directive.Args = inputValues(directive.Args, args[argsFieldName])
+		directives = append(directives, directive)
+	}
+
+	schema := introspection.Schema{
+		Description:      s.introspectionData.Schema.Description,
 		QueryType:        s.introspectionData.Schema.QueryType,
 		MutationType:     s.introspectionData.Schema.MutationType,
 		SubscriptionType: s.introspectionData.Schema.SubscriptionType,
 		Types:            types,
-		Directives:       s.introspectionData.Schema.Directives,
+		Directives:       directives,
 	}
+
+	inputFieldsAliases, argsAliases := inputFields.aliases(inputFieldsFieldName), args.aliases(argsFieldName)
+	if len(inputFieldsAliases) == 0 && len(argsAliases) == 0 {
+		return json.NewEncoder(w).Encode(schema)
+	}
+
+	schemaWithAliases := struct {
+		introspection.Schema
+		Types      []withAliases `json:"types"`
+		Directives []withAliases `json:"directives"`
+	}{
+		Schema:     schema,
+		Types:      make([]withAliases, 0, len(types)),
+		Directives: make([]withAliases, 0, len(directives)),
+	}
+	for i := range types {
+		allInputFields := s.introspectionData.Schema.Types[i].InputFields
+		schemaWithAliases.Types = append(schemaWithAliases.Types, withAliases{value: types[i], inputValues: allInputFields, aliases: inputFieldsAliases, includeDeprecated: inputFields})
+	}
+	for i := range directives {
+		allArgs := s.introspectionData.Schema.Directives[i].Args
+		schemaWithAliases.Directives = append(schemaWithAliases.Directives, withAliases{value: directives[i], inputValues: allArgs, aliases: argsAliases, includeDeprecated: args})
+	}
+
+	return json.NewEncoder(w).Encode(schemaWithAliases)
 }
 
 func (s *Source) typeInfo(typeName *string) *introspection.FullType {
@@ -68,36 +102,52 @@ func (s *Source) writeNull(w io.Writer) error {
 	return err
 }
 
-func (s *Source) singleType(w io.Writer, typeName *string) error {
+func (s *Source) singleType(w io.Writer, typeName *string, inputFields includeDeprecatedByKey) error {
 	typeInfo := s.typeInfo(typeName)
 	if typeInfo == nil {
 		return s.writeNull(w)
 	}
 
-	return json.NewEncoder(w).Encode(s.typeWithoutFieldAndEnumValues(typeInfo))
+	fullType := s.typeWithoutFieldAndEnumValues(typeInfo, inputFields)
+	if aliases := inputFields.aliases(inputFieldsFieldName); len(aliases) > 0 {
+		return json.NewEncoder(w).Encode(withAliases{value: fullType, inputValues: typeInfo.InputFields, aliases: aliases, includeDeprecated: inputFields})
+	}
+
+	return json.NewEncoder(w).Encode(fullType)
 }
 
-func (s *Source) typeWithoutFieldAndEnumValues(typeInfo *introspection.FullType) introspection.FullType {
+func (s *Source) typeWithoutFieldAndEnumValues(typeInfo *introspection.FullType, inputFields includeDeprecatedByKey) introspection.FullType {
 	typeInfoCopy := *typeInfo
 	typeInfoCopy.Fields = nil
 	typeInfoCopy.EnumValues = nil
+	typeInfoCopy.InputFields = inputValues(typeInfo.InputFields, inputFields[inputFieldsFieldName])
 
 	return typeInfoCopy
 }
 
-func (s *Source) fieldsForType(w io.Writer, typeName *string, includeDeprecated bool) error {
+func (s *Source) fieldsForType(w io.Writer, typeName *string, includeDeprecated bool, args includeDeprecatedByKey) error {
 	typeInfo := s.typeInfo(typeName)
 	if typeInfo == nil || len(typeInfo.Fields) == 0 {
 		return s.writeNull(w)
 	}
 
-	if includeDeprecated {
-		return json.NewEncoder(w).Encode(typeInfo.Fields)
+	aliases := args.aliases(argsFieldName)
+	if len(aliases) > 0 {
+		fields := make([]withAliases, 0, len(typeInfo.Fields))
+		for _, field := range typeInfo.Fields {
+			if includeDeprecated || !field.IsDeprecated {
+				allArgs := field.Args
+				field.Args = inputValues(allArgs, args[argsFieldName])
+				fields = append(fields, withAliases{value: field, inputValues: allArgs, aliases: aliases, includeDeprecated: args})
+			}
+		}
+		return json.NewEncoder(w).Encode(fields)
 	}
 
 	fields := make([]introspection.Field, 0, len(typeInfo.Fields))
 	for _, field := range typeInfo.Fields {
-		if !field.IsDeprecated {
+		if includeDeprecated || !field.IsDeprecated {
+			field.Args = inputValues(field.Args, args[argsFieldName])
 			fields = append(fields, field)
 		}
 	}
@@ -105,6 +155,77 @@ func (s *Source) fieldsForType(w io.Writer, typeName *string, includeDeprecated
 	return json.NewEncoder(w).Encode(fields)
 }
 
+// inputValues returns the input values without the deprecated ones unless includeDeprecated is true,
+// the input values are only copied when some of them are deprecated
+func inputValues(inputValues []introspection.InputValue, includeDeprecated bool) []introspection.InputValue {
+	if includeDeprecated {
+		return inputValues
+	}
+
+	for i := range inputValues {
+		if !inputValues[i].IsDeprecated {
+			continue
+		}
+
+		filtered := make([]introspection.InputValue, 0, len(inputValues)-1)
+		filtered = append(filtered, inputValues[:i]...)
+		for _, inputValue := range inputValues[i+1:] {
+			if !inputValue.IsDeprecated {
+				filtered = append(filtered, inputValue)
+			}
+		}
+		return filtered
+	}
+	return inputValues
+}
+
+// includeDeprecatedByKey holds the includeDeprecated argument of each args or inputFields selection
+// by the response key of the selection
+type includeDeprecatedByKey map[string]bool
+
+// aliases returns the sorted response keys of the aliased selections of the field with the fieldName
+func (i includeDeprecatedByKey) aliases(fieldName string) []string {
+	var aliases []string
+	for key := range i {
+		if key != fieldName {
+			aliases = append(aliases, key)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// withAliases encodes the value with an additional key for each aliased args or inputFields selection,
+// which holds the inputValues filtered by the includeDeprecated argument of the selection
+type withAliases struct {
+	value             interface{}
+	inputValues       []introspection.InputValue
+	aliases           []string
+	includeDeprecated includeDeprecatedByKey
+}
+
+func (v withAliases) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(v.value)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, alias := range v.aliases {
+		aliasData, err := json.Marshal(inputValues(v.inputValues, v.includeDeprecated[alias]))
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`,"`)
+		buf.WriteString(alias)
+		buf.WriteString(`":`)
+		buf.Write(aliasData)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
 func (s *Source) enumValuesForType(w io.Writer, typeName *string, includeDeprecated bool) error {
 	typeInfo := s.typeInfo(typeName)
 	if typeInfo == nil || len(typeInfo.EnumValues) == 0 {
@@ -43,9 +43,11 @@ func TestSource_Load(t *testing.T) {
 	t.Run("type introspection of not existing type", run(testSchema, `{"request_type":2,"type_name":"NotExisting"}`, `not_existing_type`))
 
 	t.Run("type fields", func(t *testing.T) {
-		t.Run("include deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":true}`, `fields_with_deprecated`))
+		t.Run("include deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":true,"args_include_deprecated":{"args":true}}`, `fields_with_deprecated`))
 
-		t.Run("no deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":false}`, `fields_without_deprecated`))
+		t.Run("no deprecated", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":false,"args_include_deprecated":{"args":false}}`, `fields_without_deprecated`))
+
+		t.Run("with aliased args", run(testSchema, `{"request_type":3,"on_type_name":"Query","include_deprecated":false,"args_include_deprecated":{"args":false,"allArgs":true}}`, `fields_with_aliased_args`))
 
 		t.Run("of not existing type", run(testSchema, `{"request_type":3,"on_type_name":"NotExisting","include_deprecated":true}`, `not_existing_type`))
 	})
@@ -57,18 +59,39 @@ func TestSource_Load(t *testing.T) {
 
 		t.Run("of not existing type", run(testSchema, `{"request_type":4,"on_type_name":"NotExisting","include_deprecated":true}`, `not_existing_type`))
 	})
+
+	t.Run("type input fields", func(t *testing.T) {
+		t.Run("include deprecated", run(testSchema, `{"request_type":2,"type_name":"DroidFilter","input_fields_include_deprecated":{"inputFields":true}}`, `input_fields_with_deprecated`))
+
+		t.Run("no deprecated", run(testSchema, `{"request_type":2,"type_name":"DroidFilter","input_fields_include_deprecated":{"inputFields":false}}`, `input_fields_without_deprecated`))
+
+		t.Run("with aliased input fields", run(testSchema, `{"request_type":2,"type_name":"DroidFilter","input_fields_include_deprecated":{"inputFields":false,"allInputFields":true}}`, `input_fields_with_aliases`))
+	})
+
+	t.Run("directive args", func(t *testing.T) {
+		t.Run("with aliased args", run(testSchema, `{"request_type":1,"args_include_deprecated":{"args":false,"allArgs":true}}`, `schema_introspection_with_aliased_directive_args`))
+	})
 }
 
 const testSchema = `
+"The schema of the droids."
 schema {
     query: Query
 }
 
 type Query {
     me: Droid @deprecated
-    droid(id: ID!): Droid
+    droid(id: ID!, serial: String @deprecated(reason: "Use id.")): Droid
+    droids(filter: DroidFilter): [Droid!]!
 }
 
+input DroidFilter {
+    name: String
+    model: String @deprecated(reason: "Use name.")
+}
+
+scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
 enum Episode {
     NEWHOPE
     EMPIRE
@@ -0,0 +1,335 @@
+package pubsub_datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/tidwall/sjson"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+// PubSub is implemented by message brokers which back subscription and publish fields
+type PubSub interface {
+	// Subscribe starts delivering messages published to topic into next
+	// It must not block. When ctx is done, the subscription is removed and next is closed
+	Subscribe(ctx context.Context, topic string, next chan<- []byte) error
+	// Publish sends data to all subscribers of topic
+	Publish(ctx context.Context, topic string, data []byte) error
+}
+
+// TopicArgumentValidator is implemented by PubSubs which restrict the argument values rendered into topics,
+// e.g. to keep clients from subscribing to wildcard subjects
+type TopicArgumentValidator interface {
+	ValidateTopicArgument(value string) error
+}
+
+type EventType string
+
+const (
+	// EventTypePublish maps a mutation field to publishing its arguments as a JSON object to a topic
+	EventTypePublish EventType = "publish"
+	// EventTypeSubscribe maps a subscription field to the messages of a topic
+	EventTypeSubscribe EventType = "subscribe"
+)
+
+// EventConfiguration maps a root field to a topic
+// Topic might contain argument templates, e.g. "rooms.{{ .arguments.roomId }}", which are rendered for each request
+// from the arguments of the field
+type EventConfiguration struct {
+	Type      EventType `json:"type"`
+	TypeName  string    `json:"type_name"`
+	FieldName string    `json:"field_name"`
+	Topic     string    `json:"topic"`
+}
+
+type Configuration struct {
+	Events []EventConfiguration `json:"events"`
+}
+
+func (c *Configuration) eventForTypeField(typeName, fieldName string) *EventConfiguration {
+	for i := range c.Events {
+		if c.Events[i].TypeName == typeName && c.Events[i].FieldName == fieldName {
+			return &c.Events[i]
+		}
+	}
+	return nil
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+type Factory struct {
+	PubSub PubSub
+}
+
+func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return &Planner{
+		pubSub: f.PubSub,
+	}
+}
+
+type Planner struct {
+	visitor      *plan.Visitor
+	config       Configuration
+	pubSub       PubSub
+	rootFieldRef int
+	event        *EventConfiguration
+	arguments    []byte
+	variables    resolve.Variables
+}
+
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(downstreamFieldRef int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: false,
+	}
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	p.visitor = visitor
+	p.rootFieldRef = ast.InvalidRef
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	return json.Unmarshal(configuration.Custom, &p.config)
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.rootFieldRef != ast.InvalidRef {
+		// only the root field is mapped to a topic, nested fields are resolved from the message
+		return
+	}
+	typeName := p.visitor.Walker.EnclosingTypeDefinition.NameString(p.visitor.Definition)
+	fieldName := p.visitor.Operation.FieldNameString(ref)
+	p.event = p.config.eventForTypeField(typeName, fieldName)
+	if p.event == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("no event configured for %s.%s", typeName, fieldName))
+		return
+	}
+	p.rootFieldRef = ref
+	p.configureArguments(ref)
+}
+
+// configureArguments renders all field arguments into a JSON object
+// it's used to render the topic and is the message of publish events
+func (p *Planner) configureArguments(fieldRef int) {
+	p.arguments = append(p.arguments[:0], '{')
+	for _, argRef := range p.visitor.Operation.FieldArguments(fieldRef) {
+		argumentName := p.visitor.Operation.ArgumentNameString(argRef)
+		value := p.visitor.Operation.ArgumentValue(argRef)
+		if value.Kind != ast.ValueKindVariable {
+			// normalization extracts all argument values into variables
+			continue
+		}
+		variableName := p.visitor.Operation.VariableValueNameBytes(value.Ref)
+		variableDefinition, exists := p.visitor.Operation.VariableDefinitionByNameAndOperation(p.visitor.Walker.Ancestors[0].Ref, variableName)
+		if !exists {
+			continue
+		}
+		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, p.visitor.Operation.VariableDefinitions[variableDefinition].Type, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
+		if err != nil {
+			p.visitor.Walker.StopWithInternalErr(err)
+			return
+		}
+		contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{string(variableName)},
+			Renderer: renderer,
+		})
+		if len(p.arguments) > 1 {
+			p.arguments = append(p.arguments, ',')
+		}
+		p.arguments = strconv.AppendQuote(p.arguments, argumentName)
+		p.arguments = append(p.arguments, ':')
+		p.arguments = append(p.arguments, contextVariableName...)
+	}
+	p.arguments = append(p.arguments, '}')
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.event == nil || p.event.Type != EventTypePublish {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("pubsub: fetch is only supported for publish events"))
+		return resolve.FetchConfiguration{}
+	}
+	return resolve.FetchConfiguration{
+		// the topic isn't part of the input, the planner would render its templates with plain variables
+		Input:     fmt.Sprintf(`{"data":%s}`, p.arguments),
+		Variables: p.variables,
+		DataSource: &PublishDataSource{
+			pubSub: p.pubSub,
+			topic:  p.event.Topic,
+		},
+		DisallowSingleFlight: true,
+		PostProcessing: resolve.PostProcessingConfiguration{
+			MergePath: []string{p.visitor.Operation.FieldNameString(p.rootFieldRef)},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	if p.event == nil || p.event.Type != EventTypeSubscribe {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("pubsub: subscription is only supported for subscribe events"))
+		return plan.SubscriptionConfiguration{}
+	}
+	return plan.SubscriptionConfiguration{
+		Input:     fmt.Sprintf(`{"field":%s,"arguments":%s}`, strconv.Quote(p.visitor.Operation.FieldNameString(p.rootFieldRef)), p.arguments),
+		Variables: p.variables,
+		DataSource: &SubscriptionSource{
+			pubSub: p.pubSub,
+			topic:  p.event.Topic,
+		},
+	}
+}
+
+// PublishDataSource publishes the data of its input to its topic
+// It responds with {"success":true}, so publish fields should return a type with a success field
+// Errors of the PubSub are returned as fetch errors
+type PublishDataSource struct {
+	pubSub PubSub
+	topic  string
+}
+
+func (s *PublishDataSource) Load(ctx context.Context, input []byte, w io.Writer) error {
+	data, _, _, err := jsonparser.Get(input, "data")
+	if err != nil {
+		return fmt.Errorf("pubsub: missing data in input: %w", err)
+	}
+	topic, err := renderTopic(s.topic, data, s.pubSub)
+	if err != nil {
+		return err
+	}
+	if err := s.pubSub.Publish(ctx, topic, data); err != nil {
+		return fmt.Errorf("pubsub: unable to publish to topic %s: %w", topic, err)
+	}
+	_, err = io.WriteString(w, `{"success":true}`)
+	return err
+}
+
+// SubscriptionSource forwards all messages of its topic as the value of the subscription root field
+type SubscriptionSource struct {
+	pubSub PubSub
+	topic  string
+}
+
+func (s *SubscriptionSource) Start(ctx *resolve.Context, input []byte, next chan<- []byte) error {
+	arguments, _, _, err := jsonparser.Get(input, "arguments")
+	if err != nil {
+		return fmt.Errorf("pubsub: missing arguments in input: %w", err)
+	}
+	topic, err := renderTopic(s.topic, arguments, s.pubSub)
+	if err != nil {
+		return err
+	}
+	field, err := jsonparser.GetString(input, "field")
+	if err != nil {
+		return fmt.Errorf("pubsub: missing field in input: %w", err)
+	}
+
+	messages := make(chan []byte)
+	if err := s.pubSub.Subscribe(ctx.Context(), topic, messages); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(next)
+		for message := range messages {
+			response, err := sjson.SetRawBytes([]byte(`{}`), field, message)
+			if err != nil {
+				continue
+			}
+			select {
+			case next <- response:
+			case <-ctx.Context().Done():
+			}
+		}
+	}()
+
+	return nil
+}
+
+var topicArgumentRegex = regexp.MustCompile(`{{\s*\.arguments\.([_A-Za-z][_0-9A-Za-z]*)\s*}}`)
+
+// renderTopic replaces all argument templates of the topic with the argument values,
+// the values are validated by the pubSub if it implements TopicArgumentValidator
+func renderTopic(topic string, arguments []byte, pubSub PubSub) (string, error) {
+	validator, _ := pubSub.(TopicArgumentValidator)
+	var renderErr error
+	topic = topicArgumentRegex.ReplaceAllStringFunc(topic, func(template string) string {
+		if renderErr != nil {
+			return ""
+		}
+		name := topicArgumentRegex.FindStringSubmatch(template)[1]
+		value, valueType, _, err := jsonparser.Get(arguments, name)
+		if err != nil || valueType == jsonparser.Null {
+			renderErr = fmt.Errorf("pubsub: argument %s of topic template is not set", name)
+			return ""
+		}
+		rendered := string(value)
+		if valueType == jsonparser.String {
+			if unescaped, err := jsonparser.ParseString(value); err == nil {
+				rendered = unescaped
+			}
+		}
+		if validator != nil {
+			if err := validator.ValidateTopicArgument(rendered); err != nil {
+				renderErr = fmt.Errorf("pubsub: argument %s of topic template: %w", name, err)
+				return ""
+			}
+		}
+		return rendered
+	})
+	return topic, renderErr
+}
+
+// topicSubscription delivers messages of a topic to a subscriber until its context is done
+type topicSubscription struct {
+	ctx  context.Context
+	next chan<- []byte
+	// mu guards next, so that messages aren't sent after the subscription was closed
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *topicSubscription) send(ctx context.Context, message []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	select {
+	case s.next <- message:
+		return nil
+	case <-s.ctx.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *topicSubscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.next)
+}
@@ -0,0 +1,256 @@
+package pubsub_datasource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasourcetesting"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+const schema = `
+	type Query {
+		hello: String
+	}
+
+	type Mutation {
+		sendMessage(roomId: ID!, text: String!): PublishResult!
+	}
+
+	type Subscription {
+		messageAdded(roomId: ID!): Message!
+	}
+
+	type PublishResult {
+		success: Boolean!
+	}
+
+	type Message {
+		roomId: ID!
+		text: String!
+	}
+`
+
+func TestPubSubDataSourcePlanning(t *testing.T) {
+	pubSub := NewInMemoryPubSub()
+	factory := &Factory{
+		PubSub: pubSub,
+	}
+
+	config := plan.Configuration{
+		DataSources: []plan.DataSourceConfiguration{
+			{
+				RootNodes: []plan.TypeField{
+					{
+						TypeName:   "Mutation",
+						FieldNames: []string{"sendMessage"},
+					},
+					{
+						TypeName:   "Subscription",
+						FieldNames: []string{"messageAdded"},
+					},
+				},
+				ChildNodes: []plan.TypeField{
+					{
+						TypeName:   "PublishResult",
+						FieldNames: []string{"success"},
+					},
+					{
+						TypeName:   "Message",
+						FieldNames: []string{"roomId", "text"},
+					},
+				},
+				Custom: ConfigJSON(Configuration{
+					Events: []EventConfiguration{
+						{
+							Type:      EventTypePublish,
+							TypeName:  "Mutation",
+							FieldName: "sendMessage",
+							Topic:     "rooms.{{ .arguments.roomId }}",
+						},
+						{
+							Type:      EventTypeSubscribe,
+							TypeName:  "Subscription",
+							FieldName: "messageAdded",
+							Topic:     "rooms.{{ .arguments.roomId }}",
+						},
+					},
+				}),
+				Factory: factory,
+			},
+		},
+		Fields: []plan.FieldConfiguration{
+			{
+				TypeName:  "Mutation",
+				FieldName: "sendMessage",
+				Arguments: []plan.ArgumentConfiguration{
+					{
+						Name:       "roomId",
+						SourceType: plan.FieldArgumentSource,
+					},
+					{
+						Name:       "text",
+						SourceType: plan.FieldArgumentSource,
+					},
+				},
+			},
+			{
+				TypeName:  "Subscription",
+				FieldName: "messageAdded",
+				Arguments: []plan.ArgumentConfiguration{
+					{
+						Name:       "roomId",
+						SourceType: plan.FieldArgumentSource,
+					},
+				},
+			},
+		},
+		DisableResolveFieldPositions: true,
+	}
+
+	t.Run("publish", datasourcetesting.RunTest(schema, `
+		mutation SendMessage {
+			sendMessage(roomId: "a", text: "hello") {
+				success
+			}
+		}
+	`, "SendMessage", &plan.SynchronousResponsePlan{
+		Response: &resolve.GraphQLResponse{
+			Data: &resolve.Object{
+				Fetch: &resolve.SingleFetch{
+					DataSourceIdentifier: []byte("pubsub_datasource.PublishDataSource"),
+					FetchConfiguration: resolve.FetchConfiguration{
+						Input: `{"data":{"roomId":$$0$$,"text":$$1$$}}`,
+						Variables: resolve.NewVariables(
+							&resolve.ContextVariable{
+								Path:     []string{"a"},
+								Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","integer"]}`),
+							},
+							&resolve.ContextVariable{
+								Path:     []string{"b"},
+								Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string"]}`),
+							},
+						),
+						DataSource:           &PublishDataSource{pubSub: pubSub, topic: "rooms.{{ .arguments.roomId }}"},
+						DisallowSingleFlight: true,
+						PostProcessing: resolve.PostProcessingConfiguration{
+							MergePath: []string{"sendMessage"},
+						},
+					},
+				},
+				Fields: []*resolve.Field{
+					{
+						Name: []byte("sendMessage"),
+						Value: &resolve.Object{
+							Path: []string{"sendMessage"},
+							Fields: []*resolve.Field{
+								{
+									Name: []byte("success"),
+									Value: &resolve.Boolean{
+										Path: []string{"success"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, config))
+
+	t.Run("subscribe", datasourcetesting.RunTest(schema, `
+		subscription MessageAdded {
+			messageAdded(roomId: "a") {
+				text
+			}
+		}
+	`, "MessageAdded", &plan.SubscriptionResponsePlan{
+		Response: &resolve.GraphQLSubscription{
+			Trigger: resolve.GraphQLSubscriptionTrigger{
+				Input: []byte(`{"field":"messageAdded","arguments":{"roomId":$$0$$}}`),
+				Variables: resolve.NewVariables(
+					&resolve.ContextVariable{
+						Path:     []string{"a"},
+						Renderer: resolve.NewJSONVariableRendererWithValidation(`{"type":["string","integer"]}`),
+					},
+				),
+				Source: &SubscriptionSource{pubSub: pubSub, topic: "rooms.{{ .arguments.roomId }}"},
+			},
+			Response: &resolve.GraphQLResponse{
+				Data: &resolve.Object{
+					Fields: []*resolve.Field{
+						{
+							Name: []byte("messageAdded"),
+							Value: &resolve.Object{
+								Path: []string{"messageAdded"},
+								Fields: []*resolve.Field{
+									{
+										Name: []byte("text"),
+										Value: &resolve.String{
+											Path: []string{"text"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, config))
+}
+
+func TestPubSubDataSources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pubSub := NewInMemoryPubSub()
+
+	next := make(chan []byte)
+	source := &SubscriptionSource{pubSub: pubSub, topic: "rooms.{{ .arguments.roomId }}"}
+	err := source.Start(resolve.NewContext(ctx), []byte(`{"field":"messageAdded","arguments":{"roomId":"a"}}`), next)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pubSub.Subscribers("rooms.a"))
+
+	publish := &PublishDataSource{pubSub: pubSub, topic: "rooms.{{ .arguments.roomId }}"}
+	go func() {
+		out := &bytes.Buffer{}
+		assert.NoError(t, publish.Load(ctx, []byte(`{"data":{"roomId":"b","text":"ignored"}}`), out))
+		assert.NoError(t, publish.Load(ctx, []byte(`{"data":{"roomId":"a","text":"hello"}}`), out))
+		assert.Equal(t, `{"success":true}{"success":true}`, out.String())
+	}()
+
+	assert.Equal(t, `{"messageAdded":{"roomId":"a","text":"hello"}}`, string(<-next))
+
+	cancel()
+	_, ok := <-next
+	assert.False(t, ok)
+	assert.Eventually(t, func() bool {
+		return pubSub.Subscribers("rooms.a") == 0
+	}, time.Second, time.Millisecond*10)
+}
+
+type failingPubSub struct{}
+
+func (failingPubSub) Subscribe(_ context.Context, _ string, _ chan<- []byte) error {
+	return errors.New("unavailable")
+}
+
+func (failingPubSub) Publish(_ context.Context, _ string, _ []byte) error {
+	return errors.New("unavailable")
+}
+
+func TestPublishDataSourceError(t *testing.T) {
+	publish := &PublishDataSource{pubSub: failingPubSub{}, topic: "rooms.{{ .arguments.roomId }}"}
+	out := &bytes.Buffer{}
+	err := publish.Load(context.Background(), []byte(`{"data":{"roomId":"a","text":"hello"}}`), out)
+	assert.EqualError(t, err, "pubsub: unable to publish to topic rooms.a: unavailable")
+	assert.Equal(t, "", out.String())
+}
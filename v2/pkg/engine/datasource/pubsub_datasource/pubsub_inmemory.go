@@ -0,0 +1,71 @@
+package pubsub_datasource
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryPubSub is a PubSub which delivers messages within the same process
+// It's useful for tests and for single instance deployments
+type InMemoryPubSub struct {
+	mu            sync.RWMutex
+	subscriptions map[string]map[*topicSubscription]struct{}
+}
+
+func NewInMemoryPubSub() *InMemoryPubSub {
+	return &InMemoryPubSub{
+		subscriptions: map[string]map[*topicSubscription]struct{}{},
+	}
+}
+
+func (p *InMemoryPubSub) Subscribe(ctx context.Context, topic string, next chan<- []byte) error {
+	sub := &topicSubscription{
+		ctx:  ctx,
+		next: next,
+	}
+
+	p.mu.Lock()
+	if p.subscriptions[topic] == nil {
+		p.subscriptions[topic] = map[*topicSubscription]struct{}{}
+	}
+	p.subscriptions[topic][sub] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		delete(p.subscriptions[topic], sub)
+		if len(p.subscriptions[topic]) == 0 {
+			delete(p.subscriptions, topic)
+		}
+		p.mu.Unlock()
+		sub.close()
+	}()
+
+	return nil
+}
+
+func (p *InMemoryPubSub) Publish(ctx context.Context, topic string, data []byte) error {
+	p.mu.RLock()
+	subscriptions := make([]*topicSubscription, 0, len(p.subscriptions[topic]))
+	for sub := range p.subscriptions[topic] {
+		subscriptions = append(subscriptions, sub)
+	}
+	p.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		message := make([]byte, len(data))
+		copy(message, data)
+		if err := sub.send(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribers returns the number of active subscriptions on a topic
+func (p *InMemoryPubSub) Subscribers(topic string) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.subscriptions[topic])
+}
@@ -0,0 +1,307 @@
+package pubsub_datasource
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var errNatsConnectionClosed = errors.New("nats: connection closed")
+
+// natsPendingMessages is the number of messages buffered per subscription,
+// messages for a subscriber which doesn't keep up are dropped like the NATS server does for slow consumers
+const natsPendingMessages = 1024
+
+// NatsOptions configures the CONNECT message sent to the NATS server
+type NatsOptions struct {
+	Name     string
+	User     string
+	Password string
+	Token    string
+}
+
+// NatsPubSub implements PubSub on top of the NATS client protocol
+// https://docs.nats.io/reference/reference-protocols/nats-protocol
+// Topics are used as NATS subjects, so wildcards are supported for subscriptions
+type NatsPubSub struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	nextSID       int
+	subscriptions map[int]*natsSubscription
+	err           error
+
+	done chan struct{}
+}
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name,omitempty"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+}
+
+// DialNats connects to the NATS server at address (host:port) and performs the protocol handshake
+func DialNats(ctx context.Context, address string, options NatsOptions) (*NatsPubSub, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	p := &NatsPubSub{
+		conn:          conn,
+		reader:        bufio.NewReader(conn),
+		subscriptions: map[int]*natsSubscription{},
+		done:          make(chan struct{}),
+	}
+	if err := p.handshake(options); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	go p.readLoop()
+	return p, nil
+}
+
+func (p *NatsPubSub) handshake(options NatsOptions) error {
+	line, err := p.readLine()
+	if err != nil {
+		return err
+	}
+	if op, _ := splitNatsOp(line); op != "INFO" {
+		return fmt.Errorf("nats: expected INFO, got: %s", line)
+	}
+
+	connect, err := json.Marshal(natsConnect{
+		Name:     options.Name,
+		User:     options.User,
+		Password: options.Password,
+		Token:    options.Token,
+		Lang:     "go",
+		Version:  "graphql-go-tools",
+	})
+	if err != nil {
+		return err
+	}
+	if err := p.write([]byte("CONNECT " + string(connect) + "\r\nPING\r\n")); err != nil {
+		return err
+	}
+
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		op, args := splitNatsOp(line)
+		switch op {
+		case "PONG":
+			return nil
+		case "-ERR":
+			return fmt.Errorf("nats: %s", args)
+		}
+	}
+}
+
+func (p *NatsPubSub) Subscribe(ctx context.Context, topic string, next chan<- []byte) error {
+	if strings.ContainsAny(topic, " \t\r\n") {
+		return fmt.Errorf("nats: invalid subject: %q", topic)
+	}
+
+	p.mu.Lock()
+	if p.err != nil {
+		p.mu.Unlock()
+		return p.err
+	}
+	p.nextSID++
+	sid := p.nextSID
+	sub := &natsSubscription{
+		topicSubscription: &topicSubscription{
+			ctx:  ctx,
+			next: next,
+		},
+		pending: make(chan []byte, natsPendingMessages),
+		done:    make(chan struct{}),
+	}
+	p.subscriptions[sid] = sub
+	p.mu.Unlock()
+	go sub.deliver()
+
+	if err := p.write([]byte(fmt.Sprintf("SUB %s %d\r\n", topic, sid))); err != nil {
+		p.removeSubscription(sid)
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = p.write([]byte(fmt.Sprintf("UNSUB %d\r\n", sid)))
+		case <-p.done:
+		}
+		p.removeSubscription(sid)
+	}()
+
+	return nil
+}
+
+// ValidateTopicArgument rejects argument values which aren't a single subject token,
+// so that clients can't subscribe to wildcards or other subjects than the ones of the topic template
+func (p *NatsPubSub) ValidateTopicArgument(value string) error {
+	if value == "" || strings.ContainsAny(value, " \t\r\n.*>") {
+		return fmt.Errorf("nats: invalid subject token: %q", value)
+	}
+	return nil
+}
+
+func (p *NatsPubSub) Publish(_ context.Context, topic string, data []byte) error {
+	if strings.ContainsAny(topic, " \t\r\n*>") {
+		return fmt.Errorf("nats: invalid subject: %q", topic)
+	}
+	message := make([]byte, 0, len(topic)+len(data)+24)
+	message = append(message, "PUB "...)
+	message = append(message, topic...)
+	message = append(message, ' ')
+	message = strconv.AppendInt(message, int64(len(data)), 10)
+	message = append(message, "\r\n"...)
+	message = append(message, data...)
+	message = append(message, "\r\n"...)
+	return p.write(message)
+}
+
+// Close closes the connection to the NATS server and ends all subscriptions
+func (p *NatsPubSub) Close() error {
+	return p.conn.Close()
+}
+
+func (p *NatsPubSub) removeSubscription(sid int) {
+	p.mu.Lock()
+	sub, ok := p.subscriptions[sid]
+	delete(p.subscriptions, sid)
+	p.mu.Unlock()
+	if ok {
+		close(sub.done)
+		sub.close()
+	}
+}
+
+func (p *NatsPubSub) write(data []byte) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err := p.conn.Write(data)
+	return err
+}
+
+func (p *NatsPubSub) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readLoop handles all messages sent by the server until the connection is closed
+func (p *NatsPubSub) readLoop() {
+	err := p.read()
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		err = errNatsConnectionClosed
+	}
+	p.mu.Lock()
+	p.err = err
+	p.mu.Unlock()
+	close(p.done)
+}
+
+func (p *NatsPubSub) read() error {
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		op, args := splitNatsOp(line)
+		switch op {
+		case "MSG":
+			if err := p.handleMsg(args); err != nil {
+				return err
+			}
+		case "PING":
+			if err := p.write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case "-ERR":
+			return fmt.Errorf("nats: %s", args)
+		default:
+			// INFO, PONG and +OK don't require any action
+		}
+	}
+}
+
+// handleMsg reads the payload of a MSG and forwards it to the subscription
+// MSG <subject> <sid> [reply-to] <#bytes>
+func (p *NatsPubSub) handleMsg(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) != 3 && len(fields) != 4 {
+		return fmt.Errorf("nats: malformed MSG: %s", args)
+	}
+	sid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return fmt.Errorf("nats: malformed MSG sid: %s", args)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return fmt.Errorf("nats: malformed MSG size: %s", args)
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(p.reader, payload); err != nil {
+		return err
+	}
+	payload = payload[:size]
+
+	p.mu.Lock()
+	sub, ok := p.subscriptions[sid]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	// the read loop must not block on a single subscriber
+	select {
+	case sub.pending <- payload:
+	default:
+	}
+	return nil
+}
+
+// natsSubscription buffers the messages of the read loop for a subscriber
+type natsSubscription struct {
+	*topicSubscription
+	pending chan []byte
+	// done is closed when the subscription is removed
+	done chan struct{}
+}
+
+func (s *natsSubscription) deliver() {
+	for {
+		select {
+		case message := <-s.pending:
+			_ = s.send(s.ctx, message)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func splitNatsOp(line string) (op, args string) {
+	op, args, _ = strings.Cut(line, " ")
+	return strings.ToUpper(op), strings.TrimSpace(args)
+}
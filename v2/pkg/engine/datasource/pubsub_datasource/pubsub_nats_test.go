@@ -0,0 +1,215 @@
+package pubsub_datasource
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+// fakeNatsServer implements the subset of the NATS protocol used by NatsPubSub
+type fakeNatsServer struct {
+	listener      net.Listener
+	mu            sync.Mutex
+	subscriptions map[string]string // sid -> subject
+	commands      []string
+	pongs         chan struct{}
+}
+
+func newFakeNatsServer(t *testing.T) *fakeNatsServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNatsServer{
+		listener:      listener,
+		subscriptions: map[string]string{},
+		pongs:         make(chan struct{}, 1),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *fakeNatsServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	_, _ = io.WriteString(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		op, args, _ := strings.Cut(line, " ")
+
+		s.mu.Lock()
+		s.commands = append(s.commands, op)
+		s.mu.Unlock()
+
+		switch op {
+		case "PING":
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		case "PONG":
+			select {
+			case s.pongs <- struct{}{}:
+			default:
+			}
+		case "SUB":
+			fields := strings.Fields(args)
+			s.mu.Lock()
+			s.subscriptions[fields[1]] = fields[0]
+			s.mu.Unlock()
+			// ask the client for a PONG to verify that it handles server pings
+			_, _ = io.WriteString(conn, "PING\r\n")
+		case "UNSUB":
+			s.mu.Lock()
+			delete(s.subscriptions, args)
+			s.mu.Unlock()
+		case "PUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			s.mu.Lock()
+			for sid, subject := range s.subscriptions {
+				if subject == fields[0] {
+					_, _ = fmt.Fprintf(conn, "MSG %s %s %d\r\n%s", subject, sid, size, payload)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *fakeNatsServer) subscriptionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscriptions)
+}
+
+func TestNatsPubSub(t *testing.T) {
+	server := newFakeNatsServer(t)
+	defer server.listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pubSub, err := DialNats(ctx, server.listener.Addr().String(), NatsOptions{Name: "test"})
+	require.NoError(t, err)
+	defer pubSub.Close()
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	next := make(chan []byte)
+	require.NoError(t, pubSub.Subscribe(subCtx, "rooms.a", next))
+
+	select {
+	case <-server.pongs:
+	case <-time.After(time.Second):
+		t.Fatal("client did not answer server PING")
+	}
+
+	require.NoError(t, pubSub.Publish(ctx, "rooms.b", []byte(`{"text":"ignored"}`)))
+	require.NoError(t, pubSub.Publish(ctx, "rooms.a", []byte(`{"text":"hello"}`)))
+	assert.Equal(t, `{"text":"hello"}`, string(<-next))
+
+	subCancel()
+	_, ok := <-next
+	assert.False(t, ok)
+	assert.Eventually(t, func() bool {
+		return server.subscriptionCount() == 0
+	}, time.Second, time.Millisecond*10)
+
+	assert.Error(t, pubSub.Publish(ctx, "rooms.*", []byte(`{}`)))
+}
+
+func TestNatsPubSubTopicArguments(t *testing.T) {
+	server := newFakeNatsServer(t)
+	defer server.listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pubSub, err := DialNats(ctx, server.listener.Addr().String(), NatsOptions{})
+	require.NoError(t, err)
+	defer pubSub.Close()
+
+	source := &SubscriptionSource{pubSub: pubSub, topic: "rooms.{{ .arguments.roomId }}"}
+	for _, roomId := range []string{">", "*", "a.b", "a b", ""} {
+		err := source.Start(resolve.NewContext(ctx), []byte(`{"field":"messageAdded","arguments":{"roomId":`+strconv.Quote(roomId)+`}}`), make(chan []byte))
+		assert.EqualError(t, err, fmt.Sprintf("pubsub: argument roomId of topic template: nats: invalid subject token: %q", roomId))
+	}
+	assert.Equal(t, 0, server.subscriptionCount())
+
+	require.NoError(t, source.Start(resolve.NewContext(ctx), []byte(`{"field":"messageAdded","arguments":{"roomId":"a"}}`), make(chan []byte)))
+	assert.Eventually(t, func() bool {
+		return server.subscriptionCount() == 1
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestNatsPubSubConnectionClosed(t *testing.T) {
+	server := newFakeNatsServer(t)
+	defer server.listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pubSub, err := DialNats(ctx, server.listener.Addr().String(), NatsOptions{})
+	require.NoError(t, err)
+
+	next := make(chan []byte)
+	require.NoError(t, pubSub.Subscribe(ctx, "rooms.a", next))
+	require.NoError(t, pubSub.Close())
+
+	_, ok := <-next
+	assert.False(t, ok)
+	assert.ErrorIs(t, pubSub.Subscribe(ctx, "rooms.a", make(chan []byte)), errNatsConnectionClosed)
+}
+
+func TestNatsPubSubSlowSubscriber(t *testing.T) {
+	server := newFakeNatsServer(t)
+	defer server.listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pubSub, err := DialNats(ctx, server.listener.Addr().String(), NatsOptions{})
+	require.NoError(t, err)
+	defer pubSub.Close()
+
+	// slow never reads its messages
+	slow := make(chan []byte)
+	require.NoError(t, pubSub.Subscribe(ctx, "rooms.a", slow))
+	next := make(chan []byte)
+	require.NoError(t, pubSub.Subscribe(ctx, "rooms.b", next))
+	assert.Eventually(t, func() bool {
+		return server.subscriptionCount() == 2
+	}, time.Second, time.Millisecond*10)
+
+	for i := 0; i < natsPendingMessages+2; i++ {
+		require.NoError(t, pubSub.Publish(ctx, "rooms.a", []byte(`{"text":"slow"}`)))
+	}
+	require.NoError(t, pubSub.Publish(ctx, "rooms.b", []byte(`{"text":"hello"}`)))
+
+	select {
+	case message := <-next:
+		assert.Equal(t, `{"text":"hello"}`, string(message))
+	case <-time.After(time.Second):
+		t.Fatal("subscription was blocked by a slow subscriber")
+	}
+}
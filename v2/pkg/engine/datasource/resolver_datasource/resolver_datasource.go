@@ -0,0 +1,462 @@
+// Package resolver_datasource mounts Go resolvers into the engine as an in-process data source,
+// the resolvers are usually generated with codegen.ResolverGen.
+package resolver_datasource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+const typeNameFieldName = "__typename"
+
+// Field is a field of an operation which is resolved by a Resolver
+type Field struct {
+	Name        string `json:"name"`
+	ResponseKey string `json:"response_key"`
+	// TypeConditions are the object types the field is selected on,
+	// fields of interfaces and unions are selected on all of their object types
+	TypeConditions []string        `json:"type_conditions,omitempty"`
+	Arguments      json.RawMessage `json:"arguments,omitempty"`
+	Selections     []*Field        `json:"selections,omitempty"`
+}
+
+// UnmarshalArguments decodes the arguments of the field into v, it's a no-op if the field has no arguments
+func (f *Field) UnmarshalArguments(v interface{}) error {
+	if len(f.Arguments) == 0 {
+		return nil
+	}
+	return json.Unmarshal(f.Arguments, v)
+}
+
+func (f *Field) appliesTo(typeName string) bool {
+	if len(f.TypeConditions) == 0 {
+		return true
+	}
+	for _, typeCondition := range f.TypeConditions {
+		if typeCondition == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolver resolves the fields of the object types of a schema
+type Resolver interface {
+	// ResolveField resolves the field of an object of the type typeName,
+	// object is nil for the fields of the root operation types.
+	// Values of leaf fields are encoded as JSON, values of composite fields are resolved further.
+	ResolveField(ctx context.Context, typeName string, object interface{}, field *Field) (interface{}, error)
+	// TypeName returns the object type of a value returned by ResolveField for a composite field
+	TypeName(object interface{}) (string, error)
+}
+
+// Configuration describes the resolvers of a data source
+type Configuration struct {
+	// Schema is the schema the resolvers implement, it's used to plan fields of interfaces and unions
+	Schema string `json:"schema"`
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+type Factory struct {
+	Resolver Resolver
+}
+
+func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return NewPlanner(NewSource(f.Resolver))
+}
+
+// NewPlanner returns a planner whose fetches are loaded by source,
+// source must respond like Source, e.g. by wrapping it
+func NewPlanner(source resolve.DataSource) *Planner {
+	return &Planner{
+		source: source,
+	}
+}
+
+type Planner struct {
+	visitor        *plan.Visitor
+	config         Configuration
+	upstreamSchema *ast.Document
+	source         resolve.DataSource
+	rootTypeName   string
+	rootField      *fieldTemplate
+	fields         []*fieldTemplate
+	variables      resolve.Variables
+}
+
+// fieldTemplate is a Field whose arguments contain the placeholders of context variables
+type fieldTemplate struct {
+	name           string
+	responseKey    string
+	typeConditions []string
+	arguments      []byte
+	selections     []*fieldTemplate
+}
+
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	if p.upstreamSchema != nil {
+		return p.upstreamSchema
+	}
+
+	var config Configuration
+	if err := json.Unmarshal(dataSourceConfig.Custom, &config); err != nil {
+		panic(err)
+	}
+
+	definition, report := astparser.ParseGraphqlDocumentString(config.Schema)
+	if report.HasErrors() {
+		panic(report)
+	}
+	if err := asttransform.MergeDefinitionWithBaseSchema(&definition); err != nil {
+		panic(fmt.Errorf("unable to merge upstream schema with base schema: %v", err))
+	}
+
+	p.upstreamSchema = &definition
+	return p.upstreamSchema
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(downstreamFieldRef int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+		IncludeTypeNameFields:      true,
+	}
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, _ plan.DataSourcePlannerConfiguration) error {
+	p.visitor = visitor
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	visitor.Walker.RegisterLeaveFieldVisitor(p)
+	return json.Unmarshal(configuration.Custom, &p.config)
+}
+
+func (p *Planner) EnterField(ref int) {
+	typeName := p.visitor.Walker.EnclosingTypeDefinition.NameString(p.visitor.Definition)
+	field := &fieldTemplate{
+		name:        p.visitor.Operation.FieldNameString(ref),
+		responseKey: p.visitor.Operation.FieldAliasOrNameString(ref),
+	}
+
+	switch {
+	case p.rootField == nil:
+		p.rootTypeName = typeName
+		p.rootField = field
+	case len(p.fields) == 0:
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("resolver data source: unexpected root field %s.%s", typeName, field.name))
+		return
+	default:
+		field.typeConditions = p.objectTypeNames(p.visitor.Walker.EnclosingTypeDefinition)
+		parent := p.fields[len(p.fields)-1]
+		parent.selections = append(parent.selections, field)
+	}
+
+	field.arguments = p.renderArguments(ref)
+	p.fields = append(p.fields, field)
+}
+
+// objectTypeNames returns the object types of an enclosing type
+func (p *Planner) objectTypeNames(node ast.Node) []string {
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		typeNames, _ := p.visitor.Definition.InterfaceTypeDefinitionImplementedByObjectWithNames(node.Ref)
+		return typeNames
+	case ast.NodeKindUnionTypeDefinition:
+		typeNames, _ := p.visitor.Definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+		return typeNames
+	default:
+		return []string{node.NameString(p.visitor.Definition)}
+	}
+}
+
+func (p *Planner) LeaveField(ref int) {
+	if len(p.fields) != 0 {
+		p.fields = p.fields[:len(p.fields)-1]
+	}
+}
+
+// renderArguments renders the arguments of a field into a JSON object,
+// variables are rendered with context variables
+func (p *Planner) renderArguments(fieldRef int) []byte {
+	arguments := p.visitor.Operation.FieldArguments(fieldRef)
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	out := []byte{'{'}
+	for _, argRef := range arguments {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, p.visitor.Operation.ArgumentNameString(argRef))
+		out = append(out, ':')
+
+		value := p.visitor.Operation.ArgumentValue(argRef)
+		if value.Kind != ast.ValueKindVariable {
+			literal, err := p.visitor.Operation.ValueToJSON(value)
+			if err != nil {
+				p.visitor.Walker.StopWithInternalErr(err)
+				return nil
+			}
+			out = append(out, literal...)
+			continue
+		}
+
+		variableName := p.visitor.Operation.VariableValueNameBytes(value.Ref)
+		variableDefinition, exists := p.visitor.Operation.VariableDefinitionByNameAndOperation(p.visitor.Walker.Ancestors[0].Ref, variableName)
+		if !exists {
+			out = append(out, "null"...)
+			continue
+		}
+		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, p.visitor.Operation.VariableDefinitions[variableDefinition].Type, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
+		if err != nil {
+			p.visitor.Walker.StopWithInternalErr(err)
+			return nil
+		}
+		contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+			Path:     []string{string(variableName)},
+			Renderer: renderer,
+		})
+		out = append(out, contextVariableName...)
+	}
+	return append(out, '}')
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.rootField == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("resolver data source: no root field planned"))
+		return resolve.FetchConfiguration{}
+	}
+
+	input := []byte(`{"type_name":`)
+	input = strconv.AppendQuote(input, p.rootTypeName)
+	input = append(input, `,"field":`...)
+	input = appendFieldTemplate(input, p.rootField)
+	input = append(input, '}')
+
+	return resolve.FetchConfiguration{
+		Input:                string(input),
+		Variables:            p.variables,
+		DataSource:           p.source,
+		DisallowSingleFlight: true,
+		PostProcessing: resolve.PostProcessingConfiguration{
+			SelectResponseDataPath:   []string{"data"},
+			SelectResponseErrorsPath: []string{"errors"},
+		},
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	p.visitor.Walker.StopWithInternalErr(fmt.Errorf("resolver data source: subscriptions are not supported"))
+	return plan.SubscriptionConfiguration{}
+}
+
+func appendFieldTemplate(out []byte, field *fieldTemplate) []byte {
+	out = append(out, `{"name":`...)
+	out = strconv.AppendQuote(out, field.name)
+	out = append(out, `,"response_key":`...)
+	out = strconv.AppendQuote(out, field.responseKey)
+	if len(field.typeConditions) != 0 {
+		out = append(out, `,"type_conditions":[`...)
+		for i, typeCondition := range field.typeConditions {
+			if i != 0 {
+				out = append(out, ',')
+			}
+			out = strconv.AppendQuote(out, typeCondition)
+		}
+		out = append(out, ']')
+	}
+	if field.arguments != nil {
+		out = append(out, `,"arguments":`...)
+		out = append(out, field.arguments...)
+	}
+	if len(field.selections) != 0 {
+		out = append(out, `,"selections":[`...)
+		for i, selection := range field.selections {
+			if i != 0 {
+				out = append(out, ',')
+			}
+			out = appendFieldTemplate(out, selection)
+		}
+		out = append(out, ']')
+	}
+	return append(out, '}')
+}
+
+// Source resolves the root field of its input and all of its selections with a Resolver.
+// It responds with {"data":...,"errors":[...]}, errors of fields are reported with their path and the field is null.
+type Source struct {
+	resolver Resolver
+}
+
+func NewSource(resolver Resolver) *Source {
+	return &Source{
+		resolver: resolver,
+	}
+}
+
+type sourceInput struct {
+	TypeName string `json:"type_name"`
+	Field    Field  `json:"field"`
+}
+
+type sourceError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) error {
+	var in sourceInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return fmt.Errorf("resolver data source: invalid input: %w", err)
+	}
+
+	e := &execution{
+		ctx:      ctx,
+		resolver: s.resolver,
+	}
+	e.out.WriteString(`{"data":{`)
+	if err := e.writeField(in.TypeName, nil, &in.Field, nil); err != nil {
+		return err
+	}
+	e.out.WriteString(`}`)
+	if len(e.errors) != 0 {
+		errors, err := json.Marshal(e.errors)
+		if err != nil {
+			return err
+		}
+		e.out.WriteString(`,"errors":`)
+		e.out.Write(errors)
+	}
+	e.out.WriteString(`}`)
+
+	_, err := w.Write(e.out.Bytes())
+	return err
+}
+
+type execution struct {
+	ctx      context.Context
+	resolver Resolver
+	out      bytes.Buffer
+	errors   []sourceError
+}
+
+// writeField writes the response key and the value of a field of an object,
+// errors of the resolver are collected and the value is null
+func (e *execution) writeField(typeName string, object interface{}, field *Field, path []interface{}) error {
+	path = append(path[:len(path):len(path)], field.ResponseKey)
+	e.out.Write(strconv.AppendQuote(nil, field.ResponseKey))
+	e.out.WriteByte(':')
+
+	if field.Name == typeNameFieldName {
+		e.out.Write(strconv.AppendQuote(nil, typeName))
+		return nil
+	}
+
+	value, err := e.resolver.ResolveField(e.ctx, typeName, object, field)
+	if err != nil {
+		e.errors = append(e.errors, sourceError{Message: err.Error(), Path: path})
+		e.out.WriteString("null")
+		return nil
+	}
+	return e.writeValue(reflect.ValueOf(value), field, path)
+}
+
+func (e *execution) writeValue(value reflect.Value, field *Field, path []interface{}) error {
+	if len(field.Selections) == 0 {
+		data, err := json.Marshal(valueInterface(value))
+		if err != nil {
+			return err
+		}
+		e.out.Write(data)
+		return nil
+	}
+
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Invalid:
+		e.out.WriteString("null")
+		return nil
+	case reflect.Interface, reflect.Pointer, reflect.Map:
+		if value.IsNil() {
+			e.out.WriteString("null")
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			e.out.WriteString("null")
+			return nil
+		}
+		e.out.WriteByte('[')
+		for i := 0; i < value.Len(); i++ {
+			if i != 0 {
+				e.out.WriteByte(',')
+			}
+			if err := e.writeValue(value.Index(i), field, append(path[:len(path):len(path)], i)); err != nil {
+				return err
+			}
+		}
+		e.out.WriteByte(']')
+		return nil
+	}
+
+	object := value.Interface()
+	typeName, err := e.resolver.TypeName(object)
+	if err != nil {
+		return err
+	}
+	return e.writeObject(typeName, object, field.Selections, path)
+}
+
+// writeObject writes the selections which apply to the object type,
+// __typename is always written, so that the engine can resolve fragments on interfaces and unions
+func (e *execution) writeObject(typeName string, object interface{}, selections []*Field, path []interface{}) error {
+	e.out.WriteString(`{"__typename":`)
+	e.out.Write(strconv.AppendQuote(nil, typeName))
+	written := map[string]struct{}{typeNameFieldName: {}}
+	for _, selection := range selections {
+		if !selection.appliesTo(typeName) {
+			continue
+		}
+		// the same field might be selected on an interface and on a fragment of the object type
+		if _, ok := written[selection.ResponseKey]; ok {
+			continue
+		}
+		written[selection.ResponseKey] = struct{}{}
+		e.out.WriteByte(',')
+		if err := e.writeField(typeName, object, selection, path); err != nil {
+			return err
+		}
+	}
+	e.out.WriteByte('}')
+	return nil
+}
+
+func valueInterface(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
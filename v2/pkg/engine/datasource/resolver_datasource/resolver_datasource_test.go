@@ -0,0 +1,235 @@
+package resolver_datasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const testSchema = `
+	schema { query: Query mutation: Mutation }
+
+	type Query {
+		hero(name: String!): Character
+		search: [SearchResult]
+	}
+
+	type Mutation {
+		rename(from: String!, to: String!): Character
+	}
+
+	interface Character {
+		name: String!
+		friends(first: Int): [Character!]
+	}
+
+	type Human implements Character {
+		name: String!
+		height: Float
+		friends(first: Int): [Character!]
+	}
+
+	type Droid implements Character {
+		name: String!
+		primaryFunction: String!
+		friends(first: Int): [Character!]
+	}
+
+	type Starship {
+		name: String!
+	}
+
+	union SearchResult = Human | Droid | Starship
+`
+
+type human struct {
+	name    string
+	height  *float64
+	friends []string
+}
+
+type droid struct {
+	name            string
+	primaryFunction string
+	friends         []string
+}
+
+type starship struct {
+	name string
+}
+
+// testResolver is a hand-written Resolver like the ones generated by codegen.ResolverGen
+type testResolver struct {
+	characters map[string]interface{}
+}
+
+func newTestResolver() *testResolver {
+	height := 1.72
+	return &testResolver{
+		characters: map[string]interface{}{
+			"Luke":  &human{name: "Luke", height: &height, friends: []string{"R2-D2", "Leia"}},
+			"Leia":  &human{name: "Leia", friends: []string{"Luke"}},
+			"R2-D2": &droid{name: "R2-D2", primaryFunction: "Astromech", friends: []string{"Luke"}},
+		},
+	}
+}
+
+func (r *testResolver) friends(names []string, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		First *int `json:"first"`
+	}
+	if len(arguments) != 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, err
+		}
+	}
+	if args.First != nil && *args.First < len(names) {
+		names = names[:*args.First]
+	}
+	friends := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		friends = append(friends, r.characters[name])
+	}
+	return friends, nil
+}
+
+func (r *testResolver) ResolveField(ctx context.Context, typeName string, object interface{}, field *Field) (interface{}, error) {
+	switch typeName + "." + field.Name {
+	case "Query.hero":
+		var args struct {
+			Name string `json:"name"`
+		}
+		if err := field.UnmarshalArguments(&args); err != nil {
+			return nil, err
+		}
+		character, ok := r.characters[args.Name]
+		if !ok {
+			return nil, errors.New("hero not found")
+		}
+		return character, nil
+	case "Query.search":
+		return []interface{}{r.characters["Luke"], &starship{name: "Millennium Falcon"}, r.characters["R2-D2"]}, nil
+	case "Mutation.rename":
+		var args struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		}
+		if err := field.UnmarshalArguments(&args); err != nil {
+			return nil, err
+		}
+		character := r.characters[args.From].(*droid)
+		character.name = args.To
+		return character, nil
+	case "Human.name":
+		return object.(*human).name, nil
+	case "Human.height":
+		return object.(*human).height, nil
+	case "Human.friends":
+		return r.friends(object.(*human).friends, field.Arguments)
+	case "Droid.name":
+		return object.(*droid).name, nil
+	case "Droid.primaryFunction":
+		return object.(*droid).primaryFunction, nil
+	case "Droid.friends":
+		return r.friends(object.(*droid).friends, field.Arguments)
+	case "Starship.name":
+		return object.(*starship).name, nil
+	}
+	return nil, fmt.Errorf("unknown field %s.%s", typeName, field.Name)
+}
+
+func (r *testResolver) TypeName(object interface{}) (string, error) {
+	switch object.(type) {
+	case *human:
+		return "Human", nil
+	case *droid:
+		return "Droid", nil
+	case *starship:
+		return "Starship", nil
+	}
+	return "", fmt.Errorf("unexpected object %T", object)
+}
+
+func TestResolverDataSource(t *testing.T) {
+	schema, err := graphql.NewSchemaFromString(testSchema)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{"hero", "search"}},
+				{TypeName: "Mutation", FieldNames: []string{"rename"}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "Character", FieldNames: []string{"name", "friends"}},
+				{TypeName: "Human", FieldNames: []string{"name", "height", "friends"}},
+				{TypeName: "Droid", FieldNames: []string{"name", "primaryFunction", "friends"}},
+				{TypeName: "Starship", FieldNames: []string{"name"}},
+			},
+			Factory: &Factory{Resolver: newTestResolver()},
+			Custom:  ConfigJSON(Configuration{Schema: testSchema}),
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query, variables string) string {
+		t.Helper()
+		request := graphql.Request{Query: query}
+		if variables != "" {
+			request.Variables = json.RawMessage(variables)
+		}
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &request, &resultWriter))
+		return resultWriter.String()
+	}
+
+	t.Run("arguments, aliases and fragments", func(t *testing.T) {
+		response := execute(t, `query ($name: String!) {
+			luke: hero(name: $name) {
+				__typename
+				name
+				... on Human { height }
+				friends(first: 1) {
+					name
+					... on Droid { primaryFunction }
+				}
+			}
+			leia: hero(name: "Leia") { name }
+		}`, `{"name":"Luke"}`)
+		assert.Equal(t, `{"data":{"luke":{"__typename":"Human","name":"Luke","height":1.72,"friends":[{"name":"R2-D2","primaryFunction":"Astromech"}]},"leia":{"name":"Leia"}}}`, response)
+	})
+
+	t.Run("union", func(t *testing.T) {
+		response := execute(t, `{
+			search {
+				... on Character { name }
+				... on Starship { ship: name }
+			}
+		}`, "")
+		assert.Equal(t, `{"data":{"search":[{"name":"Luke"},{"ship":"Millennium Falcon"},{"name":"R2-D2"}]}}`, response)
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		response := execute(t, `mutation { rename(from: "R2-D2", to: "Artoo") { name } }`, "")
+		assert.Equal(t, `{"data":{"rename":{"name":"Artoo"}}}`, response)
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		response := execute(t, `{ hero(name: "Vader") { name } }`, "")
+		assert.Equal(t, `{"errors":[{"message":"hero not found","path":["hero"]}],"data":{"hero":null}}`, response)
+	})
+}
@@ -0,0 +1,191 @@
+package sql_datasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL dialect of a database, it determines how identifiers are quoted and how parameters are written
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+)
+
+func (d Dialect) quoteIdentifier(identifier string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// placeholder returns the placeholder of the n-th parameter, n starts at 1
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// ArgumentKind determines how an argument is applied to a query
+type ArgumentKind string
+
+const (
+	// ArgumentKindWhere compares a column with the argument, the condition is skipped if the argument is null
+	ArgumentKindWhere ArgumentKind = "where"
+	// ArgumentKindLimit limits the number of rows
+	ArgumentKindLimit ArgumentKind = "limit"
+	// ArgumentKindOffset skips a number of rows
+	ArgumentKindOffset ArgumentKind = "offset"
+	// ArgumentKindAfter selects the rows after the given value of a column (keyset pagination),
+	// the rows are ordered by the column
+	ArgumentKindAfter ArgumentKind = "after"
+)
+
+var operators = map[string]struct{}{
+	"=": {}, "<>": {}, "<": {}, "<=": {}, ">": {}, ">=": {}, "LIKE": {}, "IN": {},
+}
+
+// Configuration maps the object types of a schema to tables and views and the fields of the root types to queries
+type Configuration struct {
+	Dialect Dialect              `json:"dialect,omitempty"`
+	Tables  []TableConfiguration `json:"tables"`
+	Fields  []FieldConfiguration `json:"fields"`
+}
+
+// TableConfiguration maps an object type to a table or view
+type TableConfiguration struct {
+	TypeName string                `json:"type_name"`
+	Table    string                `json:"table"`
+	Columns  []ColumnConfiguration `json:"columns"`
+}
+
+// ColumnConfiguration maps a field to a column, the column defaults to the field name
+type ColumnConfiguration struct {
+	FieldName string `json:"field_name"`
+	Column    string `json:"column,omitempty"`
+}
+
+// FieldConfiguration configures a field which queries a table,
+// it's either a field of a root type or a Relation of an object type
+type FieldConfiguration struct {
+	TypeName  string                  `json:"type_name"`
+	FieldName string                  `json:"field_name"`
+	Relation  *RelationConfiguration  `json:"relation,omitempty"`
+	Arguments []ArgumentConfiguration `json:"arguments,omitempty"`
+	OrderBy   []OrderByConfiguration  `json:"order_by,omitempty"`
+}
+
+// RelationConfiguration relates the table of an object type to the table of the type of its field.
+// Relations to a single object are joined, lists are loaded for all parent objects at once with an IN condition,
+// in this case Column must be mapped to a field of the parent type.
+type RelationConfiguration struct {
+	// List must be set if the field is a list
+	List bool `json:"list,omitempty"`
+	// Column is the column of the table of the parent type
+	Column string `json:"column"`
+	// ReferencedColumn is the column of the table of the field's type
+	ReferencedColumn string `json:"referenced_column"`
+}
+
+// ArgumentConfiguration applies an argument of a field to the query
+type ArgumentConfiguration struct {
+	Name string       `json:"name"`
+	Kind ArgumentKind `json:"kind"`
+	// Column is the column of a where or after argument, it defaults to the argument name
+	Column string `json:"column,omitempty"`
+	// Operator is the comparison operator of a where argument, it defaults to "="
+	Operator string `json:"operator,omitempty"`
+}
+
+type OrderByConfiguration struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+func ConfigJSON(config Configuration) json.RawMessage {
+	out, _ := json.Marshal(config)
+	return out
+}
+
+func (c *Configuration) table(typeName string) *TableConfiguration {
+	for i := range c.Tables {
+		if c.Tables[i].TypeName == typeName {
+			return &c.Tables[i]
+		}
+	}
+	return nil
+}
+
+func (c *Configuration) field(typeName, fieldName string) *FieldConfiguration {
+	for i := range c.Fields {
+		if c.Fields[i].TypeName == typeName && c.Fields[i].FieldName == fieldName {
+			return &c.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (t *TableConfiguration) column(fieldName string) (string, bool) {
+	for _, column := range t.Columns {
+		if column.FieldName != fieldName {
+			continue
+		}
+		if column.Column == "" {
+			return column.FieldName, true
+		}
+		return column.Column, true
+	}
+	return "", false
+}
+
+// fieldName returns the field a column is mapped to
+func (t *TableConfiguration) fieldName(column string) (string, bool) {
+	for _, c := range t.Columns {
+		if c.Column == column || (c.Column == "" && c.FieldName == column) {
+			return c.FieldName, true
+		}
+	}
+	return "", false
+}
+
+// validate checks the parts of the configuration which are written into queries verbatim
+func (c *Configuration) validate() error {
+	switch c.Dialect {
+	case "", DialectSQLite, DialectPostgres, DialectMySQL:
+	default:
+		return fmt.Errorf("unsupported dialect %q", c.Dialect)
+	}
+	for _, field := range c.Fields {
+		for _, argument := range field.Arguments {
+			switch argument.Kind {
+			case ArgumentKindWhere:
+				if _, ok := operators[argument.operator()]; !ok {
+					return fmt.Errorf("unsupported operator %q of argument %s of %s.%s", argument.Operator, argument.Name, field.TypeName, field.FieldName)
+				}
+			case ArgumentKindLimit, ArgumentKindOffset, ArgumentKindAfter:
+			default:
+				return fmt.Errorf("unsupported kind %q of argument %s of %s.%s", argument.Kind, argument.Name, field.TypeName, field.FieldName)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *ArgumentConfiguration) column() string {
+	if a.Column == "" {
+		return a.Name
+	}
+	return a.Column
+}
+
+func (a *ArgumentConfiguration) operator() string {
+	if a.Operator == "" {
+		return "="
+	}
+	return strings.ToUpper(a.Operator)
+}
@@ -0,0 +1,231 @@
+package sql_datasource
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+)
+
+const (
+	TableDirectiveName    = "table"
+	ColumnDirectiveName   = "column"
+	RelationDirectiveName = "relation"
+	WhereDirectiveName    = "where"
+	LimitDirectiveName    = "limit"
+	OffsetDirectiveName   = "offset"
+	AfterDirectiveName    = "after"
+	OrderByDirectiveName  = "orderBy"
+)
+
+// DirectiveDefinitions are the definitions of the directives read by ConfigurationFromSchema,
+// they must be added to a schema which uses the directives
+const DirectiveDefinitions = `
+directive @table(name: String!) on OBJECT
+directive @column(name: String!) on FIELD_DEFINITION
+directive @relation(column: String!, referencedColumn: String!) on FIELD_DEFINITION
+directive @where(column: String, operator: String = "=") on ARGUMENT_DEFINITION
+directive @limit on ARGUMENT_DEFINITION
+directive @offset on ARGUMENT_DEFINITION
+directive @after(column: String) on ARGUMENT_DEFINITION
+directive @orderBy(column: String!, descending: Boolean = false) repeatable on FIELD_DEFINITION
+`
+
+// ConfigurationFromSchema creates the configuration of a data source from the directives of a schema:
+//
+//	type Customer @table(name: "customers") {
+//		id: ID!
+//		name: String! @column(name: "full_name")
+//		company: Company @relation(column: "company_id", referencedColumn: "id")
+//		orders: [Order!]! @relation(column: "id", referencedColumn: "customer_id")
+//	}
+//
+//	type Query {
+//		customers(country: String @where, first: Int @limit, after: ID @after(column: "id")): [Customer!]! @orderBy(column: "id")
+//	}
+//
+// Fields of object types with @table are columns unless they have a @relation, a field of any other object type is
+// a root field if its type has @table.
+func ConfigurationFromSchema(dialect Dialect, schema string) (Configuration, error) {
+	definition, report := astparser.ParseGraphqlDocumentString(schema)
+	if report.HasErrors() {
+		return Configuration{}, report
+	}
+
+	r := &directiveReader{
+		definition: &definition,
+		config:     Configuration{Dialect: dialect},
+	}
+	if err := r.read(); err != nil {
+		return Configuration{}, err
+	}
+	return r.config, r.config.validate()
+}
+
+type directiveReader struct {
+	definition *ast.Document
+	config     Configuration
+}
+
+func (r *directiveReader) read() error {
+	tables := make(map[string]struct{})
+	for ref := range r.definition.ObjectTypeDefinitions {
+		directive, ok := r.directive(r.definition.ObjectTypeDefinitions[ref].Directives.Refs, TableDirectiveName)
+		if !ok {
+			continue
+		}
+		name, err := r.stringArgument(directive, "name", true)
+		if err != nil {
+			return err
+		}
+		typeName := r.definition.ObjectTypeDefinitionNameString(ref)
+		tables[typeName] = struct{}{}
+		r.config.Tables = append(r.config.Tables, TableConfiguration{TypeName: typeName, Table: name})
+	}
+
+	for ref := range r.definition.ObjectTypeDefinitions {
+		typeName := r.definition.ObjectTypeDefinitionNameString(ref)
+		_, isTable := tables[typeName]
+		for _, fieldDefinition := range r.definition.ObjectTypeDefinitions[ref].FieldsDefinition.Refs {
+			if err := r.readField(typeName, isTable, fieldDefinition, tables); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *directiveReader) readField(typeName string, isTable bool, fieldDefinition int, tables map[string]struct{}) error {
+	fieldName := r.definition.FieldDefinitionNameString(fieldDefinition)
+	fieldType := r.definition.FieldDefinitionType(fieldDefinition)
+	_, isTableType := tables[r.definition.ResolveTypeNameString(fieldType)]
+	directives := r.definition.FieldDefinitionDirectives(fieldDefinition)
+
+	relation, isRelation := r.directive(directives, RelationDirectiveName)
+	switch {
+	case isTable && !isRelation:
+		if isTableType {
+			return fmt.Errorf("field %s.%s of a table type needs a @relation", typeName, fieldName)
+		}
+		column := ColumnConfiguration{FieldName: fieldName}
+		if directive, ok := r.directive(directives, ColumnDirectiveName); ok {
+			name, err := r.stringArgument(directive, "name", true)
+			if err != nil {
+				return err
+			}
+			column.Column = name
+		}
+		table := r.config.table(typeName)
+		table.Columns = append(table.Columns, column)
+		return nil
+	case !isTableType:
+		return nil
+	}
+
+	field := FieldConfiguration{
+		TypeName:  typeName,
+		FieldName: fieldName,
+	}
+	if isRelation {
+		column, err := r.stringArgument(relation, "column", true)
+		if err != nil {
+			return err
+		}
+		referencedColumn, err := r.stringArgument(relation, "referencedColumn", true)
+		if err != nil {
+			return err
+		}
+		field.Relation = &RelationConfiguration{
+			List:             r.definition.TypeIsList(fieldType),
+			Column:           column,
+			ReferencedColumn: referencedColumn,
+		}
+	}
+
+	for _, directive := range directives {
+		if r.definition.DirectiveNameString(directive) != OrderByDirectiveName {
+			continue
+		}
+		column, err := r.stringArgument(directive, "column", true)
+		if err != nil {
+			return err
+		}
+		field.OrderBy = append(field.OrderBy, OrderByConfiguration{
+			Column:     column,
+			Descending: r.booleanArgument(directive, "descending"),
+		})
+	}
+
+	for _, argument := range r.definition.FieldDefinitionArgumentsDefinitions(fieldDefinition) {
+		argumentConfig, ok, err := r.readArgument(argument)
+		if err != nil {
+			return err
+		}
+		if ok {
+			field.Arguments = append(field.Arguments, argumentConfig)
+		}
+	}
+
+	r.config.Fields = append(r.config.Fields, field)
+	return nil
+}
+
+func (r *directiveReader) readArgument(inputValueDefinition int) (argument ArgumentConfiguration, ok bool, err error) {
+	argument.Name = r.definition.InputValueDefinitionNameString(inputValueDefinition)
+	directives := r.definition.InputValueDefinitions[inputValueDefinition].Directives.Refs
+
+	if directive, ok := r.directive(directives, WhereDirectiveName); ok {
+		argument.Kind = ArgumentKindWhere
+		if argument.Column, err = r.stringArgument(directive, "column", false); err != nil {
+			return argument, false, err
+		}
+		if argument.Operator, err = r.stringArgument(directive, "operator", false); err != nil {
+			return argument, false, err
+		}
+		return argument, true, nil
+	}
+	if _, ok := r.directive(directives, LimitDirectiveName); ok {
+		argument.Kind = ArgumentKindLimit
+		return argument, true, nil
+	}
+	if _, ok := r.directive(directives, OffsetDirectiveName); ok {
+		argument.Kind = ArgumentKindOffset
+		return argument, true, nil
+	}
+	if directive, ok := r.directive(directives, AfterDirectiveName); ok {
+		argument.Kind = ArgumentKindAfter
+		if argument.Column, err = r.stringArgument(directive, "column", false); err != nil {
+			return argument, false, err
+		}
+		return argument, true, nil
+	}
+	return argument, false, nil
+}
+
+func (r *directiveReader) directive(directives []int, name string) (int, bool) {
+	for _, directive := range directives {
+		if r.definition.DirectiveNameString(directive) == name {
+			return directive, true
+		}
+	}
+	return -1, false
+}
+
+func (r *directiveReader) stringArgument(directive int, name string, required bool) (string, error) {
+	value, ok := r.definition.DirectiveArgumentValueByName(directive, []byte(name))
+	if !ok || value.Kind == ast.ValueKindNull {
+		if required {
+			return "", fmt.Errorf("missing argument %s of @%s", name, r.definition.DirectiveNameString(directive))
+		}
+		return "", nil
+	}
+	if value.Kind != ast.ValueKindString {
+		return "", fmt.Errorf("argument %s of @%s must be a string", name, r.definition.DirectiveNameString(directive))
+	}
+	return r.definition.StringValueContentString(value.Ref), nil
+}
+
+func (r *directiveReader) booleanArgument(directive int, name string) bool {
+	value, ok := r.definition.DirectiveArgumentValueByName(directive, []byte(name))
+	return ok && value.Kind == ast.ValueKindBoolean && bool(r.definition.BooleanValue(value.Ref))
+}
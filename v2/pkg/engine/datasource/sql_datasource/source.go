@@ -0,0 +1,534 @@
+package sql_datasource
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryInput describes a query built from the selection set of a field,
+// values of arguments are rendered into it when the query is loaded
+type queryInput struct {
+	Dialect     Dialect                `json:"dialect,omitempty"`
+	ResponseKey string                 `json:"response_key"`
+	List        bool                   `json:"list,omitempty"`
+	Select      selection              `json:"select"`
+	Where       []condition            `json:"where,omitempty"`
+	OrderBy     []OrderByConfiguration `json:"order_by,omitempty"`
+	Limit       json.RawMessage        `json:"limit,omitempty"`
+	Offset      json.RawMessage        `json:"offset,omitempty"`
+	After       *condition             `json:"after,omitempty"`
+	Batch       *batch                 `json:"batch,omitempty"`
+}
+
+type selection struct {
+	Table  string           `json:"table"`
+	Fields []fieldSelection `json:"fields"`
+}
+
+// fieldSelection is either a column, the name of the type for __typename or a joined table
+type fieldSelection struct {
+	ResponseKey string `json:"response_key"`
+	Column      string `json:"column,omitempty"`
+	// ID columns are serialized as strings like the ID scalar
+	ID       bool   `json:"id,omitempty"`
+	TypeName string `json:"type_name,omitempty"`
+	Join     *join  `json:"join,omitempty"`
+}
+
+type join struct {
+	selection
+	Column           string `json:"column"`
+	ReferencedColumn string `json:"referenced_column"`
+}
+
+type condition struct {
+	Column   string          `json:"column"`
+	Operator string          `json:"operator"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// batch loads the rows of a relation for multiple parent objects,
+// the rows are grouped by Column and matched with the KeyField of the objects
+type batch struct {
+	Column   string            `json:"column"`
+	KeyField string            `json:"key_field"`
+	Objects  []json.RawMessage `json:"objects"`
+}
+
+// Source executes the query of its input with a *sql.DB.
+// It responds with {"data":{"<response_key>":...}}, or with {"data":[{"<response_key>":[...]},...]} for the objects of a batch.
+type Source struct {
+	db *sql.DB
+}
+
+func NewSource(db *sql.DB) *Source {
+	return &Source{
+		db: db,
+	}
+}
+
+func (s *Source) Load(ctx context.Context, input []byte, w io.Writer) error {
+	var in queryInput
+	if err := json.Unmarshal(input, &in); err != nil {
+		return fmt.Errorf("sql data source: invalid input: %w", err)
+	}
+
+	q, err := buildQuery(&in)
+	if err != nil {
+		return fmt.Errorf("sql data source: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q.sql, q.args...)
+	if err != nil {
+		return fmt.Errorf("sql data source: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, q.columns)
+	scan := make([]interface{}, q.columns)
+	for i := range values {
+		scan[i] = &values[i]
+	}
+
+	var (
+		objects [][]byte
+		keys    []string
+	)
+	for rows.Next() {
+		if err = rows.Scan(scan...); err != nil {
+			return fmt.Errorf("sql data source: %w", err)
+		}
+		object, err := writeObject(nil, q.fields, values)
+		if err != nil {
+			return fmt.Errorf("sql data source: %w", err)
+		}
+		objects = append(objects, object)
+		if in.Batch != nil {
+			key, err := normalizedKey(jsonValue(values[q.batchColumn]))
+			if err != nil {
+				return fmt.Errorf("sql data source: %w", err)
+			}
+			keys = append(keys, key)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("sql data source: %w", err)
+	}
+
+	out := bytes.Buffer{}
+	if in.Batch == nil {
+		out.WriteString(`{"data":{`)
+		out.Write(strconv.AppendQuote(nil, in.ResponseKey))
+		out.WriteByte(':')
+		writeRows(&out, in.List, objects)
+		out.WriteString(`}}`)
+	} else {
+		out.WriteString(`{"data":[`)
+		for i, object := range in.Batch.Objects {
+			if i != 0 {
+				out.WriteByte(',')
+			}
+			key, err := objectKey(object, in.Batch.KeyField)
+			if err != nil {
+				return fmt.Errorf("sql data source: %w", err)
+			}
+			var matches [][]byte
+			for j := range objects {
+				if keys[j] == key {
+					matches = append(matches, objects[j])
+				}
+			}
+			out.WriteByte('{')
+			out.Write(strconv.AppendQuote(nil, in.ResponseKey))
+			out.WriteByte(':')
+			writeRows(&out, in.List, matches)
+			out.WriteByte('}')
+		}
+		out.WriteString(`]}`)
+	}
+
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+func writeRows(out *bytes.Buffer, list bool, objects [][]byte) {
+	if !list {
+		if len(objects) == 0 {
+			out.WriteString("null")
+			return
+		}
+		out.Write(objects[0])
+		return
+	}
+	out.WriteByte('[')
+	for i, object := range objects {
+		if i != 0 {
+			out.WriteByte(',')
+		}
+		out.Write(object)
+	}
+	out.WriteByte(']')
+}
+
+// query is a parameterized SQL query and the layout of its columns
+type query struct {
+	sql     string
+	args    []interface{}
+	columns int
+	fields  []selectedField
+	// batchColumn is the index of the column rows of a batch are grouped by
+	batchColumn int
+}
+
+// selectedField is a field of a response object with the index of its column
+type selectedField struct {
+	responseKey string
+	column      int
+	id          bool
+	typeName    string
+	// object is set for joined tables, the object is null if the column nullColumn is null
+	object     []selectedField
+	nullColumn int
+}
+
+type queryBuilder struct {
+	dialect Dialect
+	columns []string
+	from    strings.Builder
+	where   []string
+	args    []interface{}
+	tables  int
+}
+
+func (b *queryBuilder) addColumn(table, column string) int {
+	b.columns = append(b.columns, table+"."+b.dialect.quoteIdentifier(column))
+	return len(b.columns) - 1
+}
+
+func (b *queryBuilder) tableAlias() string {
+	alias := "t" + strconv.Itoa(b.tables)
+	b.tables++
+	return alias
+}
+
+func (b *queryBuilder) addArg(value interface{}) string {
+	b.args = append(b.args, value)
+	return b.dialect.placeholder(len(b.args))
+}
+
+// selectFields adds the columns of the fields of a table and joins the tables of its relations
+func (b *queryBuilder) selectFields(table string, fields []fieldSelection) []selectedField {
+	selected := make([]selectedField, 0, len(fields))
+	for _, field := range fields {
+		switch {
+		case field.Join != nil:
+			alias := b.tableAlias()
+			fmt.Fprintf(&b.from, " LEFT JOIN %s %s ON %s.%s = %s.%s",
+				b.dialect.quoteIdentifier(field.Join.Table), alias,
+				alias, b.dialect.quoteIdentifier(field.Join.ReferencedColumn),
+				table, b.dialect.quoteIdentifier(field.Join.Column),
+			)
+			nullColumn := b.addColumn(alias, field.Join.ReferencedColumn)
+			selected = append(selected, selectedField{
+				responseKey: field.ResponseKey,
+				object:      b.selectFields(alias, field.Join.Fields),
+				nullColumn:  nullColumn,
+			})
+		case field.TypeName != "":
+			selected = append(selected, selectedField{responseKey: field.ResponseKey, typeName: field.TypeName})
+		default:
+			selected = append(selected, selectedField{responseKey: field.ResponseKey, column: b.addColumn(table, field.Column), id: field.ID})
+		}
+	}
+	return selected
+}
+
+func (b *queryBuilder) addCondition(table string, c condition) error {
+	if isNull(c.Value) {
+		// conditions of omitted arguments are skipped
+		return nil
+	}
+	operator := strings.ToUpper(c.Operator)
+	if _, ok := operators[operator]; !ok {
+		return fmt.Errorf("unsupported operator %q", c.Operator)
+	}
+	column := table + "." + b.dialect.quoteIdentifier(c.Column)
+
+	if operator == "IN" {
+		var values []json.RawMessage
+		if err := json.Unmarshal(c.Value, &values); err != nil {
+			return fmt.Errorf("value of IN condition on %s must be a list: %w", c.Column, err)
+		}
+		if len(values) == 0 {
+			b.where = append(b.where, "1 = 0")
+			return nil
+		}
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			arg, err := sqlValue(value)
+			if err != nil {
+				return err
+			}
+			placeholders = append(placeholders, b.addArg(arg))
+		}
+		b.where = append(b.where, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+		return nil
+	}
+
+	arg, err := sqlValue(c.Value)
+	if err != nil {
+		return err
+	}
+	b.where = append(b.where, fmt.Sprintf("%s %s %s", column, operator, b.addArg(arg)))
+	return nil
+}
+
+func buildQuery(in *queryInput) (*query, error) {
+	b := &queryBuilder{
+		dialect: in.Dialect,
+	}
+	root := b.tableAlias()
+	b.from.WriteString(b.dialect.quoteIdentifier(in.Select.Table))
+	b.from.WriteString(" ")
+	b.from.WriteString(root)
+
+	q := &query{
+		fields: b.selectFields(root, in.Select.Fields),
+	}
+
+	if in.Batch != nil {
+		q.batchColumn = b.addColumn(root, in.Batch.Column)
+		keys := make([]json.RawMessage, 0, len(in.Batch.Objects))
+		for _, object := range in.Batch.Objects {
+			key, err := objectValue(object, in.Batch.KeyField)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		value, _ := json.Marshal(keys)
+		if err := b.addCondition(root, condition{Column: in.Batch.Column, Operator: "IN", Value: value}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range in.Where {
+		if err := b.addCondition(root, c); err != nil {
+			return nil, err
+		}
+	}
+
+	orderBy := in.OrderBy
+	if in.After != nil && !isNull(in.After.Value) {
+		// keyset pagination continues after the value in the order of the column,
+		// so the column is the leading order term and the other terms only order rows with equal values
+		orderBy = append(make([]OrderByConfiguration, 0, len(in.OrderBy)+1), OrderByConfiguration{Column: in.After.Column})
+		for _, o := range in.OrderBy {
+			if o.Column == in.After.Column {
+				orderBy[0].Descending = o.Descending
+				continue
+			}
+			orderBy = append(orderBy, o)
+		}
+		operator := ">"
+		if orderBy[0].Descending {
+			operator = "<"
+		}
+		if err := b.addCondition(root, condition{Column: in.After.Column, Operator: operator, Value: in.After.Value}); err != nil {
+			return nil, err
+		}
+	}
+
+	sqlQuery := strings.Builder{}
+	sqlQuery.WriteString("SELECT ")
+	sqlQuery.WriteString(strings.Join(b.columns, ", "))
+	sqlQuery.WriteString(" FROM ")
+	sqlQuery.WriteString(b.from.String())
+	if len(b.where) != 0 {
+		sqlQuery.WriteString(" WHERE ")
+		sqlQuery.WriteString(strings.Join(b.where, " AND "))
+	}
+	if len(orderBy) != 0 {
+		terms := make([]string, 0, len(orderBy))
+		for _, o := range orderBy {
+			term := root + "." + b.dialect.quoteIdentifier(o.Column)
+			if o.Descending {
+				term += " DESC"
+			}
+			terms = append(terms, term)
+		}
+		sqlQuery.WriteString(" ORDER BY ")
+		sqlQuery.WriteString(strings.Join(terms, ", "))
+	}
+
+	limit, offset := in.Limit, in.Offset
+	if !in.List && in.Batch == nil {
+		limit = json.RawMessage("1")
+	}
+	switch {
+	case !isNull(limit):
+		arg, err := paginationValue("limit", limit)
+		if err != nil {
+			return nil, err
+		}
+		sqlQuery.WriteString(" LIMIT ")
+		sqlQuery.WriteString(b.addArg(arg))
+	case !isNull(offset):
+		// an offset without a limit
+		switch b.dialect {
+		case DialectMySQL:
+			sqlQuery.WriteString(" LIMIT 18446744073709551615")
+		case DialectPostgres:
+		default:
+			sqlQuery.WriteString(" LIMIT -1")
+		}
+	}
+	if !isNull(offset) {
+		arg, err := paginationValue("offset", offset)
+		if err != nil {
+			return nil, err
+		}
+		sqlQuery.WriteString(" OFFSET ")
+		sqlQuery.WriteString(b.addArg(arg))
+	}
+
+	q.sql = sqlQuery.String()
+	q.args = b.args
+	q.columns = len(b.columns)
+	return q, nil
+}
+
+func isNull(value json.RawMessage) bool {
+	value = bytes.TrimSpace(value)
+	return len(value) == 0 || bytes.Equal(value, []byte("null"))
+}
+
+// paginationValue converts the value of a limit or offset into a parameter of a query,
+// negative values are rejected as some databases treat a negative limit as no limit
+func paginationValue(name string, value json.RawMessage) (interface{}, error) {
+	arg, err := sqlValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := arg.(int64); !ok || i < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative integer, got %s", name, string(value))
+	}
+	return arg, nil
+}
+
+// sqlValue converts a JSON value into a parameter of a query
+func sqlValue(value json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	case string, bool, nil:
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter %s", string(value))
+	}
+}
+
+// jsonValue converts a value scanned from a row into a value which is encoded as JSON
+func jsonValue(value interface{}) interface{} {
+	switch t := value.(type) {
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	default:
+		return t
+	}
+}
+
+func writeObject(out []byte, fields []selectedField, values []interface{}) ([]byte, error) {
+	out = append(out, '{')
+	for i, field := range fields {
+		if i != 0 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, field.responseKey)
+		out = append(out, ':')
+		switch {
+		case field.object != nil:
+			if values[field.nullColumn] == nil {
+				out = append(out, "null"...)
+				continue
+			}
+			var err error
+			if out, err = writeObject(out, field.object, values); err != nil {
+				return nil, err
+			}
+		case field.typeName != "":
+			out = strconv.AppendQuote(out, field.typeName)
+		default:
+			v := jsonValue(values[field.column])
+			if field.id && v != nil {
+				v = fmt.Sprint(v)
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value...)
+		}
+	}
+	return append(out, '}'), nil
+}
+
+func objectValue(object json.RawMessage, field string) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return nil, err
+	}
+	value, ok := fields[field]
+	if !ok {
+		return json.RawMessage("null"), nil
+	}
+	return value, nil
+}
+
+func objectKey(object json.RawMessage, field string) (string, error) {
+	value, err := objectValue(object, field)
+	if err != nil {
+		return "", err
+	}
+	var v interface{}
+	if err = json.Unmarshal(value, &v); err != nil {
+		return "", err
+	}
+	return normalizedKey(v)
+}
+
+// normalizedKey encodes a value so that keys of objects and of rows are comparable,
+// e.g. the ID "1" of an object and the integer 1 of a row
+func normalizedKey(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err = json.Marshal(v)
+	return string(data), err
+}
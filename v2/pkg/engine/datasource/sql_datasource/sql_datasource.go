@@ -0,0 +1,395 @@
+// Package sql_datasource resolves fields from tables and views of a database/sql database.
+// Queries are generated from the selection set: only selected columns are queried, arguments become conditions
+// or pagination, relations to single objects are joined and lists of related rows are loaded for all parent objects
+// with a single IN query by the batching of entity fetches.
+package sql_datasource
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+const typeNameFieldName = "__typename"
+
+type Factory struct {
+	config Configuration
+	source *Source
+}
+
+func NewFactory(db *sql.DB, config Configuration) *Factory {
+	return &Factory{
+		config: config,
+		source: NewSource(db),
+	}
+}
+
+// DataSourceConfiguration returns the configuration of a data source which has the fields of the root types and
+// the lists of related rows as root nodes and the columns and joined relations of the tables as child nodes
+func (f *Factory) DataSourceConfiguration() plan.DataSourceConfiguration {
+	var (
+		rootNodes  plan.TypeFields
+		childNodes plan.TypeFields
+		requires   plan.FederationFieldConfigurations
+	)
+
+	for _, table := range f.config.Tables {
+		fieldNames := make([]string, 0, len(table.Columns)+1)
+		for _, column := range table.Columns {
+			fieldNames = append(fieldNames, column.FieldName)
+		}
+		childNodes = append(childNodes, plan.TypeField{TypeName: table.TypeName, FieldNames: append(fieldNames, typeNameFieldName)})
+	}
+
+	for _, field := range f.config.Fields {
+		if field.Relation == nil || field.Relation.List {
+			rootNodes = addTypeField(rootNodes, field.TypeName, field.FieldName)
+		} else {
+			childNodes = addTypeField(childNodes, field.TypeName, field.FieldName)
+		}
+		if field.Relation == nil || !field.Relation.List {
+			continue
+		}
+		if table := f.config.table(field.TypeName); table != nil {
+			if keyField, ok := table.fieldName(field.Relation.Column); ok {
+				requires = append(requires, plan.FederationFieldConfiguration{
+					TypeName:     field.TypeName,
+					FieldName:    field.FieldName,
+					SelectionSet: keyField,
+				})
+			}
+		}
+	}
+
+	return plan.DataSourceConfiguration{
+		RootNodes:  rootNodes,
+		ChildNodes: childNodes,
+		Factory:    f,
+		Custom:     ConfigJSON(f.config),
+		FederationMetaData: plan.FederationMetaData{
+			Requires: requires,
+		},
+	}
+}
+
+func addTypeField(typeFields plan.TypeFields, typeName, fieldName string) plan.TypeFields {
+	for i := range typeFields {
+		if typeFields[i].TypeName == typeName {
+			typeFields[i].FieldNames = append(typeFields[i].FieldNames, fieldName)
+			return typeFields
+		}
+	}
+	return append(typeFields, plan.TypeField{TypeName: typeName, FieldNames: []string{fieldName}})
+}
+
+func (f *Factory) Planner(ctx context.Context) plan.DataSourcePlanner {
+	return &Planner{
+		source: f.source,
+	}
+}
+
+type Planner struct {
+	visitor          *plan.Visitor
+	config           Configuration
+	dataSourceConfig plan.DataSourceConfiguration
+	plannerConfig    plan.DataSourcePlannerConfiguration
+	source           *Source
+	input            *queryInput
+	// objects contains an entry per entered field, the selection of a table for the fields of a table and nil otherwise
+	objects   []*tableSelection
+	variables resolve.Variables
+	// variableNames are the context variables which are rendered as JSON strings into the input
+	variableNames []string
+}
+
+type tableSelection struct {
+	selection *selection
+	table     *TableConfiguration
+}
+
+func (p *Planner) UpstreamSchema(dataSourceConfig plan.DataSourceConfiguration) *ast.Document {
+	return nil
+}
+
+func (p *Planner) DownstreamResponseFieldAlias(downstreamFieldRef int) (alias string, exists bool) {
+	// skip, not required
+	return
+}
+
+func (p *Planner) DataSourcePlanningBehavior() plan.DataSourcePlanningBehavior {
+	return plan.DataSourcePlanningBehavior{
+		MergeAliasedRootNodes:      false,
+		OverrideFieldPathFromAlias: true,
+		IncludeTypeNameFields:      true,
+	}
+}
+
+func (p *Planner) Register(visitor *plan.Visitor, configuration plan.DataSourceConfiguration, dataSourcePlannerConfiguration plan.DataSourcePlannerConfiguration) error {
+	p.visitor = visitor
+	p.dataSourceConfig = configuration
+	p.plannerConfig = dataSourcePlannerConfiguration
+	visitor.Walker.RegisterEnterFieldVisitor(p)
+	visitor.Walker.RegisterLeaveFieldVisitor(p)
+	if err := json.Unmarshal(configuration.Custom, &p.config); err != nil {
+		return err
+	}
+	return p.config.validate()
+}
+
+func (p *Planner) EnterField(ref int) {
+	if p.input == nil {
+		p.objects = append(p.objects, p.enterRootField(ref))
+		return
+	}
+
+	current := p.objects[len(p.objects)-1]
+	if current == nil {
+		p.objects = append(p.objects, nil)
+		return
+	}
+
+	fieldName := p.visitor.Operation.FieldNameString(ref)
+	responseKey := p.visitor.Operation.FieldAliasOrNameString(ref)
+
+	if fieldName == typeNameFieldName {
+		current.selection.Fields = append(current.selection.Fields, fieldSelection{ResponseKey: responseKey, TypeName: current.table.TypeName})
+		p.objects = append(p.objects, nil)
+		return
+	}
+
+	if column, ok := current.table.column(fieldName); ok {
+		current.selection.Fields = append(current.selection.Fields, fieldSelection{ResponseKey: responseKey, Column: column, ID: p.isID(ref)})
+		p.objects = append(p.objects, nil)
+		return
+	}
+
+	fieldConfig := p.config.field(current.table.TypeName, fieldName)
+	if fieldConfig == nil || fieldConfig.Relation == nil || fieldConfig.Relation.List {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: field %s.%s is neither a column nor a joined relation", current.table.TypeName, fieldName))
+		return
+	}
+	table, ok := p.fieldTable(ref)
+	if !ok {
+		return
+	}
+	j := &join{
+		selection:        selection{Table: table.Table},
+		Column:           fieldConfig.Relation.Column,
+		ReferencedColumn: fieldConfig.Relation.ReferencedColumn,
+	}
+	current.selection.Fields = append(current.selection.Fields, fieldSelection{ResponseKey: responseKey, Join: j})
+	p.objects = append(p.objects, &tableSelection{selection: &j.selection, table: table})
+}
+
+// enterRootField creates the query of the root field of the planner,
+// it returns nil for the fields of the parent path of a nested planner
+func (p *Planner) enterRootField(ref int) *tableSelection {
+	responseKey := p.visitor.Operation.FieldAliasOrNameString(ref)
+	if p.visitor.Walker.Path.DotDelimitedString()+"."+responseKey == p.plannerConfig.ParentPath {
+		return nil
+	}
+
+	typeName := p.visitor.Walker.EnclosingTypeDefinition.NameString(p.visitor.Definition)
+	fieldName := p.visitor.Operation.FieldNameString(ref)
+	if !p.dataSourceConfig.HasRootNode(typeName, fieldName) {
+		return nil
+	}
+	fieldConfig := p.config.field(typeName, fieldName)
+	if fieldConfig == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: missing configuration of field %s.%s", typeName, fieldName))
+		return nil
+	}
+	table, ok := p.fieldTable(ref)
+	if !ok {
+		return nil
+	}
+
+	fieldDefinition, _ := p.visitor.Walker.FieldDefinition(ref)
+	p.input = &queryInput{
+		Dialect:     p.config.Dialect,
+		ResponseKey: responseKey,
+		List:        p.visitor.Definition.TypeIsList(p.visitor.Definition.FieldDefinitionType(fieldDefinition)),
+		Select:      selection{Table: table.Table},
+		OrderBy:     fieldConfig.OrderBy,
+	}
+
+	if fieldConfig.Relation != nil {
+		parentTable := p.config.table(typeName)
+		if parentTable == nil {
+			p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: missing table of type %s", typeName))
+			return nil
+		}
+		keyField, ok := parentTable.fieldName(fieldConfig.Relation.Column)
+		if !ok {
+			p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: column %s of relation %s.%s is not a field of %s", fieldConfig.Relation.Column, typeName, fieldName, typeName))
+			return nil
+		}
+		p.input.Batch = &batch{
+			Column:   fieldConfig.Relation.ReferencedColumn,
+			KeyField: keyField,
+		}
+	}
+
+	p.applyArguments(ref, fieldConfig)
+	return &tableSelection{selection: &p.input.Select, table: table}
+}
+
+func (p *Planner) isID(ref int) bool {
+	fieldDefinition, ok := p.visitor.Walker.FieldDefinition(ref)
+	return ok && p.visitor.Definition.ResolveTypeNameString(p.visitor.Definition.FieldDefinitionType(fieldDefinition)) == "ID"
+}
+
+// fieldTable returns the table of the type of a field
+func (p *Planner) fieldTable(ref int) (*TableConfiguration, bool) {
+	fieldDefinition, ok := p.visitor.Walker.FieldDefinition(ref)
+	if !ok {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: missing definition of field %s", p.visitor.Operation.FieldNameString(ref)))
+		return nil, false
+	}
+	typeName := p.visitor.Definition.ResolveTypeNameString(p.visitor.Definition.FieldDefinitionType(fieldDefinition))
+	table := p.config.table(typeName)
+	if table == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: missing table of type %s", typeName))
+		return nil, false
+	}
+	return table, true
+}
+
+func (p *Planner) LeaveField(ref int) {
+	if len(p.objects) != 0 {
+		p.objects = p.objects[:len(p.objects)-1]
+	}
+}
+
+// applyArguments adds the configured arguments of a field to the query, omitted arguments are skipped
+func (p *Planner) applyArguments(fieldRef int, fieldConfig *FieldConfiguration) {
+	for _, argument := range fieldConfig.Arguments {
+		argumentRef, ok := p.visitor.Operation.FieldArgument(fieldRef, []byte(argument.Name))
+		if !ok {
+			continue
+		}
+		value, ok := p.renderArgument(argumentRef)
+		if !ok {
+			return
+		}
+		switch argument.Kind {
+		case ArgumentKindWhere:
+			p.input.Where = append(p.input.Where, condition{Column: argument.column(), Operator: argument.operator(), Value: value})
+		case ArgumentKindLimit:
+			p.input.Limit = value
+		case ArgumentKindOffset:
+			p.input.Offset = value
+		case ArgumentKindAfter:
+			p.input.After = &condition{Column: argument.column(), Value: value}
+		}
+	}
+}
+
+// renderArgument renders the value of an argument as JSON, variables are rendered as quoted context variables
+// which are unquoted in ConfigureFetch
+func (p *Planner) renderArgument(argumentRef int) (json.RawMessage, bool) {
+	value := p.visitor.Operation.ArgumentValue(argumentRef)
+	if value.Kind != ast.ValueKindVariable {
+		literal, err := p.visitor.Operation.ValueToJSON(value)
+		if err != nil {
+			p.visitor.Walker.StopWithInternalErr(err)
+			return nil, false
+		}
+		return literal, true
+	}
+
+	variableName := p.visitor.Operation.VariableValueNameBytes(value.Ref)
+	variableDefinition, exists := p.visitor.Operation.VariableDefinitionByNameAndOperation(p.visitor.Walker.Ancestors[0].Ref, variableName)
+	if !exists {
+		return json.RawMessage("null"), true
+	}
+	renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(p.visitor.Operation, p.visitor.Definition, p.visitor.Operation.VariableDefinitions[variableDefinition].Type, graphqljsonschema.WithScalars(p.visitor.Config.Scalars))
+	if err != nil {
+		p.visitor.Walker.StopWithInternalErr(err)
+		return nil, false
+	}
+	contextVariableName, _ := p.variables.AddVariable(&resolve.ContextVariable{
+		Path:     []string{string(variableName)},
+		Renderer: renderer,
+	})
+	p.variableNames = append(p.variableNames, contextVariableName)
+	return json.RawMessage(`"` + contextVariableName + `"`), true
+}
+
+// objectsVariable renders the key fields of the parent objects of a batched relation
+func (p *Planner) objectsVariable() resolve.Variable {
+	return resolve.NewResolvableObjectVariable(&resolve.Object{
+		Nullable: true,
+		Fields: []*resolve.Field{
+			{
+				Name: []byte(p.input.Batch.KeyField),
+				Value: &resolve.Scalar{
+					Path:     []string{p.input.Batch.KeyField},
+					Nullable: true,
+				},
+			},
+		},
+	})
+}
+
+func (p *Planner) ConfigureFetch() resolve.FetchConfiguration {
+	if p.input == nil {
+		p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: no root field planned"))
+		return resolve.FetchConfiguration{}
+	}
+
+	if p.input.Batch != nil {
+		objects, _ := p.variables.AddVariable(p.objectsVariable())
+		p.variableNames = append(p.variableNames, objects)
+		p.input.Batch.Objects = []json.RawMessage{json.RawMessage(`"` + objects + `"`)}
+	}
+
+	input, err := json.Marshal(p.input)
+	if err != nil {
+		p.visitor.Walker.StopWithInternalErr(err)
+		return resolve.FetchConfiguration{}
+	}
+	rendered := string(input)
+	for _, name := range p.variableNames {
+		rendered = strings.ReplaceAll(rendered, `"`+name+`"`, name)
+	}
+
+	postProcessing := resolve.PostProcessingConfiguration{
+		SelectResponseDataPath: []string{"data"},
+	}
+	if p.input.Batch == nil {
+		return resolve.FetchConfiguration{
+			Input:          rendered,
+			Variables:      p.variables,
+			DataSource:     p.source,
+			PostProcessing: postProcessing,
+		}
+	}
+
+	batch := p.plannerConfig.PathType != plan.PlannerPathObject
+	if !batch {
+		postProcessing.SelectResponseDataPath = []string{"data", "[0]"}
+	}
+	return resolve.FetchConfiguration{
+		Input:                                 rendered,
+		Variables:                             p.variables,
+		DataSource:                            p.source,
+		RequiresSerialFetch:                   true,
+		RequiresEntityFetch:                   !batch,
+		RequiresEntityBatchFetch:              batch,
+		SetTemplateOutputToNullOnVariableNull: true,
+		PostProcessing:                        postProcessing,
+	}
+}
+
+func (p *Planner) ConfigureSubscription() plan.SubscriptionConfiguration {
+	p.visitor.Walker.StopWithInternalErr(fmt.Errorf("sql data source: subscriptions are not supported"))
+	return plan.SubscriptionConfiguration{}
+}
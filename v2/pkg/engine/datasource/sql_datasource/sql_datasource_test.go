@@ -0,0 +1,286 @@
+package sql_datasource
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const testSchema = `
+	schema { query: Query }
+
+	type Query {
+		customers(
+			country: String @where
+			minAge: Int @where(column: "age", operator: ">=")
+			ids: [ID!] @where(column: "id", operator: "IN")
+			first: Int @limit
+			skip: Int @offset
+			after: ID @after(column: "id")
+		): [Customer!]! @orderBy(column: "id")
+		customer(id: ID! @where): Customer
+	}
+
+	type Customer @table(name: "customers") {
+		id: ID!
+		name: String! @column(name: "full_name")
+		country: String!
+		age: Int
+		company: Company @relation(column: "company_id", referencedColumn: "id")
+		orders: [Order!]! @relation(column: "id", referencedColumn: "customer_id") @orderBy(column: "total", descending: true)
+	}
+
+	type Company @table(name: "companies") {
+		id: ID!
+		name: String!
+	}
+
+	type Order @table(name: "orders") {
+		id: ID!
+		total: Float!
+		customer: Customer @relation(column: "customer_id", referencedColumn: "id")
+	}
+`
+
+const testData = `
+	CREATE TABLE companies (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE customers (id INTEGER PRIMARY KEY, full_name TEXT NOT NULL, country TEXT NOT NULL, age INTEGER, company_id INTEGER REFERENCES companies(id));
+	CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers(id), total REAL NOT NULL);
+
+	INSERT INTO companies VALUES (1, 'Acme');
+	INSERT INTO customers VALUES (1, 'Ada', 'UK', 36, 1), (2, 'Grace', 'US', 45, NULL), (3, 'Linus', 'FI', NULL, 1), (4, 'Ken', 'US', 30, NULL);
+	INSERT INTO orders VALUES (1, 1, 10.5), (2, 1, 20), (3, 3, 5), (4, 2, 7.25);
+`
+
+func TestConfigurationFromSchema(t *testing.T) {
+	config, err := ConfigurationFromSchema(DialectSQLite, testSchema)
+	require.NoError(t, err)
+
+	assert.Equal(t, Configuration{
+		Dialect: DialectSQLite,
+		Tables: []TableConfiguration{
+			{TypeName: "Customer", Table: "customers", Columns: []ColumnConfiguration{{FieldName: "id"}, {FieldName: "name", Column: "full_name"}, {FieldName: "country"}, {FieldName: "age"}}},
+			{TypeName: "Company", Table: "companies", Columns: []ColumnConfiguration{{FieldName: "id"}, {FieldName: "name"}}},
+			{TypeName: "Order", Table: "orders", Columns: []ColumnConfiguration{{FieldName: "id"}, {FieldName: "total"}}},
+		},
+		Fields: []FieldConfiguration{
+			{
+				TypeName:  "Query",
+				FieldName: "customers",
+				Arguments: []ArgumentConfiguration{
+					{Name: "country", Kind: ArgumentKindWhere},
+					{Name: "minAge", Kind: ArgumentKindWhere, Column: "age", Operator: ">="},
+					{Name: "ids", Kind: ArgumentKindWhere, Column: "id", Operator: "IN"},
+					{Name: "first", Kind: ArgumentKindLimit},
+					{Name: "skip", Kind: ArgumentKindOffset},
+					{Name: "after", Kind: ArgumentKindAfter, Column: "id"},
+				},
+				OrderBy: []OrderByConfiguration{{Column: "id"}},
+			},
+			{
+				TypeName:  "Query",
+				FieldName: "customer",
+				Arguments: []ArgumentConfiguration{{Name: "id", Kind: ArgumentKindWhere}},
+			},
+			{
+				TypeName:  "Customer",
+				FieldName: "company",
+				Relation:  &RelationConfiguration{Column: "company_id", ReferencedColumn: "id"},
+			},
+			{
+				TypeName:  "Customer",
+				FieldName: "orders",
+				Relation:  &RelationConfiguration{List: true, Column: "id", ReferencedColumn: "customer_id"},
+				OrderBy:   []OrderByConfiguration{{Column: "total", Descending: true}},
+			},
+			{
+				TypeName:  "Order",
+				FieldName: "customer",
+				Relation:  &RelationConfiguration{Column: "customer_id", ReferencedColumn: "id"},
+			},
+		},
+	}, config)
+}
+
+func TestBuildQuery(t *testing.T) {
+	t.Run("columns, joins, conditions and pagination", func(t *testing.T) {
+		q, err := buildQuery(&queryInput{
+			Dialect: DialectPostgres,
+			List:    true,
+			Select: selection{
+				Table: "customers",
+				Fields: []fieldSelection{
+					{ResponseKey: "id", Column: "id"},
+					{ResponseKey: "__typename", TypeName: "Customer"},
+					{ResponseKey: "company", Join: &join{
+						selection:        selection{Table: "companies", Fields: []fieldSelection{{ResponseKey: "name", Column: "name"}}},
+						Column:           "company_id",
+						ReferencedColumn: "id",
+					}},
+				},
+			},
+			Where: []condition{
+				{Column: "country", Operator: "=", Value: json.RawMessage(`"US"`)},
+				{Column: "age", Operator: ">=", Value: json.RawMessage(`null`)},
+				{Column: "id", Operator: "IN", Value: json.RawMessage(`["1",2]`)},
+			},
+			OrderBy: []OrderByConfiguration{{Column: "age", Descending: true}},
+			Limit:   json.RawMessage(`10`),
+			Offset:  json.RawMessage(`20`),
+			After:   &condition{Column: "id", Value: json.RawMessage(`"5"`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT t0."id", t1."id", t1."name" FROM "customers" t0 LEFT JOIN "companies" t1 ON t1."id" = t0."company_id" WHERE t0."country" = $1 AND t0."id" IN ($2, $3) AND t0."id" > $4 ORDER BY t0."id", t0."age" DESC LIMIT $5 OFFSET $6`, q.sql)
+		assert.Equal(t, []interface{}{"US", "1", int64(2), "5", int64(10), int64(20)}, q.args)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		q, err := buildQuery(&queryInput{
+			List:   true,
+			Select: selection{Table: "orders", Fields: []fieldSelection{{ResponseKey: "total", Column: "total"}}},
+			Batch: &batch{
+				Column:   "customer_id",
+				KeyField: "id",
+				Objects:  []json.RawMessage{json.RawMessage(`{"id":"1"}`), json.RawMessage(`{"id":"3"}`)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT t0."total", t0."customer_id" FROM "orders" t0 WHERE t0."customer_id" IN (?, ?)`, q.sql)
+		assert.Equal(t, []interface{}{"1", "3"}, q.args)
+	})
+
+	t.Run("offset without limit", func(t *testing.T) {
+		q, err := buildQuery(&queryInput{
+			Dialect: DialectMySQL,
+			List:    true,
+			Select:  selection{Table: "orders", Fields: []fieldSelection{{ResponseKey: "id", Column: "id"}}},
+			Offset:  json.RawMessage(`2`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT t0.`id` FROM `orders` t0 LIMIT 18446744073709551615 OFFSET ?", q.sql)
+	})
+
+	t.Run("keyset column leads the order", func(t *testing.T) {
+		q, err := buildQuery(&queryInput{
+			List:    true,
+			Select:  selection{Table: "orders", Fields: []fieldSelection{{ResponseKey: "id", Column: "id"}}},
+			OrderBy: []OrderByConfiguration{{Column: "total"}, {Column: "id", Descending: true}},
+			After:   &condition{Column: "id", Value: json.RawMessage(`7`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT t0."id" FROM "orders" t0 WHERE t0."id" < ? ORDER BY t0."id" DESC, t0."total"`, q.sql)
+	})
+
+	t.Run("negative limit and offset", func(t *testing.T) {
+		_, err := buildQuery(&queryInput{
+			List:   true,
+			Select: selection{Table: "orders", Fields: []fieldSelection{{ResponseKey: "id", Column: "id"}}},
+			Limit:  json.RawMessage(`-1`),
+		})
+		assert.EqualError(t, err, "limit must be a non-negative integer, got -1")
+
+		_, err = buildQuery(&queryInput{
+			List:   true,
+			Select: selection{Table: "orders", Fields: []fieldSelection{{ResponseKey: "id", Column: "id"}}},
+			Offset: json.RawMessage(`-5`),
+		})
+		assert.EqualError(t, err, "offset must be a non-negative integer, got -5")
+	})
+}
+
+func TestSQLDataSource(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	// every connection has its own in-memory database
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(testData)
+	require.NoError(t, err)
+
+	schemaSDL := DirectiveDefinitions + testSchema
+	schema, err := graphql.NewSchemaFromString(schemaSDL)
+	require.NoError(t, err)
+	config, err := ConfigurationFromSchema(DialectSQLite, schemaSDL)
+	require.NoError(t, err)
+
+	engineConf := graphql.NewEngineV2Configuration(schema)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		NewFactory(db, config).DataSourceConfiguration(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, query, variables string) string {
+		t.Helper()
+		request := graphql.Request{Query: query}
+		if variables != "" {
+			request.Variables = json.RawMessage(variables)
+		}
+		resultWriter := graphql.NewEngineResultWriter()
+		require.NoError(t, engine.Execute(context.Background(), &request, &resultWriter))
+		return resultWriter.String()
+	}
+
+	t.Run("conditions from arguments", func(t *testing.T) {
+		response := execute(t, `query ($country: String, $minAge: Int) {
+			customers(country: $country, minAge: $minAge) { id name }
+		}`, `{"country":"US","minAge":40}`)
+		assert.Equal(t, `{"data":{"customers":[{"id":"2","name":"Grace"}]}}`, response)
+	})
+
+	t.Run("omitted arguments are skipped", func(t *testing.T) {
+		response := execute(t, `query ($country: String) { customers(country: $country) { name } }`, `{}`)
+		assert.Equal(t, `{"data":{"customers":[{"name":"Ada"},{"name":"Grace"},{"name":"Linus"},{"name":"Ken"}]}}`, response)
+	})
+
+	t.Run("in condition", func(t *testing.T) {
+		response := execute(t, `{ customers(ids: ["1", "4"]) { name } }`, "")
+		assert.Equal(t, `{"data":{"customers":[{"name":"Ada"},{"name":"Ken"}]}}`, response)
+	})
+
+	t.Run("limit and offset", func(t *testing.T) {
+		response := execute(t, `{ customers(first: 2, skip: 1) { name } }`, "")
+		assert.Equal(t, `{"data":{"customers":[{"name":"Grace"},{"name":"Linus"}]}}`, response)
+	})
+
+	t.Run("keyset pagination", func(t *testing.T) {
+		response := execute(t, `query ($after: ID) { customers(first: 2, after: $after) { id } }`, `{"after":"2"}`)
+		assert.Equal(t, `{"data":{"customers":[{"id":"3"},{"id":"4"}]}}`, response)
+	})
+
+	t.Run("single object with joined relation and aliases", func(t *testing.T) {
+		response := execute(t, `{
+			ada: customer(id: "1") { __typename fullName: name age company { name } }
+			grace: customer(id: "2") { name company { name } }
+			nobody: customer(id: "42") { name }
+		}`, "")
+		assert.Equal(t, `{"data":{"ada":{"__typename":"Customer","fullName":"Ada","age":36,"company":{"name":"Acme"}},"grace":{"name":"Grace","company":null},"nobody":null}}`, response)
+	})
+
+	t.Run("batched relation of a list", func(t *testing.T) {
+		response := execute(t, `{
+			customers(first: 3) {
+				name
+				orders { total customer { name } }
+			}
+		}`, "")
+		assert.Equal(t, `{"data":{"customers":[{"name":"Ada","orders":[{"total":20,"customer":{"name":"Ada"}},{"total":10.5,"customer":{"name":"Ada"}}]},{"name":"Grace","orders":[{"total":7.25,"customer":{"name":"Grace"}}]},{"name":"Linus","orders":[{"total":5,"customer":{"name":"Linus"}}]}]}}`, response)
+	})
+
+	t.Run("batched relation of an object", func(t *testing.T) {
+		response := execute(t, `{ customer(id: "4") { name orders { total } } }`, "")
+		assert.Equal(t, `{"data":{"customer":{"name":"Ken","orders":[]}}}`, response)
+	})
+}
@@ -0,0 +1,56 @@
+package plan
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// addAbstractTypeNames adds a __typename selection to the selection sets of fields of interfaces and unions
+// which don't select it, so that the possible types of their objects can be validated.
+// It returns the refs of the added fields, which should not be added to the response.
+func addAbstractTypeNames(operation, definition *ast.Document, report *operationreport.Report) (addedFieldRefs []int) {
+	walker := astvisitor.NewWalker(48)
+	visitor := &abstractTypeNameVisitor{
+		Walker:     &walker,
+		operation:  operation,
+		definition: definition,
+	}
+	walker.RegisterEnterFieldVisitor(visitor)
+	walker.Walk(operation, definition, report)
+	return visitor.addedFieldRefs
+}
+
+type abstractTypeNameVisitor struct {
+	*astvisitor.Walker
+	operation, definition *ast.Document
+	addedFieldRefs        []int
+}
+
+func (v *abstractTypeNameVisitor) EnterField(ref int) {
+	selectionSet, ok := v.operation.FieldSelectionSet(ref)
+	if !ok {
+		return
+	}
+	fieldDefinition, ok := v.Walker.FieldDefinition(ref)
+	if !ok {
+		return
+	}
+	fieldType := v.definition.FieldDefinitionTypeNode(fieldDefinition)
+	if fieldType.Kind != ast.NodeKindInterfaceTypeDefinition && fieldType.Kind != ast.NodeKindUnionTypeDefinition {
+		return
+	}
+	if hasTypeName, _ := v.operation.SelectionSetHasFieldSelectionWithExactName(selectionSet, literal.TYPENAME); hasTypeName {
+		return
+	}
+
+	field := v.operation.AddField(ast.Field{
+		Name: v.operation.Input.AppendInputString(typeNameField),
+	})
+	v.operation.AddSelection(selectionSet, ast.Selection{
+		Kind: ast.SelectionKindField,
+		Ref:  field.Ref,
+	})
+	v.addedFieldRefs = append(v.addedFieldRefs, field.Ref)
+}
@@ -1,7 +1,9 @@
 package plan
 
 import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
 )
 
 type Configuration struct {
@@ -21,6 +23,15 @@ type Configuration struct {
 	// e.g. the origin of a field, possible types, etc.
 	// This information is required to compute the schema usage info from a plan
 	IncludeInfo bool
+	// IncludeResponseValidation will add the values of enums and the possible types of interfaces and unions to the plan,
+	// they are required to validate the responses of data sources with resolve.Context.StrictResponseValidation
+	IncludeResponseValidation bool
+	// VisibleDefinition is the definition without the elements hidden from clients. If it's set, the values of its enums and
+	// the possible types of its interfaces and unions are added to the plan and __typename is fetched for fields of interfaces
+	// and unions, so that hidden values in responses are resolved to null with resolve.Context.ValidateEnumValuesAndPossibleTypes
+	VisibleDefinition *ast.Document
+	// Scalars provides the JSON schemas of custom scalars, they are used to validate the variables of data sources
+	Scalars graphqljsonschema.ScalarJsonSchemas
 }
 
 type DebugConfiguration struct {
@@ -93,6 +104,25 @@ type FieldConfiguration struct {
 	// e.g. {"response":"{\"foo\":\"bar\"}"} will be returned as {"foo":"bar"} when path is "response"
 	// This way, it is possible to resolve a JSON string as part of the response without extra String encoding of the JSON
 	UnescapeResponseJson bool
+	// SubscriptionFilterCondition - drops subscription events which don't match the condition before they are resolved
+	// it is only applied to root fields of subscriptions
+	SubscriptionFilterCondition *SubscriptionFilterCondition
+}
+
+// SubscriptionFilterCondition is a predicate on subscription events
+// Exactly one of And, Or, Not or In should be set
+type SubscriptionFilterCondition struct {
+	And []SubscriptionFilterCondition
+	Or  []SubscriptionFilterCondition
+	Not *SubscriptionFilterCondition
+	In  *SubscriptionFieldCondition
+}
+
+// SubscriptionFieldCondition matches if the event field at FieldPath equals one of Values
+// Values might contain templates, e.g. "{{ .arguments.roomId }}" or "{{ .request.headers.X-Tenant }}"
+type SubscriptionFieldCondition struct {
+	FieldPath []string
+	Values    []string
 }
 
 type ArgumentsConfigurations []ArgumentConfiguration
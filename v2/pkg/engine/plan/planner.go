@@ -80,6 +80,14 @@ func (p *Planner) Plan(operation, definition *ast.Document, operationName string
 		p.config.DataSources[i].Hash()
 	}
 
+	var addedTypeNameFieldRefs []int
+	if p.config.VisibleDefinition != nil {
+		addedTypeNameFieldRefs = addAbstractTypeNames(operation, definition, report)
+		if report.HasErrors() {
+			return nil
+		}
+	}
+
 	p.findPlanningPaths(operation, definition, report)
 	if report.HasErrors() {
 		return nil
@@ -94,7 +102,7 @@ func (p *Planner) Plan(operation, definition *ast.Document, operationName string
 	p.planningVisitor.planners = p.configurationVisitor.planners
 	p.planningVisitor.Config = p.config
 	p.planningVisitor.fetchConfigurations = p.configurationVisitor.fetches
-	p.planningVisitor.skipFieldsRefs = p.configurationVisitor.skipFieldsRefs
+	p.planningVisitor.skipFieldsRefs = append(p.configurationVisitor.skipFieldsRefs, addedTypeNameFieldRefs...)
 
 	p.planningWalker.ResetVisitors()
 	p.planningWalker.SetVisitorFilter(p.planningVisitor)
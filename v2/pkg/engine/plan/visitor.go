@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astimport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 )
 
@@ -551,14 +553,20 @@ func (v *Visitor) resolveFieldValue(fieldRef, typeRef int, nullable bool, path [
 					Path:     path,
 					Nullable: nullable,
 					Export:   fieldExport,
+					TypeName: typeName,
 				}
 			}
 		case ast.NodeKindEnumTypeDefinition:
-			return &resolve.String{
+			value := &resolve.String{
 				Path:                 path,
 				Nullable:             nullable,
 				UnescapeResponseJson: unescapeResponseJson,
 			}
+			if v.includeEnumValuesAndPossibleTypes() {
+				value.EnumTypeName = typeName
+				value.EnumValues = v.enumValues(typeName)
+			}
+			return value
 		case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
 			object := &resolve.Object{
 				Nullable:             nullable,
@@ -566,6 +574,13 @@ func (v *Visitor) resolveFieldValue(fieldRef, typeRef int, nullable bool, path [
 				Fields:               []*resolve.Field{},
 				UnescapeResponseJson: unescapeResponseJson,
 			}
+			if v.includeEnumValuesAndPossibleTypes() {
+				switch typeDefinitionNode.Kind {
+				case ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+					object.TypeName = typeName
+					object.PossibleTypes = v.possibleTypes(typeName)
+				}
+			}
 			v.objects = append(v.objects, object)
 			v.Walker.DefferOnEnterField(func() {
 				v.currentFields = append(v.currentFields, objectFields{
@@ -712,6 +727,49 @@ func (v *Visitor) EnterOperationDefinition(ref int) {
 	}
 }
 
+func (v *Visitor) includeEnumValuesAndPossibleTypes() bool {
+	return v.Config.IncludeResponseValidation || v.Config.VisibleDefinition != nil
+}
+
+// enumValuesAndPossibleTypesDefinition returns the definition of the enum values and possible types of the plan,
+// it's the definition without hidden elements if it's set
+func (v *Visitor) enumValuesAndPossibleTypesDefinition() *ast.Document {
+	if v.Config.VisibleDefinition != nil {
+		return v.Config.VisibleDefinition
+	}
+	return v.Definition
+}
+
+// enumValues returns the values of the enum, they are empty if the enum isn't visible
+func (v *Visitor) enumValues(typeName string) []string {
+	definition := v.enumValuesAndPossibleTypesDefinition()
+	enumValues := []string{}
+	node, ok := definition.Index.FirstNodeByNameStr(typeName)
+	if !ok || node.Kind != ast.NodeKindEnumTypeDefinition {
+		return enumValues
+	}
+	for _, ref := range definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+		enumValues = append(enumValues, definition.EnumValueDefinitionNameString(ref))
+	}
+	return enumValues
+}
+
+// possibleTypes returns the possible types of the interface or union, they are empty if it isn't visible
+func (v *Visitor) possibleTypes(typeName string) (possibleTypes []string) {
+	definition := v.enumValuesAndPossibleTypesDefinition()
+	node, ok := definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return nil
+	}
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		possibleTypes, _ = definition.InterfaceTypeDefinitionImplementedByObjectWithNames(node.Ref)
+	case ast.NodeKindUnionTypeDefinition:
+		possibleTypes, _ = definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+	}
+	return possibleTypes
+}
+
 func (v *Visitor) resolveFieldPath(ref int) []string {
 	typeName := v.Walker.EnclosingTypeDefinition.NameString(v.Definition)
 	fieldName := v.Operation.FieldNameUnsafeString(ref)
@@ -887,13 +945,13 @@ func (v *Visitor) resolveInputTemplates(config objectFetchConfiguration, input *
 						}
 						variable.Renderer = renderer
 					case RenderArgumentAsGraphQLValue:
-						renderer, err := resolve.NewGraphQLVariableRendererFromTypeRef(v.Operation, v.Definition, variableTypeRef)
+						renderer, err := resolve.NewGraphQLVariableRendererFromTypeRef(v.Operation, v.Definition, variableTypeRef, graphqljsonschema.WithScalars(v.Config.Scalars))
 						if err != nil {
 							break
 						}
 						variable.Renderer = renderer
 					case RenderArgumentAsJSONValue:
-						renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(v.Operation, v.Definition, variableTypeRef)
+						renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(v.Operation, v.Definition, variableTypeRef, graphqljsonschema.WithScalars(v.Config.Scalars))
 						if err != nil {
 							break
 						}
@@ -969,7 +1027,7 @@ func (v *Visitor) renderJSONValueTemplate(value ast.Value, variables *resolve.Va
 	case ast.ValueKindVariable:
 		variablePath := v.Operation.VariableValueNameString(value.Ref)
 		inputType := v.Definition.InputValueDefinitions[inputValueDefinition].Type
-		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(v.Definition, v.Definition, inputType)
+		renderer, err := resolve.NewJSONVariableRendererWithValidationFromTypeRef(v.Definition, v.Definition, inputType, graphqljsonschema.WithScalars(v.Config.Scalars))
 		if err != nil {
 			renderer = resolve.NewJSONVariableRenderer()
 		}
@@ -989,6 +1047,67 @@ func (v *Visitor) configureSubscription(config objectFetchConfiguration) {
 	config.trigger.PostProcessing = subscription.PostProcessing
 	v.resolveInputTemplates(config, &subscription.Input, &config.trigger.Variables)
 	config.trigger.Input = []byte(subscription.Input)
+
+	fieldConfig, ok := v.fieldConfigs[config.fieldRef]
+	if !ok || fieldConfig.SubscriptionFilterCondition == nil {
+		return
+	}
+	if subscriptionPlan, ok := v.plan.(*SubscriptionResponsePlan); ok {
+		subscriptionPlan.Response.Filter = v.resolveSubscriptionFilterCondition(config, *fieldConfig.SubscriptionFilterCondition)
+	}
+}
+
+func (v *Visitor) resolveSubscriptionFilterCondition(config objectFetchConfiguration, condition SubscriptionFilterCondition) *resolve.SubscriptionFilter {
+	filter := &resolve.SubscriptionFilter{}
+	switch {
+	case condition.In != nil:
+		filter.In = &resolve.SubscriptionFieldFilter{
+			FieldPath: condition.In.FieldPath,
+			Values:    make([]resolve.InputTemplate, len(condition.In.Values)),
+		}
+		for i := range condition.In.Values {
+			filter.In.Values[i] = v.resolveSubscriptionFilterValue(config, condition.In.Values[i])
+		}
+	case condition.Not != nil:
+		filter.Not = v.resolveSubscriptionFilterCondition(config, *condition.Not)
+	case len(condition.And) != 0:
+		filter.And = make([]resolve.SubscriptionFilter, len(condition.And))
+		for i := range condition.And {
+			filter.And[i] = *v.resolveSubscriptionFilterCondition(config, condition.And[i])
+		}
+	case len(condition.Or) != 0:
+		filter.Or = make([]resolve.SubscriptionFilter, len(condition.Or))
+		for i := range condition.Or {
+			filter.Or[i] = *v.resolveSubscriptionFilterCondition(config, condition.Or[i])
+		}
+	}
+	return filter
+}
+
+// resolveSubscriptionFilterValue turns a filter value with templates into an InputTemplate
+func (v *Visitor) resolveSubscriptionFilterValue(config objectFetchConfiguration, value string) resolve.InputTemplate {
+	var variables resolve.Variables
+	v.resolveInputTemplates(config, &value, &variables)
+
+	template := resolve.InputTemplate{}
+	segments := strings.Split(value, "$$")
+	for i, segment := range segments {
+		if i%2 == 0 {
+			if segment != "" {
+				template.Segments = append(template.Segments, resolve.TemplateSegment{
+					SegmentType: resolve.StaticSegmentType,
+					Data:        []byte(segment),
+				})
+			}
+			continue
+		}
+		variableIndex, err := strconv.Atoi(segment)
+		if err != nil || variableIndex >= len(variables) {
+			continue
+		}
+		template.Segments = append(template.Segments, variables[variableIndex].TemplateSegment())
+	}
+	return template
 }
 
 func (v *Visitor) configureObjectFetch(config objectFetchConfiguration) {
@@ -9,6 +9,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/internal/pkg/unsafebytes"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 type Context struct {
@@ -23,6 +24,19 @@ type Context struct {
 	afterFetchHook   AfterFetchHook
 	position         Position
 	RenameTypeNames  []RenameTypeName
+	// Scalars serializes the values of custom scalars, scalars which are not registered are written as is
+	Scalars *scalar.ScalarRegistry
+	// Extensions is a JSON object which is written as the extensions of the response, e.g. {"warnings":[...]}
+	Extensions []byte
+	// StrictResponseValidation validates the values of data sources beyond their JSON types, see ResponseViolationKind.
+	// Enum values and possible types are only validated if the plan includes response validation.
+	StrictResponseValidation bool
+	// ResponseValidationHook is notified about the violations of the strict response validation, it's optional
+	ResponseValidationHook ResponseValidationHook
+	// ValidateEnumValuesAndPossibleTypes validates only the enum values and possible types included in the plan,
+	// e.g. to resolve values hidden from the schema to null, see plan.Configuration.VisibleDefinition.
+	// It's implied by StrictResponseValidation.
+	ValidateEnumValuesAndPossibleTypes bool
 }
 
 type Request struct {
@@ -43,6 +57,15 @@ func NewContext(ctx context.Context) *Context {
 	}
 }
 
+type dataSourceContextKey struct{}
+
+// GetContext returns the Context of the request a DataSource loads data for,
+// e.g. to access the headers of the request in in-process data sources
+func GetContext(ctx context.Context) (*Context, bool) {
+	c, ok := ctx.Value(dataSourceContextKey{}).(*Context)
+	return c, ok
+}
+
 func (c *Context) Context() context.Context {
 	return c.ctx
 }
@@ -93,6 +116,11 @@ func (c *Context) Free() {
 	c.Request.Header = nil
 	c.position = Position{}
 	c.RenameTypeNames = nil
+	c.Scalars = nil
+	c.Extensions = nil
+	c.StrictResponseValidation = false
+	c.ResponseValidationHook = nil
+	c.ValidateEnumValuesAndPossibleTypes = false
 }
 
 func (c *Context) SetBeforeFetchHook(hook BeforeFetchHook) {
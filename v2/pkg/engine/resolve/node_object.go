@@ -6,6 +6,10 @@ type Object struct {
 	Fields               []*Field
 	Fetch                Fetch
 	UnescapeResponseJson bool `json:"unescape_response_json,omitempty"`
+	// TypeName and PossibleTypes are set for fields of interfaces and unions if the plan includes response validation
+	// or a visible definition, PossibleTypes are the object types the __typename of the object must be one of
+	TypeName      string   `json:"type_name,omitempty"`
+	PossibleTypes []string `json:"possible_types,omitempty"`
 }
 
 func (o *Object) HasChildFetches() bool {
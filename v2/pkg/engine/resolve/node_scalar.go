@@ -4,6 +4,8 @@ type Scalar struct {
 	Path     []string
 	Nullable bool
 	Export   *FieldExport `json:"export,omitempty"`
+	// TypeName is the name of the custom scalar, it is used to look up the scalar in Context.Scalars
+	TypeName string `json:"type_name,omitempty"`
 }
 
 func (_ *Scalar) NodeKind() NodeKind {
@@ -20,6 +22,10 @@ type String struct {
 	Export               *FieldExport `json:"export,omitempty"`
 	UnescapeResponseJson bool         `json:"unescape_response_json,omitempty"`
 	IsTypeName           bool         `json:"is_type_name,omitempty"`
+	// EnumTypeName and EnumValues are set for fields of enum types if the plan includes response validation
+	// or a visible definition
+	EnumTypeName string   `json:"enum_type_name,omitempty"`
+	EnumValues   []string `json:"enum_values,omitempty"`
 }
 
 func (_ *String) NodeKind() NodeKind {
@@ -0,0 +1,46 @@
+package resolve
+
+import (
+	"bytes"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+// validateEnumValue validates a value of an enum against the EnumValues of the plan,
+// which are the visible values of the enum if parts of the schema are hidden
+func (r *Resolvable) validateEnumValue(s *String, ref int) bool {
+	value := r.storage.Nodes[ref].ValueBytes(r.storage)
+	for i := range s.EnumValues {
+		if s.EnumValues[i] == string(value) {
+			return true
+		}
+	}
+	r.addResponseViolation(ResponseViolationKindEnumValue, s.EnumTypeName, ref,
+		`Enum \"`+s.EnumTypeName+`\" cannot represent value: \"`+string(value)+`\"`, s.Path)
+	return false
+}
+
+// validatePossibleType validates the __typename of an object of an interface or union,
+// objects without __typename can't be validated, the planner adds it for a visible definition
+func (r *Resolvable) validatePossibleType(obj *Object, ref int) bool {
+	typeNameRef := r.storage.GetObjectField(ref, "__typename")
+	if !r.storage.NodeIsDefined(typeNameRef) || r.storage.Nodes[typeNameRef].Kind != astjson.NodeKindString {
+		return true
+	}
+	typeName := r.storage.Nodes[typeNameRef].ValueBytes(r.storage)
+	for i := range r.renameTypeNames {
+		if bytes.Equal(typeName, r.renameTypeNames[i].From) {
+			typeName = r.renameTypeNames[i].To
+			break
+		}
+	}
+	for i := range obj.PossibleTypes {
+		if obj.PossibleTypes[i] == string(typeName) {
+			return true
+		}
+	}
+	// the path of the object is already pushed by walkObject
+	r.addResponseViolation(ResponseViolationKindPossibleType, obj.TypeName, typeNameRef,
+		`Runtime Object type \"`+string(typeName)+`\" is not a possible type for \"`+obj.TypeName+`\".`, nil)
+	return false
+}
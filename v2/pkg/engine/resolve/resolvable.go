@@ -2,6 +2,7 @@ package resolve
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 type Resolvable struct {
@@ -23,6 +25,9 @@ type Resolvable struct {
 	depth           int
 	operationType   ast.OperationType
 	renameTypeNames []RenameTypeName
+	scalars         *scalar.ScalarRegistry
+	extensions      []byte
+	validation      responseValidation
 }
 
 func NewResolvable() *Resolvable {
@@ -44,11 +49,16 @@ func (r *Resolvable) Reset() {
 	r.printErr = nil
 	r.path = r.path[:0]
 	r.operationType = ast.OperationTypeUnknown
+	r.extensions = nil
+	r.validation = responseValidation{}
 }
 
 func (r *Resolvable) Init(ctx *Context, initialData []byte, operationType ast.OperationType) (err error) {
 	r.operationType = operationType
 	r.renameTypeNames = ctx.RenameTypeNames
+	r.scalars = ctx.Scalars
+	r.extensions = ctx.Extensions
+	r.validation = newResponseValidation(ctx)
 	r.dataRoot, r.errorsRoot, err = r.storage.InitResolvable(initialData)
 	if err != nil {
 		return
@@ -62,6 +72,9 @@ func (r *Resolvable) Init(ctx *Context, initialData []byte, operationType ast.Op
 func (r *Resolvable) InitSubscription(ctx *Context, initialData []byte, postProcessing PostProcessingConfiguration) (err error) {
 	r.operationType = ast.OperationTypeSubscription
 	r.renameTypeNames = ctx.RenameTypeNames
+	r.scalars = ctx.Scalars
+	r.extensions = ctx.Extensions
+	r.validation = newResponseValidation(ctx)
 	if len(ctx.Variables) != 0 {
 		r.variablesRoot, err = r.storage.AppendObject(ctx.Variables)
 	}
@@ -138,6 +151,9 @@ func (r *Resolvable) Resolve(root *Object, out io.Writer) error {
 	} else {
 		r.printData(root)
 	}
+	if len(r.extensions) != 0 {
+		r.printExtensions()
+	}
 	r.printBytes(rBrace)
 	return r.printErr
 }
@@ -167,6 +183,15 @@ func (r *Resolvable) printData(root *Object) {
 	r.printBytes(rBrace)
 }
 
+func (r *Resolvable) printExtensions() {
+	r.printBytes(comma)
+	r.printBytes(quote)
+	r.printBytes(literalExtensions)
+	r.printBytes(quote)
+	r.printBytes(colon)
+	r.printBytes(r.extensions)
+}
+
 func (r *Resolvable) hasErrors() bool {
 	if r.errorsRoot == -1 {
 		return false
@@ -262,6 +287,9 @@ func (r *Resolvable) walkObject(obj *Object, ref int) bool {
 		r.addTypeMismatchError("Object cannot represent non-object value.", obj.Path)
 		return r.err()
 	}
+	if r.validation.enumValuesAndPossibleTypes && len(obj.PossibleTypes) != 0 && !r.validatePossibleType(obj, ref) {
+		return r.err()
+	}
 	if r.print && !isRoot {
 		r.printBytes(lBrace)
 	}
@@ -408,6 +436,9 @@ func (r *Resolvable) walkString(s *String, ref int) bool {
 		r.addTypeMismatchError(fmt.Sprintf("String cannot represent non-string value: \\\"%s\\\"", value), s.Path)
 		return r.err()
 	}
+	if r.validation.enumValuesAndPossibleTypes && s.EnumValues != nil && !r.validateEnumValue(s, ref) {
+		return r.err()
+	}
 	if r.print {
 		if s.IsTypeName {
 			value := r.storage.Nodes[ref].ValueBytes(r.storage)
@@ -473,6 +504,9 @@ func (r *Resolvable) walkInteger(i *Integer, ref int) bool {
 		r.addTypeMismatchError(fmt.Sprintf("Int cannot represent non-integer value: \\\"%s\\\"", value), i.Path)
 		return r.err()
 	}
+	if r.validation.strict && !r.validateInt(i, ref) {
+		return r.err()
+	}
 	if r.print {
 		r.printNode(ref)
 	}
@@ -523,12 +557,46 @@ func (r *Resolvable) walkScalar(s *Scalar, ref int) bool {
 		r.addNonNullableFieldError(s.Path)
 		return r.err()
 	}
+	if r.validation.strict && s.TypeName == "ID" && !r.validateID(s, ref) {
+		return r.err()
+	}
+	if s.TypeName != "" && r.scalars != nil {
+		return r.walkRegisteredScalar(s, ref)
+	}
 	if r.print {
 		r.printNode(ref)
 	}
 	return false
 }
 
+func (r *Resolvable) walkRegisteredScalar(s *Scalar, ref int) bool {
+	if _, ok := r.scalars.Scalar(s.TypeName); !ok {
+		if r.print {
+			r.printNode(ref)
+		}
+		return false
+	}
+	buf := pool.BytesBuffer.Get()
+	defer pool.BytesBuffer.Put(buf)
+	if err := r.storage.PrintNode(r.storage.Nodes[ref], buf); err != nil {
+		r.addUnableToResolveError(err.Error(), s.Path)
+		return r.err()
+	}
+	serialized, _, err := r.scalars.Serialize(s.TypeName, buf.Bytes())
+	if err != nil {
+		message, _ := json.Marshal(err.Error())
+		r.addTypeMismatchError(string(message[1:len(message)-1]), s.Path)
+		return r.err()
+	}
+	if serialized == nil {
+		serialized = buf.Bytes()
+	}
+	if r.print {
+		r.printBytes(serialized)
+	}
+	return false
+}
+
 func (r *Resolvable) walkEmptyObject(_ *EmptyObject) bool {
 	if r.print {
 		r.printBytes(lBrace)
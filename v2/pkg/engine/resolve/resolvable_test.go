@@ -2,10 +2,12 @@ package resolve
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 func TestResolvable_Resolve(t *testing.T) {
@@ -466,3 +468,233 @@ func BenchmarkResolvable_ResolveWithErrorBubbleUp(b *testing.B) {
 		}
 	}
 }
+
+func TestResolvable_ResolveWithScalars(t *testing.T) {
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("price"),
+				Value: &Scalar{
+					Path:     []string{"price"},
+					TypeName: "BigDecimal",
+				},
+			},
+			{
+				Name: []byte("createdAt"),
+				Value: &Scalar{
+					Path:     []string{"createdAt"},
+					Nullable: true,
+					TypeName: "DateTime",
+				},
+			},
+			{
+				Name: []byte("meta"),
+				Value: &Scalar{
+					Path:     []string{"meta"},
+					TypeName: "Unregistered",
+				},
+			},
+		},
+	}
+
+	t.Run("serialize values", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{
+			Scalars: scalar.NewDefaultScalarRegistry(),
+		}
+		err := res.Init(ctx, []byte(`{"price":12.50,"createdAt":"2023-10-18T16:00:00Z","meta":{"a":1}}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"price":"12.50","createdAt":"2023-10-18T16:00:00Z","meta":{"a":1}}}`, out.String())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{
+			Scalars: scalar.NewDefaultScalarRegistry(),
+		}
+		err := res.Init(ctx, []byte(`{"price":"1","createdAt":"yesterday","meta":true}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"DateTime cannot represent an invalid date-time string: \"yesterday\"","path":["createdAt"]}],"data":null}`, out.String())
+	})
+}
+
+func TestResolvable_ResolveWithExtensions(t *testing.T) {
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("name"),
+				Value: &String{
+					Path:     []string{"name"},
+					Nullable: true,
+				},
+			},
+		},
+	}
+
+	t.Run("with data", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{
+			Extensions: []byte(`{"warnings":[{"message":"deprecated"}]}`),
+		}
+		err := res.Init(ctx, []byte(`{"name":"Jens"}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"Jens"},"extensions":{"warnings":[{"message":"deprecated"}]}}`, out.String())
+	})
+
+	t.Run("with errors", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{
+			Extensions: []byte(`{"warnings":[]}`),
+		}
+		err := res.Init(ctx, []byte(`{"name":true}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"String cannot represent non-string value: \"true\"","path":["name"]}],"data":null,"extensions":{"warnings":[]}}`, out.String())
+	})
+}
+
+type testResponseValidationHook struct {
+	violations []ResponseViolation
+}
+
+func (h *testResponseValidationHook) OnResponseViolation(_ context.Context, violation ResponseViolation) {
+	h.violations = append(h.violations, violation)
+}
+
+func TestResolvable_ResolveWithStrictResponseValidation(t *testing.T) {
+	object := &Object{
+		Fields: []*Field{
+			{
+				Name: []byte("role"),
+				Value: &String{
+					Path:         []string{"role"},
+					Nullable:     true,
+					EnumTypeName: "Role",
+					EnumValues:   []string{"ADMIN", "MEMBER"},
+				},
+			},
+			{
+				Name: []byte("age"),
+				Value: &Integer{
+					Path:     []string{"age"},
+					Nullable: true,
+				},
+			},
+			{
+				Name: []byte("id"),
+				Value: &Scalar{
+					Path:     []string{"id"},
+					Nullable: true,
+					TypeName: "ID",
+				},
+			},
+			{
+				Name: []byte("node"),
+				Value: &Object{
+					Path:          []string{"node"},
+					Nullable:      true,
+					TypeName:      "Node",
+					PossibleTypes: []string{"User", "Post"},
+					Fields: []*Field{
+						{
+							Name: []byte("__typename"),
+							Value: &String{
+								Path:       []string{"__typename"},
+								IsTypeName: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("valid values", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{
+			StrictResponseValidation: true,
+		}
+		err := res.Init(ctx, []byte(`{"role":"ADMIN","age":1e3,"id":1,"node":{"__typename":"Post"}}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"role":"ADMIN","age":1e3,"id":1,"node":{"__typename":"Post"}}}`, out.String())
+	})
+
+	invalid := func(t *testing.T, data, expectedOutput string, expectedViolation ResponseViolation) {
+		t.Helper()
+		hook := &testResponseValidationHook{}
+		res := NewResolvable()
+		ctx := &Context{
+			StrictResponseValidation: true,
+			ResponseValidationHook:   hook,
+		}
+		err := res.Init(ctx, []byte(data), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedOutput, out.String())
+		assert.Equal(t, []ResponseViolation{expectedViolation}, hook.violations)
+	}
+
+	t.Run("invalid enum value", func(t *testing.T) {
+		invalid(t, `{"role":"OWNER"}`,
+			`{"errors":[{"message":"Enum \"Role\" cannot represent value: \"OWNER\"","path":["role"]}],"data":null}`,
+			ResponseViolation{Kind: ResponseViolationKindEnumValue, FieldPath: "Query.role", TypeName: "Role", Value: `"OWNER"`})
+	})
+
+	t.Run("32-bit overflow", func(t *testing.T) {
+		invalid(t, `{"age":2147483648}`,
+			`{"errors":[{"message":"Int cannot represent non 32-bit signed integer value: 2147483648","path":["age"]}],"data":null}`,
+			ResponseViolation{Kind: ResponseViolationKindInt, FieldPath: "Query.age", TypeName: "Int", Value: `2147483648`})
+	})
+
+	t.Run("non-integer value", func(t *testing.T) {
+		invalid(t, `{"age":1.5}`,
+			`{"errors":[{"message":"Int cannot represent non-integer value: 1.5","path":["age"]}],"data":null}`,
+			ResponseViolation{Kind: ResponseViolationKindInt, FieldPath: "Query.age", TypeName: "Int", Value: `1.5`})
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		invalid(t, `{"id":true}`,
+			`{"errors":[{"message":"ID cannot represent value: true","path":["id"]}],"data":null}`,
+			ResponseViolation{Kind: ResponseViolationKindID, FieldPath: "Query.id", TypeName: "ID", Value: `true`})
+	})
+
+	t.Run("impossible type", func(t *testing.T) {
+		invalid(t, `{"node":{"__typename":"Comment"}}`,
+			`{"errors":[{"message":"Runtime Object type \"Comment\" is not a possible type for \"Node\".","path":["node"]}],"data":null}`,
+			ResponseViolation{Kind: ResponseViolationKindPossibleType, FieldPath: "Query.node", TypeName: "Node", Value: `"Comment"`})
+	})
+
+	t.Run("without strict validation", func(t *testing.T) {
+		res := NewResolvable()
+		ctx := &Context{}
+		err := res.Init(ctx, []byte(`{"role":"OWNER","age":2147483648,"id":true,"node":{"__typename":"Comment"}}`), ast.OperationTypeQuery)
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = res.Resolve(object, out)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"data":{"role":"OWNER","age":2147483648,"id":true,"node":{"__typename":"Comment"}}}`, out.String())
+	})
+}
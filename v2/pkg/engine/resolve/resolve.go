@@ -3,10 +3,13 @@
 package resolve
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"sync"
 
+	"github.com/buger/jsonparser"
+	"github.com/jensneuse/abstractlogger"
 	"github.com/pkg/errors"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
@@ -17,6 +20,7 @@ type Resolver struct {
 	enableSingleFlightLoader bool
 	sf                       *Group
 	toolPool                 sync.Pool
+	log                      abstractlogger.Logger
 }
 
 type tools struct {
@@ -30,6 +34,7 @@ func New(ctx context.Context, enableSingleFlightLoader bool) *Resolver {
 		ctx:                      ctx,
 		enableSingleFlightLoader: enableSingleFlightLoader,
 		sf:                       &Group{},
+		log:                      abstractlogger.Noop{},
 		toolPool: sync.Pool{
 			New: func() interface{} {
 				return &tools{
@@ -41,6 +46,11 @@ func New(ctx context.Context, enableSingleFlightLoader bool) *Resolver {
 	}
 }
 
+// SetLogger sets the logger for errors which don't end a request, e.g. of dropped subscription events
+func (r *Resolver) SetLogger(logger abstractlogger.Logger) {
+	r.log = logger
+}
+
 func (r *Resolver) getTools() *tools {
 	t := r.toolPool.Get().(*tools)
 	t.loader.sf = r.sf
@@ -121,6 +131,18 @@ func (r *Resolver) ResolveGraphQLSubscription(ctx *Context, subscription *GraphQ
 			if !ok {
 				return nil
 			}
+			if subscription.Filter != nil {
+				skip, err := r.skipSubscriptionEvent(ctx, subscription, data, buf)
+				if err != nil {
+					// the event can't be matched, e.g. a variable of the filter is missing,
+					// it's dropped without ending the subscription
+					r.log.Error("Resolver.ResolveGraphQLSubscription.skipSubscriptionEvent", abstractlogger.Error(err))
+					continue
+				}
+				if skip {
+					continue
+				}
+			}
 			t.resolvable.Reset()
 			if err := t.resolvable.InitSubscription(ctx, data, subscription.Trigger.PostProcessing); err != nil {
 				return err
@@ -135,3 +157,15 @@ func (r *Resolver) ResolveGraphQLSubscription(ctx *Context, subscription *GraphQ
 		}
 	}
 }
+
+func (r *Resolver) skipSubscriptionEvent(ctx *Context, subscription *GraphQLSubscription, data []byte, buf *bytes.Buffer) (bool, error) {
+	if path := subscription.Trigger.PostProcessing.SelectResponseDataPath; len(path) != 0 {
+		value, _, _, err := jsonparser.Get(data, path...)
+		if err != nil {
+			// events without data, e.g. errors, are always delivered
+			return false, nil
+		}
+		data = value
+	}
+	return subscription.Filter.SkipEvent(ctx, data, buf)
+}
@@ -11,6 +11,8 @@ import (
 type GraphQLSubscription struct {
 	Trigger  GraphQLSubscriptionTrigger
 	Response *GraphQLResponse
+	// Filter drops events which don't match, before they get resolved
+	Filter *SubscriptionFilter
 }
 
 type GraphQLSubscriptionTrigger struct {
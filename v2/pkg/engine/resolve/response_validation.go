@@ -0,0 +1,110 @@
+package resolve
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astjson"
+)
+
+// ResponseViolationKind is the kind of an invalid value found by the strict response validation
+type ResponseViolationKind string
+
+const (
+	// ResponseViolationKindEnumValue is a string which isn't a value of the enum of its field
+	ResponseViolationKindEnumValue ResponseViolationKind = "enum_value"
+	// ResponseViolationKindInt is a number which isn't a 32-bit signed integer
+	ResponseViolationKindInt ResponseViolationKind = "int"
+	// ResponseViolationKindID is an ID which is neither a string nor an integer
+	ResponseViolationKindID ResponseViolationKind = "id"
+	// ResponseViolationKindPossibleType is an object of an interface or union with a __typename which isn't a possible type
+	ResponseViolationKindPossibleType ResponseViolationKind = "possible_type"
+)
+
+// ResponseViolation is an invalid value of a data source, it's reported as an error of the field
+// and the field is resolved to null like any other type mismatch
+type ResponseViolation struct {
+	Kind ResponseViolationKind
+	// FieldPath is the path of the field without list indices, e.g. Query.user.role
+	FieldPath string
+	// TypeName is the type of the field, e.g. the name of the enum, interface or union
+	TypeName string
+	// Value is the invalid value as JSON
+	Value string
+}
+
+// ResponseValidationHook is notified about every violation of the strict response validation, e.g. to count
+// the invalid values of data sources
+type ResponseValidationHook interface {
+	OnResponseViolation(ctx context.Context, violation ResponseViolation)
+}
+
+type responseValidation struct {
+	strict bool
+	// enumValuesAndPossibleTypes is true if the enum values and possible types of the plan are validated
+	enumValuesAndPossibleTypes bool
+	hook                       ResponseValidationHook
+	ctx                        context.Context
+}
+
+func newResponseValidation(ctx *Context) responseValidation {
+	return responseValidation{
+		strict:                     ctx.StrictResponseValidation,
+		enumValuesAndPossibleTypes: ctx.StrictResponseValidation || ctx.ValidateEnumValuesAndPossibleTypes,
+		hook:                       ctx.ResponseValidationHook,
+		ctx:                        ctx.Context(),
+	}
+}
+
+func (r *Resolvable) validateInt(i *Integer, ref int) bool {
+	value := string(r.storage.Nodes[ref].ValueBytes(r.storage))
+	if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return true
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	switch {
+	case err == nil && f != math.Trunc(f):
+		r.addResponseViolation(ResponseViolationKindInt, "Int", ref, "Int cannot represent non-integer value: "+value, i.Path)
+		return false
+	case err == nil && f >= math.MinInt32 && f <= math.MaxInt32:
+		// an integer in exponent or decimal notation, e.g. 1e3 or 2.0
+		return true
+	}
+	r.addResponseViolation(ResponseViolationKindInt, "Int", ref, "Int cannot represent non 32-bit signed integer value: "+value, i.Path)
+	return false
+}
+
+func (r *Resolvable) validateID(s *Scalar, ref int) bool {
+	value := r.storage.Nodes[ref].ValueBytes(r.storage)
+	switch r.storage.Nodes[ref].Kind {
+	case astjson.NodeKindString:
+		return true
+	case astjson.NodeKindNumber:
+		if f, err := strconv.ParseFloat(string(value), 64); err == nil && f == math.Trunc(f) {
+			return true
+		}
+	}
+	r.addResponseViolation(ResponseViolationKindID, "ID", ref, "ID cannot represent value: "+string(value), s.Path)
+	return false
+}
+
+func (r *Resolvable) addResponseViolation(kind ResponseViolationKind, typeName string, valueRef int, message string, fieldPath []string) {
+	r.addTypeMismatchError(message, fieldPath)
+	if r.validation.hook == nil {
+		return
+	}
+	value := r.storage.Nodes[valueRef].ValueBytes(r.storage)
+	if r.storage.Nodes[valueRef].Kind == astjson.NodeKindString {
+		value = append(append([]byte{'"'}, value...), '"')
+	}
+	r.pushNodePathElement(fieldPath)
+	fieldPathString := r.renderFieldPath()
+	r.popNodePathElement(fieldPath)
+	r.validation.hook.OnResponseViolation(r.validation.ctx, ResponseViolation{
+		Kind:      kind,
+		FieldPath: fieldPathString,
+		TypeName:  typeName,
+		Value:     string(value),
+	})
+}
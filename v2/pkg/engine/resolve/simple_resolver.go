@@ -35,6 +35,8 @@ func (r *SimpleResolver) resolveNode(node Node, data []byte, buf *fastbuffer.Fas
 		return r.resolveInteger(n, data, buf)
 	case *Float:
 		return r.resolveFloat(n, data, buf)
+	case *Scalar:
+		return r.resolveScalar(n, data, buf)
 	case *EmptyObject:
 		r.resolveEmptyObject(buf)
 		return
@@ -289,6 +291,26 @@ func (r *SimpleResolver) resolveString(str *String, data []byte, stringBuf *fast
 	return nil
 }
 
+// resolveScalar writes the value at the path of the scalar as is
+func (r *SimpleResolver) resolveScalar(scalar *Scalar, data []byte, scalarBuf *fastbuffer.FastBuffer) error {
+	value, valueType, _, err := jsonparser.Get(data, scalar.Path...)
+	if err != nil || valueType == jsonparser.Null {
+		if !scalar.Nullable {
+			return errNonNullableFieldValueIsNull
+		}
+		r.resolveNull(scalarBuf)
+		return nil
+	}
+	if valueType == jsonparser.String {
+		scalarBuf.WriteBytes(quote)
+		scalarBuf.WriteBytes(value)
+		scalarBuf.WriteBytes(quote)
+		return nil
+	}
+	scalarBuf.WriteBytes(value)
+	return nil
+}
+
 func (r *SimpleResolver) resolveEmptyArray(b *fastbuffer.FastBuffer) {
 	b.WriteBytes(lBrack)
 	b.WriteBytes(rBrack)
@@ -0,0 +1,111 @@
+package resolve
+
+import (
+	"bytes"
+
+	"github.com/buger/jsonparser"
+)
+
+// SubscriptionFilter decides whether a subscription event is delivered to the client
+// Exactly one of And, Or, Not or In should be set
+// Events are evaluated before they are resolved, so events dropped by the filter never reach the client
+type SubscriptionFilter struct {
+	And []SubscriptionFilter
+	Or  []SubscriptionFilter
+	Not *SubscriptionFilter
+	In  *SubscriptionFieldFilter
+}
+
+// SubscriptionFieldFilter matches if the event value at FieldPath equals one of the rendered Values
+// A Value rendering to a JSON array matches if any of its items is equal to the event value
+type SubscriptionFieldFilter struct {
+	// FieldPath is the path of the field in the event payload, after SelectResponseDataPath has been applied
+	FieldPath []string
+	// Values are rendered with the request Context, e.g. to compare with subscription arguments or variables
+	Values []InputTemplate
+}
+
+// SkipEvent returns true if the event data doesn't match the filter and should not be sent to the client
+func (f *SubscriptionFilter) SkipEvent(ctx *Context, data []byte, buf *bytes.Buffer) (bool, error) {
+	matches, err := f.matches(ctx, data, buf)
+	if err != nil {
+		return false, err
+	}
+	return !matches, nil
+}
+
+func (f *SubscriptionFilter) matches(ctx *Context, data []byte, buf *bytes.Buffer) (bool, error) {
+	switch {
+	case f.In != nil:
+		return f.In.matches(ctx, data, buf)
+	case f.Not != nil:
+		matches, err := f.Not.matches(ctx, data, buf)
+		return !matches, err
+	case len(f.And) != 0:
+		for i := range f.And {
+			matches, err := f.And[i].matches(ctx, data, buf)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(f.Or) != 0:
+		for i := range f.Or {
+			matches, err := f.Or[i].matches(ctx, data, buf)
+			if err != nil {
+				return false, err
+			}
+			if matches {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (f *SubscriptionFieldFilter) matches(ctx *Context, data []byte, buf *bytes.Buffer) (bool, error) {
+	actual, actualType, _, err := jsonparser.Get(data, f.FieldPath...)
+	if err != nil {
+		// a missing field never matches
+		return false, nil
+	}
+	for i := range f.Values {
+		buf.Reset()
+		if err := f.Values[i].Render(ctx, nil, buf); err != nil {
+			return false, err
+		}
+		expected, expectedType, _, err := jsonparser.Get(buf.Bytes())
+		if err != nil {
+			// values rendered without quotes, e.g. a plain rendered string, are compared as is
+			if bytes.Equal(actual, buf.Bytes()) {
+				return true, nil
+			}
+			continue
+		}
+		if expectedType != jsonparser.Array {
+			if filterValueEquals(actual, actualType, expected, expectedType) {
+				return true, nil
+			}
+			continue
+		}
+		found := false
+		_, _ = jsonparser.ArrayEach(expected, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+			if !found && filterValueEquals(actual, actualType, value, dataType) {
+				found = true
+			}
+		})
+		if found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func filterValueEquals(actual []byte, actualType jsonparser.ValueType, expected []byte, expectedType jsonparser.ValueType) bool {
+	if actualType == jsonparser.String || expectedType == jsonparser.String {
+		// allows comparing e.g. an ID argument with a numeric event field
+		return bytes.Equal(actual, expected)
+	}
+	return actualType == expectedType && bytes.Equal(actual, expected)
+}
@@ -0,0 +1,250 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticStream struct {
+	messages []string
+}
+
+func (s *staticStream) Start(ctx *Context, input []byte, next chan<- []byte) error {
+	go func() {
+		defer close(next)
+		for _, message := range s.messages {
+			select {
+			case next <- []byte(message):
+			case <-ctx.Context().Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func TestSubscriptionFilter_SkipEvent(t *testing.T) {
+	variable := func(name string) InputTemplate {
+		return InputTemplate{
+			Segments: []TemplateSegment{
+				{
+					SegmentType:        VariableSegmentType,
+					VariableKind:       ContextVariableKind,
+					VariableSourcePath: []string{name},
+					Renderer:           NewJSONVariableRenderer(),
+				},
+			},
+		}
+	}
+	static := func(value string) InputTemplate {
+		return InputTemplate{
+			Segments: []TemplateSegment{
+				{
+					SegmentType: StaticSegmentType,
+					Data:        []byte(value),
+				},
+			},
+		}
+	}
+
+	run := func(t *testing.T, filter SubscriptionFilter, variables, data string, expectSkip bool) {
+		t.Helper()
+		ctx := NewContext(context.Background())
+		ctx.Variables = []byte(variables)
+		skip, err := filter.SkipEvent(ctx, []byte(data), &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, expectSkip, skip)
+	}
+
+	roomFilter := SubscriptionFilter{
+		In: &SubscriptionFieldFilter{
+			FieldPath: []string{"messageAdded", "roomId"},
+			Values:    []InputTemplate{variable("roomId")},
+		},
+	}
+
+	t.Run("in matches variable", func(t *testing.T) {
+		run(t, roomFilter, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"a"}}`, false)
+	})
+	t.Run("in doesn't match variable", func(t *testing.T) {
+		run(t, roomFilter, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"b"}}`, true)
+	})
+	t.Run("in skips events without the field", func(t *testing.T) {
+		run(t, roomFilter, `{"roomId":"a"}`, `{"messageAdded":{"text":"hello"}}`, true)
+	})
+	t.Run("in matches list variable", func(t *testing.T) {
+		filter := SubscriptionFilter{
+			In: &SubscriptionFieldFilter{
+				FieldPath: []string{"id"},
+				Values:    []InputTemplate{variable("ids")},
+			},
+		}
+		run(t, filter, `{"ids":[1,2,3]}`, `{"id":2}`, false)
+		run(t, filter, `{"ids":[1,2,3]}`, `{"id":4}`, true)
+		run(t, filter, `{"ids":["1","2"]}`, `{"id":2}`, false)
+	})
+	t.Run("not", func(t *testing.T) {
+		run(t, SubscriptionFilter{Not: &roomFilter}, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"a"}}`, true)
+		run(t, SubscriptionFilter{Not: &roomFilter}, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"b"}}`, false)
+	})
+	t.Run("and / or", func(t *testing.T) {
+		published := SubscriptionFilter{
+			In: &SubscriptionFieldFilter{
+				FieldPath: []string{"messageAdded", "published"},
+				Values:    []InputTemplate{static("true")},
+			},
+		}
+		and := SubscriptionFilter{And: []SubscriptionFilter{roomFilter, published}}
+		or := SubscriptionFilter{Or: []SubscriptionFilter{roomFilter, published}}
+
+		run(t, and, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"a","published":true}}`, false)
+		run(t, and, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"a","published":false}}`, true)
+		run(t, or, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"b","published":true}}`, false)
+		run(t, or, `{"roomId":"a"}`, `{"messageAdded":{"roomId":"b","published":false}}`, true)
+	})
+}
+
+func TestResolver_ResolveGraphQLSubscription_Filter(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	plan := &GraphQLSubscription{
+		Trigger: GraphQLSubscriptionTrigger{
+			Source: &staticStream{
+				messages: []string{
+					`{"data":{"counter":{"value":1,"owner":"a"}}}`,
+					`{"data":{"counter":{"value":2,"owner":"b"}}}`,
+					`{"data":{"counter":{"value":3,"owner":"a"}}}`,
+				},
+			},
+			PostProcessing: PostProcessingConfiguration{
+				SelectResponseDataPath:   []string{"data"},
+				SelectResponseErrorsPath: []string{"errors"},
+			},
+		},
+		Filter: &SubscriptionFilter{
+			In: &SubscriptionFieldFilter{
+				FieldPath: []string{"counter", "owner"},
+				Values: []InputTemplate{
+					{
+						Segments: []TemplateSegment{
+							{
+								SegmentType:        VariableSegmentType,
+								VariableKind:       ContextVariableKind,
+								VariableSourcePath: []string{"owner"},
+								Renderer:           NewJSONVariableRenderer(),
+							},
+						},
+					},
+				},
+			},
+		},
+		Response: &GraphQLResponse{
+			Data: &Object{
+				Fields: []*Field{
+					{
+						Name: []byte("counter"),
+						Value: &Integer{
+							Path: []string{"counter", "value"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	out := &TestFlushWriter{
+		buf: bytes.Buffer{},
+	}
+	ctx := NewContext(c)
+	ctx.Variables = []byte(`{"owner":"a"}`)
+
+	err := newResolver(c, false).ResolveGraphQLSubscription(ctx, plan, out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"data":{"counter":1}}`, `{"data":{"counter":3}}`}, out.flushed)
+}
+
+type failingVariableRenderer struct {
+	calls  int
+	failOn int
+}
+
+func (r *failingVariableRenderer) GetKind() string {
+	return "failing"
+}
+
+func (r *failingVariableRenderer) RenderVariable(_ context.Context, data []byte, out io.Writer) error {
+	r.calls++
+	if r.calls == r.failOn {
+		return errors.New("unable to render variable")
+	}
+	_, err := out.Write(data)
+	return err
+}
+
+func TestResolver_ResolveGraphQLSubscription_FilterError(t *testing.T) {
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	plan := &GraphQLSubscription{
+		Trigger: GraphQLSubscriptionTrigger{
+			Source: &staticStream{
+				messages: []string{
+					`{"data":{"counter":{"value":1,"owner":"a"}}}`,
+					`{"data":{"counter":{"value":2,"owner":"a"}}}`,
+					`{"data":{"counter":{"value":3,"owner":"a"}}}`,
+				},
+			},
+			PostProcessing: PostProcessingConfiguration{
+				SelectResponseDataPath:   []string{"data"},
+				SelectResponseErrorsPath: []string{"errors"},
+			},
+		},
+		Filter: &SubscriptionFilter{
+			In: &SubscriptionFieldFilter{
+				FieldPath: []string{"counter", "owner"},
+				Values: []InputTemplate{
+					{
+						Segments: []TemplateSegment{
+							{
+								SegmentType:        VariableSegmentType,
+								VariableKind:       ContextVariableKind,
+								VariableSourcePath: []string{"owner"},
+								Renderer:           &failingVariableRenderer{failOn: 2},
+							},
+						},
+					},
+				},
+			},
+		},
+		Response: &GraphQLResponse{
+			Data: &Object{
+				Fields: []*Field{
+					{
+						Name: []byte("counter"),
+						Value: &Integer{
+							Path: []string{"counter", "value"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	out := &TestFlushWriter{
+		buf: bytes.Buffer{},
+	}
+	ctx := NewContext(c)
+	ctx.Variables = []byte(`{"owner":"a"}`)
+
+	// the event which can't be filtered is dropped, the subscription continues
+	err := newResolver(c, false).ResolveGraphQLSubscription(ctx, plan, out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"data":{"counter":1}}`, `{"data":{"counter":3}}`}, out.flushed)
+}
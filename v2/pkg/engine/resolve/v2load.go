@@ -614,6 +614,7 @@ WithNextItem:
 }
 
 func (l *V2Loader) executeSourceLoad(ctx context.Context, disallowSingleFlight bool, source DataSource, input []byte, out io.Writer) error {
+	ctx = context.WithValue(ctx, dataSourceContextKey{}, l.ctx)
 	if !l.enableSingleFlight || disallowSingleFlight {
 		return source.Load(ctx, input, out)
 	}
@@ -76,8 +76,9 @@ func NewJSONVariableRendererWithValidation(jsonSchema string) *JSONVariableRende
 
 // NewJSONVariableRendererWithValidationFromTypeRef creates a new JSONVariableRenderer
 // The argument typeRef must exist on the operation ast.Document, otherwise it will panic!
-func NewJSONVariableRendererWithValidationFromTypeRef(operation, definition *ast.Document, variableTypeRef int) (*JSONVariableRenderer, error) {
-	jsonSchema := graphqljsonschema.FromTypeRef(operation, definition, variableTypeRef)
+// opts are passed to graphqljsonschema.FromTypeRef, e.g. to validate custom scalars with graphqljsonschema.WithScalars
+func NewJSONVariableRendererWithValidationFromTypeRef(operation, definition *ast.Document, variableTypeRef int, opts ...graphqljsonschema.Option) (*JSONVariableRenderer, error) {
+	jsonSchema := graphqljsonschema.FromTypeRef(operation, definition, variableTypeRef, opts...)
 	validator, err := graphqljsonschema.NewValidatorFromSchema(jsonSchema)
 	if err != nil {
 		return nil, err
@@ -171,8 +172,9 @@ func (p *PlainVariableRenderer) RenderVariable(ctx context.Context, data []byte,
 
 // NewGraphQLVariableRendererFromTypeRef creates a new GraphQLVariableRenderer
 // The argument typeRef must exist on the operation ast.Document, otherwise it will panic!
-func NewGraphQLVariableRendererFromTypeRef(operation, definition *ast.Document, variableTypeRef int) (*GraphQLVariableRenderer, error) {
-	jsonSchema := graphqljsonschema.FromTypeRef(operation, definition, variableTypeRef)
+// opts are passed to graphqljsonschema.FromTypeRef, e.g. to validate custom scalars with graphqljsonschema.WithScalars
+func NewGraphQLVariableRendererFromTypeRef(operation, definition *ast.Document, variableTypeRef int, opts ...graphqljsonschema.Option) (*GraphQLVariableRenderer, error) {
+	jsonSchema := graphqljsonschema.FromTypeRef(operation, definition, variableTypeRef, opts...)
 	validator, err := graphqljsonschema.NewValidatorFromSchema(jsonSchema)
 	if err != nil {
 		return nil, err
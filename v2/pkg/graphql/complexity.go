@@ -3,6 +3,7 @@ package graphql
 import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_complexity"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 )
 
@@ -22,21 +23,54 @@ func (d defaultComplexityCalculator) Calculate(operation, definition *ast.Docume
 	return complexityResult(globalComplexityResult, fieldsComplexityResult, report)
 }
 
-type ComplexityResult struct {
-	NodeCount    int
-	Complexity   int
-	Depth        int
-	PerRootField []FieldComplexityResult
-	Errors       Errors
+// NewCostComplexityCalculator returns a ComplexityCalculator which additionally estimates the cost of operations
+// with the @cost and @listSize directives of the schema, see operation_cost.EstimateCost.
+func NewCostComplexityCalculator(config operation_cost.Config) ComplexityCalculator {
+	return costComplexityCalculator{config: config}
 }
 
-type FieldComplexityResult struct {
-	TypeName   string
-	FieldName  string
-	Alias      string
+type costComplexityCalculator struct {
+	config operation_cost.Config
+}
+
+func (c costComplexityCalculator) Calculate(operation, definition *ast.Document) (ComplexityResult, error) {
+	report := operationreport.Report{}
+	globalComplexityResult, fieldsComplexityResult := operation_complexity.CalculateOperationComplexity(operation, definition, &report)
+	cost := operation_cost.EstimateCost(operation, definition, "", c.config, &report)
+
+	result, err := complexityResult(globalComplexityResult, fieldsComplexityResult, report)
+	result.EstimatedCost = cost.Cost
+	for _, rootFieldCost := range cost.PerRootField {
+		for i := range result.PerRootField {
+			rootField := &result.PerRootField[i]
+			if rootField.EstimatedCost == 0 && rootField.TypeName == rootFieldCost.TypeName && rootField.FieldName == rootFieldCost.FieldName && rootField.Alias == rootFieldCost.Alias {
+				rootField.EstimatedCost = rootFieldCost.Cost
+				break
+			}
+		}
+	}
+
+	return result, err
+}
+
+type ComplexityResult struct {
 	NodeCount  int
 	Complexity int
 	Depth      int
+	// EstimatedCost is only calculated by the calculator of NewCostComplexityCalculator
+	EstimatedCost int
+	PerRootField  []FieldComplexityResult
+	Errors        Errors
+}
+
+type FieldComplexityResult struct {
+	TypeName      string
+	FieldName     string
+	Alias         string
+	NodeCount     int
+	Complexity    int
+	Depth         int
+	EstimatedCost int
 }
 
 func complexityResult(globalComplexityResult operation_complexity.OperationStats, fieldsComplexityResult []operation_complexity.RootFieldStats, report operationreport.Report) (ComplexityResult, error) {
@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// CostBudget is charged with the estimated cost of every operation before it's executed, e.g. to rate limit
+// clients by the cost of their operations instead of the number of their requests.
+// An error rejects the operation and is returned by ExecutionEngineV2.Execute as is.
+type CostBudget interface {
+	Charge(request *resolve.Request, estimatedCost int) error
+}
+
+// CostBudgetFunc is a function which implements CostBudget
+type CostBudgetFunc func(request *resolve.Request, estimatedCost int) error
+
+func (f CostBudgetFunc) Charge(request *resolve.Request, estimatedCost int) error {
+	return f(request, estimatedCost)
+}
+
+// CostControl configures the estimation of the cost of operations with the @cost and @listSize directives of the schema
+type CostControl struct {
+	Config operation_cost.Config
+	// MaxEstimatedCost rejects operations with a higher estimated cost, 0 disables the limit
+	MaxEstimatedCost int
+	// Budget is charged with the estimated cost of every operation which doesn't exceed MaxEstimatedCost, nil disables the budget
+	Budget CostBudget
+}
+
+func (c CostControl) enabled() bool {
+	return c.MaxEstimatedCost > 0 || c.Budget != nil
+}
+
+// chargeEstimatedCost rejects operations which exceed the maximum estimated cost or the budget
+func (e *ExecutionEngineV2) chargeEstimatedCost(operation *Request, request *resolve.Request) error {
+	costControl := e.config.costControl
+
+	var report operationreport.Report
+	cost := operation_cost.EstimateCost(&operation.document, &e.config.schema.document, operation.OperationName, costControl.Config, &report)
+	if report.HasErrors() {
+		return RequestErrorsFromOperationReport(report)
+	}
+
+	if costControl.MaxEstimatedCost > 0 && cost.Cost > costControl.MaxEstimatedCost {
+		report.AddExternalError(operationreport.ErrEstimatedCostExceeded(cost.Cost, costControl.MaxEstimatedCost))
+		return RequestErrorsFromOperationReport(report)
+	}
+
+	if costControl.Budget != nil {
+		return costControl.Budget.Charge(request, cost.Cost)
+	}
+	return nil
+}
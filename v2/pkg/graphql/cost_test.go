@@ -0,0 +1,125 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
+)
+
+const costTestSchema = `
+directive @cost(weight: String!) on ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | OBJECT | SCALAR
+directive @listSize(assumedSize: Int, slicingArguments: [String!], sizedFields: [String!], requireOneSlicingArgument: Boolean = true) on FIELD_DEFINITION
+
+type Query {
+	users(first: Int): [User!]! @listSize(slicingArguments: ["first"])
+}
+
+type User {
+	id: ID!
+	address: Address
+}
+
+type Address @cost(weight: "5") {
+	city: String
+}
+`
+
+func TestCostComplexityCalculator(t *testing.T) {
+	schema, err := NewSchemaFromString(costTestSchema)
+	require.NoError(t, err)
+
+	request := Request{
+		Query:     `query ($first: Int) { users(first: $first) { id address { city } } }`,
+		Variables: []byte(`{"first":3}`),
+	}
+	result, err := request.CalculateComplexity(NewCostComplexityCalculator(operation_cost.Config{}), schema)
+	require.NoError(t, err)
+	assert.Equal(t, 18, result.EstimatedCost)
+	require.Len(t, result.PerRootField, 1)
+	assert.Equal(t, 18, result.PerRootField[0].EstimatedCost)
+	assert.Equal(t, 2, result.Complexity)
+}
+
+func TestRequest_CalculateActualCost(t *testing.T) {
+	schema, err := NewSchemaFromString(costTestSchema)
+	require.NoError(t, err)
+
+	request := Request{
+		Query: `{ users(first: 100) { id address { city } } }`,
+	}
+	cost, err := request.CalculateActualCost(schema, []byte(`{"users":[{"id":"1","address":{"city":"Berlin"}},{"id":"2","address":null}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, 7, cost)
+
+	_, err = request.CalculateActualCost(nil, nil)
+	assert.Equal(t, ErrNilSchema, err)
+}
+
+func TestExecutionEngineV2_CostControl(t *testing.T) {
+	t.Run("max estimated cost", func(t *testing.T) {
+		schema, err := NewSchemaFromString(costTestSchema)
+		require.NoError(t, err)
+
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetCostControl(CostControl{MaxEstimatedCost: 10})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		resultWriter := NewEngineResultWriter()
+		err = engine.Execute(context.Background(), &Request{Query: `{ users(first: 20) { id } }`}, &resultWriter)
+
+		var requestErrors RequestErrors
+		require.ErrorAs(t, err, &requestErrors)
+		require.Len(t, requestErrors, 1)
+		assert.Equal(t, `The estimated cost of the operation is 20 which exceeds the maximum cost of 10.`, requestErrors[0].Message)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		errBudgetExceeded := errors.New("budget exceeded")
+		remainingBudget := 1
+
+		schema := starwarsSchema(t)
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources(simpleHeroDataSources(t, schema))
+		engineConf.SetCostControl(CostControl{
+			Budget: CostBudgetFunc(func(request *resolve.Request, estimatedCost int) error {
+				if estimatedCost > remainingBudget {
+					return errBudgetExceeded
+				}
+				remainingBudget -= estimatedCost
+				return nil
+			}),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		execute := func() (string, error) {
+			operation := loadStarWarsQuery(starwars.FileSimpleHeroQuery, nil)(t)
+			resultWriter := NewEngineResultWriter()
+			err := engine.Execute(context.Background(), &operation, &resultWriter)
+			return resultWriter.String(), err
+		}
+
+		response, err := execute()
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hero":{"name":"Luke Skywalker"}}}`, response)
+		assert.Equal(t, 0, remainingBudget)
+
+		_, err = execute()
+		assert.Equal(t, errBudgetExceeded, err)
+	})
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"encoding/json"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// DeprecationMode configures how the ExecutionEngineV2 handles operations which use deprecated
+// fields, arguments, enum values or input fields
+type DeprecationMode int
+
+const (
+	// DeprecationModeIgnore executes operations without checking for deprecated elements
+	DeprecationModeIgnore DeprecationMode = iota
+	// DeprecationModeWarn executes operations and adds a warning for each use of a deprecated element
+	// to the "warnings" of the response extensions
+	DeprecationModeWarn
+	// DeprecationModeReject rejects operations which use deprecated elements with validation errors
+	DeprecationModeReject
+)
+
+type deprecationWarnings struct {
+	Warnings []deprecationWarning `json:"warnings"`
+}
+
+type deprecationWarning struct {
+	Message    string                   `json:"message"`
+	Locations  []graphqlerrors.Location `json:"locations,omitempty"`
+	Coordinate string                   `json:"coordinate"`
+}
+
+// Deprecations returns all uses of deprecated fields, arguments, enum values and input fields in the request,
+// including enum values and input fields in the values of variables.
+func (r *Request) Deprecations(schema *Schema) ([]astvalidation.Deprecation, error) {
+	if schema == nil {
+		return nil, ErrNilSchema
+	}
+
+	report := r.parseQueryOnce()
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	r.document.Input.Variables = r.Variables
+
+	var deprecations []astvalidation.Deprecation
+	validator := astvalidation.NewOperationValidator([]astvalidation.Rule{
+		astvalidation.DeprecatedUsage(astvalidation.WithDeprecationReport(func(deprecation astvalidation.Deprecation) {
+			deprecations = append(deprecations, deprecation)
+		})),
+	})
+	validator.Validate(&r.document, &schema.document, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+	return deprecations, nil
+}
+
+// deprecationErrors returns the deprecations as validation errors
+func deprecationErrors(deprecations []astvalidation.Deprecation) Errors {
+	report := operationreport.Report{}
+	for _, deprecation := range deprecations {
+		report.AddExternalError(deprecation.ExternalError())
+	}
+	return RequestErrorsFromOperationReport(report)
+}
+
+// deprecationExtensions returns the response extensions with a warning for each deprecation
+func deprecationExtensions(deprecations []astvalidation.Deprecation) ([]byte, error) {
+	extensions := deprecationWarnings{
+		Warnings: make([]deprecationWarning, 0, len(deprecations)),
+	}
+	for _, deprecation := range deprecations {
+		externalError := deprecation.ExternalError()
+		extensions.Warnings = append(extensions.Warnings, deprecationWarning{
+			Message:    externalError.Message,
+			Locations:  externalError.Locations,
+			Coordinate: deprecation.Coordinate,
+		})
+	}
+	return json.Marshal(extensions)
+}
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
+)
+
+func TestRequest_Deprecations(t *testing.T) {
+	schema := starwarsSchema(t)
+
+	t.Run("without deprecated elements", func(t *testing.T) {
+		request := Request{
+			Query: `query ($id: ID!) { droid(id: $id) { name } }`,
+		}
+		deprecations, err := request.Deprecations(schema)
+		require.NoError(t, err)
+		assert.Len(t, deprecations, 0)
+	})
+
+	t.Run("deprecated field and enum value of a variable", func(t *testing.T) {
+		request := Request{
+			Query:     `mutation ($episode: Episode!) { createReview(episode: $episode, review: {stars: 5}) { id } } query { hero { name ... on Human { height } } }`,
+			Variables: []byte(`{"episode":"JEDI"}`),
+		}
+		deprecations, err := request.Deprecations(schema)
+		require.NoError(t, err)
+
+		coordinates := make([]string, 0, len(deprecations))
+		for _, deprecation := range deprecations {
+			coordinates = append(coordinates, deprecation.Coordinate)
+		}
+		assert.Equal(t, []string{"Episode.JEDI", "Query.hero", "Human.height"}, coordinates)
+	})
+
+	t.Run("nil schema", func(t *testing.T) {
+		request := Request{
+			Query: `{ hero { name } }`,
+		}
+		_, err := request.Deprecations(nil)
+		assert.Equal(t, ErrNilSchema, err)
+	})
+}
+
+func TestExecutionEngineV2_DeprecationMode(t *testing.T) {
+	run := func(t *testing.T, mode DeprecationMode) (response string, err error) {
+		schema := starwarsSchema(t)
+		engineConf := NewEngineV2Configuration(schema)
+		engineConf.SetDataSources(simpleHeroDataSources(t, schema))
+		engineConf.SetDeprecationMode(mode)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		operation := loadStarWarsQuery(starwars.FileSimpleHeroQuery, nil)(t)
+		resultWriter := NewEngineResultWriter()
+		err = engine.Execute(context.Background(), &operation, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("ignore", func(t *testing.T) {
+		response, err := run(t, DeprecationModeIgnore)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hero":{"name":"Luke Skywalker"}}}`, response)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		response, err := run(t, DeprecationModeWarn)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hero":{"name":"Luke Skywalker"}},"extensions":{"warnings":[{"message":"The field \"Query.hero\" is deprecated. No longer supported","locations":[{"line":2,"column":5}],"coordinate":"Query.hero"}]}}`, response)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		response, err := run(t, DeprecationModeReject)
+		assert.Equal(t, "", response)
+
+		var requestErrors RequestErrors
+		require.ErrorAs(t, err, &requestErrors)
+		require.Len(t, requestErrors, 1)
+		assert.Equal(t, `The field "Query.hero" is deprecated. No longer supported`, requestErrors[0].Message)
+	})
+}
+
+// simpleHeroDataSources returns a graphql data source with the id "starwars" which responds to starwars.FileSimpleHeroQuery
+func simpleHeroDataSources(t *testing.T, schema *Schema) []plan.DataSourceConfiguration {
+	return []plan.DataSourceConfiguration{
+		{
+			ID: "starwars",
+			RootNodes: []plan.TypeField{
+				{
+					TypeName:   "Query",
+					FieldNames: []string{"hero"},
+				},
+			},
+			ChildNodes: []plan.TypeField{
+				{
+					TypeName:   "Character",
+					FieldNames: []string{"name"},
+				},
+			},
+			Factory: &graphql_datasource.Factory{
+				HTTPClient: testNetHttpClient(t, roundTripperTestCase{
+					expectedHost:     "example.com",
+					expectedPath:     "/",
+					expectedBody:     "",
+					sendResponseBody: `{"data":{"hero":{"name":"Luke Skywalker"}}}`,
+					sendStatusCode:   200,
+				}),
+			},
+			Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+				Fetch: graphql_datasource.FetchConfiguration{
+					URL:    "https://example.com/",
+					Method: "GET",
+				},
+				UpstreamSchema: string(schema.Document()),
+			}),
+		},
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"net/http"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	graphqlDataSource "github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation/federationdata"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/scalar"
 )
 
 const (
@@ -20,6 +22,16 @@ type EngineV2Configuration struct {
 	plannerConfig            plan.Configuration
 	websocketBeforeStartHook WebsocketBeforeStartHook
 	dataLoaderConfig         dataLoaderConfig
+	scalars                  *scalar.ScalarRegistry
+	usageCollector           *UsageCollector
+	deprecationMode          DeprecationMode
+	maxTokens                int
+	operationLimits          astvalidation.OperationLimits
+	introspectionPredicate   IntrospectionPredicate
+	visibilityFilter         VisibilityFilter
+	costControl              CostControl
+	strictResponseValidation bool
+	responseValidationHook   resolve.ResponseValidationHook
 }
 
 func NewEngineV2Configuration(schema *Schema) EngineV2Configuration {
@@ -44,10 +56,78 @@ func (e *EngineV2Configuration) SetCustomResolveMap(customResolveMap map[string]
 	e.plannerConfig.CustomResolveMap = customResolveMap
 }
 
+// SetScalarRegistry validates input values of custom scalars and serializes their output values with the
+// scalars of the registry. The JSON schemas of the scalars are also used to validate the variables of data sources.
+func (e *EngineV2Configuration) SetScalarRegistry(registry *scalar.ScalarRegistry) {
+	e.scalars = registry
+	e.plannerConfig.Scalars = registry
+}
+
+// SetUsageCollector collects the schema usage of every executed operation, see UsageCollector.
+// The planner includes the field info in the plans as it's required to determine the usage.
+func (e *EngineV2Configuration) SetUsageCollector(collector *UsageCollector) {
+	e.usageCollector = collector
+	if collector != nil {
+		e.plannerConfig.IncludeInfo = true
+	}
+}
+
+// EnableStrictResponseValidation validates the values of data sources beyond their JSON types,
+// invalid enum values, Int values, IDs and __typename values of abstract types are reported as errors of their fields.
+// The hook is notified about every violation, it's optional.
+func (e *EngineV2Configuration) EnableStrictResponseValidation(hook resolve.ResponseValidationHook) {
+	e.strictResponseValidation = true
+	e.responseValidationHook = hook
+	e.plannerConfig.IncludeResponseValidation = true
+}
+
 func (e *EngineV2Configuration) AddDataSource(dataSource plan.DataSourceConfiguration) {
 	e.plannerConfig.DataSources = append(e.plannerConfig.DataSources, dataSource)
 }
 
+// SetDeprecationMode configures whether operations using deprecated elements are executed
+// with warnings in the response extensions, rejected or executed without any checks, which is the default.
+func (e *EngineV2Configuration) SetDeprecationMode(mode DeprecationMode) {
+	e.deprecationMode = mode
+}
+
+// SetMaxTokens rejects operations with more than maxTokens tokens before they are parsed, 0 disables the limit.
+// It applies to requests which are not parsed yet when they are executed.
+func (e *EngineV2Configuration) SetMaxTokens(maxTokens int) {
+	e.maxTokens = maxTokens
+}
+
+// SetOperationLimits rejects operations which exceed the enabled limits with validation errors, see astvalidation.OperationLimits.
+func (e *EngineV2Configuration) SetOperationLimits(limits astvalidation.OperationLimits) {
+	e.operationLimits = limits
+}
+
+// DisableIntrospection rejects all operations which select the introspection fields __schema or __type.
+func (e *EngineV2Configuration) DisableIntrospection() {
+	e.introspectionPredicate = disabledIntrospection
+}
+
+// SetIntrospectionPredicate allows operations to select the introspection fields __schema or __type only if the
+// predicate returns true for the request. The predicate is called after the execution options are applied,
+// so it sees the headers added by WithAdditionalHttpHeaders. A nil predicate allows introspection for all requests.
+func (e *EngineV2Configuration) SetIntrospectionPredicate(predicate IntrospectionPredicate) {
+	e.introspectionPredicate = predicate
+}
+
+// SetVisibilityFilter hides the schema elements matched by the filter from introspection and from the validation
+// of operations, so selecting a hidden field fails like selecting an unknown field. Operations are still planned
+// with the full schema, hidden enum values and objects of hidden union members or interface implementations
+// in responses are resolved to null with an error, see VisibilityFilter.
+func (e *EngineV2Configuration) SetVisibilityFilter(filter VisibilityFilter) {
+	e.visibilityFilter = filter
+}
+
+// SetCostControl estimates the cost of every operation before it's executed to reject operations which exceed
+// the maximum estimated cost or the budget, see CostControl.
+func (e *EngineV2Configuration) SetCostControl(costControl CostControl) {
+	e.costControl = costControl
+}
+
 func (e *EngineV2Configuration) SetDataSources(dataSources []plan.DataSourceConfiguration) {
 	e.plannerConfig.DataSources = dataSources
 }
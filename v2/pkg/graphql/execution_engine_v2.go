@@ -16,11 +16,13 @@ import (
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/httpclient"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/introspection_datasource"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/postprocess"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/literal"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/pool"
 )
@@ -146,6 +148,10 @@ type ExecutionEngineV2 struct {
 	resolver                     *resolve.Resolver
 	internalExecutionContextPool sync.Pool
 	executionPlanCache           *lru.Cache
+	// visibleSchema is the schema without the elements hidden by the visibility filter, it's used
+	// for the normalization and validation of operations, the full schema is used for planning
+	// and the hidden enum values and possible types in responses are resolved to null
+	visibleSchema *Schema
 }
 
 type WebsocketBeforeStartHook interface {
@@ -199,7 +205,16 @@ func NewExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, eng
 		return nil, err
 	}
 
-	introspectionCfg, err := introspection_datasource.NewIntrospectionConfigFactory(&engineConfig.schema.document)
+	visibleSchema := engineConfig.schema
+	if engineConfig.visibilityFilter != nil {
+		visibleSchema, err = engineConfig.schema.VisibleSchema(engineConfig.visibilityFilter)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.plannerConfig.VisibleDefinition = &visibleSchema.document
+	}
+
+	introspectionCfg, err := introspection_datasource.NewIntrospectionConfigFactory(&visibleSchema.document)
 	if err != nil {
 		return nil, err
 	}
@@ -212,23 +227,44 @@ func NewExecutionEngineV2(ctx context.Context, logger abstractlogger.Logger, eng
 		engineConfig.AddFieldConfiguration(fieldCfg)
 	}
 
+	resolver := resolve.New(ctx, engineConfig.dataLoaderConfig.EnableSingleFlightLoader)
+	resolver.SetLogger(logger)
+
 	return &ExecutionEngineV2{
 		logger:   logger,
 		config:   engineConfig,
 		planner:  plan.NewPlanner(ctx, engineConfig.plannerConfig),
-		resolver: resolve.New(ctx, engineConfig.dataLoaderConfig.EnableSingleFlightLoader),
+		resolver: resolver,
 		internalExecutionContextPool: sync.Pool{
 			New: func() interface{} {
 				return newInternalExecutionContext()
 			},
 		},
 		executionPlanCache: executionPlanCache,
+		visibleSchema:      visibleSchema,
 	}, nil
 }
 
 func (e *ExecutionEngineV2) Execute(ctx context.Context, operation *Request, writer resolve.FlushWriter, options ...ExecutionOptionsV2) error {
+	if e.config.maxTokens > 0 {
+		if report := operation.parseQueryOnceWithMaxTokens(e.config.maxTokens); report.HasErrors() {
+			return RequestErrorsFromOperationReport(report)
+		}
+	}
+
+	// the limits are validated before normalization, which inlines fragments
+	if e.config.operationLimits != (astvalidation.OperationLimits{}) {
+		result, err := operation.ValidateOperationLimits(e.visibleSchema, e.config.operationLimits)
+		if err != nil {
+			return err
+		}
+		if !result.Valid {
+			return result.Errors
+		}
+	}
+
 	if !operation.IsNormalized() {
-		result, err := operation.Normalize(e.config.schema)
+		result, err := operation.Normalize(e.visibleSchema)
 		if err != nil {
 			return err
 		}
@@ -238,7 +274,7 @@ func (e *ExecutionEngineV2) Execute(ctx context.Context, operation *Request, wri
 		}
 	}
 
-	result, err := operation.ValidateForSchema(e.config.schema)
+	result, err := operation.ValidateForSchema(e.visibleSchema, astvalidation.WithScalarRegistry(e.config.scalars))
 	if err != nil {
 		return err
 	}
@@ -246,21 +282,58 @@ func (e *ExecutionEngineV2) Execute(ctx context.Context, operation *Request, wri
 		return result.Errors
 	}
 
+	var deprecations []astvalidation.Deprecation
+	if e.config.deprecationMode != DeprecationModeIgnore {
+		deprecations, err = operation.Deprecations(e.visibleSchema)
+		if err != nil {
+			return err
+		}
+		if len(deprecations) != 0 && e.config.deprecationMode == DeprecationModeReject {
+			return deprecationErrors(deprecations)
+		}
+	}
+
 	execContext := e.getExecutionCtx()
 	defer e.putExecutionCtx(execContext)
 
 	execContext.prepare(ctx, operation.Variables, operation.request)
+	execContext.resolveContext.Scalars = e.config.scalars
+	execContext.resolveContext.StrictResponseValidation = e.config.strictResponseValidation
+	execContext.resolveContext.ResponseValidationHook = e.config.responseValidationHook
+	execContext.resolveContext.ValidateEnumValuesAndPossibleTypes = e.config.visibilityFilter != nil
+	if len(deprecations) != 0 {
+		execContext.resolveContext.Extensions, err = deprecationExtensions(deprecations)
+		if err != nil {
+			return err
+		}
+	}
 
 	for i := range options {
 		options[i](execContext)
 	}
 
+	if e.config.introspectionPredicate != nil && !e.config.introspectionPredicate(&execContext.resolveContext.Request) {
+		if report := operation.introspectionReport(); report.HasErrors() {
+			return RequestErrorsFromOperationReport(report)
+		}
+	}
+
+	if e.config.costControl.enabled() {
+		if err = e.chargeEstimatedCost(operation, &execContext.resolveContext.Request); err != nil {
+			return err
+		}
+	}
+
 	var report operationreport.Report
-	cachedPlan := e.getCachedPlan(execContext, &operation.document, &e.config.schema.document, operation.OperationName, &report)
+	cachedPlan, operationHash := e.getCachedPlan(execContext, &operation.document, &e.config.schema.document, operation.OperationName, &report)
 	if report.HasErrors() {
 		return report
 	}
 
+	if e.config.usageCollector != nil {
+		e.collectUsage(cachedPlan, operationHash, operation, execContext.resolveContext.Request.Header)
+	}
+
 	switch p := cachedPlan.(type) {
 	case *plan.SynchronousResponsePlan:
 		err = e.resolver.ResolveGraphQLResponse(execContext.resolveContext, p.Response, nil, writer)
@@ -273,7 +346,7 @@ func (e *ExecutionEngineV2) Execute(ctx context.Context, operation *Request, wri
 	return err
 }
 
-func (e *ExecutionEngineV2) getCachedPlan(ctx *internalExecutionContext, operation, definition *ast.Document, operationName string, report *operationreport.Report) plan.Plan {
+func (e *ExecutionEngineV2) getCachedPlan(ctx *internalExecutionContext, operation, definition *ast.Document, operationName string, report *operationreport.Report) (plan.Plan, uint64) {
 
 	hash := pool.Hash64.Get()
 	hash.Reset()
@@ -281,14 +354,14 @@ func (e *ExecutionEngineV2) getCachedPlan(ctx *internalExecutionContext, operati
 	err := astprinter.Print(operation, definition, hash)
 	if err != nil {
 		report.AddInternalError(err)
-		return nil
+		return nil, 0
 	}
 
 	cacheKey := hash.Sum64()
 
 	if cached, ok := e.executionPlanCache.Get(cacheKey); ok {
 		if p, ok := cached.(plan.Plan); ok {
-			return p
+			return p, cacheKey
 		}
 	}
 
@@ -296,12 +369,25 @@ func (e *ExecutionEngineV2) getCachedPlan(ctx *internalExecutionContext, operati
 	defer e.plannerMu.Unlock()
 	planResult := e.planner.Plan(operation, definition, operationName, report)
 	if report.HasErrors() {
-		return nil
+		return nil, 0
 	}
 
 	p := ctx.postProcessor.Process(planResult)
 	e.executionPlanCache.Add(cacheKey, p)
-	return p
+	return p, cacheKey
+}
+
+func (e *ExecutionEngineV2) collectUsage(p plan.Plan, operationHash uint64, operation *Request, header http.Header) {
+	variables := []byte(operation.Variables)
+	if len(variables) == 0 || bytes.Equal(variables, literal.NULL) {
+		variables = []byte("{}")
+	}
+	usage, err := plan.GetSchemaUsageInfo(p, &operation.document, &e.config.schema.document, variables)
+	if err != nil {
+		e.logger.Error("ExecutionEngineV2.collectUsage", abstractlogger.Error(err))
+		return
+	}
+	e.config.usageCollector.Collect(operationHash, operation.OperationName, header, usage)
 }
 
 func (e *ExecutionEngineV2) GetWebsocketBeforeStartHook() WebsocketBeforeStartHook {
@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// IntrospectionPredicate decides whether a request may select the introspection fields __schema and __type,
+// e.g. based on the headers of the request.
+type IntrospectionPredicate func(request *resolve.Request) bool
+
+// disabledIntrospection is the IntrospectionPredicate of DisableIntrospection
+func disabledIntrospection(_ *resolve.Request) bool {
+	return false
+}
+
+// introspectionReport reports an error for every introspection root field of the selected operation.
+// Operations without introspection fields are always allowed.
+func (r *Request) introspectionReport() (report operationreport.Report) {
+	report = r.parseQueryOnce()
+	if report.HasErrors() {
+		return report
+	}
+
+	operationDefinitionRef := r.selectedOperationDefinition()
+	if operationDefinitionRef == ast.InvalidRef || !r.document.OperationDefinitions[operationDefinitionRef].HasSelections {
+		return report
+	}
+
+	r.reportIntrospectionFields(r.document.OperationDefinitions[operationDefinitionRef].SelectionSet, &report)
+	return report
+}
+
+func (r *Request) reportIntrospectionFields(selectionSet int, report *operationreport.Report) {
+	for _, selectionRef := range r.document.SelectionSets[selectionSet].SelectionRefs {
+		selection := r.document.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			switch r.document.FieldNameUnsafeString(selection.Ref) {
+			case schemaIntrospectionFieldName, typeIntrospectionFieldName:
+				report.AddExternalError(operationreport.ErrIntrospectionDisabled(r.document.FieldNameBytes(selection.Ref), r.document.Fields[selection.Ref].Position))
+			}
+		case ast.SelectionKindInlineFragment:
+			if inlineFragmentSelectionSet, ok := r.document.InlineFragmentSelectionSet(selection.Ref); ok {
+				r.reportIntrospectionFields(inlineFragmentSelectionSet, report)
+			}
+		case ast.SelectionKindFragmentSpread:
+			fragment, exists := r.document.FragmentDefinitionRef(r.document.FragmentSpreadNameBytes(selection.Ref))
+			if exists {
+				r.reportIntrospectionFields(r.document.FragmentDefinitions[fragment].SelectionSet, report)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+func TestRequest_IntrospectionReport(t *testing.T) {
+	run := func(request Request, expectedMessages ...string) func(t *testing.T) {
+		return func(t *testing.T) {
+			report := request.introspectionReport()
+
+			messages := make([]string, 0, len(report.ExternalErrors))
+			for _, externalError := range report.ExternalErrors {
+				messages = append(messages, externalError.Message)
+			}
+			assert.ElementsMatch(t, expectedMessages, messages)
+		}
+	}
+
+	t.Run("without introspection fields", run(Request{Query: `{ hero { name } }`}))
+	t.Run("schema field", run(Request{Query: `{ hero { name } __schema { types { name } } }`},
+		`Introspection is disabled, but the operation selects the field "__schema".`))
+	t.Run("type field in fragments", run(Request{Query: `query { ... on Query { __type(name: "Droid") { name } } ...Schema } fragment Schema on Query { __schema { queryType { name } } }`},
+		`Introspection is disabled, but the operation selects the field "__type".`,
+		`Introspection is disabled, but the operation selects the field "__schema".`))
+	t.Run("not selected operation", run(Request{OperationName: "Hero", Query: `query Hero { hero { name } } query Introspection { __schema { types { name } } }`}))
+	t.Run("selected operation", run(Request{OperationName: "Introspection", Query: `query Hero { hero { name } } query Introspection { __schema { types { name } } }`},
+		`Introspection is disabled, but the operation selects the field "__schema".`))
+}
+
+func TestExecutionEngineV2_IntrospectionPredicate(t *testing.T) {
+	const introspectionQuery = `{ __type(name: "Role") { name } }`
+
+	run := func(t *testing.T, configure func(engineConf *EngineV2Configuration), options ...ExecutionOptionsV2) (string, error) {
+		schema, err := NewSchemaFromString(visibilityTestSchema)
+		require.NoError(t, err)
+
+		engineConf := NewEngineV2Configuration(schema)
+		configure(&engineConf)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		resultWriter := NewEngineResultWriter()
+		err = engine.Execute(context.Background(), &Request{Query: introspectionQuery}, &resultWriter, options...)
+		return resultWriter.String(), err
+	}
+
+	allowedByHeader := func(request *resolve.Request) bool {
+		return request.Header.Get("X-Introspection") == "allowed"
+	}
+
+	t.Run("allowed by default", func(t *testing.T) {
+		response, err := run(t, func(engineConf *EngineV2Configuration) {})
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"__type":{"name":"Role"}}}`, response)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		response, err := run(t, func(engineConf *EngineV2Configuration) {
+			engineConf.DisableIntrospection()
+		})
+		assert.Equal(t, "", response)
+
+		var requestErrors RequestErrors
+		require.ErrorAs(t, err, &requestErrors)
+		require.Len(t, requestErrors, 1)
+		assert.Equal(t, `Introspection is disabled, but the operation selects the field "__type".`, requestErrors[0].Message)
+	})
+
+	t.Run("predicate rejects request", func(t *testing.T) {
+		_, err := run(t, func(engineConf *EngineV2Configuration) {
+			engineConf.SetIntrospectionPredicate(allowedByHeader)
+		}, WithAdditionalHttpHeaders(http.Header{"X-Introspection": []string{"denied"}}))
+		assert.Error(t, err)
+	})
+
+	t.Run("predicate allows request", func(t *testing.T) {
+		response, err := run(t, func(engineConf *EngineV2Configuration) {
+			engineConf.SetIntrospectionPredicate(allowedByHeader)
+		}, WithAdditionalHttpHeaders(http.Header{"X-Introspection": []string{"allowed"}}))
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"__type":{"name":"Role"}}}`, response)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_complexity"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/middleware/operation_cost"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
 )
 
@@ -81,9 +82,29 @@ func (r *Request) CalculateComplexity(complexityCalculator ComplexityCalculator,
 		)
 	}
 
+	r.document.Input.Variables = r.Variables
 	return complexityCalculator.Calculate(&r.document, &schema.document)
 }
 
+// CalculateActualCost calculates the cost of the operation with the @cost directives of the schema from the data
+// of its response, e.g. {"hero":{"name":"Luke Skywalker"}}. Lists are counted with their resolved lengths.
+func (r *Request) CalculateActualCost(schema *Schema, data []byte) (int, error) {
+	if schema == nil {
+		return 0, ErrNilSchema
+	}
+
+	report := r.parseQueryOnce()
+	if report.HasErrors() {
+		return 0, report
+	}
+
+	cost := operation_cost.ActualCost(&r.document, &schema.document, r.OperationName, data, &report)
+	if report.HasErrors() {
+		return 0, report
+	}
+	return cost.Cost, nil
+}
+
 func (r Request) Print(writer io.Writer) (n int, err error) {
 	report := r.parseQueryOnce()
 	if report.HasErrors() {
@@ -98,11 +119,17 @@ func (r *Request) IsNormalized() bool {
 }
 
 func (r *Request) parseQueryOnce() (report operationreport.Report) {
+	return r.parseQueryOnceWithMaxTokens(0)
+}
+
+// parseQueryOnceWithMaxTokens parses the query if it's not parsed yet, rejecting queries with more than maxTokens tokens.
+// A maxTokens of 0 disables the limit.
+func (r *Request) parseQueryOnceWithMaxTokens(maxTokens int) (report operationreport.Report) {
 	if r.isParsed {
 		return report
 	}
 
-	r.document, report = astparser.ParseGraphqlDocumentString(r.Query)
+	r.document, report = astparser.ParseGraphqlDocumentStringWithMaxTokens(r.Query, maxTokens)
 	if !report.HasErrors() {
 		// If the given query has problems, and we failed to parse it,
 		// we shouldn't mark it as parsed. It can be misleading for
@@ -118,31 +145,7 @@ func (r *Request) IsIntrospectionQuery() (result bool, err error) {
 		return false, report
 	}
 
-	var operationDefinitionRef = ast.InvalidRef
-	var possibleOperationDefinitionRefs = make([]int, 0)
-
-	for i := 0; i < len(r.document.RootNodes); i++ {
-		if r.document.RootNodes[i].Kind == ast.NodeKindOperationDefinition {
-			possibleOperationDefinitionRefs = append(possibleOperationDefinitionRefs, r.document.RootNodes[i].Ref)
-		}
-	}
-
-	if len(possibleOperationDefinitionRefs) == 0 {
-		return
-	} else if len(possibleOperationDefinitionRefs) == 1 {
-		operationDefinitionRef = possibleOperationDefinitionRefs[0]
-	} else {
-		for i := 0; i < len(possibleOperationDefinitionRefs); i++ {
-			ref := possibleOperationDefinitionRefs[i]
-			name := r.document.OperationDefinitionNameString(ref)
-
-			if r.OperationName == name {
-				operationDefinitionRef = ref
-				break
-			}
-		}
-	}
-
+	operationDefinitionRef := r.selectedOperationDefinition()
 	if operationDefinitionRef == ast.InvalidRef {
 		return
 	}
@@ -178,6 +181,33 @@ func (r *Request) IsIntrospectionQuery() (result bool, err error) {
 	return true, nil
 }
 
+// selectedOperationDefinition returns the only operation of the parsed document or the operation named by OperationName,
+// ast.InvalidRef if there is no such operation
+func (r *Request) selectedOperationDefinition() int {
+	var possibleOperationDefinitionRefs = make([]int, 0)
+
+	for i := 0; i < len(r.document.RootNodes); i++ {
+		if r.document.RootNodes[i].Kind == ast.NodeKindOperationDefinition {
+			possibleOperationDefinitionRefs = append(possibleOperationDefinitionRefs, r.document.RootNodes[i].Ref)
+		}
+	}
+
+	if len(possibleOperationDefinitionRefs) == 0 {
+		return ast.InvalidRef
+	} else if len(possibleOperationDefinitionRefs) == 1 {
+		return possibleOperationDefinitionRefs[0]
+	}
+
+	for i := 0; i < len(possibleOperationDefinitionRefs); i++ {
+		ref := possibleOperationDefinitionRefs[i]
+		if r.OperationName == r.document.OperationDefinitionNameString(ref) {
+			return ref
+		}
+	}
+
+	return ast.InvalidRef
+}
+
 func (r *Request) OperationType() (OperationType, error) {
 	report := r.parseQueryOnce()
 	if report.HasErrors() {
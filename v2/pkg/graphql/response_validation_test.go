@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/resolve"
+)
+
+type responseViolationCollector struct {
+	violations []resolve.ResponseViolation
+}
+
+func (c *responseViolationCollector) OnResponseViolation(_ context.Context, violation resolve.ResponseViolation) {
+	c.violations = append(c.violations, violation)
+}
+
+func TestExecutionEngineV2_StrictResponseValidation(t *testing.T) {
+	run := func(t *testing.T, hook resolve.ResponseValidationHook, strict bool) string {
+		schema := starwarsSchema(t)
+		engineConf := NewEngineV2Configuration(schema)
+		dataSources := simpleHeroDataSources(t, schema)
+		dataSources[0].Factory.(*graphql_datasource.Factory).HTTPClient = testNetHttpClient(t, roundTripperTestCase{
+			expectedHost:     "example.com",
+			expectedPath:     "/",
+			expectedBody:     "",
+			sendResponseBody: `{"data":{"hero":{"__typename":"Wookiee","name":"Chewbacca"}}}`,
+			sendStatusCode:   200,
+		})
+		engineConf.SetDataSources(dataSources)
+		if strict {
+			engineConf.EnableStrictResponseValidation(hook)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+		require.NoError(t, err)
+
+		operation := Request{
+			Query: `{ hero { __typename name } }`,
+		}
+		resultWriter := NewEngineResultWriter()
+		err = engine.Execute(context.Background(), &operation, &resultWriter)
+		require.NoError(t, err)
+		return resultWriter.String()
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		collector := &responseViolationCollector{}
+		response := run(t, collector, true)
+		assert.Equal(t, `{"errors":[{"message":"Runtime Object type \"Wookiee\" is not a possible type for \"Character\".","path":["hero"]}],"data":null}`, response)
+		assert.Equal(t, []resolve.ResponseViolation{
+			{Kind: resolve.ResponseViolationKindPossibleType, FieldPath: "Query.hero", TypeName: "Character", Value: `"Wookiee"`},
+		}, collector.violations)
+	})
+
+	t.Run("not strict", func(t *testing.T) {
+		response := run(t, nil, false)
+		assert.Equal(t, `{"data":{"hero":{"__typename":"Wookiee","name":"Chewbacca"}}}`, response)
+	})
+}
@@ -426,6 +426,16 @@ func TestSchema_GetAllFieldArguments(t *testing.T) {
 				FieldName:     "multiArgLevel2",
 				ArgumentNames: []string{"lvl", "number"},
 			},
+			{
+				TypeName:      "__Directive",
+				FieldName:     "args",
+				ArgumentNames: []string{"includeDeprecated"},
+			},
+			{
+				TypeName:      "__Field",
+				FieldName:     "args",
+				ArgumentNames: []string{"includeDeprecated"},
+			},
 			{
 				TypeName:      "__Type",
 				FieldName:     "fields",
@@ -436,6 +446,11 @@ func TestSchema_GetAllFieldArguments(t *testing.T) {
 				FieldName:     "enumValues",
 				ArgumentNames: []string{"includeDeprecated"},
 			},
+			{
+				TypeName:      "__Type",
+				FieldName:     "inputFields",
+				ArgumentNames: []string{"includeDeprecated"},
+			},
 		}
 		assert.Equal(t, expectedFieldArguments, fieldArguments)
 	})
@@ -0,0 +1,408 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+const (
+	DefaultUsageWindow              = time.Minute
+	DefaultUsageClientNameHeader    = "graphql-client-name"
+	DefaultUsageClientVersionHeader = "graphql-client-version"
+)
+
+// usageEndedWindowsBuffer is the number of ended windows which are buffered until Start or Flush exports them
+const usageEndedWindowsBuffer = 16
+
+// UsageExporter receives the aggregated usage of a time window
+type UsageExporter interface {
+	Export(records []UsageRecord) error
+}
+
+// UsageRecord is the usage of one operation by one client version within a time window
+type UsageRecord struct {
+	WindowStart   time.Time `json:"windowStart"`
+	WindowEnd     time.Time `json:"windowEnd"`
+	OperationHash string    `json:"operationHash"`
+	OperationName string    `json:"operationName,omitempty"`
+	OperationType string    `json:"operationType"`
+	ClientName    string    `json:"clientName,omitempty"`
+	ClientVersion string    `json:"clientVersion,omitempty"`
+	// RequestCount is the number of executions of the operation
+	RequestCount int                   `json:"requestCount"`
+	TypeFields   []TypeFieldUsage      `json:"typeFields,omitempty"`
+	Arguments    []ArgumentUsage       `json:"arguments,omitempty"`
+	InputFields  []InputTypeFieldUsage `json:"inputFields,omitempty"`
+}
+
+// TypeFieldUsage is a response field and the number of times it was requested
+type TypeFieldUsage struct {
+	Path               []string `json:"path"`
+	EnclosingTypeNames []string `json:"enclosingTypeNames"`
+	FieldName          string   `json:"fieldName"`
+	FieldTypeName      string   `json:"fieldTypeName"`
+	DataSourceIDs      []string `json:"dataSourceIds,omitempty"`
+	Count              int      `json:"count"`
+}
+
+// ArgumentUsage is a field argument and the number of times it was used
+type ArgumentUsage struct {
+	EnclosingTypeName string `json:"enclosingTypeName"`
+	FieldName         string `json:"fieldName"`
+	ArgumentName      string `json:"argumentName"`
+	ArgumentTypeName  string `json:"argumentTypeName"`
+	Count             int    `json:"count"`
+}
+
+// InputTypeFieldUsage is an input field or a variable and the number of times it was used,
+// the enum values of all requests are merged
+type InputTypeFieldUsage struct {
+	IsRootVariable     bool     `json:"isRootVariable,omitempty"`
+	EnclosingTypeNames []string `json:"enclosingTypeNames,omitempty"`
+	FieldName          string   `json:"fieldName"`
+	FieldTypeName      string   `json:"fieldTypeName"`
+	EnumValues         []string `json:"enumValues,omitempty"`
+	Count              int      `json:"count"`
+}
+
+// UsageCollectorConfiguration configures a UsageCollector, zero values are replaced with the defaults
+type UsageCollectorConfiguration struct {
+	Exporter UsageExporter
+	// Window is the duration for which usage is aggregated before it's exported, defaults to one minute
+	Window time.Duration
+	// ClientNameHeader is the request header with the name of the client, defaults to graphql-client-name
+	ClientNameHeader string
+	// ClientVersionHeader is the request header with the version of the client, defaults to graphql-client-version
+	ClientVersionHeader string
+	Logger              abstractlogger.Logger
+}
+
+// UsageCollector aggregates the plan.SchemaUsageInfo of executed operations per operation hash
+// and client name and version in time windows and exports them when a window has ended.
+// Set it with EngineV2Configuration.SetUsageCollector and call Start to export windows in the background,
+// otherwise windows are only exported by Flush. Exporting never happens on the goroutine of a request.
+type UsageCollector struct {
+	config UsageCollectorConfiguration
+	now    func() time.Time
+	// ended receives the windows which ended during Collect, they are exported by Start or Flush
+	ended chan []UsageRecord
+
+	mu          sync.Mutex
+	windowStart time.Time
+	records     map[usageRecordKey]*UsageRecord
+	keys        []usageRecordKey
+}
+
+type usageRecordKey struct {
+	operationHash uint64
+	clientName    string
+	clientVersion string
+}
+
+func NewUsageCollector(config UsageCollectorConfiguration) *UsageCollector {
+	if config.Window <= 0 {
+		config.Window = DefaultUsageWindow
+	}
+	if config.ClientNameHeader == "" {
+		config.ClientNameHeader = DefaultUsageClientNameHeader
+	}
+	if config.ClientVersionHeader == "" {
+		config.ClientVersionHeader = DefaultUsageClientVersionHeader
+	}
+	if config.Logger == nil {
+		config.Logger = abstractlogger.NoopLogger
+	}
+	return &UsageCollector{
+		config:  config,
+		now:     time.Now,
+		ended:   make(chan []UsageRecord, usageEndedWindowsBuffer),
+		records: map[usageRecordKey]*UsageRecord{},
+	}
+}
+
+// Start exports each window once it has ended until the context is done, then the current window is exported.
+func (c *UsageCollector) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.logError(c.Flush())
+			return
+		case <-ticker.C:
+			c.logError(c.export(c.takeEndedWindow()))
+		case records := <-c.ended:
+			c.logError(c.export(records))
+		}
+	}
+}
+
+// Collect adds the usage of an executed operation to the current window
+func (c *UsageCollector) Collect(operationHash uint64, operationName string, header http.Header, usage *plan.SchemaUsageInfo) {
+	key := usageRecordKey{
+		operationHash: operationHash,
+		clientName:    header.Get(c.config.ClientNameHeader),
+		clientVersion: header.Get(c.config.ClientVersionHeader),
+	}
+
+	c.sendEndedWindow(c.takeEndedWindow())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.windowStart.IsZero() {
+		c.windowStart = c.now().Truncate(c.config.Window)
+	}
+
+	record, ok := c.records[key]
+	if !ok {
+		record = &UsageRecord{
+			OperationHash: strconv.FormatUint(operationHash, 10),
+			OperationName: operationName,
+			OperationType: usage.OperationType.Name(),
+			ClientName:    key.clientName,
+			ClientVersion: key.clientVersion,
+		}
+		c.records[key] = record
+		c.keys = append(c.keys, key)
+	}
+	record.RequestCount++
+	record.addTypeFields(usage.TypeFields)
+	record.addArguments(usage.Arguments)
+	record.addInputFields(usage.InputTypeFields)
+}
+
+// Flush exports the ended windows which weren't exported yet and the current window, even if it hasn't ended yet
+func (c *UsageCollector) Flush() error {
+	for {
+		select {
+		case records := <-c.ended:
+			if err := c.export(records); err != nil {
+				return err
+			}
+			continue
+		default:
+		}
+		break
+	}
+
+	c.mu.Lock()
+	records := c.takeRecords(c.now())
+	c.mu.Unlock()
+	return c.export(records)
+}
+
+// sendEndedWindow passes the records of an ended window to Start,
+// if the buffer is full because Start isn't running, the window is dropped
+func (c *UsageCollector) sendEndedWindow(records []UsageRecord) {
+	if len(records) == 0 {
+		return
+	}
+	select {
+	case c.ended <- records:
+	default:
+		c.config.Logger.Error("UsageCollector.Collect: dropping usage window, the buffer of ended windows is full",
+			abstractlogger.Int("records", len(records)),
+		)
+	}
+}
+
+// takeEndedWindow removes and returns the records of the current window if it has ended
+func (c *UsageCollector) takeEndedWindow() []UsageRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.windowStart.IsZero() || c.now().Before(c.windowStart.Add(c.config.Window)) {
+		return nil
+	}
+	return c.takeRecords(c.windowStart.Add(c.config.Window))
+}
+
+func (c *UsageCollector) takeRecords(windowEnd time.Time) []UsageRecord {
+	if len(c.keys) == 0 {
+		c.windowStart = time.Time{}
+		return nil
+	}
+	records := make([]UsageRecord, 0, len(c.keys))
+	for _, key := range c.keys {
+		record := c.records[key]
+		record.WindowStart = c.windowStart
+		record.WindowEnd = windowEnd
+		records = append(records, *record)
+	}
+	c.windowStart = time.Time{}
+	c.records = map[usageRecordKey]*UsageRecord{}
+	c.keys = nil
+	return records
+}
+
+func (c *UsageCollector) export(records []UsageRecord) error {
+	if len(records) == 0 || c.config.Exporter == nil {
+		return nil
+	}
+	return c.config.Exporter.Export(records)
+}
+
+func (c *UsageCollector) logError(err error) {
+	if err != nil {
+		c.config.Logger.Error("UsageCollector.export", abstractlogger.Error(err))
+	}
+}
+
+func (r *UsageRecord) addTypeFields(fields []plan.TypeFieldUsageInfo) {
+Fields:
+	for _, field := range fields {
+		for i := range r.TypeFields {
+			existing := &r.TypeFields[i]
+			if existing.FieldName == field.FieldName && existing.FieldTypeName == field.FieldTypeName &&
+				equalStrings(existing.Path, field.Path) && equalStrings(existing.EnclosingTypeNames, field.EnclosingTypeNames) {
+				existing.Count++
+				continue Fields
+			}
+		}
+		r.TypeFields = append(r.TypeFields, TypeFieldUsage{
+			Path:               field.Path,
+			EnclosingTypeNames: field.EnclosingTypeNames,
+			FieldName:          field.FieldName,
+			FieldTypeName:      field.FieldTypeName,
+			DataSourceIDs:      field.Source.IDs,
+			Count:              1,
+		})
+	}
+}
+
+func (r *UsageRecord) addArguments(arguments []plan.ArgumentUsageInfo) {
+Arguments:
+	for _, argument := range arguments {
+		for i := range r.Arguments {
+			existing := &r.Arguments[i]
+			if existing.EnclosingTypeName == argument.EnclosingTypeName && existing.FieldName == argument.FieldName &&
+				existing.ArgumentName == argument.ArgumentName && existing.ArgumentTypeName == argument.ArgumentTypeName {
+				existing.Count++
+				continue Arguments
+			}
+		}
+		r.Arguments = append(r.Arguments, ArgumentUsage{
+			EnclosingTypeName: argument.EnclosingTypeName,
+			FieldName:         argument.FieldName,
+			ArgumentName:      argument.ArgumentName,
+			ArgumentTypeName:  argument.ArgumentTypeName,
+			Count:             1,
+		})
+	}
+}
+
+func (r *UsageRecord) addInputFields(fields []plan.InputTypeFieldUsageInfo) {
+Fields:
+	for _, field := range fields {
+		count := field.Count
+		if count == 0 {
+			count = 1
+		}
+		for i := range r.InputFields {
+			existing := &r.InputFields[i]
+			if existing.IsRootVariable != field.IsRootVariable || existing.FieldName != field.FieldName ||
+				existing.FieldTypeName != field.FieldTypeName || !equalStrings(existing.EnclosingTypeNames, field.EnclosingTypeNames) {
+				continue
+			}
+			existing.Count += count
+			for _, enumValue := range field.EnumValues {
+				if !containsString(existing.EnumValues, enumValue) {
+					existing.EnumValues = append(existing.EnumValues, enumValue)
+				}
+			}
+			continue Fields
+		}
+		r.InputFields = append(r.InputFields, InputTypeFieldUsage{
+			IsRootVariable:     field.IsRootVariable,
+			EnclosingTypeNames: field.EnclosingTypeNames,
+			FieldName:          field.FieldName,
+			FieldTypeName:      field.FieldTypeName,
+			EnumValues:         append([]string(nil), field.EnumValues...),
+			Count:              count,
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONLinesUsageExporter writes each UsageRecord as a JSON object on its own line
+type JSONLinesUsageExporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewJSONLinesUsageExporter(out io.Writer) *JSONLinesUsageExporter {
+	return &JSONLinesUsageExporter{
+		out: out,
+	}
+}
+
+func (e *JSONLinesUsageExporter) Export(records []UsageRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	encoder := json.NewEncoder(e.out)
+	for i := range records {
+		if err := encoder.Encode(records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InMemoryUsageExporter keeps all exported records, e.g. for tests or to serve them from an endpoint
+type InMemoryUsageExporter struct {
+	mu      sync.Mutex
+	records []UsageRecord
+}
+
+func NewInMemoryUsageExporter() *InMemoryUsageExporter {
+	return &InMemoryUsageExporter{}
+}
+
+func (e *InMemoryUsageExporter) Export(records []UsageRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, records...)
+	return nil
+}
+
+// Records returns a copy of all exported records
+func (e *InMemoryUsageExporter) Records() []UsageRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]UsageRecord(nil), e.records...)
+}
+
+// Reset removes all exported records
+func (e *InMemoryUsageExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = nil
+}
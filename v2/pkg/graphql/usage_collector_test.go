@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/starwars"
+)
+
+func TestUsageCollector(t *testing.T) {
+	windowStart := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	heroUsage := &plan.SchemaUsageInfo{
+		OperationType: ast.OperationTypeQuery,
+		TypeFields: []plan.TypeFieldUsageInfo{
+			{FieldName: "hero", EnclosingTypeNames: []string{"Query"}, Path: []string{"hero"}, FieldTypeName: "Character"},
+			{FieldName: "name", EnclosingTypeNames: []string{"Character"}, Path: []string{"hero", "name"}, FieldTypeName: "String"},
+		},
+		Arguments: []plan.ArgumentUsageInfo{
+			{FieldName: "hero", EnclosingTypeName: "Query", ArgumentName: "episode", ArgumentTypeName: "Episode"},
+		},
+		InputTypeFields: []plan.InputTypeFieldUsageInfo{
+			{IsRootVariable: true, FieldName: "episode", FieldTypeName: "Episode", Count: 1, EnumValues: []string{"NEWHOPE"}},
+		},
+	}
+
+	clientHeader := func(name, version string) http.Header {
+		header := http.Header{}
+		header.Set(DefaultUsageClientNameHeader, name)
+		header.Set(DefaultUsageClientVersionHeader, version)
+		return header
+	}
+
+	newCollector := func(exporter UsageExporter) (*UsageCollector, *time.Time) {
+		now := windowStart.Add(10 * time.Second)
+		collector := NewUsageCollector(UsageCollectorConfiguration{
+			Exporter: exporter,
+		})
+		collector.now = func() time.Time {
+			return now
+		}
+		return collector, &now
+	}
+
+	t.Run("aggregates usage per operation and client", func(t *testing.T) {
+		exporter := NewInMemoryUsageExporter()
+		collector, _ := newCollector(exporter)
+
+		otherEpisode := *heroUsage
+		otherEpisode.InputTypeFields = []plan.InputTypeFieldUsageInfo{
+			{IsRootVariable: true, FieldName: "episode", FieldTypeName: "Episode", Count: 1, EnumValues: []string{"EMPIRE"}},
+		}
+
+		collector.Collect(1, "Hero", clientHeader("web", "1.0.0"), heroUsage)
+		collector.Collect(1, "Hero", clientHeader("web", "1.0.0"), &otherEpisode)
+		collector.Collect(1, "Hero", clientHeader("web", "1.1.0"), heroUsage)
+		collector.Collect(2, "", http.Header{}, heroUsage)
+		assert.Len(t, exporter.Records(), 0)
+
+		require.NoError(t, collector.Flush())
+		records := exporter.Records()
+		require.Len(t, records, 3)
+
+		assert.Equal(t, "1", records[0].OperationHash)
+		assert.Equal(t, "Hero", records[0].OperationName)
+		assert.Equal(t, "query", records[0].OperationType)
+		assert.Equal(t, "web", records[0].ClientName)
+		assert.Equal(t, "1.0.0", records[0].ClientVersion)
+		assert.Equal(t, 2, records[0].RequestCount)
+		assert.Equal(t, windowStart, records[0].WindowStart)
+		assert.Equal(t, []TypeFieldUsage{
+			{Path: []string{"hero"}, EnclosingTypeNames: []string{"Query"}, FieldName: "hero", FieldTypeName: "Character", Count: 2},
+			{Path: []string{"hero", "name"}, EnclosingTypeNames: []string{"Character"}, FieldName: "name", FieldTypeName: "String", Count: 2},
+		}, records[0].TypeFields)
+		assert.Equal(t, []ArgumentUsage{
+			{EnclosingTypeName: "Query", FieldName: "hero", ArgumentName: "episode", ArgumentTypeName: "Episode", Count: 2},
+		}, records[0].Arguments)
+		assert.Equal(t, []InputTypeFieldUsage{
+			{IsRootVariable: true, FieldName: "episode", FieldTypeName: "Episode", EnumValues: []string{"NEWHOPE", "EMPIRE"}, Count: 2},
+		}, records[0].InputFields)
+
+		assert.Equal(t, "1.1.0", records[1].ClientVersion)
+		assert.Equal(t, 1, records[1].RequestCount)
+
+		assert.Equal(t, "2", records[2].OperationHash)
+		assert.Equal(t, "", records[2].ClientName)
+		assert.Equal(t, 1, records[2].RequestCount)
+
+		require.NoError(t, collector.Flush())
+		assert.Len(t, exporter.Records(), 3)
+	})
+
+	t.Run("exports a window once it has ended", func(t *testing.T) {
+		exporter := NewInMemoryUsageExporter()
+		collector, now := newCollector(exporter)
+
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+		*now = windowStart.Add(DefaultUsageWindow - time.Second)
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+		assert.Len(t, exporter.Records(), 0)
+
+		*now = windowStart.Add(DefaultUsageWindow + time.Second)
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+		// the ended window is exported by Start instead of the goroutine of the request
+		assert.Len(t, exporter.Records(), 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			collector.Start(ctx)
+			close(done)
+		}()
+
+		require.Eventually(t, func() bool {
+			return len(exporter.Records()) == 1
+		}, time.Second, time.Millisecond)
+		records := exporter.Records()
+		assert.Equal(t, 2, records[0].RequestCount)
+		assert.Equal(t, windowStart, records[0].WindowStart)
+		assert.Equal(t, windowStart.Add(DefaultUsageWindow), records[0].WindowEnd)
+
+		cancel()
+		<-done
+		records = exporter.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, 1, records[1].RequestCount)
+		assert.Equal(t, windowStart.Add(DefaultUsageWindow), records[1].WindowStart)
+	})
+
+	t.Run("flush exports ended windows", func(t *testing.T) {
+		exporter := NewInMemoryUsageExporter()
+		collector, now := newCollector(exporter)
+
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+		*now = windowStart.Add(DefaultUsageWindow + time.Second)
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+		assert.Len(t, exporter.Records(), 0)
+
+		require.NoError(t, collector.Flush())
+		records := exporter.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, windowStart, records[0].WindowStart)
+		assert.Equal(t, windowStart.Add(DefaultUsageWindow), records[1].WindowStart)
+	})
+
+	t.Run("custom client headers", func(t *testing.T) {
+		exporter := NewInMemoryUsageExporter()
+		collector := NewUsageCollector(UsageCollectorConfiguration{
+			Exporter:            exporter,
+			ClientNameHeader:    "X-Client",
+			ClientVersionHeader: "X-Client-Version",
+		})
+
+		header := http.Header{}
+		header.Set("X-Client", "ios")
+		header.Set("X-Client-Version", "2.0")
+		collector.Collect(1, "Hero", header, heroUsage)
+
+		require.NoError(t, collector.Flush())
+		records := exporter.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "ios", records[0].ClientName)
+		assert.Equal(t, "2.0", records[0].ClientVersion)
+	})
+
+	t.Run("start exports the current window when the context is done", func(t *testing.T) {
+		exporter := NewInMemoryUsageExporter()
+		collector, _ := newCollector(exporter)
+		collector.Collect(1, "Hero", http.Header{}, heroUsage)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			collector.Start(ctx)
+			close(done)
+		}()
+		cancel()
+		<-done
+
+		assert.Len(t, exporter.Records(), 1)
+	})
+}
+
+func TestJSONLinesUsageExporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	exporter := NewJSONLinesUsageExporter(out)
+
+	windowStart := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	err := exporter.Export([]UsageRecord{
+		{
+			WindowStart:   windowStart,
+			WindowEnd:     windowStart.Add(time.Minute),
+			OperationHash: "1",
+			OperationName: "Hero",
+			OperationType: "query",
+			ClientName:    "web",
+			ClientVersion: "1.0.0",
+			RequestCount:  2,
+			TypeFields: []TypeFieldUsage{
+				{Path: []string{"hero"}, EnclosingTypeNames: []string{"Query"}, FieldName: "hero", FieldTypeName: "Character", Count: 2},
+			},
+		},
+		{
+			WindowStart:   windowStart,
+			WindowEnd:     windowStart.Add(time.Minute),
+			OperationHash: "2",
+			OperationType: "mutation",
+			RequestCount:  1,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"windowStart":"2023-01-01T12:00:00Z","windowEnd":"2023-01-01T12:01:00Z","operationHash":"1","operationName":"Hero","operationType":"query","clientName":"web","clientVersion":"1.0.0","requestCount":2,"typeFields":[{"path":["hero"],"enclosingTypeNames":["Query"],"fieldName":"hero","fieldTypeName":"Character","count":2}]}
+{"windowStart":"2023-01-01T12:00:00Z","windowEnd":"2023-01-01T12:01:00Z","operationHash":"2","operationType":"mutation","requestCount":1}
+`, out.String())
+}
+
+func TestExecutionEngineV2_UsageCollector(t *testing.T) {
+	exporter := NewInMemoryUsageExporter()
+	collector := NewUsageCollector(UsageCollectorConfiguration{
+		Exporter: exporter,
+	})
+
+	schema := starwarsSchema(t)
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetUsageCollector(collector)
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		{
+			ID: "starwars",
+			RootNodes: []plan.TypeField{
+				{
+					TypeName:   "Query",
+					FieldNames: []string{"hero"},
+				},
+			},
+			ChildNodes: []plan.TypeField{
+				{
+					TypeName:   "Character",
+					FieldNames: []string{"name"},
+				},
+			},
+			Factory: &graphql_datasource.Factory{
+				HTTPClient: testNetHttpClient(t, roundTripperTestCase{
+					expectedHost:     "example.com",
+					expectedPath:     "/",
+					expectedBody:     "",
+					sendResponseBody: `{"data":{"hero":{"name":"Luke Skywalker"}}}`,
+					sendStatusCode:   200,
+				}),
+			},
+			Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+				Fetch: graphql_datasource.FetchConfiguration{
+					URL:    "https://example.com/",
+					Method: "GET",
+				},
+				UpstreamSchema: string(schema.Document()),
+			}),
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(DefaultUsageClientNameHeader, "web")
+	header.Set(DefaultUsageClientVersionHeader, "1.0.0")
+
+	for i := 0; i < 2; i++ {
+		operation := loadStarWarsQuery(starwars.FileSimpleHeroQuery, nil)(t)
+		resultWriter := NewEngineResultWriter()
+		err = engine.Execute(context.Background(), &operation, &resultWriter, WithAdditionalHttpHeaders(header))
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"hero":{"name":"Luke Skywalker"}}}`, resultWriter.String())
+	}
+
+	require.NoError(t, collector.Flush())
+	records := exporter.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, "query", records[0].OperationType)
+	assert.Equal(t, "web", records[0].ClientName)
+	assert.Equal(t, "1.0.0", records[0].ClientVersion)
+	assert.Equal(t, 2, records[0].RequestCount)
+	assert.NotEmpty(t, records[0].OperationHash)
+
+	typeFields, err := json.Marshal(records[0].TypeFields)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"path":["hero"],"enclosingTypeNames":["Query"],"fieldName":"hero","fieldTypeName":"Character","dataSourceIds":["starwars"],"count":2},
+		{"path":["hero","name"],"enclosingTypeNames":["Character"],"fieldName":"name","fieldTypeName":"String","dataSourceIds":["starwars"],"count":2}
+	]`, string(typeFields))
+}
@@ -10,7 +10,7 @@ type ValidationResult struct {
 	Errors Errors
 }
 
-func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, err error) {
+func (r *Request) ValidateForSchema(schema *Schema, options ...astvalidation.Option) (result ValidationResult, err error) {
 	if schema == nil {
 		return ValidationResult{Valid: false, Errors: nil}, ErrNilSchema
 	}
@@ -30,7 +30,7 @@ func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, er
 		return operationValidationResultFromReport(report)
 	}
 
-	validator := astvalidation.DefaultOperationValidator()
+	validator := astvalidation.DefaultOperationValidator(options...)
 	validator.Validate(&r.document, &schema.document, &report)
 	result, err = operationValidationResultFromReport(report)
 	if err != nil {
@@ -40,6 +40,23 @@ func (r *Request) ValidateForSchema(schema *Schema) (result ValidationResult, er
 	return result, err
 }
 
+// ValidateOperationLimits validates the request against the enabled limits only.
+// Call it before Normalize, as normalization inlines fragments and multiplies their fields.
+func (r *Request) ValidateOperationLimits(schema *Schema, limits astvalidation.OperationLimits) (result ValidationResult, err error) {
+	if schema == nil {
+		return ValidationResult{Valid: false, Errors: nil}, ErrNilSchema
+	}
+
+	report := r.parseQueryOnce()
+	if report.HasErrors() {
+		return operationValidationResultFromReport(report)
+	}
+
+	validator := astvalidation.NewOperationLimitsValidator(limits)
+	validator.Validate(&r.document, &schema.document, &report)
+	return operationValidationResultFromReport(report)
+}
+
 // ValidateRestrictedFields validates a request by checking if `restrictedFields` contains blocked fields.
 //
 // Deprecated: This function can only handle blocked fields. Use `ValidateFieldRestrictions` if you
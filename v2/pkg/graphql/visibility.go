@@ -0,0 +1,317 @@
+package graphql
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+)
+
+const (
+	InaccessibleDirectiveName = "inaccessible"
+	TagDirectiveName          = "tag"
+	tagNameArgName            = "name"
+)
+
+// SchemaElementKind is the kind of schema element passed to a VisibilityFilter
+type SchemaElementKind string
+
+const (
+	SchemaElementKindType       SchemaElementKind = "type"
+	SchemaElementKindField      SchemaElementKind = "field"
+	SchemaElementKindArgument   SchemaElementKind = "argument"
+	SchemaElementKindEnumValue  SchemaElementKind = "enum value"
+	SchemaElementKindInputField SchemaElementKind = "input field"
+)
+
+// SchemaElement is a type, field, argument, enum value or input field of the schema
+type SchemaElement struct {
+	Kind SchemaElementKind
+	// Coordinate is the schema coordinate of the element, e.g. User, User.name, Query.user(id:), Role.ADMIN or UserInput.name
+	Coordinate string
+	// Directives are the names of the directives of the element, e.g. inaccessible
+	Directives []string
+	// Tags are the names of the @tag directives of the element
+	Tags []string
+}
+
+// HasDirective returns true if the element has a directive with the given name
+func (s SchemaElement) HasDirective(name string) bool {
+	for i := range s.Directives {
+		if s.Directives[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibilityFilter returns true if a schema element is hidden.
+// Hidden elements are neither part of introspection results nor known to the validation of operations,
+// and hidden enum values or objects of hidden types in responses are resolved to null.
+type VisibilityFilter func(element SchemaElement) (hidden bool)
+
+// InaccessibleVisibilityFilter hides all elements with the @inaccessible directive
+func InaccessibleVisibilityFilter() VisibilityFilter {
+	return func(element SchemaElement) bool {
+		return element.HasDirective(InaccessibleDirectiveName)
+	}
+}
+
+// TagVisibilityFilter hides all elements with a @tag directive with one of the given names
+func TagVisibilityFilter(hiddenTags ...string) VisibilityFilter {
+	return func(element SchemaElement) bool {
+		for i := range element.Tags {
+			for j := range hiddenTags {
+				if element.Tags[i] == hiddenTags[j] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// CoordinateVisibilityFilter hides all elements with one of the given schema coordinates
+func CoordinateVisibilityFilter(hiddenCoordinates ...string) VisibilityFilter {
+	return func(element SchemaElement) bool {
+		for i := range hiddenCoordinates {
+			if element.Coordinate == hiddenCoordinates[i] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CombineVisibilityFilters hides all elements which are hidden by at least one of the filters
+func CombineVisibilityFilters(filters ...VisibilityFilter) VisibilityFilter {
+	return func(element SchemaElement) bool {
+		for i := range filters {
+			if filters[i](element) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// VisibleSchema returns a copy of the schema without the elements hidden by the filter.
+// Fields, arguments and input fields of hidden types are hidden as well, and so are types
+// which have no visible fields, enum values or union members left.
+func (s *Schema) VisibleSchema(filter VisibilityFilter) (*Schema, error) {
+	document, report := astparser.ParseGraphqlDocumentBytes(s.rawSchema)
+	if report.HasErrors() {
+		return nil, report
+	}
+	astnormalization.NormalizeDefinition(&document, &report)
+	if report.HasErrors() {
+		return nil, report
+	}
+
+	visibility := schemaVisibility{
+		document:    &document,
+		filter:      filter,
+		hiddenTypes: map[string]struct{}{},
+	}
+	visibility.hideElements()
+
+	visibleSchemaBuffer := &bytes.Buffer{}
+	if err := astprinter.PrintIndent(&document, nil, []byte("  "), visibleSchemaBuffer); err != nil {
+		return nil, err
+	}
+
+	return createSchema(visibleSchemaBuffer.Bytes(), false)
+}
+
+type schemaVisibility struct {
+	document    *ast.Document
+	filter      VisibilityFilter
+	hiddenTypes map[string]struct{}
+}
+
+// hideElements removes the hidden elements from the document until no further element gets hidden,
+// as removing elements can leave types without any fields, values or members
+func (v *schemaVisibility) hideElements() {
+	for _, node := range v.document.RootNodes {
+		if v.isTypeDefinition(node) && v.isHidden(SchemaElementKindType, v.document.NodeNameString(node), v.document.NodeDirectives(node)) {
+			v.hiddenTypes[v.document.NodeNameString(node)] = struct{}{}
+		}
+	}
+
+	for {
+		hiddenTypes := len(v.hiddenTypes)
+		for _, node := range v.document.RootNodes {
+			if !v.isTypeDefinition(node) || v.isHiddenType(v.document.NodeNameString(node)) {
+				continue
+			}
+			if empty := v.hideTypeElements(node); empty {
+				v.hiddenTypes[v.document.NodeNameString(node)] = struct{}{}
+			}
+		}
+		if len(v.hiddenTypes) == hiddenTypes {
+			break
+		}
+	}
+
+	v.removeHiddenTypes()
+}
+
+func (v *schemaVisibility) isTypeDefinition(node ast.Node) bool {
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition,
+		ast.NodeKindEnumTypeDefinition, ast.NodeKindInputObjectTypeDefinition, ast.NodeKindScalarTypeDefinition:
+		return !strings.HasPrefix(v.document.NodeNameString(node), "__")
+	default:
+		return false
+	}
+}
+
+// hideTypeElements removes the hidden elements of a type and returns true if the type has no elements left
+func (v *schemaVisibility) hideTypeElements(node ast.Node) (empty bool) {
+	typeName := v.document.NodeNameString(node)
+
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		objectTypeDefinition := &v.document.ObjectTypeDefinitions[node.Ref]
+		objectTypeDefinition.FieldsDefinition.Refs = v.visibleFields(typeName, objectTypeDefinition.FieldsDefinition.Refs)
+		objectTypeDefinition.HasFieldDefinitions = len(objectTypeDefinition.FieldsDefinition.Refs) != 0
+		objectTypeDefinition.ImplementsInterfaces.Refs = v.visibleTypes(objectTypeDefinition.ImplementsInterfaces.Refs)
+		return !v.hasVisibleFields(objectTypeDefinition.FieldsDefinition.Refs)
+	case ast.NodeKindInterfaceTypeDefinition:
+		interfaceTypeDefinition := &v.document.InterfaceTypeDefinitions[node.Ref]
+		interfaceTypeDefinition.FieldsDefinition.Refs = v.visibleFields(typeName, interfaceTypeDefinition.FieldsDefinition.Refs)
+		interfaceTypeDefinition.HasFieldDefinitions = len(interfaceTypeDefinition.FieldsDefinition.Refs) != 0
+		interfaceTypeDefinition.ImplementsInterfaces.Refs = v.visibleTypes(interfaceTypeDefinition.ImplementsInterfaces.Refs)
+		return !v.hasVisibleFields(interfaceTypeDefinition.FieldsDefinition.Refs)
+	case ast.NodeKindUnionTypeDefinition:
+		unionTypeDefinition := &v.document.UnionTypeDefinitions[node.Ref]
+		unionTypeDefinition.UnionMemberTypes.Refs = v.visibleTypes(unionTypeDefinition.UnionMemberTypes.Refs)
+		unionTypeDefinition.HasUnionMemberTypes = len(unionTypeDefinition.UnionMemberTypes.Refs) != 0
+		return !unionTypeDefinition.HasUnionMemberTypes
+	case ast.NodeKindEnumTypeDefinition:
+		enumTypeDefinition := &v.document.EnumTypeDefinitions[node.Ref]
+		enumTypeDefinition.EnumValuesDefinition.Refs = v.visibleEnumValues(typeName, enumTypeDefinition.EnumValuesDefinition.Refs)
+		enumTypeDefinition.HasEnumValuesDefinition = len(enumTypeDefinition.EnumValuesDefinition.Refs) != 0
+		return !enumTypeDefinition.HasEnumValuesDefinition
+	case ast.NodeKindInputObjectTypeDefinition:
+		inputObjectTypeDefinition := &v.document.InputObjectTypeDefinitions[node.Ref]
+		inputObjectTypeDefinition.InputFieldsDefinition.Refs = v.visibleInputValues(SchemaElementKindInputField, typeName+".", "", inputObjectTypeDefinition.InputFieldsDefinition.Refs)
+		inputObjectTypeDefinition.HasInputFieldsDefinition = len(inputObjectTypeDefinition.InputFieldsDefinition.Refs) != 0
+		return !inputObjectTypeDefinition.HasInputFieldsDefinition
+	default:
+		return false
+	}
+}
+
+func (v *schemaVisibility) visibleFields(typeName string, refs []int) []int {
+	visible := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		fieldName := v.document.FieldDefinitionNameString(ref)
+		if v.isHiddenType(v.document.ResolveTypeNameString(v.document.FieldDefinitions[ref].Type)) ||
+			v.isHidden(SchemaElementKindField, typeName+"."+fieldName, v.document.FieldDefinitions[ref].Directives.Refs) {
+			continue
+		}
+
+		fieldDefinition := &v.document.FieldDefinitions[ref]
+		fieldDefinition.ArgumentsDefinition.Refs = v.visibleInputValues(SchemaElementKindArgument, typeName+"."+fieldName+"(", ":)", fieldDefinition.ArgumentsDefinition.Refs)
+		fieldDefinition.HasArgumentsDefinitions = len(fieldDefinition.ArgumentsDefinition.Refs) != 0
+		visible = append(visible, ref)
+	}
+	return visible
+}
+
+// hasVisibleFields returns true if there is a field besides the introspection fields added by the normalization, e.g. __typename
+func (v *schemaVisibility) hasVisibleFields(refs []int) bool {
+	for _, ref := range refs {
+		if !strings.HasPrefix(v.document.FieldDefinitionNameString(ref), "__") {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleInputValues filters arguments and input fields, their coordinates are the name between the coordinate prefix and suffix
+func (v *schemaVisibility) visibleInputValues(kind SchemaElementKind, coordinatePrefix, coordinateSuffix string, refs []int) []int {
+	visible := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		coordinate := coordinatePrefix + v.document.InputValueDefinitionNameString(ref) + coordinateSuffix
+		if v.isHiddenType(v.document.ResolveTypeNameString(v.document.InputValueDefinitions[ref].Type)) ||
+			v.isHidden(kind, coordinate, v.document.InputValueDefinitions[ref].Directives.Refs) {
+			continue
+		}
+		visible = append(visible, ref)
+	}
+	return visible
+}
+
+func (v *schemaVisibility) visibleEnumValues(typeName string, refs []int) []int {
+	visible := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		coordinate := typeName + "." + v.document.EnumValueDefinitionNameString(ref)
+		if v.isHidden(SchemaElementKindEnumValue, coordinate, v.document.EnumValueDefinitions[ref].Directives.Refs) {
+			continue
+		}
+		visible = append(visible, ref)
+	}
+	return visible
+}
+
+func (v *schemaVisibility) visibleTypes(refs []int) []int {
+	visible := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		if !v.isHiddenType(v.document.TypeNameString(ref)) {
+			visible = append(visible, ref)
+		}
+	}
+	return visible
+}
+
+// removeHiddenTypes removes the hidden types from the root nodes and the root operation types of the schema definition
+func (v *schemaVisibility) removeHiddenTypes() {
+	rootNodes := v.document.RootNodes[:0]
+	for _, node := range v.document.RootNodes {
+		if v.isTypeDefinition(node) && v.isHiddenType(v.document.NodeNameString(node)) {
+			continue
+		}
+		rootNodes = append(rootNodes, node)
+	}
+	v.document.RootNodes = rootNodes
+
+	for i := range v.document.SchemaDefinitions {
+		schemaDefinition := &v.document.SchemaDefinitions[i]
+		refs := schemaDefinition.RootOperationTypeDefinitions.Refs[:0]
+		for _, ref := range schemaDefinition.RootOperationTypeDefinitions.Refs {
+			if !v.isHiddenType(v.document.Input.ByteSliceString(v.document.RootOperationTypeDefinitions[ref].NamedType.Name)) {
+				refs = append(refs, ref)
+			}
+		}
+		schemaDefinition.RootOperationTypeDefinitions.Refs = refs
+	}
+}
+
+func (v *schemaVisibility) isHiddenType(typeName string) bool {
+	_, hidden := v.hiddenTypes[typeName]
+	return hidden
+}
+
+func (v *schemaVisibility) isHidden(kind SchemaElementKind, coordinate string, directiveRefs []int) bool {
+	element := SchemaElement{
+		Kind:       kind,
+		Coordinate: coordinate,
+		Directives: make([]string, 0, len(directiveRefs)),
+	}
+	for _, ref := range directiveRefs {
+		directiveName := v.document.DirectiveNameString(ref)
+		element.Directives = append(element.Directives, directiveName)
+		if directiveName != TagDirectiveName {
+			continue
+		}
+		if value, ok := v.document.DirectiveArgumentValueByName(ref, []byte(tagNameArgName)); ok && value.Kind == ast.ValueKindString {
+			element.Tags = append(element.Tags, v.document.ValueContentString(value))
+		}
+	}
+	return v.filter(element)
+}
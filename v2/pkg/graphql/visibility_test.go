@@ -0,0 +1,225 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/datasource/graphql_datasource"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+const visibilityTestSchema = `
+directive @inaccessible on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+directive @tag(name: String!) repeatable on FIELD_DEFINITION | OBJECT | INTERFACE | UNION | ARGUMENT_DEFINITION | SCALAR | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+
+type Query {
+	user(id: ID!, debug: Boolean @tag(name: "internal")): User
+	audit: AuditLog
+	search(filter: UserFilter): [SearchResult!]!
+	node(id: ID!): Node
+}
+
+type User implements Node {
+	id: ID!
+	name: String!
+	secret: String! @inaccessible
+	role: Role!
+}
+
+interface Node {
+	id: ID!
+}
+
+type AuditLog @inaccessible {
+	entries: [String!]!
+}
+
+union SearchResult = User | AuditLog
+
+enum Role {
+	ADMIN
+	SUPPORT @tag(name: "internal")
+}
+
+input UserFilter {
+	name: String
+	internalFlag: Boolean @inaccessible
+}
+`
+
+func TestSchema_VisibleSchema(t *testing.T) {
+	schema, err := NewSchemaFromString(visibilityTestSchema)
+	require.NoError(t, err)
+
+	t.Run("directives", func(t *testing.T) {
+		visibleSchema, err := schema.VisibleSchema(CombineVisibilityFilters(InaccessibleVisibilityFilter(), TagVisibilityFilter("internal")))
+		require.NoError(t, err)
+
+		assert.True(t, visibleSchema.HasQueryType())
+		assert.True(t, hasTypeField(visibleSchema, "User", "name"))
+		assert.False(t, hasTypeField(visibleSchema, "User", "secret"))
+		assert.False(t, hasTypeField(visibleSchema, "Query", "audit"))
+		assert.Equal(t, []string{"id"}, fieldArgumentNames(visibleSchema, "Query", "user"))
+		assert.NotContains(t, string(visibleSchema.Document()), "AuditLog")
+		assert.NotContains(t, string(visibleSchema.Document()), "SUPPORT")
+		assert.NotContains(t, string(visibleSchema.Document()), "internalFlag")
+		assert.Contains(t, string(visibleSchema.Document()), "union SearchResult = User")
+
+		assert.True(t, hasTypeField(schema, "User", "secret"), "the original schema must not be changed")
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		var coordinates []string
+		visibleSchema, err := schema.VisibleSchema(func(element SchemaElement) bool {
+			coordinates = append(coordinates, element.Coordinate)
+			return CoordinateVisibilityFilter("Node", "User.role", "Query.user(debug:)")(element)
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, coordinates, "Query.user(debug:)")
+		assert.Contains(t, coordinates, "Role.SUPPORT")
+		assert.Contains(t, coordinates, "UserFilter.internalFlag")
+		assert.False(t, hasTypeField(visibleSchema, "User", "role"))
+		assert.True(t, hasTypeField(visibleSchema, "User", "secret"))
+		assert.Equal(t, []string{"id"}, fieldArgumentNames(visibleSchema, "Query", "user"))
+		assert.NotContains(t, string(visibleSchema.Document()), "Node")
+	})
+
+	t.Run("types without visible fields are hidden", func(t *testing.T) {
+		visibleSchema, err := schema.VisibleSchema(CoordinateVisibilityFilter("AuditLog.entries"))
+		require.NoError(t, err)
+
+		assert.False(t, hasTypeField(visibleSchema, "Query", "audit"))
+		assert.Contains(t, string(visibleSchema.Document()), "union SearchResult = User")
+	})
+}
+
+func TestExecutionEngineV2_VisibilityFilter(t *testing.T) {
+	schema, err := NewSchemaFromString(visibilityTestSchema)
+	require.NoError(t, err)
+
+	dataSource := func(fieldName, expectedBody, responseBody string) plan.DataSourceConfiguration {
+		return plan.DataSourceConfiguration{
+			RootNodes: []plan.TypeField{
+				{TypeName: "Query", FieldNames: []string{fieldName}},
+			},
+			ChildNodes: []plan.TypeField{
+				{TypeName: "User", FieldNames: []string{"id", "name", "role"}},
+				{TypeName: "AuditLog", FieldNames: []string{"entries"}},
+				{TypeName: "Node", FieldNames: []string{"id"}},
+			},
+			Factory: &graphql_datasource.Factory{
+				HTTPClient: testNetHttpClient(t, roundTripperTestCase{
+					expectedHost:     "example.com",
+					expectedPath:     "/" + fieldName,
+					expectedBody:     expectedBody,
+					sendResponseBody: responseBody,
+					sendStatusCode:   200,
+				}),
+			},
+			Custom: graphql_datasource.ConfigJson(graphql_datasource.Configuration{
+				Fetch: graphql_datasource.FetchConfiguration{
+					URL:    "https://example.com/" + fieldName,
+					Method: "POST",
+				},
+				UpstreamSchema: string(schema.Document()),
+			}),
+		}
+	}
+
+	engineConf := NewEngineV2Configuration(schema)
+	engineConf.SetVisibilityFilter(CombineVisibilityFilters(InaccessibleVisibilityFilter(), TagVisibilityFilter("internal")))
+	engineConf.SetDataSources([]plan.DataSourceConfiguration{
+		dataSource("user", "", `{"data":{"user":{"name":"Jane","role":"SUPPORT"}}}`),
+		dataSource("search", "", `{"data":{"search":[{"__typename":"User","id":"1"},{"__typename":"AuditLog"}]}}`),
+		// __typename is fetched for fields of interfaces and unions to validate the possible types
+		dataSource("node", `{"query":"query($a: ID!){node(id: $a){id __typename}}","variables":{"a":"1"}}`, `{"data":{"node":{"__typename":"User","id":"1"}}}`),
+	})
+	engineConf.SetFieldConfigurations([]plan.FieldConfiguration{
+		{
+			TypeName:  "Query",
+			FieldName: "user",
+			Arguments: plan.ArgumentsConfigurations{
+				{Name: "id", SourceType: plan.FieldArgumentSource},
+			},
+		},
+		{
+			TypeName:  "Query",
+			FieldName: "node",
+			Arguments: plan.ArgumentsConfigurations{
+				{Name: "id", SourceType: plan.FieldArgumentSource},
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := NewExecutionEngineV2(ctx, abstractlogger.Noop{}, engineConf)
+	require.NoError(t, err)
+
+	execute := func(query string) (string, error) {
+		resultWriter := NewEngineResultWriter()
+		err := engine.Execute(context.Background(), &Request{Query: query}, &resultWriter)
+		return resultWriter.String(), err
+	}
+
+	t.Run("hidden fields are unknown", func(t *testing.T) {
+		_, err := execute(`{ user(id: "1") { name secret } }`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field: secret not defined on type: User")
+	})
+
+	t.Run("hidden elements are not introspected", func(t *testing.T) {
+		response, err := execute(`{ user: __type(name: "User") { fields { name } } audit: __type(name: "AuditLog") { name } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"user":{"fields":[{"name":"id"},{"name":"name"},{"name":"role"}]},"audit":null}}`, response)
+	})
+
+	t.Run("hidden enum values are resolved to null", func(t *testing.T) {
+		response, err := execute(`{ user(id: "1") { name role } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"Enum \"Role\" cannot represent value: \"SUPPORT\"","path":["user","role"]}],"data":{"user":null}}`, response)
+	})
+
+	t.Run("objects of hidden union members are resolved to null", func(t *testing.T) {
+		response, err := execute(`{ search { __typename ... on User { id } } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"Runtime Object type \"AuditLog\" is not a possible type for \"SearchResult\".","path":["search",1]}],"data":null}`, response)
+	})
+
+	t.Run("objects of hidden union members are resolved to null without selecting __typename", func(t *testing.T) {
+		response, err := execute(`{ search { ... on User { id } } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"errors":[{"message":"Runtime Object type \"AuditLog\" is not a possible type for \"SearchResult\".","path":["search",1]}],"data":null}`, response)
+	})
+
+	t.Run("__typename fetched for the validation is not part of the response", func(t *testing.T) {
+		response, err := execute(`{ node(id: "1") { id } }`)
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"node":{"id":"1"}}}`, response)
+	})
+}
+
+func hasTypeField(schema *Schema, typeName, fieldName string) bool {
+	node, ok := schema.document.NodeByNameStr(typeName)
+	if !ok {
+		return false
+	}
+	_, ok = schema.document.NodeFieldDefinitionByName(node, []byte(fieldName))
+	return ok
+}
+
+func fieldArgumentNames(schema *Schema, typeName, fieldName string) (names []string) {
+	node, ok := schema.document.NodeByNameStr(typeName)
+	if !ok {
+		return nil
+	}
+	for _, ref := range schema.document.NodeFieldDefinitionArgumentsDefinitions(node, []byte(fieldName)) {
+		names = append(names, schema.document.InputValueDefinitionNameString(ref))
+	}
+	return names
+}
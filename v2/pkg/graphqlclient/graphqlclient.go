@@ -0,0 +1,157 @@
+// Package graphqlclient executes the operations of the typed Go clients generated by codegen.ClientGen,
+// either over HTTP or directly against an in-process graphql.ExecutionEngineV2.
+package graphqlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
+)
+
+// Request is a GraphQL request, Variables are encoded as JSON
+type Request struct {
+	Query         string      `json:"query"`
+	OperationName string      `json:"operationName,omitempty"`
+	Variables     interface{} `json:"variables,omitempty"`
+}
+
+// Executor executes a request and decodes the data of the response into data.
+// If the response contains errors, the data is decoded and the errors are returned as Errors.
+type Executor interface {
+	Execute(ctx context.Context, request Request, data interface{}) error
+}
+
+// Error is an error of a GraphQL response
+type Error struct {
+	Message    string                   `json:"message"`
+	Locations  []graphqlerrors.Location `json:"locations,omitempty"`
+	Path       []interface{}            `json:"path,omitempty"`
+	Extensions json.RawMessage          `json:"extensions,omitempty"`
+}
+
+// Errors are the errors of a GraphQL response
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for i := range e {
+		messages = append(messages, e[i].Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors Errors          `json:"errors"`
+}
+
+func decodeResponse(body []byte, data interface{}) error {
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Data) != 0 && !bytes.Equal(resp.Data, []byte("null")) {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) != 0 {
+		return resp.Errors
+	}
+	return nil
+}
+
+// HTTPExecutor executes requests with POST requests to the URL of a GraphQL server
+type HTTPExecutor struct {
+	Client *http.Client
+	URL    string
+	// Header is added to every request, e.g. for authorization
+	Header http.Header
+}
+
+func NewHTTPExecutor(client *http.Client, url string) *HTTPExecutor {
+	return &HTTPExecutor{
+		Client: client,
+		URL:    url,
+	}
+}
+
+func (h *HTTPExecutor) Execute(ctx context.Context, request Request, data interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range h.Header {
+		for _, value := range values {
+			httpRequest.Header.Add(key, value)
+		}
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json")
+
+	httpResponse, err := h.Client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+
+	err = decodeResponse(responseBody, data)
+	if httpResponse.StatusCode != http.StatusOK {
+		if _, ok := err.(Errors); ok {
+			return err
+		}
+		return fmt.Errorf("unexpected status code %d: %s", httpResponse.StatusCode, responseBody)
+	}
+	return err
+}
+
+// EngineExecutor executes requests directly with an in-process execution engine
+type EngineExecutor struct {
+	Engine *graphql.ExecutionEngineV2
+	// Options are applied to every execution, e.g. graphql.WithAdditionalHttpHeaders
+	Options []graphql.ExecutionOptionsV2
+}
+
+func NewEngineExecutor(engine *graphql.ExecutionEngineV2, options ...graphql.ExecutionOptionsV2) *EngineExecutor {
+	return &EngineExecutor{
+		Engine:  engine,
+		Options: options,
+	}
+}
+
+func (e *EngineExecutor) Execute(ctx context.Context, request Request, data interface{}) error {
+	operation := graphql.Request{
+		OperationName: request.OperationName,
+		Query:         request.Query,
+	}
+	if request.Variables != nil {
+		variables, err := json.Marshal(request.Variables)
+		if err != nil {
+			return err
+		}
+		operation.Variables = variables
+	}
+
+	resultWriter := graphql.NewEngineResultWriter()
+	if err := e.Engine.Execute(ctx, &operation, &resultWriter, e.Options...); err != nil {
+		return err
+	}
+	return decodeResponse(resultWriter.Bytes(), data)
+}
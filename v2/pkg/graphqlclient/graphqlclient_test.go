@@ -0,0 +1,128 @@
+package graphqlclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+type heroData struct {
+	Hero *struct {
+		Name string `json:"name"`
+	} `json:"hero"`
+}
+
+func TestHTTPExecutor_Execute(t *testing.T) {
+	newServer := func(t *testing.T, statusCode int, response string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.JSONEq(t, `{"query":"query Hero($episode: Episode){hero(episode: $episode){name}}","operationName":"Hero","variables":{"episode":"JEDI"}}`, string(body))
+
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(response))
+		}))
+	}
+
+	execute := func(t *testing.T, server *httptest.Server) (heroData, error) {
+		executor := NewHTTPExecutor(server.Client(), server.URL)
+		executor.Header = http.Header{"Authorization": []string{"Bearer token"}}
+
+		var data heroData
+		err := executor.Execute(context.Background(), Request{
+			Query:         "query Hero($episode: Episode){hero(episode: $episode){name}}",
+			OperationName: "Hero",
+			Variables:     map[string]string{"episode": "JEDI"},
+		}, &data)
+		return data, err
+	}
+
+	t.Run("data", func(t *testing.T) {
+		server := newServer(t, http.StatusOK, `{"data":{"hero":{"name":"Luke Skywalker"}}}`)
+		defer server.Close()
+
+		data, err := execute(t, server)
+		require.NoError(t, err)
+		require.NotNil(t, data.Hero)
+		assert.Equal(t, "Luke Skywalker", data.Hero.Name)
+	})
+
+	t.Run("data with errors", func(t *testing.T) {
+		server := newServer(t, http.StatusOK, `{"data":{"hero":null},"errors":[{"message":"hero not found","path":["hero"]},{"message":"unauthorized"}]}`)
+		defer server.Close()
+
+		data, err := execute(t, server)
+		var errs Errors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, "hero not found, unauthorized", err.Error())
+		assert.Equal(t, []interface{}{"hero"}, errs[0].Path)
+		assert.Nil(t, data.Hero)
+	})
+
+	t.Run("errors with unexpected status code", func(t *testing.T) {
+		server := newServer(t, http.StatusBadRequest, `{"errors":[{"message":"invalid operation"}]}`)
+		defer server.Close()
+
+		_, err := execute(t, server)
+		assert.Equal(t, Errors{{Message: "invalid operation"}}, err)
+	})
+
+	t.Run("unexpected status code", func(t *testing.T) {
+		server := newServer(t, http.StatusInternalServerError, `internal server error`)
+		defer server.Close()
+
+		_, err := execute(t, server)
+		assert.EqualError(t, err, "unexpected status code 500: internal server error")
+	})
+}
+
+func TestEngineExecutor_Execute(t *testing.T) {
+	schema, err := graphql.NewSchemaFromString(`
+		schema { query: Query }
+		type Query { hero: Character }
+		type Character { name: String! }
+	`)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine, err := graphql.NewExecutionEngineV2(ctx, abstractlogger.Noop{}, graphql.NewEngineV2Configuration(schema))
+	require.NoError(t, err)
+
+	executor := NewEngineExecutor(engine)
+
+	t.Run("data", func(t *testing.T) {
+		var data struct {
+			Type struct {
+				Name string `json:"name"`
+			} `json:"__type"`
+		}
+		err := executor.Execute(context.Background(), Request{
+			Query:         `query Type($name: String!){__type(name: $name){name}}`,
+			OperationName: "Type",
+			Variables:     map[string]string{"name": "Character"},
+		}, &data)
+		require.NoError(t, err)
+		assert.Equal(t, "Character", data.Type.Name)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		var data json.RawMessage
+		err := executor.Execute(context.Background(), Request{
+			Query: `{ villain { name } }`,
+		}, &data)
+		assert.Error(t, err)
+	})
+}
@@ -13,9 +13,15 @@ import (
 
 type options struct {
 	overrides map[string]JsonSchema
+	scalars   ScalarJsonSchemas
 	path      []string
 }
 
+// ScalarJsonSchemas provides the JSON schema of custom scalars, e.g. scalar.ScalarRegistry.
+type ScalarJsonSchemas interface {
+	ScalarJsonSchema(name string, nonNull bool) (schema JsonSchema, ok bool)
+}
+
 type Option func(opts *options)
 
 func WithOverrides(overrides map[string]JsonSchema) Option {
@@ -24,6 +30,13 @@ func WithOverrides(overrides map[string]JsonSchema) Option {
 	}
 }
 
+// WithScalars uses the JSON schemas of scalars for custom scalars instead of accepting any value.
+func WithScalars(scalars ScalarJsonSchemas) Option {
+	return func(opts *options) {
+		opts.scalars = scalars
+	}
+}
+
 func WithPath(path []string) Option {
 	return func(opts *options) {
 		opts.path = path
@@ -46,6 +59,7 @@ func FromTypeRef(operation, definition *ast.Document, typeRef int, opts ...Optio
 			overrides: map[string]JsonSchema{},
 		}
 	}
+	resolver.scalars = appliedOptions.scalars
 
 	jsonSchema := resolver.fromTypeRef(operation, definition, typeRef)
 	return resolveJsonSchemaPath(jsonSchema, appliedOptions.path)
@@ -68,6 +82,7 @@ func resolveJsonSchemaPath(jsonSchema JsonSchema, path []string) JsonSchema {
 
 type fromTypeRefResolver struct {
 	overrides map[string]JsonSchema
+	scalars   ScalarJsonSchemas
 	defs      *map[string]JsonSchema
 }
 
@@ -103,30 +118,8 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 		if schema, ok := r.overrides[name]; ok {
 			return schema
 		}
-		typeDefinitionNode, ok := definition.Index.FirstNodeByNameStr(name)
-		if !ok {
-			return NewAny()
-		}
-		if typeDefinitionNode.Kind == ast.NodeKindEnumTypeDefinition {
-			return NewString(nonNull)
-		}
-		if typeDefinitionNode.Kind == ast.NodeKindScalarTypeDefinition {
-			switch name {
-			case "Boolean":
-				return NewBoolean(nonNull)
-			case "String":
-				return NewString(nonNull)
-			case "ID":
-				return NewID(nonNull)
-			case "Int":
-				return NewInteger(nonNull)
-			case "Float":
-				return NewNumber(nonNull)
-			case "_Any":
-				return NewObjectAny(nonNull)
-			default:
-				return NewAny()
-			}
+		if schema, ok := r.leafSchema(definition, name, nonNull); ok {
+			return schema
 		}
 		object := NewObject(nonNull)
 		isRootObject := false
@@ -144,6 +137,14 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 		if node, ok := definition.Index.FirstNodeByNameStr(name); ok {
 			switch node.Kind {
 			case ast.NodeKindInputObjectTypeDefinition:
+				isOneOf := definition.InputObjectTypeDefinitionIsOneOf(node.Ref)
+				if isOneOf {
+					object.MinProperties = intPtr(1)
+					object.MaxProperties = intPtr(1)
+					if !nonNull {
+						object.OneOf = append(object.OneOf, NewNullAlternative())
+					}
+				}
 				for _, ref := range definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
 					fieldName := definition.Input.ByteSliceString(definition.InputValueDefinitions[ref].Name)
 					fieldType := definition.InputValueDefinitions[ref].Type
@@ -152,6 +153,9 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 					if definition.TypeIsNonNull(fieldType) {
 						object.Required = append(object.Required, fieldName)
 					}
+					if isOneOf {
+						object.OneOf = append(object.OneOf, NewNonNullPropertyAlternative(fieldName))
+					}
 				}
 			case ast.NodeKindObjectTypeDefinition:
 				for _, ref := range definition.ObjectTypeDefinitions[node.Ref].FieldsDefinition.Refs {
@@ -174,6 +178,41 @@ func (r *fromTypeRefResolver) fromTypeRef(operation, definition *ast.Document, t
 	return NewObject(nonNull)
 }
 
+// leafSchema returns the schema of an enum or a scalar, ok is false for composite types
+func (r *fromTypeRefResolver) leafSchema(definition *ast.Document, name string, nonNull bool) (schema JsonSchema, ok bool) {
+	typeDefinitionNode, ok := definition.Index.FirstNodeByNameStr(name)
+	if !ok {
+		return NewAny(), true
+	}
+	switch typeDefinitionNode.Kind {
+	case ast.NodeKindEnumTypeDefinition:
+		return NewString(nonNull), true
+	case ast.NodeKindScalarTypeDefinition:
+		switch name {
+		case "Boolean":
+			return NewBoolean(nonNull), true
+		case "String":
+			return NewString(nonNull), true
+		case "ID":
+			return NewID(nonNull), true
+		case "Int":
+			return NewInteger(nonNull), true
+		case "Float":
+			return NewNumber(nonNull), true
+		case "_Any":
+			return NewObjectAny(nonNull), true
+		default:
+			if r.scalars != nil {
+				if schema, ok := r.scalars.ScalarJsonSchema(name, nonNull); ok {
+					return schema, true
+				}
+			}
+			return NewAny(), true
+		}
+	}
+	return nil, false
+}
+
 type Validator struct {
 	schema *jsonschema.Schema
 }
@@ -260,6 +299,9 @@ const (
 	AnyKind
 	IDKind
 	RefKind
+	ConstKind
+	AbstractTypeKind
+	NullKind
 )
 
 func maybeAppendNull(nonNull bool, types ...string) []string {
@@ -284,7 +326,9 @@ func (a Any) Kind() Kind {
 }
 
 type String struct {
-	Type []string `json:"type"`
+	Type    []string `json:"type"`
+	Format  string   `json:"format,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
 }
 
 func (String) Kind() Kind {
@@ -372,6 +416,9 @@ type Object struct {
 	Properties           map[string]JsonSchema `json:"properties,omitempty"`
 	Required             []string              `json:"required,omitempty"`
 	AdditionalProperties bool                  `json:"additionalProperties"`
+	MinProperties        *int                  `json:"minProperties,omitempty"`
+	MaxProperties        *int                  `json:"maxProperties,omitempty"`
+	OneOf                []Alternative         `json:"oneOf,omitempty"`
 	Defs                 map[string]JsonSchema `json:"$defs,omitempty"`
 }
 
@@ -395,6 +442,61 @@ func NewObjectAny(nonNull bool) Object {
 	}
 }
 
+// Alternative is one of the schemas of a @oneOf input object. Exactly one alternative matches a valid value.
+type Alternative struct {
+	Type       []string           `json:"type"`
+	Required   []string           `json:"required,omitempty"`
+	Properties map[string]NotNull `json:"properties,omitempty"`
+}
+
+// NewNullAlternative matches the null value of a nullable @oneOf input object.
+func NewNullAlternative() Alternative {
+	return Alternative{
+		Type: []string{"null"},
+	}
+}
+
+// NewNonNullPropertyAlternative matches objects which set the property to a non-null value.
+func NewNonNullPropertyAlternative(propertyName string) Alternative {
+	return Alternative{
+		Type:     []string{"object"},
+		Required: []string{propertyName},
+		Properties: map[string]NotNull{
+			propertyName: NewNotNull(),
+		},
+	}
+}
+
+type Null struct {
+	Type []string `json:"type"`
+}
+
+func (Null) Kind() Kind {
+	return NullKind
+}
+
+func NewNull() Null {
+	return Null{
+		Type: []string{"null"},
+	}
+}
+
+type NotNull struct {
+	Not Null `json:"not"`
+}
+
+func NewNotNull() NotNull {
+	return NotNull{
+		Not: Null{
+			Type: []string{"null"},
+		},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
 type Array struct {
 	Type     []string              `json:"type"`
 	Items    JsonSchema            `json:"items"`
@@ -412,3 +514,31 @@ func NewArray(itemSchema JsonSchema, nonNull bool) Array {
 		Items: itemSchema,
 	}
 }
+
+// Const matches a single string, it's the schema of __typename in responses
+type Const struct {
+	Type  []string `json:"type"`
+	Const string   `json:"const"`
+}
+
+func (Const) Kind() Kind {
+	return ConstKind
+}
+
+func NewConst(value string) Const {
+	return Const{
+		Type:  []string{"string"},
+		Const: value,
+	}
+}
+
+// AbstractType is the schema of an interface or union in responses, it has an alternative per possible type.
+// Exactly one alternative matches with OneOf, it's used when the alternatives are distinguished by __typename.
+type AbstractType struct {
+	OneOf []JsonSchema `json:"oneOf,omitempty"`
+	AnyOf []JsonSchema `json:"anyOf,omitempty"`
+}
+
+func (AbstractType) Kind() Kind {
+	return AbstractTypeKind
+}
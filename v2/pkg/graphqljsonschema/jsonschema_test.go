@@ -269,6 +269,37 @@ func TestJsonSchema(t *testing.T) {
 		},
 		WithPath([]string{"pet", "name"}),
 	))
+	t.Run("oneOf input object", runTest(
+		`scalar String scalar Int input PetBy @oneOf { name: String id: Int }`,
+		`query ($input: PetBy!){}`,
+		`{"type":["object"],"properties":{"name":{"type":["string","null"]},"id":{"type":["integer","null"]}},"additionalProperties":false,"minProperties":1,"maxProperties":1,
+			"oneOf":[{"type":["object"],"required":["name"],"properties":{"name":{"not":{"type":["null"]}}}},{"type":["object"],"required":["id"],"properties":{"id":{"not":{"type":["null"]}}}}]}`,
+		[]string{
+			`{"name":"Doggie"}`,
+			`{"id":1}`,
+		},
+		[]string{
+			`{}`,
+			`null`,
+			`{"name":null}`,
+			`{"name":"Doggie","id":1}`,
+			`{"name":"Doggie","id":null}`,
+		},
+	))
+	t.Run("nullable oneOf input object", runTest(
+		`scalar String scalar Int input PetBy @oneOf { name: String id: Int }`,
+		`query ($input: PetBy){}`,
+		`{"type":["object","null"],"properties":{"name":{"type":["string","null"]},"id":{"type":["integer","null"]}},"additionalProperties":false,"minProperties":1,"maxProperties":1,
+			"oneOf":[{"type":["null"]},{"type":["object"],"required":["name"],"properties":{"name":{"not":{"type":["null"]}}}},{"type":["object"],"required":["id"],"properties":{"id":{"not":{"type":["null"]}}}}]}`,
+		[]string{
+			`{"name":"Doggie"}`,
+			`null`,
+		},
+		[]string{
+			`{}`,
+			`{"id":null}`,
+		},
+	))
 	t.Run("not defined scalar", runTest(
 		`input Container { name: MyScalar }`,
 		`query ($input: Container){}`,
@@ -276,6 +307,31 @@ func TestJsonSchema(t *testing.T) {
 		[]string{},
 		[]string{},
 	))
+	t.Run("custom scalar with json schema", runTest(
+		`scalar Decimal input Container { amount: Decimal! price: Decimal }`,
+		`query ($input: Container){}`,
+		`{"type":["object","null"],"properties":{"amount":{"type":["string"],"pattern":"^[0-9]+$"},"price":{"type":["string","null"],"pattern":"^[0-9]+$"}},"required":["amount"],"additionalProperties":false}`,
+		[]string{
+			`{"amount":"12"}`,
+			`{"amount":"12","price":null}`,
+		},
+		[]string{
+			`{"amount":12}`,
+			`{"amount":"1.5"}`,
+		},
+		WithScalars(decimalScalar{}),
+	))
+}
+
+type decimalScalar struct{}
+
+func (decimalScalar) ScalarJsonSchema(name string, nonNull bool) (JsonSchema, bool) {
+	if name != "Decimal" {
+		return nil, false
+	}
+	schema := NewString(nonNull)
+	schema.Pattern = "^[0-9]+$"
+	return schema, true
 }
 
 const complexRecursiveSchema = `
@@ -0,0 +1,336 @@
+package graphqljsonschema
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// OperationJsonSchema contains the JSON schemas of the contract of an operation
+type OperationJsonSchema struct {
+	// Variables is the schema of the variables object
+	Variables JsonSchema
+	// Response is the schema of the response with data, errors and extensions
+	Response JsonSchema
+}
+
+// FromOperation creates the JSON schemas of the variables and of the response of an operation,
+// operationName may be empty if the document contains a single operation.
+func FromOperation(operation, definition *ast.Document, operationName string, opts ...Option) (*OperationJsonSchema, error) {
+	variables, err := VariablesFromOperation(operation, definition, operationName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ResponseFromOperation(operation, definition, operationName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &OperationJsonSchema{
+		Variables: variables,
+		Response:  response,
+	}, nil
+}
+
+// VariablesFromOperation creates the JSON schema of the variables object of an operation.
+// Non-null variables without default value are required, input objects are shared definitions in $defs.
+func VariablesFromOperation(operation, definition *ast.Document, operationName string, opts ...Option) (JsonSchema, error) {
+	operationRef, err := operationDefinitionRef(operation, operationName)
+	if err != nil {
+		return nil, err
+	}
+	resolver := newFromTypeRefResolver(opts)
+
+	object := NewObject(true)
+	object.Defs = make(map[string]JsonSchema)
+	resolver.defs = &object.Defs
+	for _, ref := range operation.OperationDefinitions[operationRef].VariableDefinitions.Refs {
+		name := operation.VariableDefinitionNameString(ref)
+		typeRef := operation.VariableDefinitions[ref].Type
+		object.Properties[name] = resolver.fromTypeRef(operation, definition, typeRef)
+		if operation.TypeIsNonNull(typeRef) && !operation.VariableDefinitionHasDefaultValue(ref) {
+			object.Required = append(object.Required, name)
+		}
+	}
+	return object, nil
+}
+
+// ResponseFromOperation creates the JSON schema of the response of an operation.
+//
+// The properties of objects are the response keys of the selected fields, fields which are only selected with
+// @skip or @include are optional. Interfaces and unions have an alternative per possible type which contains the
+// fields selected for the type, the alternatives are exclusive with oneOf if __typename is selected for every type.
+// Data is null if a non-null root field fails, errors and extensions are optional.
+func ResponseFromOperation(operation, definition *ast.Document, operationName string, opts ...Option) (JsonSchema, error) {
+	operationRef, err := operationDefinitionRef(operation, operationName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rootTypeName ast.ByteSlice
+	switch operation.OperationDefinitions[operationRef].OperationType {
+	case ast.OperationTypeMutation:
+		rootTypeName = definition.Index.MutationTypeName
+	case ast.OperationTypeSubscription:
+		rootTypeName = definition.Index.SubscriptionTypeName
+	default:
+		rootTypeName = definition.Index.QueryTypeName
+	}
+	if len(rootTypeName) == 0 {
+		return nil, fmt.Errorf("schema has no root type for operation %s", operationName)
+	}
+
+	r := &responseResolver{
+		fromTypeRefResolver: newFromTypeRefResolver(opts),
+		operation:           operation,
+		definition:          definition,
+	}
+	data, err := r.selectionSetSchema(string(rootTypeName), []int{operation.OperationDefinitions[operationRef].SelectionSet}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	graphqlError := NewObject(true)
+	graphqlError.Properties["message"] = NewString(true)
+	graphqlError.Properties["locations"] = NewArray(NewAny(), true)
+	graphqlError.Properties["path"] = NewArray(NewID(true), true)
+	graphqlError.Properties["extensions"] = NewObjectAny(true)
+	graphqlError.Required = []string{"message"}
+
+	response := NewObject(true)
+	response.Properties["data"] = data
+	response.Properties["errors"] = NewArray(graphqlError, true)
+	response.Properties["extensions"] = NewObjectAny(true)
+	return response, nil
+}
+
+func newFromTypeRefResolver(opts []Option) *fromTypeRefResolver {
+	appliedOptions := &options{}
+	for _, opt := range opts {
+		opt(appliedOptions)
+	}
+	resolver := &fromTypeRefResolver{
+		overrides: appliedOptions.overrides,
+		scalars:   appliedOptions.scalars,
+	}
+	if resolver.overrides == nil {
+		resolver.overrides = map[string]JsonSchema{}
+	}
+	return resolver
+}
+
+func operationDefinitionRef(operation *ast.Document, operationName string) (int, error) {
+	ref := ast.InvalidRef
+	for i := range operation.OperationDefinitions {
+		if operationName != "" && operation.OperationDefinitionNameString(i) != operationName {
+			continue
+		}
+		if ref != ast.InvalidRef {
+			return ast.InvalidRef, fmt.Errorf("operation name is required for documents with multiple operations")
+		}
+		ref = i
+	}
+	if ref == ast.InvalidRef {
+		return ast.InvalidRef, fmt.Errorf("operation %s not found", operationName)
+	}
+	return ref, nil
+}
+
+type responseResolver struct {
+	*fromTypeRefResolver
+	operation, definition *ast.Document
+}
+
+// collectedField is a response key of a selection set with all fields selected for it
+type collectedField struct {
+	responseKey string
+	fieldName   string
+	fieldRefs   []int
+	// conditional is true if all selections of the field have @skip or @include
+	conditional bool
+}
+
+// typeSchema returns the schema of the value of a field of the type typeRef with the selection sets of the field
+func (r *responseResolver) typeSchema(typeRef int, selectionSets []int) (JsonSchema, error) {
+	nonNull := r.definition.TypeIsNonNull(typeRef)
+	if nonNull {
+		typeRef = r.definition.Types[typeRef].OfType
+	}
+
+	switch r.definition.Types[typeRef].TypeKind {
+	case ast.TypeKindList:
+		items, err := r.typeSchema(r.definition.Types[typeRef].OfType, selectionSets)
+		if err != nil {
+			return nil, err
+		}
+		return NewArray(items, nonNull), nil
+	case ast.TypeKindNamed:
+		name := r.definition.ResolveTypeNameString(typeRef)
+		if schema, ok := r.overrides[name]; ok {
+			return schema, nil
+		}
+		if schema, ok := r.leafSchema(r.definition, name, nonNull); ok {
+			return schema, nil
+		}
+		return r.selectionSetSchema(name, selectionSets, nonNull)
+	}
+	return nil, fmt.Errorf("unexpected type kind %s", r.definition.Types[typeRef].TypeKind)
+}
+
+// selectionSetSchema returns the schema of the selection sets of an object type, an interface or a union
+func (r *responseResolver) selectionSetSchema(typeName string, selectionSets []int, nonNull bool) (JsonSchema, error) {
+	node, ok := r.definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", typeName)
+	}
+
+	var possibleTypes []string
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		object, _, err := r.objectSchema(node, typeName, selectionSets, nonNull)
+		return object, err
+	case ast.NodeKindInterfaceTypeDefinition:
+		possibleTypes, _ = r.definition.InterfaceTypeDefinitionImplementedByObjectWithNames(node.Ref)
+	case ast.NodeKindUnionTypeDefinition:
+		possibleTypes, _ = r.definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+	default:
+		return nil, fmt.Errorf("type %s has no selection set", typeName)
+	}
+
+	alternatives := make([]JsonSchema, 0, len(possibleTypes)+1)
+	exclusive := true
+	for _, possibleType := range possibleTypes {
+		possibleTypeNode, ok := r.definition.Index.FirstNodeByNameStr(possibleType)
+		if !ok || possibleTypeNode.Kind != ast.NodeKindObjectTypeDefinition {
+			continue
+		}
+		object, hasTypeName, err := r.objectSchema(possibleTypeNode, possibleType, selectionSets, true)
+		if err != nil {
+			return nil, err
+		}
+		exclusive = exclusive && hasTypeName
+		alternatives = append(alternatives, object)
+	}
+	if !nonNull {
+		alternatives = append(alternatives, NewNull())
+	}
+	if exclusive {
+		return AbstractType{OneOf: alternatives}, nil
+	}
+	return AbstractType{AnyOf: alternatives}, nil
+}
+
+// objectSchema returns the schema of the fields selected for an object type,
+// hasTypeName is true if __typename is always selected
+func (r *responseResolver) objectSchema(node ast.Node, typeName string, selectionSets []int, nonNull bool) (object Object, hasTypeName bool, err error) {
+	var fields []*collectedField
+	for _, selectionSet := range selectionSets {
+		fields = r.collectFields(fields, typeName, selectionSet, false)
+	}
+
+	object = NewObject(nonNull)
+	for _, field := range fields {
+		var schema JsonSchema
+		if field.fieldName == "__typename" {
+			schema = NewConst(typeName)
+			hasTypeName = hasTypeName || !field.conditional
+		} else {
+			fieldDefinition, ok := r.definition.NodeFieldDefinitionByName(node, []byte(field.fieldName))
+			if !ok {
+				return object, false, fmt.Errorf("field %s.%s not found", typeName, field.fieldName)
+			}
+			var selectionSets []int
+			for _, fieldRef := range field.fieldRefs {
+				if selectionSet, ok := r.operation.FieldSelectionSet(fieldRef); ok {
+					selectionSets = append(selectionSets, selectionSet)
+				}
+			}
+			if schema, err = r.typeSchema(r.definition.FieldDefinitionType(fieldDefinition), selectionSets); err != nil {
+				return object, false, err
+			}
+		}
+		object.Properties[field.responseKey] = schema
+		if !field.conditional {
+			object.Required = append(object.Required, field.responseKey)
+		}
+	}
+	return object, hasTypeName, nil
+}
+
+// collectFields adds the fields of a selection set which are selected for an object type to fields,
+// fields of fragments are added if the type condition of the fragment matches the object type
+func (r *responseResolver) collectFields(fields []*collectedField, typeName string, selectionSet int, conditional bool) []*collectedField {
+	for _, selectionRef := range r.operation.SelectionSets[selectionSet].SelectionRefs {
+		selection := r.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			fields = r.addField(fields, selection.Ref, conditional || r.hasConditionalDirective(r.operation.FieldDirectives(selection.Ref)))
+		case ast.SelectionKindInlineFragment:
+			if r.operation.InlineFragmentHasTypeCondition(selection.Ref) && !r.typeConditionMatches(r.operation.InlineFragmentTypeConditionNameString(selection.Ref), typeName) {
+				continue
+			}
+			fragmentSelectionSet, ok := r.operation.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			fields = r.collectFields(fields, typeName, fragmentSelectionSet, conditional || r.hasConditionalDirective(r.operation.InlineFragments[selection.Ref].Directives.Refs))
+		case ast.SelectionKindFragmentSpread:
+			fragment, ok := r.operation.FragmentDefinitionRef(r.operation.FragmentSpreadNameBytes(selection.Ref))
+			if !ok || !r.typeConditionMatches(r.operation.FragmentDefinitionTypeNameString(fragment), typeName) {
+				continue
+			}
+			fields = r.collectFields(fields, typeName, r.operation.FragmentDefinitions[fragment].SelectionSet, conditional || r.hasConditionalDirective(r.operation.FragmentSpreads[selection.Ref].Directives.Refs))
+		}
+	}
+	return fields
+}
+
+func (r *responseResolver) addField(fields []*collectedField, fieldRef int, conditional bool) []*collectedField {
+	responseKey := r.operation.FieldAliasOrNameString(fieldRef)
+	for _, field := range fields {
+		if field.responseKey == responseKey {
+			field.fieldRefs = append(field.fieldRefs, fieldRef)
+			field.conditional = field.conditional && conditional
+			return fields
+		}
+	}
+	return append(fields, &collectedField{
+		responseKey: responseKey,
+		fieldName:   r.operation.FieldNameString(fieldRef),
+		fieldRefs:   []int{fieldRef},
+		conditional: conditional,
+	})
+}
+
+func (r *responseResolver) hasConditionalDirective(directives []int) bool {
+	for _, directive := range directives {
+		switch r.operation.DirectiveNameString(directive) {
+		case "skip", "include":
+			return true
+		}
+	}
+	return false
+}
+
+// typeConditionMatches returns true if a fragment with the type condition is applied to objects of the object type
+func (r *responseResolver) typeConditionMatches(typeCondition, objectTypeName string) bool {
+	if typeCondition == objectTypeName {
+		return true
+	}
+	node, ok := r.definition.Index.FirstNodeByNameStr(typeCondition)
+	if !ok {
+		return false
+	}
+	switch node.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		objectNode, ok := r.definition.Index.FirstNodeByNameStr(objectTypeName)
+		return ok && r.definition.NodeImplementsInterface(objectNode, []byte(typeCondition))
+	case ast.NodeKindUnionTypeDefinition:
+		members, _ := r.definition.UnionTypeDefinitionMemberTypeNames(node.Ref)
+		for _, member := range members {
+			if member == objectTypeName {
+				return true
+			}
+		}
+	}
+	return false
+}
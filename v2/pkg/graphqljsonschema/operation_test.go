@@ -0,0 +1,230 @@
+package graphqljsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/internal/pkg/unsafeparser"
+)
+
+const operationTestSchema = `
+	schema { query: Query mutation: Mutation }
+
+	type Query {
+		user(id: ID!): User
+		users(filter: UserFilter): [User!]!
+		search(term: String!): [SearchResult]!
+		node(id: ID!): Node
+	}
+
+	type Mutation {
+		rename(id: ID!, name: String!): User!
+	}
+
+	input UserFilter {
+		name: String
+		roles: [Role!]
+	}
+
+	enum Role { ADMIN MEMBER }
+
+	interface Node { id: ID! }
+
+	type User implements Node {
+		id: ID!
+		name: String!
+		email: String
+		role: Role!
+		friends: [User!]
+	}
+
+	type Post implements Node {
+		id: ID!
+		title: String!
+	}
+
+	union SearchResult = User | Post
+`
+
+func TestFromOperation(t *testing.T) {
+	definition := unsafeparser.ParseGraphqlDocumentStringWithBaseSchema(operationTestSchema)
+
+	schemas := func(t *testing.T, operation, operationName string) (variables, response string) {
+		t.Helper()
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(operation)
+		schema, err := FromOperation(&operationDocument, &definition, operationName)
+		require.NoError(t, err)
+		variablesJSON, err := json.Marshal(schema.Variables)
+		require.NoError(t, err)
+		responseJSON, err := json.Marshal(schema.Response)
+		require.NoError(t, err)
+		return string(variablesJSON), string(responseJSON)
+	}
+
+	validate := func(t *testing.T, schema string, valid, invalid []string) {
+		t.Helper()
+		validator, err := NewValidatorFromString(schema)
+		require.NoError(t, err)
+		for _, input := range valid {
+			assert.NoError(t, validator.Validate(context.Background(), []byte(input)), "Incorrectly judged invalid: %v", input)
+		}
+		for _, input := range invalid {
+			assert.Error(t, validator.Validate(context.Background(), []byte(input)), "Incorrectly judged valid: %v", input)
+		}
+	}
+
+	t.Run("variables and response with aliases and nullability", func(t *testing.T) {
+		variables, response := schemas(t, `
+			query User($id: ID!, $withEmail: Boolean! = false) {
+				user(id: $id) {
+					userId: id
+					name
+					email @include(if: $withEmail)
+					friends { name }
+				}
+			}`, "")
+
+		assert.Equal(t, prettyPrint(`{
+			"type":["object"],
+			"properties":{
+				"id":{"type":["string","integer"]},
+				"withEmail":{"type":["boolean"]}
+			},
+			"required":["id"],
+			"additionalProperties":false
+		}`), prettyPrint(variables))
+
+		assert.Equal(t, prettyPrint(`{
+			"type":["object"],
+			"properties":{
+				"data":{
+					"type":["object","null"],
+					"properties":{
+						"user":{
+							"type":["object","null"],
+							"properties":{
+								"userId":{"type":["string","integer"]},
+								"name":{"type":["string"]},
+								"email":{"type":["string","null"]},
+								"friends":{
+									"type":["array","null"],
+									"items":{
+										"type":["object"],
+										"properties":{"name":{"type":["string"]}},
+										"required":["name"],
+										"additionalProperties":false
+									}
+								}
+							},
+							"required":["userId","name","friends"],
+							"additionalProperties":false
+						}
+					},
+					"required":["user"],
+					"additionalProperties":false
+				},
+				"errors":{
+					"type":["array"],
+					"items":{
+						"type":["object"],
+						"properties":{
+							"message":{"type":["string"]},
+							"locations":{"type":["array"],"items":{}},
+							"path":{"type":["array"],"items":{"type":["string","integer"]}},
+							"extensions":{"type":["object"],"additionalProperties":true}
+						},
+						"required":["message"],
+						"additionalProperties":false
+					}
+				},
+				"extensions":{"type":["object"],"additionalProperties":true}
+			},
+			"additionalProperties":false
+		}`), prettyPrint(response))
+
+		validate(t, response, []string{
+			`{"data":{"user":{"userId":"1","name":"Ada","friends":null}}}`,
+			`{"data":{"user":{"userId":"1","name":"Ada","email":null,"friends":[{"name":"Grace"}]}}}`,
+			`{"data":{"user":null}}`,
+			`{"data":null,"errors":[{"message":"failed","path":["user"]}]}`,
+		}, []string{
+			`{"data":{"user":{"id":"1","name":"Ada","friends":null}}}`,
+			`{"data":{"user":{"userId":"1","name":null,"friends":null}}}`,
+			`{"data":{"user":{"userId":"1","name":"Ada","friends":[null]}}}`,
+			`{"data":{}}`,
+		})
+	})
+
+	t.Run("input objects of variables are definitions", func(t *testing.T) {
+		variables, _ := schemas(t, `query ($filter: UserFilter) { users(filter: $filter) { id } }`, "")
+		assert.Equal(t, prettyPrint(`{
+			"type":["object"],
+			"properties":{"filter":{"$ref":"#/$defs/UserFilter"}},
+			"additionalProperties":false,
+			"$defs":{
+				"UserFilter":{
+					"type":["object","null"],
+					"properties":{
+						"name":{"type":["string","null"]},
+						"roles":{"type":["array","null"],"items":{"type":["string"]}}
+					},
+					"additionalProperties":false
+				}
+			}
+		}`), prettyPrint(variables))
+		validate(t, variables, []string{`{}`, `{"filter":{"roles":["ADMIN"]}}`}, []string{`{"filter":{"roles":[null]}}`, `{"other":1}`})
+	})
+
+	t.Run("fragments and abstract types", func(t *testing.T) {
+		_, response := schemas(t, `
+			query Search {
+				search(term: "a") {
+					__typename
+					... on User { ...UserFields }
+					... on Post { title }
+				}
+			}
+			query Node { node(id: "1") { id } }
+			fragment UserFields on User { name friends @skip(if: true) { id } }`, "Search")
+
+		validate(t, response, []string{
+			`{"data":{"search":[{"__typename":"User","name":"Ada"},{"__typename":"Post","title":"GraphQL"},null]}}`,
+			`{"data":{"search":[{"__typename":"User","name":"Ada","friends":[{"id":"2"}]}]}}`,
+		}, []string{
+			`{"data":{"search":[{"__typename":"Post","name":"Ada"}]}}`,
+			`{"data":{"search":[{"__typename":"User","title":"GraphQL"}]}}`,
+			`{"data":{"search":[{"__typename":"Comment"}]}}`,
+			`{"data":{"search":[{"name":"Ada"}]}}`,
+		})
+
+		_, response = schemas(t, `
+			query Search { search(term: "a") { __typename } }
+			query Node { node(id: "1") { id ... on Post { title } } }`, "Node")
+		assert.Contains(t, response, `"anyOf"`)
+		validate(t, response, []string{
+			`{"data":{"node":{"id":"1"}}}`,
+			`{"data":{"node":{"id":"1","title":"GraphQL"}}}`,
+			`{"data":{"node":null}}`,
+		}, []string{
+			`{"data":{"node":{"title":"GraphQL"}}}`,
+		})
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		variables, response := schemas(t, `mutation ($name: String!) { rename(id: "1", name: $name) { name role } }`, "")
+		validate(t, variables, []string{`{"name":"Ada"}`}, []string{`{}`, `{"name":null}`})
+		validate(t, response, []string{`{"data":{"rename":{"name":"Ada","role":"ADMIN"}}}`}, []string{`{"data":{"rename":null}}`})
+	})
+
+	t.Run("operation name is required for multiple operations", func(t *testing.T) {
+		operationDocument := unsafeparser.ParseGraphqlDocumentString(`query A { node(id: "1") { id } } query B { node(id: "2") { id } }`)
+		_, err := FromOperation(&operationDocument, &definition, "")
+		assert.Error(t, err)
+		_, err = FromOperation(&operationDocument, &definition, "C")
+		assert.Error(t, err)
+	})
+}
@@ -36,7 +36,8 @@ func (j *JsonConverter) GraphQLDocument(introspectionJSON io.Reader) (*ast.Docum
 }
 
 func (j *JsonConverter) importSchema() error {
-	j.doc.ImportSchemaDefinition(j.schema.TypeNames())
+	queryTypeName, mutationTypeName, subscriptionTypeName := j.schema.TypeNames()
+	j.doc.ImportSchemaDefinitionWithDescription(j.schema.Description, queryTypeName, mutationTypeName, subscriptionTypeName)
 
 	for i := 0; i < len(j.schema.Types); i++ {
 		if err := j.importFullType(j.schema.Types[i]); err != nil {
@@ -56,7 +57,7 @@ func (j *JsonConverter) importSchema() error {
 func (j *JsonConverter) importFullType(fullType FullType) (err error) {
 	switch fullType.Kind {
 	case SCALAR:
-		j.doc.ImportScalarTypeDefinition(fullType.Name, fullType.Description)
+		j.importScalar(fullType)
 	case OBJECT:
 		err = j.importObject(fullType)
 	case ENUM:
@@ -71,6 +72,18 @@ func (j *JsonConverter) importFullType(fullType FullType) (err error) {
 	return
 }
 
+func (j *JsonConverter) importScalar(fullType FullType) {
+	var directiveRefs []int
+	if fullType.SpecifiedByURL != nil {
+		directiveRefs = append(directiveRefs, j.importSpecifiedByDirective(*fullType.SpecifiedByURL))
+	}
+
+	j.doc.ImportScalarTypeDefinitionWithDirectives(
+		fullType.Name,
+		fullType.Description,
+		directiveRefs)
+}
+
 func (j *JsonConverter) importObject(fullType FullType) error {
 	fieldRefs, err := j.importFields(fullType.Fields)
 	if err != nil {
@@ -97,10 +110,17 @@ func (j *JsonConverter) importInterface(fullType FullType) error {
 		return err
 	}
 
-	j.doc.ImportInterfaceTypeDefinition(
+	iRefs := make([]int, len(fullType.Interfaces))
+	for i := 0; i < len(iRefs); i++ {
+		iRefs[i] = j.importType(fullType.Interfaces[i])
+	}
+
+	j.doc.ImportInterfaceTypeDefinitionWithDirectives(
 		fullType.Name,
 		fullType.Description,
-		fieldRefs)
+		fieldRefs,
+		iRefs,
+		nil)
 
 	return nil
 }
@@ -111,11 +131,12 @@ func (j *JsonConverter) importDirective(directive Directive) error {
 		return err
 	}
 
-	j.doc.ImportDirectiveDefinition(
+	ref := j.doc.ImportDirectiveDefinition(
 		directive.Name,
 		directive.Description,
 		argRefs,
 		directive.Locations)
+	j.doc.DirectiveDefinitions[ref].Repeatable.IsRepeatable = directive.IsRepeatable
 
 	return nil
 }
@@ -126,10 +147,16 @@ func (j *JsonConverter) importInputObject(fullType FullType) error {
 		return err
 	}
 
-	j.doc.ImportInputObjectTypeDefinition(
+	var directiveRefs []int
+	if fullType.IsOneOf != nil && *fullType.IsOneOf {
+		directiveRefs = append(directiveRefs, j.doc.ImportDirective(ast.OneOfDirectiveName, nil))
+	}
+
+	j.doc.ImportInputObjectTypeDefinitionWithDirectives(
 		fullType.Name,
 		fullType.Description,
-		argRefs)
+		argRefs,
+		directiveRefs)
 
 	return nil
 }
@@ -219,8 +246,13 @@ func (j *JsonConverter) importInputField(field InputValue) (ref int, err error)
 		return -1, err
 	}
 
-	return j.doc.ImportInputValueDefinition(
-		field.Name, field.Description, typeRef, defaultValue), nil
+	var directiveRefs []int
+	if field.IsDeprecated {
+		directiveRefs = append(directiveRefs, j.importDeprecatedDirective(field.DeprecationReason))
+	}
+
+	return j.doc.ImportInputValueDefinitionWithDirectives(
+		field.Name, field.Description, typeRef, defaultValue, directiveRefs), nil
 }
 
 func (j *JsonConverter) importType(typeRef TypeRef) (ref int) {
@@ -273,3 +305,14 @@ func (j *JsonConverter) importDeprecatedDirective(reason *string) (ref int) {
 
 	return j.doc.ImportDirective(DeprecatedDirectiveName, args)
 }
+
+func (j *JsonConverter) importSpecifiedByDirective(url string) (ref int) {
+	valueRef := j.doc.ImportStringValue([]byte(url), false)
+	value := ast.Value{
+		Kind: ast.ValueKindString,
+		Ref:  valueRef,
+	}
+	j.doc.AddValue(value)
+
+	return j.doc.ImportDirective(SpecifiedByDirectiveName, []int{j.doc.ImportArgument(SpecifiedByURLArgName, value)})
+}
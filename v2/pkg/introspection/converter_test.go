@@ -63,6 +63,103 @@ func TestJSONConverter_GraphQLDocument(t *testing.T) {
 	}
 }
 
+func TestJSONConverter_GraphQLDocument_OneOf(t *testing.T) {
+	definition, report := astparser.ParseGraphqlDocumentString(`
+		type Query { pet(by: PetBy!, filter: PetFilter): String }
+		input PetBy @oneOf { id: ID name: String }
+		input PetFilter { name: String }
+		scalar ID
+		scalar String`)
+	require.False(t, report.HasErrors())
+
+	gen := NewGenerator()
+	var data Data
+	gen.Generate(&definition, &report, &data)
+	require.False(t, report.HasErrors())
+
+	isOneOf := map[string]*bool{}
+	for _, fullType := range data.Schema.Types {
+		isOneOf[fullType.Name] = fullType.IsOneOf
+	}
+	require.NotNil(t, isOneOf["PetBy"])
+	assert.True(t, *isOneOf["PetBy"])
+	require.NotNil(t, isOneOf["PetFilter"])
+	assert.False(t, *isOneOf["PetFilter"])
+	assert.Nil(t, isOneOf["Query"])
+
+	introspectionJSON, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	converter := JsonConverter{}
+	doc, err := converter.GraphQLDocument(bytes.NewBuffer(introspectionJSON))
+	require.NoError(t, err)
+
+	schema, err := astprinter.PrintString(doc, nil)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "input PetBy @oneOf {")
+	assert.Contains(t, schema, "input PetFilter {")
+}
+
+func TestJSONConverter_GraphQLDocument_SpecCurrent(t *testing.T) {
+	const sdl = `"The pets schema."
+schema {
+  query: Query
+}
+
+type Query {
+  pet(id: ID, name: String @deprecated(reason: "Use id.")): Pet
+}
+
+interface Node {
+  id: ID
+}
+
+interface Pet implements Node {
+  id: ID
+}
+
+input PetFilter {
+  name: String
+  nickname: String @deprecated(reason: "Use name.")
+}
+
+scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+directive @cached(ttl: Int) repeatable on FIELD_DEFINITION
+
+scalar ID
+
+scalar String
+
+scalar Int`
+
+	definition, report := astparser.ParseGraphqlDocumentString(sdl)
+	require.False(t, report.HasErrors())
+
+	gen := NewGenerator()
+	var data Data
+	gen.Generate(&definition, &report, &data)
+	require.False(t, report.HasErrors())
+
+	assert.Equal(t, "The pets schema.", data.Schema.Description)
+
+	introspectionJSON, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	converter := JsonConverter{}
+	doc, err := converter.GraphQLDocument(bytes.NewBuffer(introspectionJSON))
+	require.NoError(t, err)
+
+	schema, err := astprinter.PrintString(doc, nil)
+	require.NoError(t, err)
+	assert.Contains(t, schema, "\"The pets schema.\"\nschema {")
+	assert.Contains(t, schema, `pet(id: ID, name: String @deprecated(reason: "Use id.")): Pet`)
+	assert.Contains(t, schema, `interface Pet implements Node {`)
+	assert.Contains(t, schema, `nickname: String @deprecated(reason: "Use name.")`)
+	assert.Contains(t, schema, `scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")`)
+	assert.Contains(t, schema, `directive @cached(ttl: Int) repeatable on FIELD_DEFINITION`)
+}
+
 func BenchmarkJsonConverter_GraphQLDocument(b *testing.B) {
 	introspectedBytes, err := os.ReadFile("./testdata/swapi_introspection_response.json")
 	require.NoError(b, err)
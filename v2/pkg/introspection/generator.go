@@ -12,6 +12,8 @@ import (
 const (
 	DeprecatedDirectiveName  = "deprecated"
 	DeprecationReasonArgName = "reason"
+	SpecifiedByDirectiveName = "specifiedBy"
+	SpecifiedByURLArgName    = "url"
 )
 
 type Generator struct {
@@ -66,6 +68,9 @@ type introspectionVisitor struct {
 
 func (i *introspectionVisitor) EnterDocument(operation, definition *ast.Document) {
 	i.data.Schema = NewSchema()
+	if schemaDefinitionRef := i.definition.SchemaDefinitionRef(); schemaDefinitionRef != ast.InvalidRef {
+		i.data.Schema.Description = i.definition.SchemaDefinitionDescriptionString(schemaDefinitionRef)
+	}
 }
 
 func (i *introspectionVisitor) EnterObjectTypeDefinition(ref int) {
@@ -131,6 +136,11 @@ func (i *introspectionVisitor) EnterInputValueDefinition(ref int) {
 		DefaultValue: defaultValue,
 	}
 
+	if directiveRef, exists := i.definition.InputValueDefinitionDirectiveByName(ref, []byte(DeprecatedDirectiveName)); exists {
+		inputValue.IsDeprecated = true
+		inputValue.DeprecationReason = i.deprecationReason(directiveRef)
+	}
+
 	switch i.Ancestors[len(i.Ancestors)-1].Kind {
 	case ast.NodeKindInputObjectTypeDefinition:
 		i.currentType.InputFields = append(i.currentType.InputFields, inputValue)
@@ -192,6 +202,12 @@ func (i *introspectionVisitor) EnterScalarTypeDefinition(ref int) {
 	typeDefinition.Kind = SCALAR
 	typeDefinition.Name = i.definition.ScalarTypeDefinitionNameString(ref)
 	typeDefinition.Description = i.definition.ScalarTypeDefinitionDescriptionString(ref)
+	if directiveRef, exists := i.definition.ScalarTypeDefinitionDirectiveByName(ref, []byte(SpecifiedByDirectiveName)); exists {
+		if url, exists := i.definition.DirectiveArgumentValueByName(directiveRef, []byte(SpecifiedByURLArgName)); exists {
+			specifiedByURL := i.definition.ValueContentString(url)
+			typeDefinition.SpecifiedByURL = &specifiedByURL
+		}
+	}
 	i.data.Schema.Types = append(i.data.Schema.Types, typeDefinition)
 }
 
@@ -253,6 +269,8 @@ func (i *introspectionVisitor) EnterInputObjectTypeDefinition(ref int) {
 	i.currentType.Kind = INPUTOBJECT
 	i.currentType.Name = i.definition.InputObjectTypeDefinitionNameString(ref)
 	i.currentType.Description = i.definition.InputObjectTypeDefinitionDescriptionString(ref)
+	isOneOf := i.definition.InputObjectTypeDefinitionIsOneOf(ref)
+	i.currentType.IsOneOf = &isOneOf
 }
 
 func (i *introspectionVisitor) LeaveInputObjectTypeDefinition(ref int) {
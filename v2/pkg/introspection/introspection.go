@@ -12,6 +12,7 @@ type Data struct {
 }
 
 type Schema struct {
+	Description      string      `json:"description,omitempty"`
 	QueryType        *TypeName   `json:"queryType"`
 	MutationType     *TypeName   `json:"mutationType"`
 	SubscriptionType *TypeName   `json:"subscriptionType"`
@@ -47,16 +48,20 @@ type FullType struct {
 	Kind        __TypeKind `json:"kind"`
 	Name        string     `json:"name"`
 	Description string     `json:"description"`
+	// not nil for __TypeKind SCALAR with a @specifiedBy directive only
+	SpecifiedByURL *string `json:"specifiedByURL,omitempty"`
 	// not empty for __TypeKind OBJECT and INTERFACE only
 	Fields []Field `json:"fields,omitempty"`
 	// not empty for __TypeKind INPUT_OBJECT only
 	InputFields []InputValue `json:"inputFields"`
-	// not empty for __TypeKind OBJECT only
+	// not empty for __TypeKind OBJECT and INTERFACE only
 	Interfaces []TypeRef `json:"interfaces"`
 	// not empty for __TypeKind ENUM only
 	EnumValues []EnumValue `json:"enumValues,omitempty"`
 	// not empty for __TypeKind INTERFACE and UNION only
 	PossibleTypes []TypeRef `json:"possibleTypes"`
+	// not nil for __TypeKind INPUT_OBJECT only
+	IsOneOf *bool `json:"isOneOf,omitempty"`
 }
 
 func NewFullType() FullType {
@@ -133,10 +138,12 @@ type EnumValue struct {
 }
 
 type InputValue struct {
-	Name         string  `json:"name"`
-	Description  string  `json:"description"`
-	Type         TypeRef `json:"type"`
-	DefaultValue *string `json:"defaultValue"`
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	Type              TypeRef `json:"type"`
+	DefaultValue      *string `json:"defaultValue"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
 }
 
 type Directive struct {
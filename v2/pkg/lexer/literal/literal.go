@@ -65,6 +65,8 @@ var (
 	INCLUDE                       = []byte("include")
 	IF                            = []byte("if")
 	SKIP                          = []byte("skip")
+	DEPRECATED                    = []byte("deprecated")
+	REASON                        = []byte("reason")
 	SCHEMA                        = []byte("schema")
 	EXTEND                        = []byte("extend")
 	SCALAR                        = []byte("scalar")
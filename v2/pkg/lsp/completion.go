@@ -0,0 +1,134 @@
+package lsp
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func (s *Server) completion(doc *document, pos Position) []CompletionItem {
+	c := s.cursorAt(doc, pos)
+	switch c.kind {
+	case cursorSelectionSet, cursorField:
+		return s.fieldCompletion(c.enclosingType)
+	case cursorArguments:
+		return s.argumentCompletion(doc, c)
+	case cursorFragmentSpread:
+		return s.fragmentCompletion(doc)
+	case cursorTypeCondition:
+		return s.typeConditionCompletion()
+	default:
+		return []CompletionItem{}
+	}
+}
+
+func (s *Server) fieldCompletion(enclosingType ast.Node) []CompletionItem {
+	definition := s.schema.definition
+	fields := definition.NodeFieldDefinitions(enclosingType)
+	items := make([]CompletionItem, 0, len(fields))
+	for _, ref := range fields {
+		items = append(items, CompletionItem{
+			Label:         definition.FieldDefinitionNameString(ref),
+			Kind:          completionItemKindField,
+			Detail:        s.typeString(definition.FieldDefinitionType(ref)),
+			Documentation: markdown(definition.FieldDefinitionDescriptionString(ref)),
+			Deprecated:    definition.FieldDefinitionHasNamedDirective(ref, "deprecated"),
+		})
+	}
+	return items
+}
+
+// argumentCompletion returns the arguments of a field which are not used yet
+func (s *Server) argumentCompletion(doc *document, c cursor) []CompletionItem {
+	if c.fieldDefinition == ast.InvalidRef {
+		return []CompletionItem{}
+	}
+
+	used := map[string]struct{}{}
+	for _, ref := range doc.ast.Fields[c.field].Arguments.Refs {
+		argument := doc.ast.Arguments[ref]
+		if c.isOn(argument.Name) {
+			continue
+		}
+		used[doc.ast.ArgumentNameString(ref)] = struct{}{}
+	}
+
+	definition := s.schema.definition
+	arguments := definition.FieldDefinitionArgumentsDefinitions(c.fieldDefinition)
+	items := make([]CompletionItem, 0, len(arguments))
+	for _, ref := range arguments {
+		name := definition.InputValueDefinitionNameString(ref)
+		if _, exists := used[name]; exists {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         name,
+			Kind:          completionItemKindVariable,
+			Detail:        s.typeString(definition.InputValueDefinitionType(ref)),
+			Documentation: markdown(definition.InputValueDefinitionDescriptionString(ref)),
+			InsertText:    name + ": ",
+		})
+	}
+	return items
+}
+
+// fragmentCompletion returns the fragments of the document and the keyword of inline fragments
+func (s *Server) fragmentCompletion(doc *document) []CompletionItem {
+	items := []CompletionItem{
+		{
+			Label: "on",
+			Kind:  completionItemKindKeyword,
+		},
+	}
+	for ref := range doc.ast.FragmentDefinitions {
+		items = append(items, CompletionItem{
+			Label:  doc.ast.FragmentDefinitionNameString(ref),
+			Kind:   completionItemKindReference,
+			Detail: "fragment on " + doc.ast.FragmentDefinitionTypeNameString(ref),
+		})
+	}
+	return items
+}
+
+// typeConditionCompletion returns all types which can be used in a type condition
+func (s *Server) typeConditionCompletion() []CompletionItem {
+	definition := s.schema.definition
+	items := []CompletionItem{}
+	for _, node := range definition.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		default:
+			continue
+		}
+		name := definition.NodeNameString(node)
+		if isIntrospectionName(name) {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:         name,
+			Kind:          completionItemKindClass,
+			Documentation: markdown(s.schema.typeDescription(node)),
+		})
+	}
+	return items
+}
+
+func (s *Server) typeString(ref int) string {
+	out, err := s.schema.definition.PrintTypeBytes(ref, nil)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func markdown(value string) *MarkupContent {
+	if value == "" {
+		return nil
+	}
+	return &MarkupContent{
+		Kind:  markupKindMarkdown,
+		Value: value,
+	}
+}
+
+func isIntrospectionName(name string) bool {
+	return len(name) > 1 && name[0] == '_' && name[1] == '_'
+}
@@ -0,0 +1,217 @@
+package lsp
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+type cursorKind int
+
+const (
+	cursorUnknown cursorKind = iota
+	// cursorSelectionSet is a cursor between the selections of a selection set
+	cursorSelectionSet
+	// cursorField is a cursor on the name or alias of a field
+	cursorField
+	// cursorArguments is a cursor between the parentheses of the arguments of a field
+	cursorArguments
+	// cursorFragmentSpread is a cursor on a fragment spread
+	cursorFragmentSpread
+	// cursorTypeCondition is a cursor on the type condition of a fragment
+	cursorTypeCondition
+)
+
+// cursor describes the node of an operation at the position of the cursor
+type cursor struct {
+	kind cursorKind
+	// offset is the byte offset of the cursor
+	offset int
+	// enclosingType is the schema node of the selection set around the cursor
+	enclosingType ast.Node
+	// field is the field of the operation for cursorField and cursorArguments
+	field int
+	// fieldDefinition is the schema definition of the field, ast.InvalidRef if the field is unknown
+	fieldDefinition int
+	// fragmentSpread is the fragment spread of the operation for cursorFragmentSpread
+	fragmentSpread int
+	// typeName is the literal of the type condition for cursorTypeCondition
+	typeName ast.ByteSliceReference
+}
+
+// cursorFinder finds the node of an operation at a byte offset
+type cursorFinder struct {
+	doc    *document
+	schema *Schema
+	offset int
+}
+
+func (s *Server) cursorAt(doc *document, pos Position) cursor {
+	finder := cursorFinder{
+		doc:    doc,
+		schema: s.schema,
+		offset: doc.text.offset(pos),
+	}
+	c := finder.find()
+	c.offset = finder.offset
+	return c
+}
+
+// isOn reports whether the cursor is on a literal, including the position right after it
+func (c cursor) isOn(literal ast.ByteSliceReference) bool {
+	return c.offset >= int(literal.Start) && c.offset <= int(literal.End)
+}
+
+func (f *cursorFinder) find() cursor {
+	operation := f.doc.ast
+	for _, root := range operation.RootNodes {
+		switch root.Kind {
+		case ast.NodeKindOperationDefinition:
+			definition := operation.OperationDefinitions[root.Ref]
+			rootType, _ := f.schema.definition.NodeByNameStr(f.schema.rootTypeName(definition.OperationType))
+			if c, ok := f.selectionSet(definition.SelectionSet, rootType); ok {
+				return c
+			}
+		case ast.NodeKindFragmentDefinition:
+			definition := operation.FragmentDefinitions[root.Ref]
+			if definition.TypeCondition.Type == ast.InvalidRef {
+				continue
+			}
+			typeName := operation.Types[definition.TypeCondition.Type].Name
+			if f.onLiteral(typeName) {
+				return cursor{kind: cursorTypeCondition, typeName: typeName}
+			}
+			typeNode, _ := f.schema.definition.NodeByName(operation.Input.ByteSlice(typeName))
+			if c, ok := f.selectionSet(definition.SelectionSet, typeNode); ok {
+				return c
+			}
+		}
+	}
+	return cursor{kind: cursorUnknown}
+}
+
+// selectionSet returns the cursor if it's inside the selection set
+func (f *cursorFinder) selectionSet(ref int, enclosingType ast.Node) (cursor, bool) {
+	operation := f.doc.ast
+	if ref < 0 || ref >= len(operation.SelectionSets) {
+		return cursor{}, false
+	}
+	set := operation.SelectionSets[ref]
+	start, end := f.doc.text.lexerOffset(set.LBrace)+1, f.doc.text.lexerOffset(set.RBrace)
+	if isUnclosed(set.RBrace) {
+		end = len(f.doc.text.content)
+	}
+	if f.offset < start || f.offset > end {
+		return cursor{}, false
+	}
+
+	for i, selectionRef := range set.SelectionRefs {
+		// the next selection limits nodes which are not closed, e.g. arguments without closing parenthesis
+		next := end
+		if i+1 < len(set.SelectionRefs) {
+			next = f.selectionStart(set.SelectionRefs[i+1])
+		}
+
+		selection := operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			if c, ok := f.field(selection.Ref, enclosingType, next); ok {
+				return c, true
+			}
+		case ast.SelectionKindFragmentSpread:
+			spread := operation.FragmentSpreads[selection.Ref]
+			if f.offset >= f.doc.text.lexerOffset(spread.Spread) && f.offset <= int(spread.FragmentName.End) {
+				return cursor{kind: cursorFragmentSpread, enclosingType: enclosingType, fragmentSpread: selection.Ref}, true
+			}
+		case ast.SelectionKindInlineFragment:
+			fragment := operation.InlineFragments[selection.Ref]
+			typeNode := enclosingType
+			if fragment.TypeCondition.Type != ast.InvalidRef {
+				typeName := operation.Types[fragment.TypeCondition.Type].Name
+				if f.onLiteral(typeName) {
+					return cursor{kind: cursorTypeCondition, enclosingType: enclosingType, typeName: typeName}, true
+				}
+				typeNode, _ = f.schema.definition.NodeByName(operation.Input.ByteSlice(typeName))
+			}
+			if f.hasSelectionSet(fragment.SelectionSet, f.doc.text.lexerOffset(fragment.Spread), next) {
+				if c, ok := f.selectionSet(fragment.SelectionSet, typeNode); ok {
+					return c, true
+				}
+			}
+		}
+	}
+
+	return cursor{kind: cursorSelectionSet, enclosingType: enclosingType}, true
+}
+
+func (f *cursorFinder) field(ref int, enclosingType ast.Node, next int) (cursor, bool) {
+	operation := f.doc.ast
+	field := operation.Fields[ref]
+	fieldStart := f.doc.text.lexerOffset(field.Position)
+
+	c := cursor{
+		enclosingType:   enclosingType,
+		field:           ref,
+		fieldDefinition: ast.InvalidRef,
+	}
+	if definition, exists := f.schema.definition.NodeFieldDefinitionByName(enclosingType, operation.Input.ByteSlice(field.Name)); exists {
+		c.fieldDefinition = definition
+	}
+
+	if f.hasSelectionSet(field.SelectionSet, fieldStart, next) {
+		// the selection set of an unknown field has an unknown enclosing type and nothing to complete
+		var fieldType ast.Node
+		if c.fieldDefinition != ast.InvalidRef {
+			fieldType = f.schema.definition.FieldDefinitionTypeNode(c.fieldDefinition)
+		}
+		if set, ok := f.selectionSet(field.SelectionSet, fieldType); ok {
+			return set, true
+		}
+	}
+
+	if field.Arguments.LPAREN.LineStart != 0 {
+		argumentsStart, argumentsEnd := f.doc.text.lexerOffset(field.Arguments.LPAREN)+1, next
+		if !isUnclosed(field.Arguments.RPAREN) {
+			argumentsEnd = f.doc.text.lexerOffset(field.Arguments.RPAREN)
+		}
+		if f.offset >= argumentsStart && f.offset <= argumentsEnd {
+			c.kind = cursorArguments
+			return c, true
+		}
+	}
+
+	if f.offset >= fieldStart && f.offset <= int(field.Name.End) {
+		c.kind = cursorField
+		return c, true
+	}
+
+	return cursor{}, false
+}
+
+// hasSelectionSet reports whether a node between nodeStart and next has a selection set,
+// the selection set of a field or inline fragment without braces isn't set and defaults to 0
+func (f *cursorFinder) hasSelectionSet(ref int, nodeStart, next int) bool {
+	if ref < 0 || ref >= len(f.doc.ast.SelectionSets) {
+		return false
+	}
+	lbrace := f.doc.text.lexerOffset(f.doc.ast.SelectionSets[ref].LBrace)
+	return lbrace > nodeStart && lbrace < next
+}
+
+func (f *cursorFinder) selectionStart(ref int) int {
+	operation := f.doc.ast
+	selection := operation.Selections[ref]
+	switch selection.Kind {
+	case ast.SelectionKindField:
+		return f.doc.text.lexerOffset(operation.Fields[selection.Ref].Position)
+	case ast.SelectionKindFragmentSpread:
+		return f.doc.text.lexerOffset(operation.FragmentSpreads[selection.Ref].Spread)
+	case ast.SelectionKindInlineFragment:
+		return f.doc.text.lexerOffset(operation.InlineFragments[selection.Ref].Spread)
+	default:
+		return len(f.doc.text.content)
+	}
+}
+
+// onLiteral reports whether the cursor is on a literal, including the position right after it
+func (f *cursorFinder) onLiteral(literal ast.ByteSliceReference) bool {
+	return f.offset >= int(literal.Start) && f.offset <= int(literal.End)
+}
@@ -0,0 +1,41 @@
+package lsp
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// definition returns the location of the fragment definition of a fragment spread,
+// the type definition of a type condition or the field definition of a field
+func (s *Server) definition(doc *document, pos Position) *Location {
+	c := s.cursorAt(doc, pos)
+	definition := s.schema.definition
+
+	var key string
+	switch c.kind {
+	case cursorFragmentSpread:
+		name := doc.ast.FragmentSpreads[c.fragmentSpread].FragmentName
+		ref, exists := doc.ast.FragmentDefinitionRef(doc.ast.Input.ByteSlice(name))
+		if !exists {
+			return nil
+		}
+		return &Location{
+			URI:   doc.uri,
+			Range: doc.text.literalRange(doc.ast.FragmentDefinitions[ref].Name),
+		}
+	case cursorTypeCondition:
+		key = doc.ast.Input.ByteSliceString(c.typeName)
+	case cursorField:
+		if c.fieldDefinition == ast.InvalidRef {
+			return nil
+		}
+		key = definition.NodeNameString(c.enclosingType) + "." + definition.FieldDefinitionNameString(c.fieldDefinition)
+	default:
+		return nil
+	}
+
+	location, exists := s.schema.locations[key]
+	if !exists {
+		return nil
+	}
+	return &location
+}
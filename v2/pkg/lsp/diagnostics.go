@@ -0,0 +1,30 @@
+package lsp
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const diagnosticSource = "graphql"
+
+// diagnostics returns the syntax errors of a document or, if there are none, its validation errors
+func (s *Server) diagnostics(doc *document) []Diagnostic {
+	report := doc.report
+	if !report.HasErrors() && len(doc.ast.RootNodes) != 0 {
+		report = operationreport.Report{}
+		s.validator.Validate(doc.ast, s.schema.definition, &report)
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(report.ExternalErrors))
+	for _, err := range report.ExternalErrors {
+		diagnostic := Diagnostic{
+			Severity: diagnosticSeverityError,
+			Source:   diagnosticSource,
+			Message:  err.Message,
+		}
+		if len(err.Locations) != 0 {
+			diagnostic.Range = doc.text.wordRange(err.Locations[0].Line, err.Locations[0].Column)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
@@ -0,0 +1,159 @@
+package lsp
+
+import (
+	"unicode/utf8"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/lexer/position"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// text maps between byte offsets, positions of the protocol and positions of the lexer.
+// The lexer counts characters in bytes, the protocol counts them in UTF-16 code units.
+type text struct {
+	content    string
+	lineStarts []int
+}
+
+func newText(content string) *text {
+	t := &text{
+		content:    content,
+		lineStarts: []int{0},
+	}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+	return t
+}
+
+// lineBounds returns the byte offsets of the start and the end of a line, lines outside the text are clamped
+func (t *text) lineBounds(line int) (start, end int) {
+	if line < 0 {
+		return 0, 0
+	}
+	if line >= len(t.lineStarts) {
+		return len(t.content), len(t.content)
+	}
+	end = len(t.content)
+	if line+1 < len(t.lineStarts) {
+		end = t.lineStarts[line+1] - 1
+	}
+	return t.lineStarts[line], end
+}
+
+// offset returns the byte offset of a position, positions outside the text are clamped
+func (t *text) offset(pos Position) int {
+	offset, lineEnd := t.lineBounds(pos.Line)
+	for character := 0; character < pos.Character && offset < lineEnd; {
+		r, size := utf8.DecodeRuneInString(t.content[offset:lineEnd])
+		character += utf16Len(r)
+		offset += size
+	}
+	return offset
+}
+
+// byteOffset returns the byte offset of a line and a column counted in bytes, positions outside the text are clamped
+func (t *text) byteOffset(line, column int) int {
+	lineStart, lineEnd := t.lineBounds(line)
+	if column < 0 {
+		return lineStart
+	}
+	if lineStart+column > lineEnd {
+		return lineEnd
+	}
+	return lineStart + column
+}
+
+// position returns the position of a byte offset
+func (t *text) position(offset int) Position {
+	if offset > len(t.content) {
+		offset = len(t.content)
+	}
+	line := 0
+	for line+1 < len(t.lineStarts) && t.lineStarts[line+1] <= offset {
+		line++
+	}
+	character := 0
+	for _, r := range t.content[t.lineStarts[line]:offset] {
+		character += utf16Len(r)
+	}
+	return Position{
+		Line:      line,
+		Character: character,
+	}
+}
+
+// lexerOffset returns the byte offset of the start of a lexer position, lines and chars of the lexer start at 1
+func (t *text) lexerOffset(pos position.Position) int {
+	return t.byteOffset(int(pos.LineStart)-1, int(pos.CharStart)-1)
+}
+
+func (t *text) literalRange(literal ast.ByteSliceReference) Range {
+	return Range{
+		Start: t.position(int(literal.Start)),
+		End:   t.position(int(literal.End)),
+	}
+}
+
+// wordRange returns the range of the word starting at a line and column of an error location
+func (t *text) wordRange(line, column uint32) Range {
+	start := t.byteOffset(int(line)-1, int(column)-1)
+	end := start
+	for end < len(t.content) && isNameChar(t.content[end]) {
+		end++
+	}
+	if end == start && end < len(t.content) && t.content[end] != '\n' {
+		end++
+	}
+	return Range{
+		Start: t.position(start),
+		End:   t.position(end),
+	}
+}
+
+func (t *text) fullRange() Range {
+	return Range{
+		Start: Position{},
+		End:   t.position(len(t.content)),
+	}
+}
+
+// utf16Len returns the number of UTF-16 code units of a rune, runes outside the basic multilingual plane need two
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// document is an open text document, it's parsed in recovery mode,
+// so that incomplete operations can still be completed
+type document struct {
+	uri    string
+	text   *text
+	ast    *ast.Document
+	report operationreport.Report
+}
+
+func newDocument(uri, content string) *document {
+	doc := &document{
+		uri:  uri,
+		text: newText(content),
+		ast:  ast.NewSmallDocument(),
+	}
+	doc.ast.Input.ResetInputString(content)
+	astparser.NewParser().ParseWithRecovery(doc.ast, &doc.report)
+	return doc
+}
+
+// isUnclosed reports whether a closing token was inserted as placeholder by the parser
+func isUnclosed(pos position.Position) bool {
+	return pos.LineStart == 0 || (pos.LineStart == pos.LineEnd && pos.CharStart == pos.CharEnd)
+}
@@ -0,0 +1,38 @@
+package lsp
+
+import (
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// formatting replaces the whole document with the output of the formatter,
+// documents with syntax errors are left untouched
+func (s *Server) formatting(doc *document, options formattingOptions) []TextEdit {
+	operation := ast.NewSmallDocument()
+	operation.Input.ResetInputString(doc.text.content)
+	report := operationreport.Report{}
+	astparser.NewParser().ParseWithComments(operation, &report)
+	if report.HasErrors() {
+		return []TextEdit{}
+	}
+
+	indent := "\t"
+	if options.InsertSpaces {
+		indent = strings.Repeat(" ", options.TabSize)
+	}
+	formatted, err := astprinter.FormatString(operation, astprinter.FormatOptions{Indent: indent})
+	if err != nil || formatted == doc.text.content {
+		return []TextEdit{}
+	}
+
+	return []TextEdit{
+		{
+			Range:   doc.text.fullRange(),
+			NewText: formatted,
+		},
+	}
+}
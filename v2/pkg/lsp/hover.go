@@ -0,0 +1,88 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+func (s *Server) hover(doc *document, pos Position) *Hover {
+	c := s.cursorAt(doc, pos)
+	definition := s.schema.definition
+
+	switch c.kind {
+	case cursorField:
+		if c.fieldDefinition == ast.InvalidRef {
+			return nil
+		}
+		field := doc.ast.Fields[c.field]
+		signature := fmt.Sprintf("%s.%s: %s",
+			definition.NodeNameString(c.enclosingType),
+			definition.FieldDefinitionNameString(c.fieldDefinition),
+			s.typeString(definition.FieldDefinitionType(c.fieldDefinition)),
+		)
+		fieldRange := doc.text.literalRange(field.Name)
+		return &Hover{
+			Contents: hoverContent(signature, definition.FieldDefinitionDescriptionString(c.fieldDefinition)),
+			Range:    &fieldRange,
+		}
+	case cursorTypeCondition:
+		node, exists := definition.NodeByName(doc.ast.Input.ByteSlice(c.typeName))
+		if !exists {
+			return nil
+		}
+		signature := fmt.Sprintf("%s %s", typeKeyword(node.Kind), definition.NodeNameString(node))
+		typeRange := doc.text.literalRange(c.typeName)
+		return &Hover{
+			Contents: hoverContent(signature, s.schema.typeDescription(node)),
+			Range:    &typeRange,
+		}
+	case cursorFragmentSpread:
+		name := doc.ast.FragmentSpreads[c.fragmentSpread].FragmentName
+		ref, exists := doc.ast.FragmentDefinitionRef(doc.ast.Input.ByteSlice(name))
+		if !exists {
+			return nil
+		}
+		signature := fmt.Sprintf("fragment %s on %s", doc.ast.FragmentDefinitionNameString(ref), doc.ast.FragmentDefinitionTypeNameString(ref))
+		spreadRange := doc.text.literalRange(name)
+		return &Hover{
+			Contents: hoverContent(signature, ""),
+			Range:    &spreadRange,
+		}
+	default:
+		return nil
+	}
+}
+
+func hoverContent(signature, description string) MarkupContent {
+	content := strings.Builder{}
+	content.WriteString("```graphql\n")
+	content.WriteString(signature)
+	content.WriteString("\n```")
+	if description != "" {
+		content.WriteString("\n\n")
+		content.WriteString(description)
+	}
+	return MarkupContent{
+		Kind:  markupKindMarkdown,
+		Value: content.String(),
+	}
+}
+
+func typeKeyword(kind ast.NodeKind) string {
+	switch kind {
+	case ast.NodeKindObjectTypeDefinition:
+		return "type"
+	case ast.NodeKindInterfaceTypeDefinition:
+		return "interface"
+	case ast.NodeKindUnionTypeDefinition:
+		return "union"
+	case ast.NodeKindEnumTypeDefinition:
+		return "enum"
+	case ast.NodeKindInputObjectTypeDefinition:
+		return "input"
+	default:
+		return "scalar"
+	}
+}
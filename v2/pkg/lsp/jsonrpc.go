@@ -0,0 +1,68 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const contentLengthHeader = "Content-Length"
+
+// readMessage reads the content of the next message, messages are framed by a header part as defined by the base protocol
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid header: %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), contentLengthHeader) {
+			contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid content length: %w", err)
+			}
+		}
+	}
+	if contentLength < 0 {
+		return nil, fmt.Errorf("missing header: %s", contentLengthHeader)
+	}
+
+	content := make([]byte, contentLength)
+	if _, err := io.ReadFull(reader, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// messageWriter writes messages with a header part, it's safe for concurrent use
+type messageWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *messageWriter) write(message interface{}) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := fmt.Fprintf(w.out, "%s: %d\r\n\r\n", contentLengthHeader, len(content)); err != nil {
+		return err
+	}
+	_, err = w.out.Write(content)
+	return err
+}
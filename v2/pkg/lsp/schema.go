@@ -0,0 +1,281 @@
+package lsp
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astnormalization"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astprinter"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/federation"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/introspection"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// Schema is the schema operations are validated and completed against.
+type Schema struct {
+	definition *ast.Document
+	// locations holds the locations of type definitions by type name
+	// and the locations of field definitions by "TypeName.fieldName"
+	locations map[string]Location
+}
+
+// NewSchemaFromSDLFiles creates a Schema from one or more files containing SDL, e.g. a schema split by domain.
+func NewSchemaFromSDLFiles(paths ...string) (*Schema, error) {
+	files, err := readSDLFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var sdl string
+	for _, file := range files {
+		sdl += file.content + "\n"
+	}
+
+	return newSchema(sdl, files)
+}
+
+// NewFederatedSchemaFromSDLFiles creates a Schema composed of the SDLs of federated subgraphs.
+func NewFederatedSchemaFromSDLFiles(paths ...string) (*Schema, error) {
+	files, err := readSDLFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	sdls := make([]string, 0, len(files))
+	for _, file := range files {
+		sdls = append(sdls, file.content)
+	}
+	sdl, err := federation.BuildBaseSchemaDocument(sdls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose federated schema: %w", err)
+	}
+
+	return newSchema(sdl, files)
+}
+
+// NewSchemaFromIntrospectionFile creates a Schema from the JSON response of an introspection query.
+// Type definitions have no locations, as there is no SDL file to point to.
+func NewSchemaFromIntrospectionFile(path string) (*Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	converter := introspection.JsonConverter{}
+	doc, err := converter.GraphQLDocument(file)
+	if err != nil {
+		return nil, err
+	}
+	removeBaseSchemaDefinitions(doc)
+	sdl, err := astprinter.PrintString(doc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSchema(sdl, nil)
+}
+
+// baseSchemaDefinitions are the names of the scalars and directives which are added by the base schema
+var baseSchemaDefinitions = map[string]struct{}{
+	"Int": {}, "Float": {}, "String": {}, "Boolean": {}, "ID": {},
+	"include": {}, "skip": {}, "deprecated": {}, "oneOf": {}, "specifiedBy": {},
+}
+
+// removeBaseSchemaDefinitions removes the built-in scalars, directives and introspection types of an introspection
+// response, as they would otherwise be defined twice after merging the schema with the base schema.
+func removeBaseSchemaDefinitions(doc *ast.Document) {
+	rootNodes := doc.RootNodes[:0]
+	for _, node := range doc.RootNodes {
+		name := node.NameString(doc)
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		if node.Kind == ast.NodeKindScalarTypeDefinition || node.Kind == ast.NodeKindDirectiveDefinition {
+			if _, ok := baseSchemaDefinitions[name]; ok {
+				continue
+			}
+		}
+		rootNodes = append(rootNodes, node)
+	}
+	doc.RootNodes = rootNodes
+}
+
+type sdlFile struct {
+	uri     string
+	content string
+}
+
+func readSDLFiles(paths []string) ([]sdlFile, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no schema files")
+	}
+
+	files := make([]sdlFile, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		uri, err := fileURI(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sdlFile{
+			uri:     uri,
+			content: string(content),
+		})
+	}
+	return files, nil
+}
+
+func fileURI(path string) (string, error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	uri := url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(absolutePath),
+	}
+	return uri.String(), nil
+}
+
+func newSchema(sdl string, files []sdlFile) (*Schema, error) {
+	definition := ast.NewSmallDocument()
+	definition.Input.ResetInputString(sdl)
+	if err := asttransform.MergeDefinitionWithBaseSchema(definition); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	// normalization merges type extensions into their definitions, so that their fields can be validated and completed
+	report := operationreport.Report{}
+	astnormalization.NormalizeDefinition(definition, &report)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("failed to normalize schema: %w", report)
+	}
+	astvalidation.DefaultDefinitionValidator().Validate(definition, &report)
+	if report.HasErrors() {
+		return nil, fmt.Errorf("invalid schema: %w", report)
+	}
+
+	schema := &Schema{
+		definition: definition,
+		locations:  map[string]Location{},
+	}
+	schema.indexLocations(files)
+	return schema, nil
+}
+
+// indexLocations adds the locations of all type and field definitions of the files.
+// Definitions take precedence over extensions, otherwise the first location wins,
+// e.g. if an entity is defined in several subgraphs.
+func (s *Schema) indexLocations(files []sdlFile) {
+	docs := make([]*ast.Document, 0, len(files))
+	for _, file := range files {
+		doc := ast.NewSmallDocument()
+		doc.Input.ResetInputString(file.content)
+		report := operationreport.Report{}
+		astparser.NewParser().ParseWithRecovery(doc, &report)
+		docs = append(docs, doc)
+	}
+
+	for _, extensions := range []bool{false, true} {
+		for i, file := range files {
+			s.indexFileLocations(file, docs[i], extensions)
+		}
+	}
+}
+
+func (s *Schema) indexFileLocations(file sdlFile, doc *ast.Document, extensions bool) {
+	content := newText(file.content)
+	addLocation := func(key string, name ast.ByteSliceReference) {
+		if _, exists := s.locations[key]; exists {
+			return
+		}
+		s.locations[key] = Location{
+			URI:   file.uri,
+			Range: content.literalRange(name),
+		}
+	}
+
+	for _, node := range doc.RootNodes {
+		var name ast.ByteSliceReference
+		isExtension := false
+		switch node.Kind {
+		case ast.NodeKindObjectTypeDefinition:
+			name = doc.ObjectTypeDefinitions[node.Ref].Name
+		case ast.NodeKindObjectTypeExtension:
+			name, isExtension = doc.ObjectTypeExtensions[node.Ref].Name, true
+		case ast.NodeKindInterfaceTypeDefinition:
+			name = doc.InterfaceTypeDefinitions[node.Ref].Name
+		case ast.NodeKindInterfaceTypeExtension:
+			name, isExtension = doc.InterfaceTypeExtensions[node.Ref].Name, true
+		case ast.NodeKindInputObjectTypeDefinition:
+			name = doc.InputObjectTypeDefinitions[node.Ref].Name
+		case ast.NodeKindInputObjectTypeExtension:
+			name, isExtension = doc.InputObjectTypeExtensions[node.Ref].Name, true
+		case ast.NodeKindUnionTypeDefinition:
+			name = doc.UnionTypeDefinitions[node.Ref].Name
+		case ast.NodeKindEnumTypeDefinition:
+			name = doc.EnumTypeDefinitions[node.Ref].Name
+		case ast.NodeKindScalarTypeDefinition:
+			name = doc.ScalarTypeDefinitions[node.Ref].Name
+		default:
+			continue
+		}
+		if isExtension != extensions {
+			continue
+		}
+
+		typeName := doc.Input.ByteSliceString(name)
+		addLocation(typeName, name)
+		for _, ref := range doc.NodeFieldDefinitions(node) {
+			addLocation(typeName+"."+doc.FieldDefinitionNameString(ref), doc.FieldDefinitions[ref].Name)
+		}
+		for _, ref := range doc.NodeInputFieldDefinitions(node) {
+			addLocation(typeName+"."+doc.InputValueDefinitionNameString(ref), doc.InputValueDefinitions[ref].Name)
+		}
+	}
+}
+
+// rootTypeName returns the name of the root type of an operation type
+func (s *Schema) rootTypeName(operationType ast.OperationType) string {
+	switch operationType {
+	case ast.OperationTypeMutation:
+		return string(s.definition.Index.MutationTypeName)
+	case ast.OperationTypeSubscription:
+		return string(s.definition.Index.SubscriptionTypeName)
+	default:
+		return string(s.definition.Index.QueryTypeName)
+	}
+}
+
+// typeDescription returns the description of a type definition
+func (s *Schema) typeDescription(node ast.Node) string {
+	var description ast.Description
+	switch node.Kind {
+	case ast.NodeKindObjectTypeDefinition:
+		description = s.definition.ObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInterfaceTypeDefinition:
+		description = s.definition.InterfaceTypeDefinitions[node.Ref].Description
+	case ast.NodeKindInputObjectTypeDefinition:
+		description = s.definition.InputObjectTypeDefinitions[node.Ref].Description
+	case ast.NodeKindUnionTypeDefinition:
+		description = s.definition.UnionTypeDefinitions[node.Ref].Description
+	case ast.NodeKindEnumTypeDefinition:
+		description = s.definition.EnumTypeDefinitions[node.Ref].Description
+	case ast.NodeKindScalarTypeDefinition:
+		description = s.definition.ScalarTypeDefinitions[node.Ref].Description
+	}
+	if !description.IsDefined {
+		return ""
+	}
+	return s.definition.Input.ByteSliceString(description.Content)
+}
@@ -0,0 +1,178 @@
+// Package lsp implements a GraphQL language server for editors supporting the Language Server Protocol.
+//
+// The server communicates over JSON-RPC, e.g. over stdio, and provides diagnostics, completion, hover information,
+// go-to-definition and formatting for GraphQL operations based on a Schema.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvalidation"
+)
+
+const serverName = "graphql-go-tools"
+
+// Server is a GraphQL language server for operations against a Schema.
+// Use NewServer to create a Server.
+type Server struct {
+	schema    *Schema
+	validator *astvalidation.OperationValidator
+	documents map[string]*document
+	writer    *messageWriter
+}
+
+// NewServer returns a Server validating operations against the schema.
+func NewServer(schema *Schema, options ...astvalidation.Option) *Server {
+	return &Server{
+		schema:    schema,
+		validator: astvalidation.DefaultOperationValidator(options...),
+		documents: map[string]*document{},
+	}
+}
+
+// Serve handles messages from in and writes responses as well as notifications to out.
+// Serve returns once the client sends the exit notification or closes in.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.writer = &messageWriter{out: out}
+	reader := bufio.NewReader(in)
+
+	for {
+		content, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(content, &req); err != nil {
+			if err := s.writer.write(errorResponse{JSONRPC: "2.0", Error: responseError{Code: errorCodeParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	var (
+		result interface{}
+		err    error
+	)
+
+	switch req.Method {
+	case "initialize":
+		result = initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncKindFull,
+				CompletionProvider: completionOptions{
+					TriggerCharacters: []string{"{", "(", "."},
+				},
+				HoverProvider:              true,
+				DefinitionProvider:         true,
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: serverInfo{
+				Name: serverName,
+			},
+		}
+	case "shutdown":
+		result = nil
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			return s.updateDocument(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err = json.Unmarshal(req.Params, &params); err == nil && len(params.ContentChanges) != 0 {
+			// the server only supports full document sync, so the last change contains the whole document
+			return s.updateDocument(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			delete(s.documents, params.TextDocument.URI)
+			return s.publishDiagnostics(params.TextDocument.URI, []Diagnostic{})
+		}
+	case "textDocument/completion":
+		result, err = s.withDocumentPosition(req, func(doc *document, pos Position) interface{} {
+			return s.completion(doc, pos)
+		})
+	case "textDocument/hover":
+		result, err = s.withDocumentPosition(req, func(doc *document, pos Position) interface{} {
+			if hover := s.hover(doc, pos); hover != nil {
+				return hover
+			}
+			return nil
+		})
+	case "textDocument/definition":
+		result, err = s.withDocumentPosition(req, func(doc *document, pos Position) interface{} {
+			if location := s.definition(doc, pos); location != nil {
+				return location
+			}
+			return nil
+		})
+	case "textDocument/formatting":
+		var params documentFormattingParams
+		if err = json.Unmarshal(req.Params, &params); err == nil {
+			result = []TextEdit{}
+			if doc, exists := s.documents[params.TextDocument.URI]; exists {
+				result = s.formatting(doc, params.Options)
+			}
+		}
+	default:
+		if req.ID == nil {
+			// notifications which are not supported are ignored, e.g. initialized
+			return nil
+		}
+		return s.writer.write(errorResponse{JSONRPC: "2.0", ID: req.ID, Error: responseError{Code: errorCodeMethodNotFound, Message: "method not found: " + req.Method}})
+	}
+
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		return s.writer.write(errorResponse{JSONRPC: "2.0", ID: req.ID, Error: responseError{Code: errorCodeInvalidParams, Message: err.Error()}})
+	}
+	return s.writer.write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func (s *Server) withDocumentPosition(req request, handler func(doc *document, pos Position) interface{}) (interface{}, error) {
+	var params textDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, err
+	}
+	doc, exists := s.documents[params.TextDocument.URI]
+	if !exists {
+		return nil, nil
+	}
+	return handler(doc, params.Position), nil
+}
+
+func (s *Server) updateDocument(uri, content string) error {
+	doc := newDocument(uri, content)
+	s.documents[uri] = doc
+	return s.publishDiagnostics(uri, s.diagnostics(doc))
+}
+
+func (s *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) error {
+	return s.writer.write(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params: publishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: diagnostics,
+		},
+	})
+}
@@ -0,0 +1,369 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocumentURI = "file:///operations.graphql"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	schema, err := NewSchemaFromSDLFiles("./testdata/schema.graphql", "./testdata/node.graphql")
+	require.NoError(t, err)
+	return NewServer(schema)
+}
+
+// openDocument opens a document with a cursor marked by "|" and returns the position of the cursor
+func openDocument(t *testing.T, server *Server, content string) (*document, Position) {
+	t.Helper()
+	offset := strings.Index(content, "|")
+	content = strings.Replace(content, "|", "", 1)
+	doc := newDocument(testDocumentURI, content)
+	server.documents[testDocumentURI] = doc
+	if offset == -1 {
+		return doc, Position{}
+	}
+	return doc, doc.text.position(offset)
+}
+
+func completionLabels(items []CompletionItem) []string {
+	labels := make([]string, 0, len(items))
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	return labels
+}
+
+func TestServer_Completion(t *testing.T) {
+	run := func(content string, expectedLabels ...string) func(t *testing.T) {
+		return func(t *testing.T) {
+			server := newTestServer(t)
+			doc, pos := openDocument(t, server, content)
+			items := server.completion(doc, pos)
+			if len(expectedLabels) == 0 {
+				assert.Empty(t, items)
+				return
+			}
+			assert.Equal(t, expectedLabels, completionLabels(items))
+		}
+	}
+
+	t.Run("root fields", run(`{ | }`, "user", "users", "node", "__schema", "__type", "__typename"))
+	t.Run("root fields of named query", run("query Q {\n  |\n}", "user", "users", "node", "__schema", "__type", "__typename"))
+	t.Run("fields of nested type", run(`{ user(id: 1) { | } }`, "id", "name", "username", "friends", "__typename"))
+	t.Run("fields while typing", run(`{ user(id: 1) { na| } }`, "id", "name", "username", "friends", "__typename"))
+	t.Run("fields of unclosed selection set", run("{\n  user(id: 1) {\n    friends {\n      |", "id", "name", "username", "friends", "__typename"))
+	t.Run("fields after other fields", run(`{ user(id: 1) { id name friends { id } | } }`, "id", "name", "username", "friends", "__typename"))
+	t.Run("fields of interface", run(`{ node(id: 1) { | } }`, "id", "__typename"))
+	t.Run("fields of inline fragment", run(`{ node(id: 1) { ... on User { | } } }`, "id", "name", "username", "friends", "__typename"))
+	t.Run("fields of fragment definition", run(`fragment F on User { friends { | } }`, "id", "name", "username", "friends", "__typename"))
+	t.Run("arguments", run(`{ user(|) }`, "id", "locale"))
+	t.Run("unused arguments", run(`{ user(id: 1, |) { id } }`, "locale"))
+	t.Run("arguments while typing", run(`{ user(i|) { id } }`, "id", "locale"))
+	t.Run("fragment spread", run("{ user(id: 1) { ...| } }\nfragment UserFields on User { id }", "on", "UserFields"))
+	t.Run("type condition", run(`{ node(id: 1) { ... on U| { id } } }`, "Query", "User", "Node"))
+	t.Run("unknown field", run(`{ unknown { | } }`))
+	t.Run("outside of operation", run(`| { user { id } }`))
+
+	t.Run("items", func(t *testing.T) {
+		server := newTestServer(t)
+		doc, pos := openDocument(t, server, `{ user(id: 1) { | } }`)
+		items := server.completion(doc, pos)
+		require.Len(t, items, 5)
+		assert.Equal(t, CompletionItem{
+			Label:         "name",
+			Kind:          completionItemKindField,
+			Detail:        "String",
+			Documentation: &MarkupContent{Kind: markupKindMarkdown, Value: "The display name of the user."},
+		}, items[1])
+		assert.True(t, items[2].Deprecated)
+
+		doc, pos = openDocument(t, server, `{ user(|) }`)
+		items = server.completion(doc, pos)
+		require.Len(t, items, 2)
+		assert.Equal(t, CompletionItem{
+			Label:      "id",
+			Kind:       completionItemKindVariable,
+			Detail:     "ID!",
+			InsertText: "id: ",
+		}, items[0])
+	})
+}
+
+func TestServer_Hover(t *testing.T) {
+	server := newTestServer(t)
+
+	t.Run("field", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ us|er(id: 1) { name } }`)
+		assert.Equal(t, &Hover{
+			Contents: MarkupContent{Kind: markupKindMarkdown, Value: "```graphql\nQuery.user: User\n```\n\nReturns a user by id."},
+			Range:    &Range{Start: Position{Line: 0, Character: 2}, End: Position{Line: 0, Character: 6}},
+		}, server.hover(doc, pos))
+	})
+
+	t.Run("aliased field", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ user(id: 1) { displayName: name| } }`)
+		hover := server.hover(doc, pos)
+		require.NotNil(t, hover)
+		assert.Equal(t, "```graphql\nUser.name: String\n```\n\nThe display name of the user.", hover.Contents.Value)
+	})
+
+	t.Run("type condition", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ node(id: 1) { ... on Us|er { id } } }`)
+		hover := server.hover(doc, pos)
+		require.NotNil(t, hover)
+		assert.Equal(t, "```graphql\ntype User\n```\n\nA registered user.", hover.Contents.Value)
+	})
+
+	t.Run("fragment spread", func(t *testing.T) {
+		doc, pos := openDocument(t, server, "{ user(id: 1) { ...UserFie|lds } }\nfragment UserFields on User { id }")
+		hover := server.hover(doc, pos)
+		require.NotNil(t, hover)
+		assert.Equal(t, "```graphql\nfragment UserFields on User\n```", hover.Contents.Value)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ unkno|wn }`)
+		assert.Nil(t, server.hover(doc, pos))
+	})
+
+	t.Run("range counts utf-16 code units", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ user(id: "😀") { na|me } }`)
+		assert.Equal(t, Position{Line: 0, Character: 21}, pos)
+		hover := server.hover(doc, pos)
+		require.NotNil(t, hover)
+		assert.Equal(t, &Range{Start: Position{Line: 0, Character: 19}, End: Position{Line: 0, Character: 23}}, hover.Range)
+	})
+}
+
+func TestServer_Definition(t *testing.T) {
+	server := newTestServer(t)
+	schemaURI, err := fileURI("./testdata/schema.graphql")
+	require.NoError(t, err)
+	nodeURI, err := fileURI("./testdata/node.graphql")
+	require.NoError(t, err)
+
+	t.Run("fragment", func(t *testing.T) {
+		doc, pos := openDocument(t, server, "{ user(id: 1) { ...UserFie|lds } }\nfragment UserFields on User { id }")
+		assert.Equal(t, &Location{
+			URI:   testDocumentURI,
+			Range: Range{Start: Position{Line: 1, Character: 9}, End: Position{Line: 1, Character: 19}},
+		}, server.definition(doc, pos))
+	})
+
+	t.Run("type", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `fragment F on No|de { id }`)
+		assert.Equal(t, &Location{
+			URI:   nodeURI,
+			Range: Range{Start: Position{Line: 0, Character: 10}, End: Position{Line: 0, Character: 14}},
+		}, server.definition(doc, pos))
+	})
+
+	t.Run("field", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ user(id: 1) { friends| { id } } }`)
+		assert.Equal(t, &Location{
+			URI:   schemaURI,
+			Range: Range{Start: Position{Line: 13, Character: 2}, End: Position{Line: 13, Character: 9}},
+		}, server.definition(doc, pos))
+	})
+
+	t.Run("unknown fragment", func(t *testing.T) {
+		doc, pos := openDocument(t, server, `{ user(id: 1) { ...Unkn|own } }`)
+		assert.Nil(t, server.definition(doc, pos))
+	})
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	server := newTestServer(t)
+
+	t.Run("valid", func(t *testing.T) {
+		doc, _ := openDocument(t, server, `{ user(id: 1) { name } }`)
+		assert.Empty(t, server.diagnostics(doc))
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		doc, _ := openDocument(t, server, "{ user(id: 1) { name: }\n")
+		assert.Equal(t, []Diagnostic{
+			{
+				Range:    Range{Start: Position{Line: 0, Character: 22}, End: Position{Line: 0, Character: 23}},
+				Severity: diagnosticSeverityError,
+				Source:   diagnosticSource,
+				Message:  "unexpected token - got: RBRACE want one of: [IDENT]",
+			},
+			{
+				Range:    Range{Start: Position{Line: 0, Character: 23}, End: Position{Line: 0, Character: 23}},
+				Severity: diagnosticSeverityError,
+				Source:   diagnosticSource,
+				Message:  "unexpected token - got: EOF want one of: [RBRACE]",
+			},
+		}, server.diagnostics(doc))
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		doc, _ := openDocument(t, server, "{\n  user(id: true) {\n    name\n  }\n}")
+		assert.Equal(t, []Diagnostic{
+			{
+				Range:    Range{Start: Position{Line: 1, Character: 11}, End: Position{Line: 1, Character: 15}},
+				Severity: diagnosticSeverityError,
+				Source:   diagnosticSource,
+				Message:  `ID cannot represent a non-string and non-integer value: true`,
+			},
+		}, server.diagnostics(doc))
+	})
+
+	t.Run("validation errors after multi-byte characters", func(t *testing.T) {
+		doc, _ := openDocument(t, server, "{\n  user(id: \"😀\", locale: true) {\n    name\n  }\n}")
+		diagnostics := server.diagnostics(doc)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, Range{Start: Position{Line: 1, Character: 25}, End: Position{Line: 1, Character: 29}}, diagnostics[0].Range)
+	})
+
+	t.Run("validation errors without location", func(t *testing.T) {
+		doc, _ := openDocument(t, server, "{\n  user(id: 1) {\n    age\n  }\n}")
+		assert.Equal(t, []Diagnostic{
+			{
+				Severity: diagnosticSeverityError,
+				Source:   diagnosticSource,
+				Message:  "field: age not defined on type: User",
+			},
+		}, server.diagnostics(doc))
+	})
+}
+
+func TestServer_Formatting(t *testing.T) {
+	server := newTestServer(t)
+
+	doc, _ := openDocument(t, server, "query Q{user(id:1){name}}")
+	assert.Equal(t, []TextEdit{
+		{
+			Range:   Range{Start: Position{}, End: Position{Line: 0, Character: 25}},
+			NewText: "query Q {\n  user(id: 1) {\n    name\n  }\n}\n",
+		},
+	}, server.formatting(doc, formattingOptions{TabSize: 2, InsertSpaces: true}))
+
+	doc, _ = openDocument(t, server, "query Q {\n  user(id: 1) {\n    name\n  }\n}\n")
+	assert.Empty(t, server.formatting(doc, formattingOptions{TabSize: 2, InsertSpaces: true}))
+
+	doc, _ = openDocument(t, server, "query Q{user(id:1){name}}")
+	edits := server.formatting(doc, formattingOptions{TabSize: 4})
+	require.Len(t, edits, 1)
+	assert.Equal(t, "query Q {\n\tuser(id: 1) {\n\t\tname\n\t}\n}\n", edits[0].NewText)
+
+	doc, _ = openDocument(t, server, "# users\nquery Q{user(id:1){name # name\n}}")
+	edits = server.formatting(doc, formattingOptions{TabSize: 2, InsertSpaces: true})
+	require.Len(t, edits, 1)
+	assert.Equal(t, "# users\nquery Q {\n  user(id: 1) {\n    name # name\n  }\n}\n", edits[0].NewText)
+
+	doc, _ = openDocument(t, server, "query Q { user(")
+	assert.Empty(t, server.formatting(doc, formattingOptions{TabSize: 2, InsertSpaces: true}))
+}
+
+func TestServer_Serve(t *testing.T) {
+	server := newTestServer(t)
+
+	in := &bytes.Buffer{}
+	write := func(message string) {
+		_, _ = fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+	write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	write(`{"jsonrpc":"2.0","method":"initialized","params":{}}`)
+	write(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///a.graphql","text":"{ user(id: 1) { age } }"}}}`)
+	write(`{"jsonrpc":"2.0","id":2,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///a.graphql"},"position":{"line":0,"character":2}}}`)
+	write(`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///a.graphql"},"contentChanges":[{"text":"{ user(id: 1) { name } }"}]}}`)
+	write(`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///a.graphql"},"position":{"line":0,"character":0}}}`)
+	write(`{"jsonrpc":"2.0","id":4,"method":"unknown"}`)
+	write(`{"jsonrpc":"2.0","id":5,"method":"shutdown"}`)
+	write(`{"jsonrpc":"2.0","method":"exit"}`)
+	write(`{"jsonrpc":"2.0","id":6,"method":"shutdown"}`)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, server.Serve(in, out))
+
+	var messages []string
+	reader := bufio.NewReader(out)
+	for {
+		content, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		messages = append(messages, string(content))
+	}
+
+	require.Len(t, messages, 7)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"textDocumentSync":1,"completionProvider":{"triggerCharacters":["{","(","."]},"hoverProvider":true,"definitionProvider":true,"documentFormattingProvider":true},"serverInfo":{"name":"graphql-go-tools"}}}`, messages[0])
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.graphql","diagnostics":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"severity":1,"source":"graphql","message":"field: age not defined on type: User"}]}}`, messages[1])
+
+	var completion struct {
+		ID     int              `json:"id"`
+		Result []CompletionItem `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(messages[2]), &completion))
+	assert.Equal(t, 2, completion.ID)
+	assert.Equal(t, []string{"user", "users", "node", "__schema", "__type", "__typename"}, completionLabels(completion.Result))
+
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.graphql","diagnostics":[]}}`, messages[3])
+	assert.Equal(t, `{"jsonrpc":"2.0","id":3,"result":null}`, messages[4])
+	assert.Equal(t, `{"jsonrpc":"2.0","id":4,"error":{"code":-32601,"message":"method not found: unknown"}}`, messages[5])
+	assert.Equal(t, `{"jsonrpc":"2.0","id":5,"result":null}`, messages[6])
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("introspection", func(t *testing.T) {
+		schema, err := NewSchemaFromIntrospectionFile("./testdata/introspection.json")
+		require.NoError(t, err)
+		assert.Empty(t, schema.locations)
+
+		server := NewServer(schema)
+		doc, pos := openDocument(t, server, `{ user(id: 1) { | } }`)
+		assert.Equal(t, []string{"id", "name", "username", "friends", "__typename"}, completionLabels(server.completion(doc, pos)))
+
+		doc, _ = openDocument(t, server, `{ user(id: 1) { name friends { id } } }`)
+		assert.Empty(t, server.diagnostics(doc))
+	})
+
+	t.Run("federation", func(t *testing.T) {
+		schema, err := NewFederatedSchemaFromSDLFiles("./testdata/accounts.graphql", "./testdata/reviews.graphql")
+		require.NoError(t, err)
+
+		server := NewServer(schema)
+		doc, pos := openDocument(t, server, `{ me { | } }`)
+		assert.Equal(t, []string{"id", "username", "reviews", "__typename"}, completionLabels(server.completion(doc, pos)))
+
+		doc, pos = openDocument(t, server, `{ me { revie|ws { body } } }`)
+		location := server.definition(doc, pos)
+		require.NotNil(t, location)
+		reviewsURI, err := fileURI("./testdata/reviews.graphql")
+		require.NoError(t, err)
+		assert.Equal(t, reviewsURI, location.URI)
+	})
+
+	t.Run("type extensions", func(t *testing.T) {
+		schema, err := NewSchemaFromSDLFiles("./testdata/schema.graphql", "./testdata/node.graphql", "./testdata/extension.graphql")
+		require.NoError(t, err)
+
+		server := NewServer(schema)
+		doc, pos := openDocument(t, server, `{ | }`)
+		assert.Equal(t, []string{"user", "users", "node", "__schema", "__type", "__typename", "viewer"}, completionLabels(server.completion(doc, pos)))
+
+		doc, _ = openDocument(t, server, `{ viewer { name } }`)
+		assert.Empty(t, server.diagnostics(doc))
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		_, err := NewSchemaFromSDLFiles("./testdata/node.graphql", "./testdata/node.graphql")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewSchemaFromSDLFiles("./testdata/missing.graphql")
+		assert.Error(t, err)
+	})
+}
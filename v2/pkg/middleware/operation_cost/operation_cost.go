@@ -0,0 +1,629 @@
+/*
+Package operation_cost calculates the cost of GraphQL operations following the GraphQL Cost Directive specification.
+
+The cost of an operation is the sum of the costs of its fields, the calculation can be influenced with two directives:
+
+  - directive @cost(weight: String!) on ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | OBJECT | SCALAR
+  - directive @listSize(assumedSize: Int, slicingArguments: [String!], sizedFields: [String!], requireOneSlicingArgument: Boolean = true) on FIELD_DEFINITION
+
+cost:
+The weight of a field replaces the weight of its type. Without @cost, object, interface and union types have a weight of 1,
+scalars and enums a weight of 0. Arguments, input fields and enum values add their weight when they are used in an argument.
+
+listSize:
+Estimates the size of the list returned by a field. The size is the value of the slicing argument, e.g. first or last,
+or the assumed size if no slicing argument is used. With sizedFields the size applies to the named child fields instead,
+e.g. the edges of a connection.
+
+The estimated cost is calculated statically from the operation, the fields of a list are counted with the estimated size of the list.
+The actual cost is calculated from the response data, the fields of a list are counted for every item of the list.
+*/
+package operation_cost
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+const (
+	CostDirectiveName     = "cost"
+	ListSizeDirectiveName = "listSize"
+
+	typeNameFieldName                = "__typename"
+	slicingArgumentPathSeparator     = "."
+	defaultCompositeTypeWeight       = 1.0
+	defaultLeafTypeWeight            = 0.0
+	defaultRequireOneSlicingArgument = true
+	defaultListSize                  = 1
+)
+
+var (
+	costDirectiveName                = []byte(CostDirectiveName)
+	listSizeDirectiveName            = []byte(ListSizeDirectiveName)
+	weightArgName                    = []byte("weight")
+	assumedSizeArgName               = []byte("assumedSize")
+	slicingArgumentsArgName          = []byte("slicingArguments")
+	sizedFieldsArgName               = []byte("sizedFields")
+	requireOneSlicingArgumentArgName = []byte("requireOneSlicingArgument")
+)
+
+// Config configures the cost calculation
+type Config struct {
+	// DefaultListSize is the estimated size of lists without a slicing argument or assumed size, defaults to 1
+	DefaultListSize int
+}
+
+type OperationCost struct {
+	Cost         int
+	PerRootField []RootFieldCost
+}
+
+type RootFieldCost struct {
+	TypeName  string
+	FieldName string
+	Alias     string
+	Cost      int
+}
+
+// EstimateCost calculates the estimated cost of the operation with the given name or of all operations if the name is empty.
+// Slicing arguments are read from the arguments of the operation and the variables of the operation document.
+func EstimateCost(operation, definition *ast.Document, operationName string, config Config, report *operationreport.Report) OperationCost {
+	if config.DefaultListSize == 0 {
+		config.DefaultListSize = defaultListSize
+	}
+
+	c := calculator{
+		operation:  operation,
+		definition: definition,
+		config:     config,
+		report:     report,
+	}
+	return c.operationCost(operationName, func(field int, enclosingType ast.Node) float64 {
+		return c.estimateField(field, enclosingType, sizedFields{})
+	})
+}
+
+// ActualCost calculates the cost of the operation with the given name or of all operations if the name is empty
+// from the data of its response, e.g. {"user":{"name":"Jens"}}.
+func ActualCost(operation, definition *ast.Document, operationName string, data []byte, report *operationreport.Report) OperationCost {
+	c := calculator{
+		operation:  operation,
+		definition: definition,
+		report:     report,
+	}
+	counted := map[string]struct{}{}
+	return c.operationCost(operationName, func(field int, enclosingType ast.Node) float64 {
+		return c.actualField(field, enclosingType, data, counted)
+	})
+}
+
+type calculator struct {
+	operation, definition *ast.Document
+	config                Config
+	report                *operationreport.Report
+}
+
+// sizedFields are the fields of a selection set whose list size is determined by the slicing arguments of the parent field
+type sizedFields struct {
+	names []string
+	size  float64
+}
+
+func (s sizedFields) contains(fieldName string) bool {
+	for i := range s.names {
+		if s.names[i] == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *calculator) operationCost(operationName string, rootFieldCost func(field int, enclosingType ast.Node) float64) (result OperationCost) {
+	var cost float64
+	for _, rootNode := range c.operation.RootNodes {
+		if rootNode.Kind != ast.NodeKindOperationDefinition {
+			continue
+		}
+		if operationName != "" && c.operation.OperationDefinitionNameString(rootNode.Ref) != operationName {
+			continue
+		}
+		operationDefinition := c.operation.OperationDefinitions[rootNode.Ref]
+		if !operationDefinition.HasSelections {
+			continue
+		}
+		rootType, ok := c.rootOperationType(operationDefinition.OperationType)
+		if !ok {
+			continue
+		}
+
+		c.eachField(operationDefinition.SelectionSet, rootType, func(field int, enclosingType ast.Node) {
+			fieldCost := rootFieldCost(field, enclosingType)
+			cost += fieldCost
+
+			fieldName := c.operation.FieldNameString(field)
+			if fieldName == typeNameFieldName {
+				return
+			}
+			alias := c.operation.FieldAliasOrNameString(field)
+			if alias == fieldName {
+				alias = ""
+			}
+			result.PerRootField = append(result.PerRootField, RootFieldCost{
+				TypeName:  c.definition.NodeNameString(enclosingType),
+				FieldName: fieldName,
+				Alias:     alias,
+				Cost:      costToInt(fieldCost),
+			})
+		})
+	}
+
+	result.Cost = costToInt(cost)
+	return result
+}
+
+// costToInt rounds the cost up, costs beyond the range of int are capped to not overflow,
+// e.g. the estimated cost of nested lists with huge slicing arguments
+func costToInt(cost float64) int {
+	cost = math.Ceil(cost)
+	switch {
+	case cost >= math.MaxInt || math.IsNaN(cost):
+		return math.MaxInt
+	case cost <= math.MinInt:
+		return math.MinInt
+	}
+	return int(cost)
+}
+
+func (c *calculator) rootOperationType(operationType ast.OperationType) (ast.Node, bool) {
+	var typeName ast.ByteSlice
+	switch operationType {
+	case ast.OperationTypeQuery:
+		typeName = c.definition.Index.QueryTypeName
+	case ast.OperationTypeMutation:
+		typeName = c.definition.Index.MutationTypeName
+	case ast.OperationTypeSubscription:
+		typeName = c.definition.Index.SubscriptionTypeName
+	}
+	if len(typeName) == 0 {
+		return ast.Node{}, false
+	}
+	return c.definition.Index.FirstNodeByNameBytes(typeName)
+}
+
+// eachField calls visitField for the root fields of an operation, including the fields of fragments
+func (c *calculator) eachField(selectionSet int, enclosingType ast.Node, visitField func(field int, enclosingType ast.Node)) {
+	c.eachSelection(selectionSet, enclosingType, visitField, func(selectionSet int, typeCondition ast.Node) {
+		c.eachField(selectionSet, typeCondition, visitField)
+	})
+}
+
+// eachSelection calls visitField for the fields and visitFragment for the inline fragments and fragment spreads
+// of the selection set, fragments without a type condition have the enclosing type as type condition
+func (c *calculator) eachSelection(selectionSet int, enclosingType ast.Node, visitField func(field int, enclosingType ast.Node), visitFragment func(selectionSet int, typeCondition ast.Node)) {
+	for _, selectionRef := range c.operation.SelectionSets[selectionSet].SelectionRefs {
+		selection := c.operation.Selections[selectionRef]
+		switch selection.Kind {
+		case ast.SelectionKindField:
+			visitField(selection.Ref, enclosingType)
+		case ast.SelectionKindInlineFragment:
+			inlineFragmentSelectionSet, ok := c.operation.InlineFragmentSelectionSet(selection.Ref)
+			if !ok {
+				continue
+			}
+			typeCondition := enclosingType
+			if c.operation.InlineFragmentHasTypeCondition(selection.Ref) {
+				if typeCondition, ok = c.definition.Index.FirstNodeByNameStr(c.operation.InlineFragmentTypeConditionNameString(selection.Ref)); !ok {
+					continue
+				}
+			}
+			visitFragment(inlineFragmentSelectionSet, typeCondition)
+		case ast.SelectionKindFragmentSpread:
+			fragment, exists := c.operation.FragmentDefinitionRef(c.operation.FragmentSpreadNameBytes(selection.Ref))
+			if !exists {
+				continue
+			}
+			typeCondition, ok := c.definition.Index.FirstNodeByNameStr(c.operation.FragmentDefinitionTypeNameString(fragment))
+			if !ok {
+				continue
+			}
+			visitFragment(c.operation.FragmentDefinitions[fragment].SelectionSet, typeCondition)
+		}
+	}
+}
+
+// estimateSelectionSet sums the costs of the fields of the selection set. Fragments on different types exclude each other,
+// so only the most expensive type condition is counted.
+func (c *calculator) estimateSelectionSet(selectionSet int, enclosingType ast.Node, sized sizedFields) float64 {
+	var (
+		cost          float64
+		fragmentCosts = map[string]float64{}
+	)
+	c.eachSelection(selectionSet, enclosingType, func(field int, enclosingType ast.Node) {
+		cost += c.estimateField(field, enclosingType, sized)
+	}, func(selectionSet int, typeCondition ast.Node) {
+		fragmentCost := c.estimateSelectionSet(selectionSet, typeCondition, sized)
+		if typeCondition == enclosingType {
+			cost += fragmentCost
+			return
+		}
+		fragmentCosts[c.definition.NodeNameString(typeCondition)] += fragmentCost
+	})
+
+	var maxFragmentCost float64
+	for _, fragmentCost := range fragmentCosts {
+		maxFragmentCost = math.Max(maxFragmentCost, fragmentCost)
+	}
+	return cost + maxFragmentCost
+}
+
+func (c *calculator) estimateField(field int, enclosingType ast.Node, sized sizedFields) float64 {
+	fieldDefinition, ok := c.fieldDefinition(field, enclosingType)
+	if !ok {
+		return 0
+	}
+
+	multiplier := 1.0
+	listSize, childSized := c.estimateListSize(field, fieldDefinition, enclosingType)
+	switch {
+	case sized.contains(c.operation.FieldNameString(field)):
+		multiplier = sized.size
+	case c.definition.TypeIsList(c.definition.FieldDefinitions[fieldDefinition].Type):
+		multiplier = listSize
+	}
+
+	cost := c.typeWeight(fieldDefinition)
+	if selectionSet, ok := c.operation.FieldSelectionSet(field); ok {
+		if returnType, ok := c.returnType(fieldDefinition); ok {
+			cost += c.estimateSelectionSet(selectionSet, returnType, childSized)
+		}
+	}
+
+	return c.argumentsCost(field, fieldDefinition) + multiplier*cost
+}
+
+// estimateListSize returns the estimated size of the list returned by the field,
+// or the sized fields of its selection set if the @listSize directive of the field has sized fields
+func (c *calculator) estimateListSize(field, fieldDefinition int, enclosingType ast.Node) (size float64, sized sizedFields) {
+	size = float64(c.config.DefaultListSize)
+	directive, ok := c.definition.FieldDefinitionDirectiveByName(fieldDefinition, listSizeDirectiveName)
+	if !ok {
+		return size, sized
+	}
+
+	if value, ok := c.definition.DirectiveArgumentValueByName(directive, assumedSizeArgName); ok && value.Kind == ast.ValueKindInteger {
+		size = math.Max(0, float64(c.definition.IntValueAsInt(value.Ref)))
+	}
+
+	slicingArguments := c.stringListArgument(directive, slicingArgumentsArgName)
+	// negative slicing arguments count as 0, so they can't lower the cost of the operation
+	slicingArgumentSize, usedSlicingArguments := 0.0, 0
+	for _, slicingArgument := range slicingArguments {
+		path := strings.Split(slicingArgument, slicingArgumentPathSeparator)
+		argument, ok := c.operation.FieldArgument(field, []byte(path[0]))
+		if !ok {
+			continue
+		}
+		if argumentSize, ok := c.intValue(c.operation.ArgumentValue(argument), path[1:]); ok {
+			usedSlicingArguments++
+			slicingArgumentSize = math.Max(slicingArgumentSize, float64(argumentSize))
+		}
+	}
+
+	requireOneSlicingArgument := defaultRequireOneSlicingArgument
+	if value, ok := c.definition.DirectiveArgumentValueByName(directive, requireOneSlicingArgumentArgName); ok && value.Kind == ast.ValueKindBoolean {
+		requireOneSlicingArgument = bool(c.definition.BooleanValue(value.Ref))
+	}
+	if len(slicingArguments) != 0 && requireOneSlicingArgument && usedSlicingArguments != 1 {
+		c.report.AddExternalError(operationreport.ErrListSizeSlicingArguments(
+			c.definition.NodeNameString(enclosingType)+"."+c.definition.FieldDefinitionNameString(fieldDefinition),
+			slicingArguments,
+			c.operation.Fields[field].Position,
+		))
+	}
+	if usedSlicingArguments != 0 {
+		size = slicingArgumentSize
+	}
+
+	if sizedFieldNames := c.stringListArgument(directive, sizedFieldsArgName); len(sizedFieldNames) != 0 {
+		return float64(c.config.DefaultListSize), sizedFields{names: sizedFieldNames, size: size}
+	}
+	return size, sized
+}
+
+// actualField calculates the cost of the field for the given object of the response data
+func (c *calculator) actualField(field int, enclosingType ast.Node, data []byte, counted map[string]struct{}) float64 {
+	responseKey := c.operation.FieldAliasOrNameString(field)
+	if _, ok := counted[responseKey]; ok {
+		return 0
+	}
+	counted[responseKey] = struct{}{}
+
+	fieldDefinition, ok := c.fieldDefinition(field, enclosingType)
+	if !ok {
+		return 0
+	}
+	value, dataType, _, err := jsonparser.Get(data, responseKey)
+	if err != nil {
+		return 0
+	}
+
+	return c.argumentsCost(field, fieldDefinition) + c.actualValue(field, fieldDefinition, value, dataType)
+}
+
+// actualValue counts the field for every item of a list value
+func (c *calculator) actualValue(field, fieldDefinition int, value []byte, dataType jsonparser.ValueType) (cost float64) {
+	switch dataType {
+	case jsonparser.Null:
+		return 0
+	case jsonparser.Array:
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemDataType jsonparser.ValueType, _ int, _ error) {
+			cost += c.actualValue(field, fieldDefinition, item, itemDataType)
+		})
+		return cost
+	case jsonparser.Object:
+		cost = c.typeWeight(fieldDefinition)
+		selectionSet, ok := c.operation.FieldSelectionSet(field)
+		if !ok {
+			return cost
+		}
+		returnType, ok := c.returnType(fieldDefinition)
+		if !ok {
+			return cost
+		}
+		return cost + c.actualSelectionSet(selectionSet, returnType, value, map[string]struct{}{})
+	default:
+		return c.typeWeight(fieldDefinition)
+	}
+}
+
+// actualSelectionSet sums the costs of the fields of the selection set which are part of the object,
+// fragments are skipped if the __typename of the object doesn't match their type condition
+func (c *calculator) actualSelectionSet(selectionSet int, enclosingType ast.Node, data []byte, counted map[string]struct{}) (cost float64) {
+	typeName, _ := jsonparser.GetString(data, typeNameFieldName)
+	c.eachSelection(selectionSet, enclosingType, func(field int, enclosingType ast.Node) {
+		cost += c.actualField(field, enclosingType, data, counted)
+	}, func(selectionSet int, typeCondition ast.Node) {
+		if typeName != "" && !c.typeConditionMatches(typeCondition, typeName) {
+			return
+		}
+		cost += c.actualSelectionSet(selectionSet, typeCondition, data, counted)
+	})
+	return cost
+}
+
+func (c *calculator) typeConditionMatches(typeCondition ast.Node, typeName string) bool {
+	if c.definition.NodeNameString(typeCondition) == typeName {
+		return true
+	}
+	objectType, ok := c.definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return false
+	}
+	switch typeCondition.Kind {
+	case ast.NodeKindInterfaceTypeDefinition:
+		return c.definition.NodeImplementsInterface(objectType, c.definition.NodeNameBytes(typeCondition))
+	case ast.NodeKindUnionTypeDefinition:
+		return c.definition.NodeIsUnionMember(objectType, typeCondition)
+	default:
+		return false
+	}
+}
+
+func (c *calculator) fieldDefinition(field int, enclosingType ast.Node) (int, bool) {
+	return c.definition.NodeFieldDefinitionByName(enclosingType, c.operation.FieldNameBytes(field))
+}
+
+func (c *calculator) returnType(fieldDefinition int) (ast.Node, bool) {
+	return c.definition.Index.FirstNodeByNameBytes(c.definition.ResolveTypeNameBytes(c.definition.FieldDefinitions[fieldDefinition].Type))
+}
+
+// typeWeight returns the weight of the field or of its type if the field has no weight
+func (c *calculator) typeWeight(fieldDefinition int) float64 {
+	if weight, ok := c.weight(c.definition.FieldDefinitionDirectives(fieldDefinition)); ok {
+		return weight
+	}
+
+	returnType, ok := c.returnType(fieldDefinition)
+	if !ok {
+		return defaultLeafTypeWeight
+	}
+	if weight, ok := c.weight(c.definition.NodeDirectives(returnType)); ok {
+		return weight
+	}
+	switch returnType.Kind {
+	case ast.NodeKindObjectTypeDefinition, ast.NodeKindInterfaceTypeDefinition, ast.NodeKindUnionTypeDefinition:
+		return defaultCompositeTypeWeight
+	default:
+		return defaultLeafTypeWeight
+	}
+}
+
+// argumentsCost sums the weights of the arguments of the field and the weights of the input fields and enum values of their values
+func (c *calculator) argumentsCost(field, fieldDefinition int) (cost float64) {
+	for _, argument := range c.operation.FieldArguments(field) {
+		argumentDefinition, ok := c.argumentDefinition(fieldDefinition, c.operation.ArgumentNameBytes(argument))
+		if !ok {
+			continue
+		}
+		value := c.operation.ArgumentValue(argument)
+		if !c.isProvided(value) {
+			continue
+		}
+		if weight, ok := c.weight(c.definition.InputValueDefinitions[argumentDefinition].Directives.Refs); ok {
+			cost += weight
+		}
+		cost += c.inputValueCost(value, c.definition.InputValueDefinitions[argumentDefinition].Type)
+	}
+	return cost
+}
+
+func (c *calculator) argumentDefinition(fieldDefinition int, argumentName ast.ByteSlice) (int, bool) {
+	for _, ref := range c.definition.FieldDefinitions[fieldDefinition].ArgumentsDefinition.Refs {
+		if c.definition.InputValueDefinitionNameString(ref) == string(argumentName) {
+			return ref, true
+		}
+	}
+	return ast.InvalidRef, false
+}
+
+func (c *calculator) isProvided(value ast.Value) bool {
+	switch value.Kind {
+	case ast.ValueKindNull:
+		return false
+	case ast.ValueKindVariable:
+		_, dataType, _, err := jsonparser.Get(c.operation.Input.Variables, c.operation.VariableValueNameString(value.Ref))
+		return err == nil && dataType != jsonparser.Null
+	default:
+		return true
+	}
+}
+
+func (c *calculator) inputValueCost(value ast.Value, typeRef int) (cost float64) {
+	valueType, ok := c.definition.Index.FirstNodeByNameBytes(c.definition.ResolveTypeNameBytes(typeRef))
+	if !ok {
+		return 0
+	}
+
+	switch value.Kind {
+	case ast.ValueKindList:
+		for _, item := range c.operation.ListValues[value.Ref].Refs {
+			cost += c.inputValueCost(c.operation.Values[item], typeRef)
+		}
+	case ast.ValueKindObject:
+		for _, objectField := range c.operation.ObjectValues[value.Ref].Refs {
+			inputField, ok := c.definition.NodeInputFieldDefinitionByName(valueType, c.operation.ObjectFieldNameBytes(objectField))
+			if !ok || !c.isProvided(c.operation.ObjectFieldValue(objectField)) {
+				continue
+			}
+			cost += c.inputFieldCost(inputField) + c.inputValueCost(c.operation.ObjectFieldValue(objectField), c.definition.InputValueDefinitions[inputField].Type)
+		}
+	case ast.ValueKindEnum:
+		cost += c.enumValueCost(valueType, c.operation.EnumValueNameBytes(value.Ref))
+	case ast.ValueKindVariable:
+		variableValue, dataType, _, err := jsonparser.Get(c.operation.Input.Variables, c.operation.VariableValueNameString(value.Ref))
+		if err == nil {
+			cost += c.variableValueCost(variableValue, dataType, typeRef)
+		}
+	}
+	return cost
+}
+
+// variableValueCost is the inputValueCost of a JSON value of the variables
+func (c *calculator) variableValueCost(value []byte, dataType jsonparser.ValueType, typeRef int) (cost float64) {
+	valueType, ok := c.definition.Index.FirstNodeByNameBytes(c.definition.ResolveTypeNameBytes(typeRef))
+	if !ok {
+		return 0
+	}
+
+	switch dataType {
+	case jsonparser.Array:
+		_, _ = jsonparser.ArrayEach(value, func(item []byte, itemDataType jsonparser.ValueType, _ int, _ error) {
+			cost += c.variableValueCost(item, itemDataType, typeRef)
+		})
+	case jsonparser.Object:
+		_ = jsonparser.ObjectEach(value, func(key []byte, fieldValue []byte, fieldDataType jsonparser.ValueType, _ int) error {
+			inputField, ok := c.definition.NodeInputFieldDefinitionByName(valueType, key)
+			if !ok || fieldDataType == jsonparser.Null {
+				return nil
+			}
+			cost += c.inputFieldCost(inputField) + c.variableValueCost(fieldValue, fieldDataType, c.definition.InputValueDefinitions[inputField].Type)
+			return nil
+		})
+	case jsonparser.String:
+		if valueType.Kind == ast.NodeKindEnumTypeDefinition {
+			cost += c.enumValueCost(valueType, value)
+		}
+	}
+	return cost
+}
+
+func (c *calculator) inputFieldCost(inputField int) float64 {
+	weight, _ := c.weight(c.definition.InputValueDefinitions[inputField].Directives.Refs)
+	return weight
+}
+
+func (c *calculator) enumValueCost(enumType ast.Node, enumValueName ast.ByteSlice) float64 {
+	if enumType.Kind != ast.NodeKindEnumTypeDefinition {
+		return 0
+	}
+	for _, enumValue := range c.definition.EnumTypeDefinitions[enumType.Ref].EnumValuesDefinition.Refs {
+		if c.definition.EnumValueDefinitionNameString(enumValue) != string(enumValueName) {
+			continue
+		}
+		weight, _ := c.weight(c.definition.EnumValueDefinitionDirectives(enumValue))
+		return weight
+	}
+	return 0
+}
+
+// weight returns the weight of the @cost directive of the directives, the weight can be a string, int or float value
+func (c *calculator) weight(directives []int) (float64, bool) {
+	for _, directive := range directives {
+		if !c.definition.DirectiveNameBytes(directive).Equals(costDirectiveName) {
+			continue
+		}
+		value, ok := c.definition.DirectiveArgumentValueByName(directive, weightArgName)
+		if !ok {
+			return 0, false
+		}
+		switch value.Kind {
+		case ast.ValueKindString:
+			weight, err := strconv.ParseFloat(c.definition.StringValueContentString(value.Ref), 64)
+			return weight, err == nil
+		case ast.ValueKindInteger:
+			return float64(c.definition.IntValueAsInt(value.Ref)), true
+		case ast.ValueKindFloat:
+			return float64(c.definition.FloatValueAsFloat32(value.Ref)), true
+		}
+	}
+	return 0, false
+}
+
+// intValue returns the int value at the path of an argument value, e.g. the path ["first"] of {first: 10}
+func (c *calculator) intValue(value ast.Value, path []string) (int, bool) {
+	switch value.Kind {
+	case ast.ValueKindInteger:
+		if len(path) != 0 {
+			return 0, false
+		}
+		return int(c.operation.IntValueAsInt(value.Ref)), true
+	case ast.ValueKindObject:
+		if len(path) == 0 {
+			return 0, false
+		}
+		for _, objectField := range c.operation.ObjectValues[value.Ref].Refs {
+			if c.operation.ObjectFieldNameString(objectField) == path[0] {
+				return c.intValue(c.operation.ObjectFieldValue(objectField), path[1:])
+			}
+		}
+	case ast.ValueKindVariable:
+		keys := append([]string{c.operation.VariableValueNameString(value.Ref)}, path...)
+		intValue, err := jsonparser.GetInt(c.operation.Input.Variables, keys...)
+		return int(intValue), err == nil
+	}
+	return 0, false
+}
+
+func (c *calculator) stringListArgument(directive int, argumentName ast.ByteSlice) (values []string) {
+	value, ok := c.definition.DirectiveArgumentValueByName(directive, argumentName)
+	if !ok {
+		return nil
+	}
+	switch value.Kind {
+	case ast.ValueKindString:
+		return []string{c.definition.StringValueContentString(value.Ref)}
+	case ast.ValueKindList:
+		for _, item := range c.definition.ListValues[value.Ref].Refs {
+			if c.definition.Values[item].Kind == ast.ValueKindString {
+				values = append(values, c.definition.StringValueContentString(c.definition.Values[item].Ref))
+			}
+		}
+	}
+	return values
+}
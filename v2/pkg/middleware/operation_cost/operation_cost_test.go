@@ -0,0 +1,153 @@
+package operation_cost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/internal/pkg/unsafeparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+func TestEstimateCost(t *testing.T) {
+	run := func(operation, variables string, config Config, expectedCost int) func(t *testing.T) {
+		return func(t *testing.T) {
+			cost, report := estimate(t, operation, variables, config)
+			require.False(t, report.HasErrors(), report.Error())
+			assert.Equal(t, expectedCost, cost.Cost)
+		}
+	}
+
+	t.Run("list with slicing argument", run(`{ users(first: 10) { id name } }`, ``, Config{}, 10))
+	t.Run("nested lists and type weight", run(`{ users(first: 2) { address { city } friends { id } } }`, ``, Config{}, 12))
+	t.Run("sized fields", run(`{ usersConnection(first: 5) { totalCount edges { node { name } } } }`, ``, Config{}, 11))
+	t.Run("sized fields with nested slicing argument of a variable", run(`query ($input: PageInput) { usersConnection(input: $input) { edges { node { name } } } }`, `{"input":{"first":3}}`, Config{}, 7))
+	t.Run("field weight", run(`{ expensive { title } }`, ``, Config{}, 11))
+	t.Run("most expensive fragment of an abstract type", run(`{ search { ... on User { address { city } } ... on Report { title } } }`, ``, Config{}, 15))
+	t.Run("fragment spreads", run(`{ search { ...UserAddress } } fragment UserAddress on User { address { city } }`, ``, Config{}, 15))
+	t.Run("input field weight", run(`{ search(filter: {name: "a", fuzzy: true}) { __typename } }`, ``, Config{}, 8))
+	t.Run("input field weight of a variable", run(`query ($filter: SearchFilter) { search(filter: $filter) { __typename } }`, `{"filter":{"fuzzy":true}}`, Config{}, 8))
+	t.Run("enum value weight", run(`{ sortedUsers(order: SCORE) { id } }`, ``, Config{}, 6))
+	t.Run("enum value weight of a variable", run(`query ($order: Order) { sortedUsers(order: $order) { id } }`, `{"order":"SCORE"}`, Config{}, 6))
+	t.Run("argument weight", run(`{ sortedUsers(order: NAME, debug: true) { id } }`, ``, Config{}, 3))
+	t.Run("default list size", run(`{ allUsers { id } }`, ``, Config{}, 1))
+	t.Run("configured default list size", run(`{ allUsers { id } }`, ``, Config{DefaultListSize: 20}, 20))
+	t.Run("list of scalars", run(`{ tags }`, ``, Config{}, 0))
+	t.Run("negative slicing argument", run(`{ users(first: -5) { id address { city } } }`, ``, Config{}, 0))
+	t.Run("cost exceeding int is capped", run(`{ users(first: 2000000000) { friends(first: 2000000000) { friends(first: 2000000000) { id } } } }`, ``, Config{}, math.MaxInt))
+
+	t.Run("per root field", func(t *testing.T) {
+		cost, report := estimate(t, `{ a: users(first: 1) { id } expensive { title } }`, ``, Config{})
+		require.False(t, report.HasErrors())
+		assert.Equal(t, OperationCost{
+			Cost: 12,
+			PerRootField: []RootFieldCost{
+				{TypeName: "Query", FieldName: "users", Alias: "a", Cost: 1},
+				{TypeName: "Query", FieldName: "expensive", Cost: 11},
+			},
+		}, cost)
+	})
+
+	t.Run("requires one slicing argument", func(t *testing.T) {
+		for _, operation := range []string{`{ users { id } }`, `{ users(first: 1, last: 2) { id } }`} {
+			_, report := estimate(t, operation, ``, Config{})
+			require.Len(t, report.ExternalErrors, 1)
+			assert.Equal(t, `Field "Query.users" must use exactly one of the slicing arguments "first", "last".`, report.ExternalErrors[0].Message)
+		}
+	})
+}
+
+func TestActualCost(t *testing.T) {
+	run := func(operation, data string, expectedCost int) func(t *testing.T) {
+		return func(t *testing.T) {
+			definition := testDefinition(t)
+			op := unsafeparser.ParseGraphqlDocumentString(operation)
+			report := operationreport.Report{}
+
+			cost := ActualCost(&op, &definition, "", []byte(data), &report)
+			require.False(t, report.HasErrors())
+			assert.Equal(t, expectedCost, cost.Cost)
+		}
+	}
+
+	t.Run("resolved list lengths", run(`{ users(first: 10) { id address { city } friends { id } } }`,
+		`{"users":[{"id":"1","address":{"city":"Berlin"},"friends":[{"id":"2"}]},{"id":"3","address":null,"friends":[]}]}`, 5))
+	t.Run("fragments of the resolved types", run(`{ search { __typename ... on User { address { city } } ... on Report { title } } }`,
+		`{"search":[{"__typename":"User","address":{"city":"Berlin"}},{"__typename":"Report","title":"Q3"}]}`, 5))
+	t.Run("sized fields", run(`{ usersConnection(first: 100) { edges { node { name } } } }`,
+		`{"usersConnection":{"edges":[{"node":{"name":"Jens"}}]}}`, 3))
+}
+
+func estimate(t *testing.T, operation, variables string, config Config) (OperationCost, operationreport.Report) {
+	definition := testDefinition(t)
+	op := unsafeparser.ParseGraphqlDocumentString(operation)
+	op.Input.Variables = []byte(variables)
+	report := operationreport.Report{}
+
+	return EstimateCost(&op, &definition, "", config, &report), report
+}
+
+func testDefinition(t *testing.T) ast.Document {
+	definition := unsafeparser.ParseGraphqlDocumentString(costTestDefinition)
+	require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(&definition))
+	return definition
+}
+
+const costTestDefinition = `
+directive @cost(weight: String!) on ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | OBJECT | SCALAR
+directive @listSize(assumedSize: Int, slicingArguments: [String!], sizedFields: [String!], requireOneSlicingArgument: Boolean = true) on FIELD_DEFINITION
+
+type Query {
+	users(first: Int, last: Int): [User!]! @listSize(slicingArguments: ["first", "last"])
+	allUsers: [User!]!
+	usersConnection(first: Int, input: PageInput): UserConnection! @listSize(slicingArguments: ["first", "input.first"], sizedFields: ["edges"], requireOneSlicingArgument: false)
+	search(filter: SearchFilter): [SearchResult!]! @listSize(assumedSize: 5)
+	sortedUsers(order: Order, debug: Boolean @cost(weight: "1")): [User!]! @listSize(assumedSize: 2)
+	expensive: Report @cost(weight: "10")
+	tags: [String!]!
+}
+
+type User {
+	id: ID!
+	name: String!
+	address: Address
+	friends(first: Int): [User!]! @listSize(assumedSize: 3, slicingArguments: ["first"], requireOneSlicingArgument: false)
+}
+
+type Address @cost(weight: "2") {
+	city: String
+}
+
+type Report {
+	title: String @cost(weight: "0.5")
+}
+
+type UserConnection {
+	totalCount: Int!
+	edges: [UserEdge!]!
+}
+
+type UserEdge {
+	node: User!
+}
+
+union SearchResult = User | Report
+
+input SearchFilter {
+	name: String
+	fuzzy: Boolean @cost(weight: "3")
+}
+
+input PageInput {
+	first: Int
+}
+
+enum Order {
+	NAME
+	SCORE @cost(weight: "4")
+}
+`
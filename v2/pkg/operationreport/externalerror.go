@@ -2,6 +2,7 @@ package operationreport
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqlerrors"
@@ -27,6 +28,20 @@ const (
 	UnknownFieldOfInputObjectErrMsg         = `Field "%s" is not defined by type "%s".`
 	DuplicatedFieldInputObjectErrMsg        = `There can be only one input field named "%s".`
 	ValueIsNotAnInputObjectTypeErrMsg       = `Expected value of type "%s", found %s.`
+	OneOfInputObjectFieldCountErrMsg        = `OneOf Input Object "%s" must specify exactly one key.`
+	OneOfInputObjectNullFieldErrMsg         = `Field "%s.%s" must be non-null.`
+	OneOfInputObjectNullableVariableErrMsg  = `Variable "$%s" must be non-nullable to be used for OneOf Input Object "%s".`
+	InvalidScalarValueErrMsg                = `Expected value of type "%s", found %s; %s`
+	InvalidScalarVariableValueErrMsg        = `Variable "$%s" got invalid value %s%s; Expected type "%s".`
+	DeprecatedUsageErrMsg                   = `The %s "%s" is deprecated. %s`
+	DocumentTokenLimitExceededErrMsg        = `Document contains more than %d tokens, parsing aborted.`
+	MaxDepthExceededErrMsg                  = `%s has a depth of %d which exceeds the maximum depth of %d.`
+	MaxAliasesExceededErrMsg                = `%s has %d aliases which exceeds the maximum of %d aliases.`
+	MaxRootFieldsExceededErrMsg             = `%s has %d root fields which exceeds the maximum of %d root fields.`
+	MaxDirectivesPerFieldExceededErrMsg     = `Field "%s" has %d directives which exceeds the maximum of %d directives per field.`
+	IntrospectionDisabledErrMsg             = `Introspection is disabled, but the operation selects the field "%s".`
+	ListSizeSlicingArgumentsErrMsg          = `Field "%s" must use exactly one of the slicing arguments %s.`
+	EstimatedCostExceededErrMsg             = `The estimated cost of the operation is %d which exceeds the maximum cost of %d.`
 )
 
 type ExternalError struct {
@@ -193,6 +208,119 @@ func ErrUnknownFieldOfInputObject(objName, fieldName ast.ByteSlice, position pos
 	return err
 }
 
+func ErrOneOfInputObjectFieldCount(objName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectFieldCountErrMsg, objName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrInvalidScalarValue(value, scalarName ast.ByteSlice, reason string, position position.Position) (err ExternalError) {
+	if reason == "" {
+		err.Message = fmt.Sprintf(ValueIsNotAnInputObjectTypeErrMsg, scalarName, value)
+	} else {
+		err.Message = fmt.Sprintf(InvalidScalarValueErrMsg, scalarName, value, reason)
+	}
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrInvalidScalarVariableValue(variableName, value ast.ByteSlice, valuePath, scalarName, reason string, position position.Position) (err ExternalError) {
+	var at string
+	if valuePath != "" {
+		at = fmt.Sprintf(` at "%s"`, valuePath)
+	}
+	err.Message = fmt.Sprintf(InvalidScalarVariableValueErrMsg, variableName, value, at, scalarName)
+	if reason != "" {
+		err.Message += " " + reason
+	}
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrDeprecatedUsage(kind, coordinate, reason string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(DeprecatedUsageErrMsg, kind, coordinate, reason)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrDocumentTokenLimitExceeded(maxTokens int) (err ExternalError) {
+	err.Message = fmt.Sprintf(DocumentTokenLimitExceededErrMsg, maxTokens)
+	return err
+}
+
+func ErrIntrospectionDisabled(fieldName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(IntrospectionDisabledErrMsg, fieldName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrListSizeSlicingArguments(fieldCoordinate string, slicingArguments []string, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(ListSizeSlicingArgumentsErrMsg, fieldCoordinate, `"`+strings.Join(slicingArguments, `", "`)+`"`)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrEstimatedCostExceeded(estimatedCost, maxEstimatedCost int) (err ExternalError) {
+	err.Message = fmt.Sprintf(EstimatedCostExceededErrMsg, estimatedCost, maxEstimatedCost)
+	return err
+}
+
+// operationLabel names the operation in messages, anonymous operations have an empty name
+func operationLabel(operationName string) string {
+	if operationName == "" {
+		return "Anonymous operation"
+	}
+	return fmt.Sprintf(`Operation "%s"`, operationName)
+}
+
+func ErrMaxDepthExceeded(operationName string, depth, maxDepth int, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(MaxDepthExceededErrMsg, operationLabel(operationName), depth, maxDepth)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrMaxAliasesExceeded(operationName string, aliases, maxAliases int, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(MaxAliasesExceededErrMsg, operationLabel(operationName), aliases, maxAliases)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrMaxRootFieldsExceeded(operationName string, rootFields, maxRootFields int, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(MaxRootFieldsExceededErrMsg, operationLabel(operationName), rootFields, maxRootFields)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrMaxDirectivesPerFieldExceeded(fieldName ast.ByteSlice, directives, maxDirectives int, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(MaxDirectivesPerFieldExceededErrMsg, fieldName, directives, maxDirectives)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrOneOfInputObjectNullField(objName, fieldName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectNullFieldErrMsg, objName, fieldName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
+func ErrOneOfInputObjectNullableVariable(variableName, objName ast.ByteSlice, position position.Position) (err ExternalError) {
+	err.Message = fmt.Sprintf(OneOfInputObjectNullableVariableErrMsg, variableName, objName)
+	err.Locations = LocationsFromPosition(position)
+
+	return err
+}
+
 func ErrDuplicatedFieldInputObject(fieldName ast.ByteSlice, first, duplicated position.Position) (err ExternalError) {
 	err.Message = fmt.Sprintf(DuplicatedFieldInputObjectErrMsg, fieldName)
 
@@ -476,6 +604,66 @@ func ErrTypeBodyMustNotBeEmpty(definitionType, typeName string) (err ExternalErr
 	return err
 }
 
+func ErrFieldTypeMustBeOutputType(typeName, fieldName, fieldTypeName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the type of field '%s.%s' must be an output type but got '%s'", typeName, fieldName, fieldTypeName)
+	return err
+}
+
+func ErrInputValueTypeMustBeInputType(coordinate, inputValueTypeName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the type of '%s' must be an input type but got '%s'", coordinate, inputValueTypeName)
+	return err
+}
+
+func ErrCircularNonNullInputObjectReference(typeName, fieldPath string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the input object named '%s' references itself through a series of non-null fields: '%s'", typeName, fieldPath)
+	return err
+}
+
+func ErrDirectiveDefinitionReferencesItself(directiveName, coordinate string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the directive '@%s' must not reference itself but is used on '%s'", directiveName, coordinate)
+	return err
+}
+
+func ErrDirectiveDefinitionArgumentNameMustBeUnique(directiveName, argumentName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the argument '%s' can only be defined once on directive '@%s'", argumentName, directiveName)
+	return err
+}
+
+func ErrRootOperationTypeMustBeObjectType(operationType, typeName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the %s root operation type '%s' must be an object type", operationType, typeName)
+	return err
+}
+
+func ErrNameIsReserved(definitionType, name string) (err ExternalError) {
+	err.Message = fmt.Sprintf("the %s named '%s' must not begin with '__', which is reserved by GraphQL introspection", definitionType, name)
+	return err
+}
+
+func ErrImplementingFieldDoesNotDefineArgument(typeName, fieldName, argumentName, interfaceName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("field '%s.%s' does not define argument '%s' from interface '%s'", typeName, fieldName, argumentName, interfaceName)
+	return err
+}
+
+func ErrImplementingFieldArgumentTypeMismatch(typeName, fieldName, argumentName, argumentTypeName, interfaceName, interfaceArgumentTypeName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("argument '%s.%s(%s:)' has type '%s' but interface '%s' expects type '%s'", typeName, fieldName, argumentName, argumentTypeName, interfaceName, interfaceArgumentTypeName)
+	return err
+}
+
+func ErrImplementingFieldAdditionalArgumentMustBeOptional(typeName, fieldName, argumentName, interfaceName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("argument '%s.%s(%s:)' must be optional because it is not defined by interface '%s'", typeName, fieldName, argumentName, interfaceName)
+	return err
+}
+
+func ErrOneOfInputFieldMustBeNullable(typeName, fieldName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("OneOf input field '%s.%s' must be nullable", typeName, fieldName)
+	return err
+}
+
+func ErrOneOfInputFieldMustNotHaveDefaultValue(typeName, fieldName string) (err ExternalError) {
+	err.Message = fmt.Sprintf("OneOf input field '%s.%s' cannot have a default value", typeName, fieldName)
+	return err
+}
+
 func ErrEntityExtensionMustHaveKeyDirective(typeName string) (err ExternalError) {
 	err.Message = fmt.Sprintf("an extension of the entity named '%s' does not have a key directive", typeName)
 	return err
@@ -0,0 +1,133 @@
+package scalar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"time"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+const bigDecimalPattern = `^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`
+
+var bigDecimalRegexp = regexp.MustCompile(bigDecimalPattern)
+
+// DateTime is a scalar for date-time strings as defined by RFC 3339, e.g. "2023-10-18T16:00:00Z".
+func DateTime() Scalar {
+	coerce := func(value []byte) ([]byte, error) {
+		str, err := stringValue("DateTime", value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			return nil, fmt.Errorf("DateTime cannot represent an invalid date-time string: %s", value)
+		}
+		return value, nil
+	}
+
+	return Scalar{
+		ParseValue: func(value []byte) error {
+			_, err := coerce(value)
+			return err
+		},
+		Serialize: coerce,
+		JsonSchema: func(nonNull bool) graphqljsonschema.JsonSchema {
+			schema := graphqljsonschema.NewString(nonNull)
+			schema.Format = "date-time"
+			return schema
+		},
+	}
+}
+
+// Email is a scalar for plain email addresses without display name, e.g. "jane@example.com".
+func Email() Scalar {
+	coerce := func(value []byte) ([]byte, error) {
+		str, err := stringValue("Email", value)
+		if err != nil {
+			return nil, err
+		}
+		address, err := mail.ParseAddress(str)
+		if err != nil || address.Address != str {
+			return nil, fmt.Errorf("Email cannot represent an invalid email address: %s", value)
+		}
+		return value, nil
+	}
+
+	return Scalar{
+		ParseValue: func(value []byte) error {
+			_, err := coerce(value)
+			return err
+		},
+		Serialize: coerce,
+		JsonSchema: func(nonNull bool) graphqljsonschema.JsonSchema {
+			schema := graphqljsonschema.NewString(nonNull)
+			schema.Format = "email"
+			return schema
+		},
+	}
+}
+
+// BigDecimal is a scalar for arbitrary precision decimals.
+// Inputs must be strings, so that no precision is lost by clients. Resolved numbers are serialized as strings.
+func BigDecimal() Scalar {
+	return Scalar{
+		ParseValue: func(value []byte) error {
+			str, err := stringValue("BigDecimal", value)
+			if err != nil {
+				return err
+			}
+			if !bigDecimalRegexp.MatchString(str) {
+				return fmt.Errorf("BigDecimal cannot represent an invalid decimal string: %s", value)
+			}
+			return nil
+		},
+		Serialize: func(value []byte) ([]byte, error) {
+			decimal, dataType, _, err := jsonparser.Get(value)
+			if err != nil || (dataType != jsonparser.String && dataType != jsonparser.Number) || !bigDecimalRegexp.Match(decimal) {
+				return nil, fmt.Errorf("BigDecimal cannot represent value: %s", value)
+			}
+			if dataType == jsonparser.String {
+				return value, nil
+			}
+			return append(append([]byte(`"`), decimal...), '"'), nil
+		},
+		JsonSchema: func(nonNull bool) graphqljsonschema.JsonSchema {
+			schema := graphqljsonschema.NewString(nonNull)
+			schema.Pattern = bigDecimalPattern
+			return schema
+		},
+	}
+}
+
+// JSON is a scalar for arbitrary JSON values.
+func JSON() Scalar {
+	return Scalar{
+		ParseValue: func(value []byte) error {
+			if !json.Valid(value) {
+				return fmt.Errorf("JSON cannot represent value: %s", value)
+			}
+			return nil
+		},
+		Serialize: func(value []byte) ([]byte, error) {
+			if !json.Valid(value) {
+				return nil, fmt.Errorf("JSON cannot represent value: %s", value)
+			}
+			return value, nil
+		},
+		JsonSchema: func(_ bool) graphqljsonschema.JsonSchema {
+			return graphqljsonschema.NewAny()
+		},
+	}
+}
+
+func stringValue(scalarName string, value []byte) (string, error) {
+	var str string
+	if err := json.Unmarshal(value, &str); err != nil {
+		return "", fmt.Errorf("%s cannot represent a non string value: %s", scalarName, value)
+	}
+	return str, nil
+}
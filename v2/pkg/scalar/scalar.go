@@ -0,0 +1,100 @@
+// Package scalar provides a registry of custom scalars to coerce their input and output values.
+package scalar
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphqljsonschema"
+)
+
+// ErrInvalidValue can be returned by ParseValue or Serialize to reject a value without giving a reason.
+var ErrInvalidValue = errors.New("invalid value")
+
+// Scalar defines how values of a custom scalar are coerced.
+// All values are passed as JSON, literals of an operation are converted to JSON before they are parsed.
+type Scalar struct {
+	// ParseValue validates an input value of the scalar, e.g. an argument or a variable value.
+	// The message of the returned error is added to the validation error.
+	ParseValue func(value []byte) error
+	// Serialize coerces a resolved value of the scalar before it is written to the response.
+	Serialize func(value []byte) ([]byte, error)
+	// JsonSchema returns the JSON schema of the scalar. If nil, the scalar accepts any value.
+	JsonSchema func(nonNull bool) graphqljsonschema.JsonSchema
+}
+
+// ScalarRegistry holds the custom scalars of a schema.
+// Scalars which are not registered keep the default behaviour.
+// A nil ScalarRegistry has no scalars, all methods except Register are safe to call on it.
+type ScalarRegistry struct {
+	mu      sync.RWMutex
+	scalars map[string]Scalar
+}
+
+// NewScalarRegistry returns an empty ScalarRegistry.
+func NewScalarRegistry() *ScalarRegistry {
+	return &ScalarRegistry{
+		scalars: map[string]Scalar{},
+	}
+}
+
+// NewDefaultScalarRegistry returns a ScalarRegistry with the scalars DateTime, Email, BigDecimal and JSON.
+func NewDefaultScalarRegistry() *ScalarRegistry {
+	registry := NewScalarRegistry()
+	registry.Register("DateTime", DateTime())
+	registry.Register("Email", Email())
+	registry.Register("BigDecimal", BigDecimal())
+	registry.Register("JSON", JSON())
+	return registry
+}
+
+// Register adds a scalar to the registry, an already registered scalar with the same name is replaced.
+func (r *ScalarRegistry) Register(name string, scalar Scalar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.scalars == nil {
+		r.scalars = map[string]Scalar{}
+	}
+	r.scalars[name] = scalar
+}
+
+// Scalar returns the scalar registered for name.
+func (r *ScalarRegistry) Scalar(name string) (scalar Scalar, ok bool) {
+	if r == nil {
+		return Scalar{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	scalar, ok = r.scalars[name]
+	return scalar, ok
+}
+
+// ParseValue validates the JSON value of an input of the scalar name.
+// ok is false if no ParseValue func is registered for the scalar.
+func (r *ScalarRegistry) ParseValue(name string, value []byte) (ok bool, err error) {
+	scalar, exists := r.Scalar(name)
+	if !exists || scalar.ParseValue == nil {
+		return false, nil
+	}
+	return true, scalar.ParseValue(value)
+}
+
+// Serialize coerces the JSON value of the scalar name for the response.
+// ok is false if no Serialize func is registered for the scalar.
+func (r *ScalarRegistry) Serialize(name string, value []byte) (serialized []byte, ok bool, err error) {
+	scalar, exists := r.Scalar(name)
+	if !exists || scalar.Serialize == nil {
+		return nil, false, nil
+	}
+	serialized, err = scalar.Serialize(value)
+	return serialized, true, err
+}
+
+// ScalarJsonSchema is an implementation of graphqljsonschema.ScalarJsonSchemas.
+func (r *ScalarRegistry) ScalarJsonSchema(name string, nonNull bool) (schema graphqljsonschema.JsonSchema, ok bool) {
+	scalar, exists := r.Scalar(name)
+	if !exists || scalar.JsonSchema == nil {
+		return nil, false
+	}
+	return scalar.JsonSchema(nonNull), true
+}
@@ -0,0 +1,132 @@
+package scalar
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarRegistry(t *testing.T) {
+	t.Run("unregistered scalar", func(t *testing.T) {
+		registry := NewScalarRegistry()
+
+		ok, err := registry.ParseValue("DateTime", []byte(`"abc"`))
+		assert.False(t, ok)
+		assert.NoError(t, err)
+
+		_, ok, err = registry.Serialize("DateTime", []byte(`"abc"`))
+		assert.False(t, ok)
+		assert.NoError(t, err)
+
+		_, ok = registry.ScalarJsonSchema("DateTime", true)
+		assert.False(t, ok)
+	})
+
+	t.Run("nil registry", func(t *testing.T) {
+		var registry *ScalarRegistry
+
+		_, ok := registry.Scalar("DateTime")
+		assert.False(t, ok)
+		ok, err := registry.ParseValue("DateTime", []byte(`"abc"`))
+		assert.False(t, ok)
+		assert.NoError(t, err)
+	})
+
+	t.Run("register on zero value registry", func(t *testing.T) {
+		registry := &ScalarRegistry{}
+		registry.Register("Upper", Scalar{})
+
+		_, ok := registry.Scalar("Upper")
+		assert.True(t, ok)
+	})
+
+	t.Run("registered scalar", func(t *testing.T) {
+		registry := NewScalarRegistry()
+		registry.Register("Upper", Scalar{
+			ParseValue: func(value []byte) error {
+				return ErrInvalidValue
+			},
+		})
+
+		ok, err := registry.ParseValue("Upper", []byte(`"abc"`))
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, ErrInvalidValue)
+
+		_, ok, _ = registry.Serialize("Upper", []byte(`"abc"`))
+		assert.False(t, ok)
+	})
+}
+
+func TestDefaultScalars(t *testing.T) {
+	registry := NewDefaultScalarRegistry()
+
+	parse := func(t *testing.T, name, value string, expectedErr string) {
+		t.Helper()
+		ok, err := registry.ParseValue(name, []byte(value))
+		require.True(t, ok)
+		if expectedErr == "" {
+			assert.NoError(t, err)
+			return
+		}
+		assert.EqualError(t, err, expectedErr)
+	}
+
+	serialize := func(t *testing.T, name, value string, expected string, expectedErr string) {
+		t.Helper()
+		serialized, ok, err := registry.Serialize(name, []byte(value))
+		require.True(t, ok)
+		if expectedErr != "" {
+			assert.EqualError(t, err, expectedErr)
+			return
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(serialized))
+	}
+
+	t.Run("DateTime", func(t *testing.T) {
+		parse(t, "DateTime", `"2023-10-18T16:00:00Z"`, "")
+		parse(t, "DateTime", `"2023-10-18T16:00:00.123+02:00"`, "")
+		parse(t, "DateTime", `"2023-10-18"`, `DateTime cannot represent an invalid date-time string: "2023-10-18"`)
+		parse(t, "DateTime", `1697644800`, `DateTime cannot represent a non string value: 1697644800`)
+		serialize(t, "DateTime", `"2023-10-18T16:00:00Z"`, `"2023-10-18T16:00:00Z"`, "")
+		serialize(t, "DateTime", `"yesterday"`, "", `DateTime cannot represent an invalid date-time string: "yesterday"`)
+	})
+
+	t.Run("Email", func(t *testing.T) {
+		parse(t, "Email", `"jane@example.com"`, "")
+		parse(t, "Email", `"Jane <jane@example.com>"`, `Email cannot represent an invalid email address: "Jane <jane@example.com>"`)
+		parse(t, "Email", `"jane"`, `Email cannot represent an invalid email address: "jane"`)
+		serialize(t, "Email", `"jane@example.com"`, `"jane@example.com"`, "")
+	})
+
+	t.Run("BigDecimal", func(t *testing.T) {
+		parse(t, "BigDecimal", `"123456789012345678901234567890.123456789"`, "")
+		parse(t, "BigDecimal", `"-1.5e10"`, "")
+		parse(t, "BigDecimal", `1.5`, `BigDecimal cannot represent a non string value: 1.5`)
+		parse(t, "BigDecimal", `"1,5"`, `BigDecimal cannot represent an invalid decimal string: "1,5"`)
+		serialize(t, "BigDecimal", `12.50`, `"12.50"`, "")
+		serialize(t, "BigDecimal", `"12.50"`, `"12.50"`, "")
+		serialize(t, "BigDecimal", `true`, "", `BigDecimal cannot represent value: true`)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		parse(t, "JSON", `{"deep":[1,"abc",null]}`, "")
+		serialize(t, "JSON", `[1,2]`, `[1,2]`, "")
+	})
+
+	t.Run("json schema", func(t *testing.T) {
+		schema, ok := registry.ScalarJsonSchema("DateTime", false)
+		require.True(t, ok)
+		printed, err := json.Marshal(schema)
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":["string","null"],"format":"date-time"}`, string(printed))
+
+		schema, ok = registry.ScalarJsonSchema("BigDecimal", true)
+		require.True(t, ok)
+		printed, err = json.Marshal(schema)
+		require.NoError(t, err)
+		assert.Equal(t, `{"type":["string"],"pattern":"^-?(0|[1-9][0-9]*)(\\.[0-9]+)?([eE][+-]?[0-9]+)?$"}`, string(printed))
+	})
+}
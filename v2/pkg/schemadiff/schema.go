@@ -0,0 +1,342 @@
+package schemadiff
+
+import (
+	"strings"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+const (
+	kindScalar      = "SCALAR"
+	kindObject      = "OBJECT"
+	kindInterface   = "INTERFACE"
+	kindUnion       = "UNION"
+	kindEnum        = "ENUM"
+	kindInputObject = "INPUT_OBJECT"
+)
+
+var builtInScalars = map[string]struct{}{
+	"Int":     {},
+	"Float":   {},
+	"String":  {},
+	"Boolean": {},
+	"ID":      {},
+}
+
+var builtInDirectives = map[string]struct{}{
+	"include":     {},
+	"skip":        {},
+	"deprecated":  {},
+	"specifiedBy": {},
+	"oneOf":       {},
+}
+
+// schema is a flat view on the type system definitions and extensions of a document
+type schema struct {
+	document       *ast.Document
+	typeNames      []string
+	types          map[string]*typeDefinition
+	directiveNames []string
+	directives     map[string]*directiveDefinition
+	rootTypes      map[ast.OperationType]string
+}
+
+type typeDefinition struct {
+	name        string
+	kind        string
+	fields      []*fieldDefinition
+	inputFields []*inputValueDefinition
+	interfaces  []string
+	members     []string
+	enumValues  []*enumValueDefinition
+}
+
+type fieldDefinition struct {
+	name       string
+	typeRef    int
+	arguments  []*inputValueDefinition
+	deprecated bool
+}
+
+type inputValueDefinition struct {
+	name         string
+	typeRef      int
+	defaultValue string
+	hasDefault   bool
+}
+
+type enumValueDefinition struct {
+	name       string
+	deprecated bool
+}
+
+type directiveDefinition struct {
+	name       string
+	arguments  []*inputValueDefinition
+	locations  []string
+	repeatable bool
+}
+
+func newSchema(document *ast.Document) *schema {
+	s := &schema{
+		document:   document,
+		types:      map[string]*typeDefinition{},
+		directives: map[string]*directiveDefinition{},
+		rootTypes:  map[ast.OperationType]string{},
+	}
+
+	for _, node := range document.RootNodes {
+		switch node.Kind {
+		case ast.NodeKindSchemaDefinition:
+			s.addRootOperationTypes(document.SchemaDefinitions[node.Ref].RootOperationTypeDefinitions.Refs)
+		case ast.NodeKindSchemaExtension:
+			s.addRootOperationTypes(document.SchemaExtensions[node.Ref].RootOperationTypeDefinitions.Refs)
+		case ast.NodeKindObjectTypeDefinition:
+			s.addObjectType(document.ObjectTypeDefinitions[node.Ref])
+		case ast.NodeKindObjectTypeExtension:
+			s.addObjectType(document.ObjectTypeExtensions[node.Ref].ObjectTypeDefinition)
+		case ast.NodeKindInterfaceTypeDefinition:
+			s.addInterfaceType(document.InterfaceTypeDefinitions[node.Ref])
+		case ast.NodeKindInterfaceTypeExtension:
+			s.addInterfaceType(document.InterfaceTypeExtensions[node.Ref].InterfaceTypeDefinition)
+		case ast.NodeKindUnionTypeDefinition:
+			s.addUnionType(document.UnionTypeDefinitions[node.Ref])
+		case ast.NodeKindUnionTypeExtension:
+			s.addUnionType(document.UnionTypeExtensions[node.Ref].UnionTypeDefinition)
+		case ast.NodeKindEnumTypeDefinition:
+			s.addEnumType(document.EnumTypeDefinitions[node.Ref])
+		case ast.NodeKindEnumTypeExtension:
+			s.addEnumType(document.EnumTypeExtensions[node.Ref].EnumTypeDefinition)
+		case ast.NodeKindInputObjectTypeDefinition:
+			s.addInputObjectType(document.InputObjectTypeDefinitions[node.Ref])
+		case ast.NodeKindInputObjectTypeExtension:
+			s.addInputObjectType(document.InputObjectTypeExtensions[node.Ref].InputObjectTypeDefinition)
+		case ast.NodeKindScalarTypeDefinition:
+			s.typeDefinition(document.ScalarTypeDefinitionNameString(node.Ref), kindScalar)
+		case ast.NodeKindScalarTypeExtension:
+			s.typeDefinition(document.Input.ByteSliceString(document.ScalarTypeExtensions[node.Ref].Name), kindScalar)
+		case ast.NodeKindDirectiveDefinition:
+			s.addDirective(node.Ref)
+		}
+	}
+
+	if len(s.rootTypes) == 0 {
+		for operationType, name := range map[ast.OperationType]string{
+			ast.OperationTypeQuery:        string(ast.DefaultQueryTypeName),
+			ast.OperationTypeMutation:     string(ast.DefaultMutationTypeName),
+			ast.OperationTypeSubscription: string(ast.DefaultSubscriptionTypeName),
+		} {
+			if _, ok := s.types[name]; ok {
+				s.rootTypes[operationType] = name
+			}
+		}
+	}
+
+	return s
+}
+
+// typeDefinition returns the type of the given name, definitions and extensions of a type are merged into one typeDefinition
+func (s *schema) typeDefinition(name, kind string) *typeDefinition {
+	if definition, ok := s.types[name]; ok {
+		return definition
+	}
+	definition := &typeDefinition{
+		name: name,
+		kind: kind,
+	}
+	if !isIgnoredType(name) {
+		s.typeNames = append(s.typeNames, name)
+	}
+	s.types[name] = definition
+	return definition
+}
+
+// isIgnoredType reports whether a type is part of every schema, e.g. introspection types and built-in scalars
+func isIgnoredType(name string) bool {
+	if strings.HasPrefix(name, "__") {
+		return true
+	}
+	_, ok := builtInScalars[name]
+	return ok
+}
+
+func (s *schema) addRootOperationTypes(refs []int) {
+	for _, ref := range refs {
+		rootOperationType := s.document.RootOperationTypeDefinitions[ref]
+		s.rootTypes[rootOperationType.OperationType] = s.document.Input.ByteSliceString(rootOperationType.NamedType.Name)
+	}
+}
+
+func (s *schema) addObjectType(definition ast.ObjectTypeDefinition) {
+	typeDefinition := s.typeDefinition(s.document.Input.ByteSliceString(definition.Name), kindObject)
+	typeDefinition.interfaces = append(typeDefinition.interfaces, s.namedTypes(definition.ImplementsInterfaces.Refs)...)
+	typeDefinition.fields = append(typeDefinition.fields, s.fieldDefinitions(definition.FieldsDefinition.Refs)...)
+}
+
+func (s *schema) addInterfaceType(definition ast.InterfaceTypeDefinition) {
+	typeDefinition := s.typeDefinition(s.document.Input.ByteSliceString(definition.Name), kindInterface)
+	typeDefinition.interfaces = append(typeDefinition.interfaces, s.namedTypes(definition.ImplementsInterfaces.Refs)...)
+	typeDefinition.fields = append(typeDefinition.fields, s.fieldDefinitions(definition.FieldsDefinition.Refs)...)
+}
+
+func (s *schema) addUnionType(definition ast.UnionTypeDefinition) {
+	typeDefinition := s.typeDefinition(s.document.Input.ByteSliceString(definition.Name), kindUnion)
+	typeDefinition.members = append(typeDefinition.members, s.namedTypes(definition.UnionMemberTypes.Refs)...)
+}
+
+func (s *schema) addEnumType(definition ast.EnumTypeDefinition) {
+	typeDefinition := s.typeDefinition(s.document.Input.ByteSliceString(definition.Name), kindEnum)
+	for _, ref := range definition.EnumValuesDefinition.Refs {
+		enumValue := s.document.EnumValueDefinitions[ref]
+		typeDefinition.enumValues = append(typeDefinition.enumValues, &enumValueDefinition{
+			name:       s.document.EnumValueDefinitionNameString(ref),
+			deprecated: enumValue.HasDirectives && enumValue.Directives.HasDirectiveByName(s.document, "deprecated"),
+		})
+	}
+}
+
+func (s *schema) addInputObjectType(definition ast.InputObjectTypeDefinition) {
+	typeDefinition := s.typeDefinition(s.document.Input.ByteSliceString(definition.Name), kindInputObject)
+	typeDefinition.inputFields = append(typeDefinition.inputFields, s.inputValueDefinitions(definition.InputFieldsDefinition.Refs)...)
+}
+
+func (s *schema) addDirective(ref int) {
+	definition := s.document.DirectiveDefinitions[ref]
+	directive := &directiveDefinition{
+		name:       s.document.DirectiveDefinitionNameString(ref),
+		arguments:  s.inputValueDefinitions(definition.ArgumentsDefinition.Refs),
+		repeatable: definition.Repeatable.IsRepeatable,
+	}
+	iter := definition.DirectiveLocations.Iterable()
+	for iter.Next() {
+		directive.locations = append(directive.locations, iter.Value().LiteralString())
+	}
+	if _, ok := builtInDirectives[directive.name]; !ok {
+		s.directiveNames = append(s.directiveNames, directive.name)
+	}
+	s.directives[directive.name] = directive
+}
+
+func (s *schema) namedTypes(refs []int) (names []string) {
+	for _, ref := range refs {
+		names = append(names, s.document.ResolveTypeNameString(ref))
+	}
+	return names
+}
+
+func (s *schema) fieldDefinitions(refs []int) (fields []*fieldDefinition) {
+	for _, ref := range refs {
+		definition := s.document.FieldDefinitions[ref]
+		if strings.HasPrefix(s.document.FieldDefinitionNameString(ref), "__") {
+			// introspection fields are added to the query type of every schema
+			continue
+		}
+		fields = append(fields, &fieldDefinition{
+			name:       s.document.FieldDefinitionNameString(ref),
+			typeRef:    definition.Type,
+			arguments:  s.inputValueDefinitions(definition.ArgumentsDefinition.Refs),
+			deprecated: definition.HasDirectives && definition.Directives.HasDirectiveByName(s.document, "deprecated"),
+		})
+	}
+	return fields
+}
+
+func (s *schema) inputValueDefinitions(refs []int) (inputValues []*inputValueDefinition) {
+	for _, ref := range refs {
+		definition := s.document.InputValueDefinitions[ref]
+		inputValue := &inputValueDefinition{
+			name:       s.document.InputValueDefinitionNameString(ref),
+			typeRef:    definition.Type,
+			hasDefault: definition.DefaultValue.IsDefined,
+		}
+		if inputValue.hasDefault {
+			defaultValue, _ := s.document.PrintValueBytes(definition.DefaultValue.Value, nil)
+			inputValue.defaultValue = string(defaultValue)
+		}
+		inputValues = append(inputValues, inputValue)
+	}
+	return inputValues
+}
+
+func (s *schema) typeString(ref int) string {
+	out, _ := s.document.PrintTypeBytes(ref, nil)
+	return string(out)
+}
+
+func (s *schema) isRequired(inputValue *inputValueDefinition) bool {
+	return s.document.Types[inputValue.typeRef].TypeKind == ast.TypeKindNonNull && !inputValue.hasDefault
+}
+
+func (t *typeDefinition) field(name string) *fieldDefinition {
+	for _, field := range t.fields {
+		if field.name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func (t *typeDefinition) enumValue(name string) *enumValueDefinition {
+	for _, enumValue := range t.enumValues {
+		if enumValue.name == name {
+			return enumValue
+		}
+	}
+	return nil
+}
+
+func inputValue(inputValues []*inputValueDefinition, name string) *inputValueDefinition {
+	for _, inputValue := range inputValues {
+		if inputValue.name == name {
+			return inputValue
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isSafeOutputTypeChange reports whether clients can still handle the values of a field after the type has changed,
+// which is the case if the named type stays the same and the new type is at most stricter, e.g. String to String!
+func isSafeOutputTypeChange(oldDocument *ast.Document, oldRef int, newDocument *ast.Document, newRef int) bool {
+	oldType, newType := oldDocument.Types[oldRef], newDocument.Types[newRef]
+	switch {
+	case newType.TypeKind == ast.TypeKindNonNull && oldType.TypeKind == ast.TypeKindNonNull:
+		return isSafeOutputTypeChange(oldDocument, oldType.OfType, newDocument, newType.OfType)
+	case newType.TypeKind == ast.TypeKindNonNull:
+		return isSafeOutputTypeChange(oldDocument, oldRef, newDocument, newType.OfType)
+	case oldType.TypeKind == ast.TypeKindNonNull:
+		return false
+	case oldType.TypeKind == ast.TypeKindList && newType.TypeKind == ast.TypeKindList:
+		return isSafeOutputTypeChange(oldDocument, oldType.OfType, newDocument, newType.OfType)
+	case oldType.TypeKind == ast.TypeKindNamed && newType.TypeKind == ast.TypeKindNamed:
+		return oldDocument.Input.ByteSliceString(oldType.Name) == newDocument.Input.ByteSliceString(newType.Name)
+	}
+	return false
+}
+
+// isSafeInputTypeChange reports whether all values clients send for an input still are valid after the type has changed,
+// which is the case if the named type stays the same and the new type is at most less strict, e.g. String! to String
+func isSafeInputTypeChange(oldDocument *ast.Document, oldRef int, newDocument *ast.Document, newRef int) bool {
+	oldType, newType := oldDocument.Types[oldRef], newDocument.Types[newRef]
+	switch {
+	case oldType.TypeKind == ast.TypeKindNonNull && newType.TypeKind == ast.TypeKindNonNull:
+		return isSafeInputTypeChange(oldDocument, oldType.OfType, newDocument, newType.OfType)
+	case oldType.TypeKind == ast.TypeKindNonNull:
+		return isSafeInputTypeChange(oldDocument, oldType.OfType, newDocument, newRef)
+	case newType.TypeKind == ast.TypeKindNonNull:
+		return false
+	case oldType.TypeKind == ast.TypeKindList && newType.TypeKind == ast.TypeKindList:
+		return isSafeInputTypeChange(oldDocument, oldType.OfType, newDocument, newType.OfType)
+	case oldType.TypeKind == ast.TypeKindNamed && newType.TypeKind == ast.TypeKindNamed:
+		return oldDocument.Input.ByteSliceString(oldType.Name) == newDocument.Input.ByteSliceString(newType.Name)
+	}
+	return false
+}
@@ -0,0 +1,429 @@
+// Package schemadiff compares two GraphQL schemas and classifies each change as breaking, dangerous or safe.
+//
+// Changes are breaking if they can make valid operations invalid or break clients handling the response,
+// e.g. removing a field. Dangerous changes keep operations valid but can change the behaviour of clients,
+// e.g. adding an enum value. Usage recorded from client operations or plan.SchemaUsageInfo can be passed
+// to only report breaking changes which affect the schema coordinates clients actually use.
+package schemadiff
+
+import (
+	"fmt"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+)
+
+// Criticality describes the impact of a Change on clients
+type Criticality int
+
+const (
+	CriticalitySafe Criticality = iota
+	CriticalityDangerous
+	CriticalityBreaking
+)
+
+func (c Criticality) String() string {
+	switch c {
+	case CriticalitySafe:
+		return "SAFE"
+	case CriticalityDangerous:
+		return "DANGEROUS"
+	case CriticalityBreaking:
+		return "BREAKING"
+	}
+	return "UNKNOWN"
+}
+
+// ChangeType identifies the kind of a Change
+type ChangeType string
+
+const (
+	ChangeTypeTypeRemoved                    ChangeType = "TYPE_REMOVED"
+	ChangeTypeTypeAdded                      ChangeType = "TYPE_ADDED"
+	ChangeTypeTypeKindChanged                ChangeType = "TYPE_KIND_CHANGED"
+	ChangeTypeFieldRemoved                   ChangeType = "FIELD_REMOVED"
+	ChangeTypeFieldAdded                     ChangeType = "FIELD_ADDED"
+	ChangeTypeFieldTypeChanged               ChangeType = "FIELD_TYPE_CHANGED"
+	ChangeTypeFieldDeprecationAdded          ChangeType = "FIELD_DEPRECATION_ADDED"
+	ChangeTypeFieldDeprecationRemoved        ChangeType = "FIELD_DEPRECATION_REMOVED"
+	ChangeTypeArgumentRemoved                ChangeType = "ARGUMENT_REMOVED"
+	ChangeTypeArgumentAdded                  ChangeType = "ARGUMENT_ADDED"
+	ChangeTypeArgumentTypeChanged            ChangeType = "ARGUMENT_TYPE_CHANGED"
+	ChangeTypeArgumentDefaultValueChanged    ChangeType = "ARGUMENT_DEFAULT_VALUE_CHANGED"
+	ChangeTypeInputFieldRemoved              ChangeType = "INPUT_FIELD_REMOVED"
+	ChangeTypeInputFieldAdded                ChangeType = "INPUT_FIELD_ADDED"
+	ChangeTypeInputFieldTypeChanged          ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	ChangeTypeInputFieldDefaultValueChanged  ChangeType = "INPUT_FIELD_DEFAULT_VALUE_CHANGED"
+	ChangeTypeEnumValueRemoved               ChangeType = "ENUM_VALUE_REMOVED"
+	ChangeTypeEnumValueAdded                 ChangeType = "ENUM_VALUE_ADDED"
+	ChangeTypeEnumValueDeprecationAdded      ChangeType = "ENUM_VALUE_DEPRECATION_ADDED"
+	ChangeTypeEnumValueDeprecationRemoved    ChangeType = "ENUM_VALUE_DEPRECATION_REMOVED"
+	ChangeTypeUnionMemberRemoved             ChangeType = "UNION_MEMBER_REMOVED"
+	ChangeTypeUnionMemberAdded               ChangeType = "UNION_MEMBER_ADDED"
+	ChangeTypeInterfaceImplementationRemoved ChangeType = "INTERFACE_IMPLEMENTATION_REMOVED"
+	ChangeTypeInterfaceImplementationAdded   ChangeType = "INTERFACE_IMPLEMENTATION_ADDED"
+	ChangeTypeDirectiveRemoved               ChangeType = "DIRECTIVE_REMOVED"
+	ChangeTypeDirectiveAdded                 ChangeType = "DIRECTIVE_ADDED"
+	ChangeTypeDirectiveLocationRemoved       ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	ChangeTypeDirectiveLocationAdded         ChangeType = "DIRECTIVE_LOCATION_ADDED"
+	ChangeTypeDirectiveRepeatableRemoved     ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+	ChangeTypeDirectiveRepeatableAdded       ChangeType = "DIRECTIVE_REPEATABLE_ADDED"
+	ChangeTypeRootOperationTypeChanged       ChangeType = "ROOT_OPERATION_TYPE_CHANGED"
+	ChangeTypeRootOperationTypeAdded         ChangeType = "ROOT_OPERATION_TYPE_ADDED"
+	ChangeTypeRootOperationTypeRemoved       ChangeType = "ROOT_OPERATION_TYPE_REMOVED"
+)
+
+// Change is a single difference between two schemas
+type Change struct {
+	Type        ChangeType
+	Criticality Criticality
+	// Coordinate is the schema coordinate of the changed element, e.g. User, User.name, Query.user(id:), Role.ADMIN or @auth
+	Coordinate string
+	Message    string
+	// Unused is true if the change would be breaking, but the affected coordinate isn't used by any recorded operation.
+	// Such changes are reported as dangerous.
+	Unused bool
+
+	// usageCoordinate is the coordinate clients must use to be affected by the change
+	usageCoordinate string
+}
+
+// Diff is the list of changes between two schemas
+type Diff struct {
+	Changes []Change
+}
+
+// HasBreakingChanges reports whether the diff contains at least one breaking change
+func (d Diff) HasBreakingChanges() bool {
+	for i := range d.Changes {
+		if d.Changes[i].Criticality == CriticalityBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns all changes of the given criticality
+func (d Diff) Filter(criticality Criticality) (changes []Change) {
+	for i := range d.Changes {
+		if d.Changes[i].Criticality == criticality {
+			changes = append(changes, d.Changes[i])
+		}
+	}
+	return changes
+}
+
+type options struct {
+	usage *Usage
+}
+
+// Option configures Compare
+type Option func(options *options)
+
+// WithUsage only reports breaking changes of coordinates which are used according to the usage.
+// Breaking changes of unused coordinates are reported as dangerous and marked as Unused.
+func WithUsage(usage *Usage) Option {
+	return func(options *options) {
+		options.usage = usage
+	}
+}
+
+// Compare returns the changes from the old to the new schema.
+// Type extensions are merged into their types, introspection types, built-in scalars and built-in directives are ignored.
+func Compare(oldDefinition, newDefinition *ast.Document, opts ...Option) Diff {
+	var applied options
+	for _, opt := range opts {
+		opt(&applied)
+	}
+
+	d := differ{
+		oldSchema: newSchema(oldDefinition),
+		newSchema: newSchema(newDefinition),
+	}
+	d.compare()
+
+	if applied.usage != nil {
+		for i := range d.changes {
+			change := &d.changes[i]
+			if change.Criticality == CriticalityBreaking && !applied.usage.IsUsed(change.usageCoordinate) {
+				change.Criticality = CriticalityDangerous
+				change.Unused = true
+			}
+		}
+	}
+
+	return Diff{
+		Changes: d.changes,
+	}
+}
+
+type differ struct {
+	oldSchema *schema
+	newSchema *schema
+	changes   []Change
+}
+
+func (d *differ) report(changeType ChangeType, criticality Criticality, coordinate, usageCoordinate, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Type:            changeType,
+		Criticality:     criticality,
+		Coordinate:      coordinate,
+		Message:         fmt.Sprintf(format, args...),
+		usageCoordinate: usageCoordinate,
+	})
+}
+
+func (d *differ) compare() {
+	d.compareRootOperationTypes()
+
+	for _, name := range d.oldSchema.typeNames {
+		oldType := d.oldSchema.types[name]
+		newType, ok := d.newSchema.types[name]
+		if !ok {
+			d.report(ChangeTypeTypeRemoved, CriticalityBreaking, name, name, "Type '%s' was removed", name)
+			continue
+		}
+		if oldType.kind != newType.kind {
+			d.report(ChangeTypeTypeKindChanged, CriticalityBreaking, name, name, "Type '%s' changed from %s to %s", name, oldType.kind, newType.kind)
+			continue
+		}
+		d.compareType(oldType, newType)
+	}
+	for _, name := range d.newSchema.typeNames {
+		if _, ok := d.oldSchema.types[name]; !ok {
+			d.report(ChangeTypeTypeAdded, CriticalitySafe, name, name, "Type '%s' was added", name)
+		}
+	}
+
+	for _, name := range d.oldSchema.directiveNames {
+		newDirective, ok := d.newSchema.directives[name]
+		if !ok {
+			d.report(ChangeTypeDirectiveRemoved, CriticalityBreaking, "@"+name, "@"+name, "Directive '@%s' was removed", name)
+			continue
+		}
+		d.compareDirective(d.oldSchema.directives[name], newDirective)
+	}
+	for _, name := range d.newSchema.directiveNames {
+		if _, ok := d.oldSchema.directives[name]; !ok {
+			d.report(ChangeTypeDirectiveAdded, CriticalitySafe, "@"+name, "@"+name, "Directive '@%s' was added", name)
+		}
+	}
+}
+
+func (d *differ) compareRootOperationTypes() {
+	for _, operationType := range []ast.OperationType{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription} {
+		oldName, oldOk := d.oldSchema.rootTypes[operationType]
+		newName, newOk := d.newSchema.rootTypes[operationType]
+		operation := operationType.Name()
+		switch {
+		case oldOk && !newOk:
+			d.report(ChangeTypeRootOperationTypeRemoved, CriticalityBreaking, oldName, oldName, "Schema %s root type '%s' was removed", operation, oldName)
+		case !oldOk && newOk:
+			d.report(ChangeTypeRootOperationTypeAdded, CriticalitySafe, newName, newName, "Schema %s root type '%s' was added", operation, newName)
+		case oldOk && oldName != newName:
+			d.report(ChangeTypeRootOperationTypeChanged, CriticalityBreaking, oldName, oldName, "Schema %s root type changed from '%s' to '%s'", operation, oldName, newName)
+		}
+	}
+}
+
+func (d *differ) compareType(oldType, newType *typeDefinition) {
+	switch oldType.kind {
+	case kindObject, kindInterface:
+		d.compareFields(oldType, newType)
+		d.compareInterfaces(oldType, newType)
+	case kindInputObject:
+		d.compareInputFields(oldType, newType)
+	case kindEnum:
+		d.compareEnumValues(oldType, newType)
+	case kindUnion:
+		d.compareUnionMembers(oldType, newType)
+	}
+}
+
+func (d *differ) compareFields(oldType, newType *typeDefinition) {
+	for _, oldField := range oldType.fields {
+		coordinate := oldType.name + "." + oldField.name
+		newField := newType.field(oldField.name)
+		if newField == nil {
+			d.report(ChangeTypeFieldRemoved, CriticalityBreaking, coordinate, coordinate, "Field '%s' was removed", coordinate)
+			continue
+		}
+
+		oldTypeString, newTypeString := d.oldSchema.typeString(oldField.typeRef), d.newSchema.typeString(newField.typeRef)
+		switch {
+		case !isSafeOutputTypeChange(d.oldSchema.document, oldField.typeRef, d.newSchema.document, newField.typeRef):
+			d.report(ChangeTypeFieldTypeChanged, CriticalityBreaking, coordinate, coordinate, "Field '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		case oldTypeString != newTypeString:
+			d.report(ChangeTypeFieldTypeChanged, CriticalitySafe, coordinate, coordinate, "Field '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		}
+
+		switch {
+		case !oldField.deprecated && newField.deprecated:
+			d.report(ChangeTypeFieldDeprecationAdded, CriticalitySafe, coordinate, coordinate, "Field '%s' was deprecated", coordinate)
+		case oldField.deprecated && !newField.deprecated:
+			d.report(ChangeTypeFieldDeprecationRemoved, CriticalitySafe, coordinate, coordinate, "Field '%s' is no longer deprecated", coordinate)
+		}
+
+		d.compareArguments(coordinate, oldField.arguments, newField.arguments)
+	}
+
+	for _, newField := range newType.fields {
+		if oldType.field(newField.name) == nil {
+			coordinate := newType.name + "." + newField.name
+			d.report(ChangeTypeFieldAdded, CriticalitySafe, coordinate, coordinate, "Field '%s' was added", coordinate)
+		}
+	}
+}
+
+// compareArguments compares the arguments of a field or a directive, parent is the coordinate of the field or directive
+func (d *differ) compareArguments(parent string, oldArguments, newArguments []*inputValueDefinition) {
+	for _, oldArgument := range oldArguments {
+		coordinate := parent + "(" + oldArgument.name + ":)"
+		newArgument := inputValue(newArguments, oldArgument.name)
+		if newArgument == nil {
+			d.report(ChangeTypeArgumentRemoved, CriticalityBreaking, coordinate, coordinate, "Argument '%s' was removed", coordinate)
+			continue
+		}
+
+		oldTypeString, newTypeString := d.oldSchema.typeString(oldArgument.typeRef), d.newSchema.typeString(newArgument.typeRef)
+		switch {
+		case !isSafeInputTypeChange(d.oldSchema.document, oldArgument.typeRef, d.newSchema.document, newArgument.typeRef):
+			d.report(ChangeTypeArgumentTypeChanged, CriticalityBreaking, coordinate, coordinate, "Argument '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		case oldTypeString != newTypeString:
+			d.report(ChangeTypeArgumentTypeChanged, CriticalitySafe, coordinate, coordinate, "Argument '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		}
+
+		if oldArgument.hasDefault != newArgument.hasDefault || oldArgument.defaultValue != newArgument.defaultValue {
+			d.report(ChangeTypeArgumentDefaultValueChanged, CriticalityDangerous, coordinate, coordinate, "Argument '%s' changed default value from '%s' to '%s'",
+				coordinate, defaultValueString(oldArgument), defaultValueString(newArgument))
+		}
+	}
+
+	for _, newArgument := range newArguments {
+		if inputValue(oldArguments, newArgument.name) != nil {
+			continue
+		}
+		coordinate := parent + "(" + newArgument.name + ":)"
+		if d.newSchema.isRequired(newArgument) {
+			d.report(ChangeTypeArgumentAdded, CriticalityBreaking, coordinate, parent, "Required argument '%s' was added", coordinate)
+			continue
+		}
+		d.report(ChangeTypeArgumentAdded, CriticalitySafe, coordinate, parent, "Argument '%s' was added", coordinate)
+	}
+}
+
+func (d *differ) compareInputFields(oldType, newType *typeDefinition) {
+	for _, oldField := range oldType.inputFields {
+		coordinate := oldType.name + "." + oldField.name
+		newField := inputValue(newType.inputFields, oldField.name)
+		if newField == nil {
+			d.report(ChangeTypeInputFieldRemoved, CriticalityBreaking, coordinate, coordinate, "Input field '%s' was removed", coordinate)
+			continue
+		}
+
+		oldTypeString, newTypeString := d.oldSchema.typeString(oldField.typeRef), d.newSchema.typeString(newField.typeRef)
+		switch {
+		case !isSafeInputTypeChange(d.oldSchema.document, oldField.typeRef, d.newSchema.document, newField.typeRef):
+			d.report(ChangeTypeInputFieldTypeChanged, CriticalityBreaking, coordinate, coordinate, "Input field '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		case oldTypeString != newTypeString:
+			d.report(ChangeTypeInputFieldTypeChanged, CriticalitySafe, coordinate, coordinate, "Input field '%s' changed type from '%s' to '%s'", coordinate, oldTypeString, newTypeString)
+		}
+
+		if oldField.hasDefault != newField.hasDefault || oldField.defaultValue != newField.defaultValue {
+			d.report(ChangeTypeInputFieldDefaultValueChanged, CriticalityDangerous, coordinate, coordinate, "Input field '%s' changed default value from '%s' to '%s'",
+				coordinate, defaultValueString(oldField), defaultValueString(newField))
+		}
+	}
+
+	for _, newField := range newType.inputFields {
+		if inputValue(oldType.inputFields, newField.name) != nil {
+			continue
+		}
+		coordinate := newType.name + "." + newField.name
+		if d.newSchema.isRequired(newField) {
+			d.report(ChangeTypeInputFieldAdded, CriticalityBreaking, coordinate, newType.name, "Required input field '%s' was added", coordinate)
+			continue
+		}
+		d.report(ChangeTypeInputFieldAdded, CriticalitySafe, coordinate, newType.name, "Input field '%s' was added", coordinate)
+	}
+}
+
+func (d *differ) compareEnumValues(oldType, newType *typeDefinition) {
+	for _, oldValue := range oldType.enumValues {
+		coordinate := oldType.name + "." + oldValue.name
+		newValue := newType.enumValue(oldValue.name)
+		switch {
+		case newValue == nil:
+			d.report(ChangeTypeEnumValueRemoved, CriticalityBreaking, coordinate, coordinate, "Enum value '%s' was removed", coordinate)
+		case !oldValue.deprecated && newValue.deprecated:
+			d.report(ChangeTypeEnumValueDeprecationAdded, CriticalitySafe, coordinate, coordinate, "Enum value '%s' was deprecated", coordinate)
+		case oldValue.deprecated && !newValue.deprecated:
+			d.report(ChangeTypeEnumValueDeprecationRemoved, CriticalitySafe, coordinate, coordinate, "Enum value '%s' is no longer deprecated", coordinate)
+		}
+	}
+	for _, newValue := range newType.enumValues {
+		if oldType.enumValue(newValue.name) == nil {
+			coordinate := newType.name + "." + newValue.name
+			d.report(ChangeTypeEnumValueAdded, CriticalityDangerous, coordinate, coordinate,
+				"Enum value '%s' was added, clients may not handle the new value", coordinate)
+		}
+	}
+}
+
+func (d *differ) compareUnionMembers(oldType, newType *typeDefinition) {
+	for _, member := range oldType.members {
+		if !contains(newType.members, member) {
+			d.report(ChangeTypeUnionMemberRemoved, CriticalityBreaking, oldType.name, member, "Member '%s' was removed from union '%s'", member, oldType.name)
+		}
+	}
+	for _, member := range newType.members {
+		if !contains(oldType.members, member) {
+			d.report(ChangeTypeUnionMemberAdded, CriticalityDangerous, newType.name, member,
+				"Member '%s' was added to union '%s', clients may not handle the new type", member, newType.name)
+		}
+	}
+}
+
+func (d *differ) compareInterfaces(oldType, newType *typeDefinition) {
+	for _, name := range oldType.interfaces {
+		if !contains(newType.interfaces, name) {
+			d.report(ChangeTypeInterfaceImplementationRemoved, CriticalityBreaking, oldType.name, oldType.name,
+				"Type '%s' no longer implements interface '%s'", oldType.name, name)
+		}
+	}
+	for _, name := range newType.interfaces {
+		if !contains(oldType.interfaces, name) {
+			d.report(ChangeTypeInterfaceImplementationAdded, CriticalityDangerous, newType.name, newType.name,
+				"Type '%s' now implements interface '%s', clients may not handle the new type", newType.name, name)
+		}
+	}
+}
+
+func (d *differ) compareDirective(oldDirective, newDirective *directiveDefinition) {
+	coordinate := "@" + oldDirective.name
+	for _, location := range oldDirective.locations {
+		if !contains(newDirective.locations, location) {
+			d.report(ChangeTypeDirectiveLocationRemoved, CriticalityBreaking, coordinate, coordinate, "Location '%s' was removed from directive '%s'", location, coordinate)
+		}
+	}
+	for _, location := range newDirective.locations {
+		if !contains(oldDirective.locations, location) {
+			d.report(ChangeTypeDirectiveLocationAdded, CriticalitySafe, coordinate, coordinate, "Location '%s' was added to directive '%s'", location, coordinate)
+		}
+	}
+
+	switch {
+	case oldDirective.repeatable && !newDirective.repeatable:
+		d.report(ChangeTypeDirectiveRepeatableRemoved, CriticalityBreaking, coordinate, coordinate, "Directive '%s' is no longer repeatable", coordinate)
+	case !oldDirective.repeatable && newDirective.repeatable:
+		d.report(ChangeTypeDirectiveRepeatableAdded, CriticalitySafe, coordinate, coordinate, "Directive '%s' is now repeatable", coordinate)
+	}
+
+	d.compareArguments(coordinate, oldDirective.arguments, newDirective.arguments)
+}
+
+func defaultValueString(inputValue *inputValueDefinition) string {
+	if !inputValue.hasDefault {
+		return "none"
+	}
+	return inputValue.defaultValue
+}
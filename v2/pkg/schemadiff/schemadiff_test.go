@@ -0,0 +1,226 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astparser"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/asttransform"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+)
+
+type expectedChange struct {
+	criticality Criticality
+	changeType  ChangeType
+	coordinate  string
+	message     string
+}
+
+func parseSchema(t *testing.T, sdl string) *ast.Document {
+	t.Helper()
+	doc, report := astparser.ParseGraphqlDocumentString(sdl)
+	require.False(t, report.HasErrors(), report.Error())
+	return &doc
+}
+
+func changes(diff Diff) (out []expectedChange) {
+	for _, change := range diff.Changes {
+		out = append(out, expectedChange{change.Criticality, change.Type, change.Coordinate, change.Message})
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	run := func(t *testing.T, oldSDL, newSDL string, expected ...expectedChange) {
+		t.Helper()
+		diff := Compare(parseSchema(t, oldSDL), parseSchema(t, newSDL))
+		assert.Equal(t, expected, changes(diff))
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		sdl := `type Query { user(id: ID!): User } type User { id: ID! }`
+		diff := Compare(parseSchema(t, sdl), parseSchema(t, sdl))
+		assert.Empty(t, diff.Changes)
+		assert.False(t, diff.HasBreakingChanges())
+	})
+
+	t.Run("types", func(t *testing.T) {
+		run(t, `type Query { a: Int } type User { id: ID } scalar Date`, `type Query { a: Int } interface User { id: ID } type Post { id: ID }`,
+			expectedChange{CriticalityBreaking, ChangeTypeTypeKindChanged, "User", "Type 'User' changed from OBJECT to INTERFACE"},
+			expectedChange{CriticalityBreaking, ChangeTypeTypeRemoved, "Date", "Type 'Date' was removed"},
+			expectedChange{CriticalitySafe, ChangeTypeTypeAdded, "Post", "Type 'Post' was added"},
+		)
+	})
+
+	t.Run("fields and nullability", func(t *testing.T) {
+		run(t, `
+			type Query { a: String b: String! c: [String] d: String e: Int @deprecated }`, `
+			type Query { a: String! b: String c: [String!]! e: Int f: Int }`,
+			expectedChange{CriticalitySafe, ChangeTypeFieldTypeChanged, "Query.a", "Field 'Query.a' changed type from 'String' to 'String!'"},
+			expectedChange{CriticalityBreaking, ChangeTypeFieldTypeChanged, "Query.b", "Field 'Query.b' changed type from 'String!' to 'String'"},
+			expectedChange{CriticalitySafe, ChangeTypeFieldTypeChanged, "Query.c", "Field 'Query.c' changed type from '[String]' to '[String!]!'"},
+			expectedChange{CriticalityBreaking, ChangeTypeFieldRemoved, "Query.d", "Field 'Query.d' was removed"},
+			expectedChange{CriticalitySafe, ChangeTypeFieldDeprecationRemoved, "Query.e", "Field 'Query.e' is no longer deprecated"},
+			expectedChange{CriticalitySafe, ChangeTypeFieldAdded, "Query.f", "Field 'Query.f' was added"},
+		)
+	})
+
+	t.Run("arguments", func(t *testing.T) {
+		run(t, `
+			type Query { users(first: Int, after: String!, filter: String, order: String = "ASC"): [String] }`, `
+			type Query { users(first: Int!, after: String, order: String = "DESC", limit: Int!, offset: Int, page: Int! = 1): [String] }`,
+			expectedChange{CriticalityBreaking, ChangeTypeArgumentTypeChanged, "Query.users(first:)", "Argument 'Query.users(first:)' changed type from 'Int' to 'Int!'"},
+			expectedChange{CriticalitySafe, ChangeTypeArgumentTypeChanged, "Query.users(after:)", "Argument 'Query.users(after:)' changed type from 'String!' to 'String'"},
+			expectedChange{CriticalityBreaking, ChangeTypeArgumentRemoved, "Query.users(filter:)", "Argument 'Query.users(filter:)' was removed"},
+			expectedChange{CriticalityDangerous, ChangeTypeArgumentDefaultValueChanged, "Query.users(order:)", `Argument 'Query.users(order:)' changed default value from '"ASC"' to '"DESC"'`},
+			expectedChange{CriticalityBreaking, ChangeTypeArgumentAdded, "Query.users(limit:)", "Required argument 'Query.users(limit:)' was added"},
+			expectedChange{CriticalitySafe, ChangeTypeArgumentAdded, "Query.users(offset:)", "Argument 'Query.users(offset:)' was added"},
+			expectedChange{CriticalitySafe, ChangeTypeArgumentAdded, "Query.users(page:)", "Argument 'Query.users(page:)' was added"},
+		)
+	})
+
+	t.Run("input objects", func(t *testing.T) {
+		run(t, `input Filter { name: String age: Int limit: Int = 10 }`, `input Filter { name: String! limit: Int = 20 role: String! search: String }`,
+			expectedChange{CriticalityBreaking, ChangeTypeInputFieldTypeChanged, "Filter.name", "Input field 'Filter.name' changed type from 'String' to 'String!'"},
+			expectedChange{CriticalityBreaking, ChangeTypeInputFieldRemoved, "Filter.age", "Input field 'Filter.age' was removed"},
+			expectedChange{CriticalityDangerous, ChangeTypeInputFieldDefaultValueChanged, "Filter.limit", "Input field 'Filter.limit' changed default value from '10' to '20'"},
+			expectedChange{CriticalityBreaking, ChangeTypeInputFieldAdded, "Filter.role", "Required input field 'Filter.role' was added"},
+			expectedChange{CriticalitySafe, ChangeTypeInputFieldAdded, "Filter.search", "Input field 'Filter.search' was added"},
+		)
+	})
+
+	t.Run("enums, unions and interfaces", func(t *testing.T) {
+		run(t, `
+			enum Role { ADMIN USER GUEST }
+			union Result = User | Post
+			interface Node { id: ID }
+			type User implements Node { id: ID }
+			type Post { id: ID }`, `
+			enum Role { ADMIN USER @deprecated MODERATOR }
+			union Result = User | Comment
+			interface Node { id: ID }
+			type User { id: ID }
+			type Post implements Node { id: ID }
+			type Comment { id: ID }`,
+			expectedChange{CriticalitySafe, ChangeTypeEnumValueDeprecationAdded, "Role.USER", "Enum value 'Role.USER' was deprecated"},
+			expectedChange{CriticalityBreaking, ChangeTypeEnumValueRemoved, "Role.GUEST", "Enum value 'Role.GUEST' was removed"},
+			expectedChange{CriticalityDangerous, ChangeTypeEnumValueAdded, "Role.MODERATOR", "Enum value 'Role.MODERATOR' was added, clients may not handle the new value"},
+			expectedChange{CriticalityBreaking, ChangeTypeUnionMemberRemoved, "Result", "Member 'Post' was removed from union 'Result'"},
+			expectedChange{CriticalityDangerous, ChangeTypeUnionMemberAdded, "Result", "Member 'Comment' was added to union 'Result', clients may not handle the new type"},
+			expectedChange{CriticalityBreaking, ChangeTypeInterfaceImplementationRemoved, "User", "Type 'User' no longer implements interface 'Node'"},
+			expectedChange{CriticalityDangerous, ChangeTypeInterfaceImplementationAdded, "Post", "Type 'Post' now implements interface 'Node', clients may not handle the new type"},
+			expectedChange{CriticalitySafe, ChangeTypeTypeAdded, "Comment", "Type 'Comment' was added"},
+		)
+	})
+
+	t.Run("directives", func(t *testing.T) {
+		run(t, `
+			directive @auth(role: String) repeatable on FIELD_DEFINITION | OBJECT
+			directive @cache on FIELD
+			directive @include(if: Boolean!) on FIELD`, `
+			directive @auth(role: String!) on FIELD_DEFINITION | INTERFACE
+			directive @log on QUERY`,
+			expectedChange{CriticalityBreaking, ChangeTypeDirectiveLocationRemoved, "@auth", "Location 'OBJECT' was removed from directive '@auth'"},
+			expectedChange{CriticalitySafe, ChangeTypeDirectiveLocationAdded, "@auth", "Location 'INTERFACE' was added to directive '@auth'"},
+			expectedChange{CriticalityBreaking, ChangeTypeDirectiveRepeatableRemoved, "@auth", "Directive '@auth' is no longer repeatable"},
+			expectedChange{CriticalityBreaking, ChangeTypeArgumentTypeChanged, "@auth(role:)", "Argument '@auth(role:)' changed type from 'String' to 'String!'"},
+			expectedChange{CriticalityBreaking, ChangeTypeDirectiveRemoved, "@cache", "Directive '@cache' was removed"},
+			expectedChange{CriticalitySafe, ChangeTypeDirectiveAdded, "@log", "Directive '@log' was added"},
+		)
+	})
+
+	t.Run("root operation types", func(t *testing.T) {
+		run(t, `
+			schema { query: Query mutation: Mutation }
+			type Query { a: Int } type Mutation { a: Int }`, `
+			schema { query: RootQuery subscription: Subscription }
+			type Query { a: Int } type Mutation { a: Int } type RootQuery { a: Int } type Subscription { a: Int }`,
+			expectedChange{CriticalityBreaking, ChangeTypeRootOperationTypeChanged, "Query", "Schema query root type changed from 'Query' to 'RootQuery'"},
+			expectedChange{CriticalityBreaking, ChangeTypeRootOperationTypeRemoved, "Mutation", "Schema mutation root type 'Mutation' was removed"},
+			expectedChange{CriticalitySafe, ChangeTypeRootOperationTypeAdded, "Subscription", "Schema subscription root type 'Subscription' was added"},
+			expectedChange{CriticalitySafe, ChangeTypeTypeAdded, "RootQuery", "Type 'RootQuery' was added"},
+			expectedChange{CriticalitySafe, ChangeTypeTypeAdded, "Subscription", "Type 'Subscription' was added"},
+		)
+	})
+
+	t.Run("extensions and base schema are merged", func(t *testing.T) {
+		oldSchema := parseSchema(t, `type Query { a: Int } extend type Query { b: Int }`)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(oldSchema))
+		diff := Compare(oldSchema, parseSchema(t, `type Query { a: Int b: Int }`))
+		assert.Empty(t, diff.Changes)
+	})
+}
+
+func TestCompare_WithUsage(t *testing.T) {
+	oldSDL := `
+		type Query { user(id: ID!, filter: Filter): User users: [User] }
+		type User { id: ID! name: String role: Role }
+		input Filter { name: String role: Role }
+		enum Role { ADMIN USER }`
+	newSDL := `
+		type Query { user(id: ID!, filter: Filter): User }
+		type User { id: ID! role: Role }
+		input Filter { role: Role }
+		enum Role { ADMIN }`
+
+	t.Run("operations", func(t *testing.T) {
+		definition := parseSchema(t, oldSDL)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(definition))
+
+		usage := NewUsage()
+		operation, report := astparser.ParseGraphqlDocumentString(`query { user(id: 1, filter: {role: ADMIN}) { id name } }`)
+		require.False(t, report.HasErrors())
+		require.NoError(t, usage.AddOperation(&operation, definition))
+
+		diff := Compare(parseSchema(t, oldSDL), parseSchema(t, newSDL), WithUsage(usage))
+		assert.Equal(t, []expectedChange{
+			{CriticalityDangerous, ChangeTypeFieldRemoved, "Query.users", "Field 'Query.users' was removed"},
+			{CriticalityBreaking, ChangeTypeFieldRemoved, "User.name", "Field 'User.name' was removed"},
+			{CriticalityDangerous, ChangeTypeInputFieldRemoved, "Filter.name", "Input field 'Filter.name' was removed"},
+			{CriticalityDangerous, ChangeTypeEnumValueRemoved, "Role.USER", "Enum value 'Role.USER' was removed"},
+		}, changes(diff))
+		assert.True(t, diff.Changes[0].Unused)
+		assert.False(t, diff.Changes[1].Unused)
+		assert.True(t, diff.HasBreakingChanges())
+	})
+
+	t.Run("variables use all input fields", func(t *testing.T) {
+		definition := parseSchema(t, oldSDL)
+		require.NoError(t, asttransform.MergeDefinitionWithBaseSchema(definition))
+
+		usage := NewUsage()
+		operation, report := astparser.ParseGraphqlDocumentString(`query ($filter: Filter) { user(id: 1, filter: $filter) { id } }`)
+		require.False(t, report.HasErrors())
+		require.NoError(t, usage.AddOperation(&operation, definition))
+
+		diff := Compare(parseSchema(t, oldSDL), parseSchema(t, newSDL), WithUsage(usage))
+		assert.Equal(t, []ChangeType{ChangeTypeInputFieldRemoved, ChangeTypeEnumValueRemoved}, changeTypes(diff.Filter(CriticalityBreaking)))
+	})
+
+	t.Run("schema usage info", func(t *testing.T) {
+		usage := NewUsage()
+		usage.AddSchemaUsageInfo(&plan.SchemaUsageInfo{
+			TypeFields: []plan.TypeFieldUsageInfo{
+				{FieldName: "users", FieldTypeName: "User", EnclosingTypeNames: []string{"Query"}},
+				{FieldName: "id", FieldTypeName: "ID", EnclosingTypeNames: []string{"User"}},
+			},
+			InputTypeFields: []plan.InputTypeFieldUsageInfo{
+				{FieldName: "role", FieldTypeName: "Role", EnclosingTypeNames: []string{"Filter"}, IsEnumField: true, EnumValues: []string{"USER"}},
+			},
+		})
+
+		diff := Compare(parseSchema(t, oldSDL), parseSchema(t, newSDL), WithUsage(usage))
+		assert.Equal(t, []ChangeType{ChangeTypeFieldRemoved, ChangeTypeEnumValueRemoved}, changeTypes(diff.Filter(CriticalityBreaking)))
+		assert.Equal(t, "Query.users", diff.Filter(CriticalityBreaking)[0].Coordinate)
+	})
+}
+
+func changeTypes(changes []Change) (out []ChangeType) {
+	for _, change := range changes {
+		out = append(out, change.Type)
+	}
+	return out
+}
@@ -0,0 +1,197 @@
+package schemadiff
+
+import (
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/astvisitor"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/engine/plan"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/operationreport"
+)
+
+// Usage is the set of schema coordinates used by clients, e.g. User, User.name, Query.user(id:), Role.ADMIN or @auth.
+// It's collected from client operations or from the plan.SchemaUsageInfo of executed operations.
+type Usage struct {
+	coordinates map[string]struct{}
+}
+
+// NewUsage returns an empty Usage
+func NewUsage() *Usage {
+	return &Usage{
+		coordinates: map[string]struct{}{},
+	}
+}
+
+// Add marks a schema coordinate as used
+func (u *Usage) Add(coordinate string) {
+	u.coordinates[coordinate] = struct{}{}
+}
+
+// IsUsed reports whether a schema coordinate is used
+func (u *Usage) IsUsed(coordinate string) bool {
+	_, ok := u.coordinates[coordinate]
+	return ok
+}
+
+// AddSchemaUsageInfo adds the coordinates used by an executed operation
+func (u *Usage) AddSchemaUsageInfo(info *plan.SchemaUsageInfo) {
+	for _, field := range info.TypeFields {
+		u.Add(field.FieldTypeName)
+		for _, typeName := range field.EnclosingTypeNames {
+			u.Add(typeName)
+			u.Add(typeName + "." + field.FieldName)
+		}
+	}
+	for _, argument := range info.Arguments {
+		u.Add(argument.ArgumentTypeName)
+		u.Add(argument.EnclosingTypeName + "." + argument.FieldName)
+		u.Add(argument.EnclosingTypeName + "." + argument.FieldName + "(" + argument.ArgumentName + ":)")
+	}
+	for _, field := range info.InputTypeFields {
+		u.Add(field.FieldTypeName)
+		if !field.IsRootVariable {
+			for _, typeName := range field.EnclosingTypeNames {
+				u.Add(typeName)
+				u.Add(typeName + "." + field.FieldName)
+			}
+		}
+		for _, enumValue := range field.EnumValues {
+			u.Add(field.FieldTypeName + "." + enumValue)
+		}
+	}
+}
+
+// AddOperation adds the coordinates used by an operation, the definition must be the schema the operation was sent against
+// including the base schema, see asttransform.MergeDefinitionWithBaseSchema.
+// Input types of variables are marked as used including all of their fields and enum values, as the variable values are unknown.
+func (u *Usage) AddOperation(operation, definition *ast.Document) error {
+	walker := astvisitor.NewWalker(48)
+	visitor := &usageVisitor{
+		Walker:     &walker,
+		usage:      u,
+		operation:  operation,
+		definition: definition,
+		inputTypes: map[string]struct{}{},
+	}
+	walker.RegisterEnterFieldVisitor(visitor)
+	walker.RegisterEnterArgumentVisitor(visitor)
+	walker.RegisterEnterDirectiveVisitor(visitor)
+	walker.RegisterEnterVariableDefinitionVisitor(visitor)
+	walker.RegisterEnterInlineFragmentVisitor(visitor)
+	walker.RegisterEnterFragmentDefinitionVisitor(visitor)
+
+	report := operationreport.Report{}
+	walker.Walk(operation, definition, &report)
+	if report.HasErrors() {
+		return report
+	}
+	return nil
+}
+
+type usageVisitor struct {
+	*astvisitor.Walker
+	usage      *Usage
+	operation  *ast.Document
+	definition *ast.Document
+	inputTypes map[string]struct{}
+}
+
+func (v *usageVisitor) EnterField(ref int) {
+	typeName := v.definition.NodeNameString(v.EnclosingTypeDefinition)
+	v.usage.Add(typeName)
+
+	definition, ok := v.FieldDefinition(ref)
+	if !ok {
+		return
+	}
+	v.usage.Add(typeName + "." + v.operation.FieldNameString(ref))
+	v.usage.Add(v.definition.FieldDefinitionTypeNameString(definition))
+}
+
+func (v *usageVisitor) EnterArgument(ref int) {
+	definition, ok := v.ArgumentInputValueDefinition(ref)
+	if !ok {
+		return
+	}
+
+	var parent string
+	ancestor := v.Ancestor()
+	switch ancestor.Kind {
+	case ast.NodeKindField:
+		parent = v.definition.NodeNameString(v.EnclosingTypeDefinition) + "." + v.operation.FieldNameString(ancestor.Ref)
+	case ast.NodeKindDirective:
+		parent = "@" + v.operation.DirectiveNameString(ancestor.Ref)
+	default:
+		return
+	}
+	v.usage.Add(parent + "(" + v.operation.ArgumentNameString(ref) + ":)")
+	v.addValue(v.operation.ArgumentValue(ref), v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(definition)))
+}
+
+func (v *usageVisitor) EnterDirective(ref int) {
+	v.usage.Add("@" + v.operation.DirectiveNameString(ref))
+}
+
+func (v *usageVisitor) EnterVariableDefinition(ref int) {
+	v.addInputType(v.operation.ResolveTypeNameString(v.operation.VariableDefinitions[ref].Type))
+}
+
+func (v *usageVisitor) EnterInlineFragment(ref int) {
+	if v.operation.InlineFragmentHasTypeCondition(ref) {
+		v.usage.Add(v.operation.InlineFragmentTypeConditionNameString(ref))
+	}
+}
+
+func (v *usageVisitor) EnterFragmentDefinition(ref int) {
+	v.usage.Add(v.operation.FragmentDefinitionTypeNameString(ref))
+}
+
+// addValue marks the input fields and enum values of a literal value of the given type as used
+func (v *usageVisitor) addValue(value ast.Value, typeName string) {
+	v.usage.Add(typeName)
+	switch value.Kind {
+	case ast.ValueKindList:
+		for _, ref := range v.operation.ListValues[value.Ref].Refs {
+			v.addValue(v.operation.Value(ref), typeName)
+		}
+	case ast.ValueKindEnum:
+		v.usage.Add(typeName + "." + v.operation.EnumValueNameString(value.Ref))
+	case ast.ValueKindObject:
+		node, ok := v.definition.Index.FirstNodeByNameStr(typeName)
+		if !ok || node.Kind != ast.NodeKindInputObjectTypeDefinition {
+			return
+		}
+		for _, ref := range v.operation.ObjectValues[value.Ref].Refs {
+			fieldName := v.operation.ObjectFieldNameString(ref)
+			v.usage.Add(typeName + "." + fieldName)
+			inputValueDefinition := v.definition.InputObjectTypeDefinitionInputValueDefinitionByName(node.Ref, []byte(fieldName))
+			if inputValueDefinition == ast.InvalidRef {
+				continue
+			}
+			v.addValue(v.operation.ObjectFieldValue(ref), v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(inputValueDefinition)))
+		}
+	}
+}
+
+// addInputType marks an input type including all of its fields, nested input types and enum values as used
+func (v *usageVisitor) addInputType(typeName string) {
+	if _, ok := v.inputTypes[typeName]; ok {
+		return
+	}
+	v.inputTypes[typeName] = struct{}{}
+	v.usage.Add(typeName)
+
+	node, ok := v.definition.Index.FirstNodeByNameStr(typeName)
+	if !ok {
+		return
+	}
+	switch node.Kind {
+	case ast.NodeKindInputObjectTypeDefinition:
+		for _, ref := range v.definition.InputObjectTypeDefinitions[node.Ref].InputFieldsDefinition.Refs {
+			v.usage.Add(typeName + "." + v.definition.InputValueDefinitionNameString(ref))
+			v.addInputType(v.definition.ResolveTypeNameString(v.definition.InputValueDefinitionType(ref)))
+		}
+	case ast.NodeKindEnumTypeDefinition:
+		for _, ref := range v.definition.EnumTypeDefinitions[node.Ref].EnumValuesDefinition.Refs {
+			v.usage.Add(typeName + "." + v.definition.EnumValueDefinitionNameString(ref))
+		}
+	}
+}
@@ -24,6 +24,20 @@ func NewInitialHttpRequestContext(r *http.Request) *InitialHttpRequestContext {
 	}
 }
 
+type initialHttpRequestContextKey struct{}
+
+// WithInitialHttpRequestContext returns a context which carries the request that initiated a connection,
+// e.g. to identify the client of an operation.
+func WithInitialHttpRequestContext(ctx context.Context, requestContext *InitialHttpRequestContext) context.Context {
+	return context.WithValue(ctx, initialHttpRequestContextKey{}, requestContext)
+}
+
+// InitialHttpRequestContextFromContext returns the request context set by WithInitialHttpRequestContext.
+func InitialHttpRequestContextFromContext(ctx context.Context) (requestContext *InitialHttpRequestContext, ok bool) {
+	requestContext, ok = ctx.Value(initialHttpRequestContextKey{}).(*InitialHttpRequestContext)
+	return requestContext, ok && requestContext != nil && requestContext.Request != nil
+}
+
 type subscriptionCancellations struct {
 	mu            sync.RWMutex
 	cancellations map[string]context.CancelFunc
@@ -4,10 +4,12 @@ package subscription
 //go:generate mockgen -destination=websocket/engine_mock_test.go -package=websocket . Engine
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jensneuse/abstractlogger"
@@ -46,6 +48,35 @@ type ExecutorEngine struct {
 	bufferPool *sync.Pool
 	// subscriptionUpdateInterval is the actual interval on which the server sends subscription updates to the client.
 	subscriptionUpdateInterval time.Duration
+	// maxSubscriptions is the maximum number of concurrently running subscriptions. Zero means unlimited.
+	maxSubscriptions int
+	// activeSubscriptions counts the running subscriptions, queries and mutations are not counted.
+	activeSubscriptions atomic.Int64
+}
+
+// NewExecutorEngine creates an ExecutorEngine. A zero subscriptionUpdateInterval falls back to
+// DefaultSubscriptionUpdateInterval.
+func NewExecutorEngine(logger abstractlogger.Logger, executorPool ExecutorPool, subscriptionUpdateInterval time.Duration) (*ExecutorEngine, error) {
+	if subscriptionUpdateInterval == 0 {
+		var err error
+		subscriptionUpdateInterval, err = time.ParseDuration(DefaultSubscriptionUpdateInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ExecutorEngine{
+		logger:           logger,
+		subCancellations: subscriptionCancellations{},
+		executorPool:     executorPool,
+		bufferPool: &sync.Pool{
+			New: func() interface{} {
+				writer := graphql.NewEngineResultWriterFromBuffer(bytes.NewBuffer(make([]byte, 0, 1024)))
+				return &writer
+			},
+		},
+		subscriptionUpdateInterval: subscriptionUpdateInterval,
+	}, nil
 }
 
 // StartOperation will start any operation.
@@ -65,6 +96,16 @@ func (e *ExecutorEngine) StartOperation(ctx context.Context, id string, payload
 	}
 
 	if executor.OperationType() == ast.OperationTypeSubscription {
+		if !e.acquireSubscription() {
+			e.subCancellations.Cancel(id)
+			if err := e.executorPool.Put(executor); err != nil {
+				e.logger.Error("subscription.Handle.StartOperation()",
+					abstractlogger.Error(err),
+				)
+			}
+			eventHandler.Emit(EventTypeOnLimitExceeded, id, nil, ErrTooManySubscriptions)
+			return ErrTooManySubscriptions
+		}
 		go e.startSubscription(ctx, id, executor, eventHandler)
 		return nil
 	}
@@ -94,6 +135,23 @@ func (e *ExecutorEngine) TerminateAllSubscriptions(eventHandler EventHandler) er
 	return nil
 }
 
+// acquireSubscription reserves one of the maxSubscriptions, the check and the increment are a single atomic step.
+func (e *ExecutorEngine) acquireSubscription() bool {
+	for {
+		active := e.activeSubscriptions.Load()
+		if e.maxSubscriptions > 0 && active >= int64(e.maxSubscriptions) {
+			return false
+		}
+		if e.activeSubscriptions.CompareAndSwap(active, active+1) {
+			return true
+		}
+	}
+}
+
+func (e *ExecutorEngine) releaseSubscription() {
+	e.activeSubscriptions.Add(-1)
+}
+
 func (e *ExecutorEngine) handleOnBeforeStart(executor Executor) error {
 	switch e := executor.(type) {
 	case *ExecutorV2:
@@ -121,6 +179,7 @@ func (e *ExecutorEngine) checkForDuplicateSubscriberID(ctx context.Context, id s
 }
 
 func (e *ExecutorEngine) startSubscription(ctx context.Context, id string, executor Executor, eventHandler EventHandler) {
+	defer e.releaseSubscription()
 	defer func() {
 		err := e.executorPool.Put(executor)
 		if err != nil {
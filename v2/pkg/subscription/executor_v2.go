@@ -59,8 +59,10 @@ type ExecutorV2 struct {
 
 func (e *ExecutorV2) Execute(writer resolve.FlushWriter) error {
 	options := make([]graphql.ExecutionOptionsV2, 0)
-	switch ctx := e.reqCtx.(type) {
-	case *InitialHttpRequestContext:
+	if requestContext, ok := InitialHttpRequestContextFromContext(e.context); ok {
+		// the request of the operation, e.g. of a connection of a shared engine
+		options = append(options, graphql.WithAdditionalHttpHeaders(requestContext.Request.Header))
+	} else if ctx, ok := e.reqCtx.(*InitialHttpRequestContext); ok {
 		options = append(options, graphql.WithAdditionalHttpHeaders(ctx.Request.Header))
 	}
 
@@ -3,15 +3,11 @@ package subscription
 //go:generate mockgen -destination=handler_mock_test.go -package=subscription . Protocol,EventHandler
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"sync"
 	"time"
 
 	"github.com/jensneuse/abstractlogger"
-
-	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
 )
 
 var ErrCouldNotReadMessageFromClient = errors.New("could not read message from client")
@@ -29,6 +25,8 @@ const (
 	EventTypeOnConnectionError
 	EventTypeOnConnectionOpened
 	EventTypeOnDuplicatedSubscriberID
+	// EventTypeOnLimitExceeded is emitted when a client violates one of the Limits. The connection should be closed.
+	EventTypeOnLimitExceeded
 )
 
 // Protocol defines an interface for a subscription protocol decoupled from the underlying transport.
@@ -48,6 +46,9 @@ type UniversalProtocolHandlerOptions struct {
 	CustomSubscriptionUpdateInterval time.Duration
 	CustomReadErrorTimeOut           time.Duration
 	CustomEngine                     Engine
+	Limits                           Limits
+	// InitialHttpRequestContext is used to identify the client for Limits.ConnectionLimiter.
+	InitialHttpRequestContext *InitialHttpRequestContext
 }
 
 // UniversalProtocolHandler can handle any protocol by using the Protocol interface.
@@ -59,6 +60,10 @@ type UniversalProtocolHandler struct {
 	readErrorTimeOut          time.Duration
 	isReadTimeOutTimerRunning bool
 	readTimeOutCancel         context.CancelFunc
+	limits                    Limits
+	initialHttpRequestContext *InitialHttpRequestContext
+	messageRateLimiter        messageRateLimiter
+	limitSubscriptions        bool
 }
 
 // NewUniversalProtocolHandler creates a new UniversalProtocolHandler.
@@ -73,9 +78,14 @@ func NewUniversalProtocolHandler(client TransportClient, protocol Protocol, exec
 // NewUniversalProtocolHandlerWithOptions creates a new UniversalProtocolHandler. It requires an option struct.
 func NewUniversalProtocolHandlerWithOptions(client TransportClient, protocol Protocol, executorPool ExecutorPool, options UniversalProtocolHandlerOptions) (*UniversalProtocolHandler, error) {
 	handler := UniversalProtocolHandler{
-		logger:   abstractlogger.Noop{},
-		client:   client,
-		protocol: protocol,
+		logger:                    abstractlogger.Noop{},
+		client:                    client,
+		protocol:                  protocol,
+		limits:                    options.Limits,
+		initialHttpRequestContext: options.InitialHttpRequestContext,
+		messageRateLimiter: messageRateLimiter{
+			maxMessages: options.Limits.MaxMessagesPerSecond,
+		},
 	}
 
 	if options.Logger != nil {
@@ -94,29 +104,15 @@ func NewUniversalProtocolHandlerWithOptions(client TransportClient, protocol Pro
 
 	if options.CustomEngine != nil {
 		handler.engine = options.CustomEngine
+		// a custom engine doesn't know the limits, so the subscriptions are counted per connection by the handler
+		handler.limitSubscriptions = options.Limits.MaxSubscriptionsPerConnection > 0
 	} else {
-		engine := ExecutorEngine{
-			logger:           handler.logger,
-			subCancellations: subscriptionCancellations{},
-			executorPool:     executorPool,
-			bufferPool: &sync.Pool{
-				New: func() interface{} {
-					writer := graphql.NewEngineResultWriterFromBuffer(bytes.NewBuffer(make([]byte, 0, 1024)))
-					return &writer
-				},
-			},
-		}
-
-		if options.CustomSubscriptionUpdateInterval != 0 {
-			engine.subscriptionUpdateInterval = options.CustomSubscriptionUpdateInterval
-		} else {
-			subscriptionUpdateInterval, err := time.ParseDuration(DefaultSubscriptionUpdateInterval)
-			if err != nil {
-				return nil, err
-			}
-			engine.subscriptionUpdateInterval = subscriptionUpdateInterval
+		engine, err := NewExecutorEngine(handler.logger, executorPool, options.CustomSubscriptionUpdateInterval)
+		if err != nil {
+			return nil, err
 		}
-		handler.engine = &engine
+		engine.maxSubscriptions = options.Limits.MaxSubscriptionsPerConnection
+		handler.engine = engine
 	}
 
 	return &handler, nil
@@ -124,9 +120,13 @@ func NewUniversalProtocolHandlerWithOptions(client TransportClient, protocol Pro
 
 // Handle will handle the subscription logic and forward messages to the actual protocol handler.
 func (u *UniversalProtocolHandler) Handle(ctx context.Context) {
+	if u.initialHttpRequestContext != nil {
+		ctx = WithInitialHttpRequestContext(ctx, u.initialHttpRequestContext)
+	}
 	ctxWithCancel, cancel := context.WithCancel(ctx)
+	engine := u.connectionEngine(ctxWithCancel, cancel)
 	defer func() {
-		err := u.engine.TerminateAllSubscriptions(u.protocol.EventHandler())
+		err := engine.TerminateAllSubscriptions(u.protocol.EventHandler())
 		if err != nil {
 			u.logger.Error("subscription.UniversalProtocolHandler.Handle: on terminate connections",
 				abstractlogger.Error(err),
@@ -135,6 +135,18 @@ func (u *UniversalProtocolHandler) Handle(ctx context.Context) {
 		cancel()
 	}()
 
+	if u.limits.ConnectionLimiter != nil {
+		release, err := u.limits.ConnectionLimiter.Acquire(u.initialHttpRequestContext)
+		if err != nil {
+			u.logger.Debug("subscription.UniversalProtocolHandler.Handle: on acquiring connection",
+				abstractlogger.Error(err),
+			)
+			u.protocol.EventHandler().Emit(EventTypeOnLimitExceeded, "", nil, err)
+			return
+		}
+		defer release()
+	}
+
 	u.protocol.EventHandler().Emit(EventTypeOnConnectionOpened, "", nil, nil)
 
 	for {
@@ -180,13 +192,21 @@ func (u *UniversalProtocolHandler) Handle(ctx context.Context) {
 				u.readTimeOutCancel = nil
 			}
 
+			if len(message) > 0 && !u.messageRateLimiter.allow(time.Now()) {
+				u.logger.Debug("subscription.UniversalProtocolHandler.Handle: on rate limiting message",
+					abstractlogger.Error(ErrTooManyMessages),
+				)
+				u.protocol.EventHandler().Emit(EventTypeOnLimitExceeded, "", nil, ErrTooManyMessages)
+				return
+			}
+
 			if len(message) > 0 {
-				err := u.protocol.Handle(ctxWithCancel, u.engine, message)
+				err := u.protocol.Handle(ctxWithCancel, engine, message)
 				if err != nil {
 					var onBeforeStartHookError *errOnBeforeStartHookFailure
-					if errors.As(err, &onBeforeStartHookError) {
-						// if we do have an errOnBeforeStartHookFailure than the error is expected and should be
-						// logged as 'Debug'.
+					if errors.As(err, &onBeforeStartHookError) || IsLimitExceededError(err) {
+						// if we do have an errOnBeforeStartHookFailure or a violated limit than the error is
+						// expected and should be logged as 'Debug'.
 						u.logger.Debug("subscription.UniversalProtocolHandler.Handle: on protocol handling message",
 							abstractlogger.Error(err),
 						)
@@ -208,3 +228,29 @@ func (u *UniversalProtocolHandler) Handle(ctx context.Context) {
 		}
 	}
 }
+
+// connectionEngine wraps the engine with the limits which apply to a single connection.
+func (u *UniversalProtocolHandler) connectionEngine(ctx context.Context, cancel context.CancelFunc) Engine {
+	if u.limits.MaxOutboundBufferSize <= 0 && !u.limitSubscriptions {
+		return u.engine
+	}
+
+	engine := u.engine
+	eventHandler := u.protocol.EventHandler()
+
+	var buffered *bufferedEventHandler
+	if u.limits.MaxOutboundBufferSize > 0 {
+		buffered = newBufferedEventHandler(ctx, u.logger, eventHandler, u.limits.MaxOutboundBufferSize, u.limits.SlowConsumerPolicy, cancel)
+		eventHandler = buffered
+	}
+	if u.limitSubscriptions {
+		engine = newSubscriptionLimitEngine(engine, u.limits.MaxSubscriptionsPerConnection, eventHandler)
+	}
+	if buffered != nil {
+		engine = &bufferedEngine{
+			Engine:       engine,
+			eventHandler: buffered,
+		}
+	}
+	return engine
+}
@@ -0,0 +1,354 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+var (
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+	ErrTooManyMessages      = errors.New("too many messages")
+	ErrTooManyConnections   = errors.New("too many connections")
+	ErrSlowConsumer         = errors.New("slow consumer")
+)
+
+// IsLimitExceededError indicates if err is caused by violating one of the Limits.
+func IsLimitExceededError(err error) bool {
+	return errors.Is(err, ErrTooManySubscriptions) ||
+		errors.Is(err, ErrTooManyMessages) ||
+		errors.Is(err, ErrTooManyConnections) ||
+		errors.Is(err, ErrSlowConsumer)
+}
+
+// SlowConsumerPolicy defines what happens when the outbound buffer of a connection is full.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerPolicyDrop drops subscription data until the buffer has space again.
+	SlowConsumerPolicyDrop SlowConsumerPolicy = iota
+	// SlowConsumerPolicyDisconnect closes the connection.
+	SlowConsumerPolicyDisconnect
+)
+
+// Limits protects the server from misbehaving clients. A zero value disables the corresponding limit.
+// Violations are emitted as EventTypeOnLimitExceeded and close the connection.
+type Limits struct {
+	// MaxSubscriptionsPerConnection is the maximum number of concurrently running subscriptions on one connection.
+	// Queries and mutations are not counted.
+	MaxSubscriptionsPerConnection int
+	// MaxMessagesPerSecond is the maximum number of messages a client is allowed to send per second.
+	MaxMessagesPerSecond int
+	// MaxOutboundBufferSize is the number of events which are buffered for a client before SlowConsumerPolicy applies.
+	MaxOutboundBufferSize int
+	// SlowConsumerPolicy is applied when the outbound buffer is full.
+	SlowConsumerPolicy SlowConsumerPolicy
+	// ConnectionLimiter limits the number of connections per identity. It has to be shared between all connections.
+	ConnectionLimiter *ConnectionLimiter
+}
+
+// IdentityFunc returns the identity of the client which initiated a connection.
+type IdentityFunc func(ctx *InitialHttpRequestContext) string
+
+// RemoteAddrIdentity identifies clients by the IP of the initial HTTP request.
+func RemoteAddrIdentity(ctx *InitialHttpRequestContext) string {
+	if ctx == nil || ctx.Request == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		return ctx.Request.RemoteAddr
+	}
+	return host
+}
+
+// ConnectionLimiter counts active connections per identity.
+type ConnectionLimiter struct {
+	maxConnections int
+	identity       IdentityFunc
+	mu             sync.Mutex
+	connections    map[string]int
+}
+
+// NewConnectionLimiter creates a ConnectionLimiter. If identity is nil, RemoteAddrIdentity is used.
+func NewConnectionLimiter(maxConnectionsPerIdentity int, identity IdentityFunc) *ConnectionLimiter {
+	if identity == nil {
+		identity = RemoteAddrIdentity
+	}
+	return &ConnectionLimiter{
+		maxConnections: maxConnectionsPerIdentity,
+		identity:       identity,
+		connections:    map[string]int{},
+	}
+}
+
+// Acquire registers a connection for the identity of ctx. The returned release func must be called
+// when the connection is closed. It returns ErrTooManyConnections if the limit is reached.
+func (c *ConnectionLimiter) Acquire(ctx *InitialHttpRequestContext) (release func(), err error) {
+	identity := c.identity(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxConnections > 0 && c.connections[identity] >= c.maxConnections {
+		return nil, ErrTooManyConnections
+	}
+	c.connections[identity]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.connections[identity]--
+			if c.connections[identity] <= 0 {
+				delete(c.connections, identity)
+			}
+		})
+	}, nil
+}
+
+// Connections returns the number of active connections for an identity.
+func (c *ConnectionLimiter) Connections(identity string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connections[identity]
+}
+
+// messageRateLimiter counts messages in fixed windows of one second.
+type messageRateLimiter struct {
+	maxMessages int
+	windowStart time.Time
+	count       int
+}
+
+func (m *messageRateLimiter) allow(now time.Time) bool {
+	if m.maxMessages <= 0 {
+		return true
+	}
+	if now.Sub(m.windowStart) >= time.Second {
+		m.windowStart = now
+		m.count = 0
+	}
+	m.count++
+	return m.count <= m.maxMessages
+}
+
+type bufferedEvent struct {
+	eventType EventType
+	id        string
+	eventID   string
+	data      []byte
+	err       error
+}
+
+// bufferedEventHandler decouples the engine from writing to slow clients.
+// Events are written by a single goroutine in the order they were emitted.
+type bufferedEventHandler struct {
+	logger         abstractlogger.Logger
+	eventHandler   EventHandler
+	events         chan bufferedEvent
+	policy         SlowConsumerPolicy
+	onSlowConsumer func()
+	slowConsumer   sync.Once
+	done           <-chan struct{}
+}
+
+func newBufferedEventHandler(ctx context.Context, logger abstractlogger.Logger, eventHandler EventHandler, size int, policy SlowConsumerPolicy, onSlowConsumer func()) *bufferedEventHandler {
+	b := &bufferedEventHandler{
+		logger:         logger,
+		eventHandler:   eventHandler,
+		events:         make(chan bufferedEvent, size),
+		policy:         policy,
+		onSlowConsumer: onSlowConsumer,
+		done:           ctx.Done(),
+	}
+	go b.run()
+	return b
+}
+
+func (b *bufferedEventHandler) run() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case event := <-b.events:
+			if resumable, ok := b.eventHandler.(ResumableEventHandler); ok && event.eventID != "" {
+				resumable.EmitWithEventID(event.eventType, event.id, event.eventID, event.data, event.err)
+				continue
+			}
+			b.eventHandler.Emit(event.eventType, event.id, event.data, event.err)
+		}
+	}
+}
+
+// Emit is an implementation of EventHandler.
+func (b *bufferedEventHandler) Emit(eventType EventType, id string, data []byte, err error) {
+	b.EmitWithEventID(eventType, id, "", data, err)
+}
+
+// EmitWithEventID is an implementation of ResumableEventHandler.
+func (b *bufferedEventHandler) EmitWithEventID(eventType EventType, id string, eventID string, data []byte, err error) {
+	if data != nil {
+		// the engine re-uses its buffers after emitting
+		data = append([]byte(nil), data...)
+	}
+	event := bufferedEvent{eventType: eventType, id: id, eventID: eventID, data: data, err: err}
+
+	select {
+	case b.events <- event:
+		return
+	case <-b.done:
+		return
+	default:
+	}
+
+	switch {
+	case b.policy == SlowConsumerPolicyDisconnect:
+		b.slowConsumer.Do(func() {
+			b.eventHandler.Emit(EventTypeOnLimitExceeded, id, nil, ErrSlowConsumer)
+			b.onSlowConsumer()
+		})
+	case eventType == EventTypeOnSubscriptionData:
+		b.logger.Debug("subscription.bufferedEventHandler.Emit: dropping event for slow consumer",
+			abstractlogger.String("id", id),
+		)
+	default:
+		// never drop events which change the state of a subscription
+		select {
+		case b.events <- event:
+		case <-b.done:
+		}
+	}
+}
+
+// bufferedEngine routes all events of the wrapped Engine through a bufferedEventHandler.
+type bufferedEngine struct {
+	Engine
+	eventHandler *bufferedEventHandler
+}
+
+func (b *bufferedEngine) StartOperation(ctx context.Context, id string, payload []byte, _ EventHandler) error {
+	return b.Engine.StartOperation(ctx, id, payload, b.eventHandler)
+}
+
+func (b *bufferedEngine) StopSubscription(id string, _ EventHandler) error {
+	return b.Engine.StopSubscription(id, b.eventHandler)
+}
+
+func (b *bufferedEngine) TerminateAllSubscriptions(_ EventHandler) error {
+	return b.Engine.TerminateAllSubscriptions(b.eventHandler)
+}
+
+// subscriptionLimitEngine enforces MaxSubscriptionsPerConnection for engines which don't count the subscriptions
+// of a connection themselves, e.g. a CustomEngine shared between connections.
+type subscriptionLimitEngine struct {
+	Engine
+	maxSubscriptions int
+	eventHandler     *subscriptionLimitEventHandler
+}
+
+func newSubscriptionLimitEngine(engine Engine, maxSubscriptions int, eventHandler EventHandler) *subscriptionLimitEngine {
+	return &subscriptionLimitEngine{
+		Engine:           engine,
+		maxSubscriptions: maxSubscriptions,
+		eventHandler: &subscriptionLimitEventHandler{
+			eventHandler:  eventHandler,
+			subscriptions: map[string]struct{}{},
+		},
+	}
+}
+
+func (s *subscriptionLimitEngine) StartOperation(ctx context.Context, id string, payload []byte, _ EventHandler) error {
+	var request graphql.Request
+	if err := graphql.UnmarshalRequest(bytes.NewReader(payload), &request); err != nil {
+		// the engine reports invalid requests
+		return s.Engine.StartOperation(ctx, id, payload, s.eventHandler)
+	}
+	if operationType, err := request.OperationType(); err != nil || operationType != graphql.OperationTypeSubscription {
+		return s.Engine.StartOperation(ctx, id, payload, s.eventHandler)
+	}
+
+	acquired, ok := s.eventHandler.acquire(id, s.maxSubscriptions)
+	if !ok {
+		s.eventHandler.Emit(EventTypeOnLimitExceeded, id, nil, ErrTooManySubscriptions)
+		return ErrTooManySubscriptions
+	}
+	err := s.Engine.StartOperation(ctx, id, payload, s.eventHandler)
+	if err != nil && acquired {
+		s.eventHandler.release(id)
+	}
+	return err
+}
+
+func (s *subscriptionLimitEngine) StopSubscription(id string, _ EventHandler) error {
+	s.eventHandler.release(id)
+	return s.Engine.StopSubscription(id, s.eventHandler)
+}
+
+func (s *subscriptionLimitEngine) TerminateAllSubscriptions(_ EventHandler) error {
+	s.eventHandler.releaseAll()
+	return s.Engine.TerminateAllSubscriptions(s.eventHandler)
+}
+
+// subscriptionLimitEventHandler tracks the running subscriptions of a connection by the events of the engine.
+type subscriptionLimitEventHandler struct {
+	eventHandler  EventHandler
+	mu            sync.Mutex
+	subscriptions map[string]struct{}
+}
+
+// acquire reserves a subscription for id. A duplicated id is not counted again and left to the engine to reject,
+// so acquired is false if ok is true because of an existing subscription.
+func (s *subscriptionLimitEventHandler) acquire(id string, maxSubscriptions int) (acquired, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.subscriptions[id]; exists {
+		return false, true
+	}
+	if len(s.subscriptions) >= maxSubscriptions {
+		return false, false
+	}
+	s.subscriptions[id] = struct{}{}
+	return true, true
+}
+
+func (s *subscriptionLimitEventHandler) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscriptions, id)
+}
+
+func (s *subscriptionLimitEventHandler) releaseAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = map[string]struct{}{}
+}
+
+// Emit is an implementation of EventHandler.
+func (s *subscriptionLimitEventHandler) Emit(eventType EventType, id string, data []byte, err error) {
+	s.releaseOnCompletion(eventType, id)
+	s.eventHandler.Emit(eventType, id, data, err)
+}
+
+// EmitWithEventID is an implementation of ResumableEventHandler.
+func (s *subscriptionLimitEventHandler) EmitWithEventID(eventType EventType, id string, eventID string, data []byte, err error) {
+	s.releaseOnCompletion(eventType, id)
+	if resumable, ok := s.eventHandler.(ResumableEventHandler); ok {
+		resumable.EmitWithEventID(eventType, id, eventID, data, err)
+		return
+	}
+	s.eventHandler.Emit(eventType, id, data, err)
+}
+
+func (s *subscriptionLimitEventHandler) releaseOnCompletion(eventType EventType, id string) {
+	if eventType == EventTypeOnSubscriptionCompleted || eventType == EventTypeOnError {
+		s.release(id)
+	}
+}
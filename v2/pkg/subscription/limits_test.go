@@ -0,0 +1,337 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jensneuse/abstractlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/ast"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+func TestConnectionLimiter(t *testing.T) {
+	requestContext := func(remoteAddr string) *InitialHttpRequestContext {
+		return NewInitialHttpRequestContext(&http.Request{RemoteAddr: remoteAddr})
+	}
+
+	limiter := NewConnectionLimiter(2, nil)
+
+	releaseFirst, err := limiter.Acquire(requestContext("10.0.0.1:1234"))
+	require.NoError(t, err)
+	releaseSecond, err := limiter.Acquire(requestContext("10.0.0.1:1235"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, limiter.Connections("10.0.0.1"))
+
+	_, err = limiter.Acquire(requestContext("10.0.0.1:1236"))
+	assert.ErrorIs(t, err, ErrTooManyConnections)
+
+	releaseOther, err := limiter.Acquire(requestContext("10.0.0.2:1234"))
+	require.NoError(t, err)
+
+	releaseFirst()
+	releaseFirst() // releasing twice must not free another slot
+	assert.Equal(t, 1, limiter.Connections("10.0.0.1"))
+
+	releaseThird, err := limiter.Acquire(requestContext("10.0.0.1:1236"))
+	require.NoError(t, err)
+
+	releaseSecond()
+	releaseThird()
+	releaseOther()
+	assert.Equal(t, 0, limiter.Connections("10.0.0.1"))
+	assert.Equal(t, 0, limiter.Connections("10.0.0.2"))
+}
+
+func TestMessageRateLimiter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should allow all messages without limit", func(t *testing.T) {
+		limiter := messageRateLimiter{}
+		for i := 0; i < 100; i++ {
+			assert.True(t, limiter.allow(now))
+		}
+	})
+
+	t.Run("should limit messages per second", func(t *testing.T) {
+		limiter := messageRateLimiter{maxMessages: 2}
+		assert.True(t, limiter.allow(now))
+		assert.True(t, limiter.allow(now.Add(100*time.Millisecond)))
+		assert.False(t, limiter.allow(now.Add(200*time.Millisecond)))
+		assert.True(t, limiter.allow(now.Add(time.Second)))
+	})
+}
+
+// blockingEventHandler records events and blocks until unblock is closed.
+type blockingEventHandler struct {
+	unblock chan struct{}
+	mu      sync.Mutex
+	events  []EventType
+	data    []string
+}
+
+func (b *blockingEventHandler) Emit(eventType EventType, _ string, data []byte, _ error) {
+	if eventType != EventTypeOnLimitExceeded {
+		<-b.unblock
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, eventType)
+	b.data = append(b.data, string(data))
+}
+
+func (b *blockingEventHandler) recorded() ([]EventType, []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]EventType(nil), b.events...), append([]string(nil), b.data...)
+}
+
+func TestBufferedEventHandler(t *testing.T) {
+	t.Run("should drop data for slow consumers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inner := &blockingEventHandler{unblock: make(chan struct{})}
+		handler := newBufferedEventHandler(ctx, abstractlogger.Noop{}, inner, 1, SlowConsumerPolicyDrop, cancel)
+
+		data := []byte("1")
+		handler.Emit(EventTypeOnSubscriptionData, "1", data, nil)
+		data[0] = 'x' // buffered events must not share memory with the engine
+		// wait until the first event is consumed and blocks the writer
+		time.Sleep(10 * time.Millisecond)
+		handler.Emit(EventTypeOnSubscriptionData, "1", []byte("2"), nil)
+		handler.Emit(EventTypeOnSubscriptionData, "1", []byte("3"), nil)
+		close(inner.unblock)
+
+		assert.Eventually(t, func() bool {
+			events, _ := inner.recorded()
+			return len(events) == 2
+		}, time.Second, 5*time.Millisecond)
+
+		_, data2 := inner.recorded()
+		assert.Equal(t, []string{"1", "2"}, data2)
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("should disconnect slow consumers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inner := &blockingEventHandler{unblock: make(chan struct{})}
+		defer close(inner.unblock)
+		handler := newBufferedEventHandler(ctx, abstractlogger.Noop{}, inner, 1, SlowConsumerPolicyDisconnect, cancel)
+
+		handler.Emit(EventTypeOnSubscriptionData, "1", []byte("1"), nil)
+		time.Sleep(10 * time.Millisecond)
+		handler.Emit(EventTypeOnSubscriptionData, "1", []byte("2"), nil)
+		handler.Emit(EventTypeOnSubscriptionData, "1", []byte("3"), nil)
+
+		events, _ := inner.recorded()
+		assert.Equal(t, []EventType{EventTypeOnLimitExceeded}, events)
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+}
+
+func TestExecutorEngine_MaxSubscriptions(t *testing.T) {
+	newEngine := func(executorPool ExecutorPool) *ExecutorEngine {
+		return &ExecutorEngine{
+			logger:           abstractlogger.Noop{},
+			subCancellations: subscriptionCancellations{},
+			executorPool:     executorPool,
+			maxSubscriptions: 1,
+		}
+	}
+
+	t.Run("should reject subscriptions above the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		eventHandlerMock := NewMockEventHandler(ctrl)
+		eventHandlerMock.EXPECT().Emit(EventTypeOnLimitExceeded, "2", gomock.Nil(), ErrTooManySubscriptions).Times(1)
+
+		executorMock := NewMockExecutor(ctrl)
+		executorMock.EXPECT().OperationType().Return(ast.OperationTypeSubscription).Times(1)
+
+		executorPoolMock := NewMockExecutorPool(ctrl)
+		executorPoolMock.EXPECT().Get(gomock.Any()).Return(executorMock, nil).Times(1)
+		executorPoolMock.EXPECT().Put(executorMock).Return(nil).Times(1)
+
+		engine := newEngine(executorPoolMock)
+		require.True(t, engine.acquireSubscription())
+
+		err := engine.StartOperation(context.Background(), "2", []byte(`{}`), eventHandlerMock)
+		assert.ErrorIs(t, err, ErrTooManySubscriptions)
+		assert.Equal(t, int64(1), engine.activeSubscriptions.Load())
+	})
+
+	t.Run("should not count queries and mutations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		done := make(chan struct{})
+		eventHandlerMock := NewMockEventHandler(ctrl)
+		eventHandlerMock.EXPECT().Emit(EventTypeOnNonSubscriptionExecutionResult, "2", gomock.Any(), gomock.Nil()).Times(1)
+
+		executorMock := NewMockExecutor(ctrl)
+		executorMock.EXPECT().OperationType().Return(ast.OperationTypeQuery).Times(1)
+		executorMock.EXPECT().SetContext(gomock.Any()).Times(1)
+		executorMock.EXPECT().Execute(gomock.Any()).Return(nil).Times(1)
+
+		executorPoolMock := NewMockExecutorPool(ctrl)
+		executorPoolMock.EXPECT().Get(gomock.Any()).Return(executorMock, nil).Times(1)
+		executorPoolMock.EXPECT().Put(executorMock).
+			Do(func(_ Executor) {
+				close(done)
+			}).
+			Return(nil).
+			Times(1)
+
+		engine := newEngine(executorPoolMock)
+		engine.bufferPool = &sync.Pool{
+			New: func() interface{} {
+				writer := graphql.NewEngineResultWriterFromBuffer(bytes.NewBuffer(make([]byte, 0, 1024)))
+				return &writer
+			},
+		}
+		require.True(t, engine.acquireSubscription())
+
+		err := engine.StartOperation(context.Background(), "2", []byte(`{}`), eventHandlerMock)
+		assert.NoError(t, err)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("query was not executed")
+		}
+	})
+
+	t.Run("should release reserved subscriptions", func(t *testing.T) {
+		engine := newEngine(nil)
+		require.True(t, engine.acquireSubscription())
+		assert.False(t, engine.acquireSubscription())
+		engine.releaseSubscription()
+		assert.True(t, engine.acquireSubscription())
+	})
+}
+
+func TestUniversalProtocolHandler_Limits(t *testing.T) {
+	t.Run("should close connection when message rate is exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		clientMock := NewMockTransportClient(ctrl)
+		clientMock.EXPECT().IsConnected().Return(true).Times(2)
+		clientMock.EXPECT().ReadBytesFromClient().Return([]byte(`{"type":"ping"}`), nil).Times(2)
+
+		eventHandlerMock := NewMockEventHandler(ctrl)
+		eventHandlerMock.EXPECT().Emit(EventTypeOnConnectionOpened, "", gomock.Nil(), gomock.Nil())
+		eventHandlerMock.EXPECT().Emit(EventTypeOnLimitExceeded, "", gomock.Nil(), ErrTooManyMessages)
+
+		engineMock := NewMockEngine(ctrl)
+		engineMock.EXPECT().TerminateAllSubscriptions(eventHandlerMock).Times(1)
+
+		protocolMock := NewMockProtocol(ctrl)
+		protocolMock.EXPECT().EventHandler().Return(eventHandlerMock).AnyTimes()
+		protocolMock.EXPECT().Handle(gomock.Any(), engineMock, []byte(`{"type":"ping"}`)).Return(nil).Times(1)
+
+		handler, err := NewUniversalProtocolHandlerWithOptions(clientMock, protocolMock, nil, UniversalProtocolHandlerOptions{
+			Logger:       abstractlogger.Noop{},
+			CustomEngine: engineMock,
+			Limits: Limits{
+				MaxMessagesPerSecond: 1,
+			},
+		})
+		require.NoError(t, err)
+
+		handler.Handle(context.Background())
+	})
+
+	t.Run("should close connection when connection limit is exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		limiter := NewConnectionLimiter(1, func(_ *InitialHttpRequestContext) string {
+			return "client"
+		})
+		release, err := limiter.Acquire(nil)
+		require.NoError(t, err)
+		defer release()
+
+		eventHandlerMock := NewMockEventHandler(ctrl)
+		eventHandlerMock.EXPECT().Emit(EventTypeOnLimitExceeded, "", gomock.Nil(), ErrTooManyConnections)
+
+		engineMock := NewMockEngine(ctrl)
+		engineMock.EXPECT().TerminateAllSubscriptions(eventHandlerMock).Times(1)
+
+		protocolMock := NewMockProtocol(ctrl)
+		protocolMock.EXPECT().EventHandler().Return(eventHandlerMock).AnyTimes()
+
+		handler, err := NewUniversalProtocolHandlerWithOptions(NewMockTransportClient(ctrl), protocolMock, nil, UniversalProtocolHandlerOptions{
+			Logger:       abstractlogger.Noop{},
+			CustomEngine: engineMock,
+			Limits: Limits{
+				ConnectionLimiter: limiter,
+			},
+		})
+		require.NoError(t, err)
+
+		handler.Handle(context.Background())
+		assert.Equal(t, 1, limiter.Connections("client"))
+	})
+
+	t.Run("should limit subscriptions of a custom engine", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messages := [][]byte{
+			[]byte(`start:1:{"query":"subscription { counter }"}`),
+			[]byte(`start:2:{"query":"subscription { counter }"}`),
+			[]byte(`start:3:{"query":"{ hello }"}`),
+			[]byte(`stop:1`),
+			[]byte(`start:4:{"query":"subscription { counter }"}`),
+		}
+		clientMock := NewMockTransportClient(ctrl)
+		clientMock.EXPECT().IsConnected().Return(true).AnyTimes()
+		for _, message := range messages {
+			clientMock.EXPECT().ReadBytesFromClient().Return(message, nil)
+		}
+		clientMock.EXPECT().ReadBytesFromClient().Return(nil, ErrTransportClientClosedConnection)
+
+		var errs []error
+		protocolMock := NewMockProtocol(ctrl)
+		eventHandler := &recordingEventHandler{}
+		protocolMock.EXPECT().EventHandler().Return(eventHandler).AnyTimes()
+		protocolMock.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, engine Engine, message []byte) error {
+			command, args, _ := strings.Cut(string(message), ":")
+			if command == "stop" {
+				return engine.StopSubscription(args, eventHandler)
+			}
+			id, payload, _ := strings.Cut(args, ":")
+			err := engine.StartOperation(ctx, id, []byte(payload), eventHandler)
+			errs = append(errs, err)
+			return err
+		}).Times(len(messages))
+
+		customEngine := &fakeTriggerEngine{}
+		handler, err := NewUniversalProtocolHandlerWithOptions(clientMock, protocolMock, nil, UniversalProtocolHandlerOptions{
+			Logger:       abstractlogger.Noop{},
+			CustomEngine: customEngine,
+			Limits: Limits{
+				MaxSubscriptionsPerConnection: 1,
+			},
+		})
+		require.NoError(t, err)
+
+		handler.Handle(context.Background())
+		assert.Equal(t, []error{nil, ErrTooManySubscriptions, nil, nil}, errs)
+		assert.Equal(t, 3, customEngine.started)
+		assert.Contains(t, eventHandler.recorded(), recordedEvent{eventType: EventTypeOnLimitExceeded, id: "2"})
+	})
+}
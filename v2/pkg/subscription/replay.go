@@ -0,0 +1,593 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buger/jsonparser"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+)
+
+const (
+	DefaultReplayMaxEvents            = 100
+	DefaultReplayMaxAge               = time.Minute
+	DefaultReplaySubscriberBufferSize = 100
+)
+
+var ErrInvalidLastEventID = errors.New("invalid last event id")
+
+// ReplayGapPayload is sent as subscription data to a resuming client if events after its last event ID are no longer
+// buffered, e.g. because they were evicted or the trigger expired. The client missed events and has to refetch its state.
+const ReplayGapPayload = `{"errors":[{"message":"events after the last event id are no longer available","extensions":{"code":"SUBSCRIPTION_EVENTS_MISSED"}}]}`
+
+type lastEventIDContextKey struct{}
+
+// WithLastEventID returns a context which makes the ReplayEngine resume an operation after the event with
+// the given ID. Transports use it to pass e.g. the SSE Last-Event-ID header to the engine.
+func WithLastEventID(ctx context.Context, lastEventID string) context.Context {
+	return context.WithValue(ctx, lastEventIDContextKey{}, lastEventID)
+}
+
+// LastEventIDFromContext returns the ID set by WithLastEventID.
+func LastEventIDFromContext(ctx context.Context) (lastEventID string, ok bool) {
+	lastEventID, ok = ctx.Value(lastEventIDContextKey{}).(string)
+	return lastEventID, ok && lastEventID != ""
+}
+
+// ResumableEventHandler is implemented by EventHandlers which are able to send event IDs to their clients.
+// Clients can use the ID of the last received event to resume a subscription.
+type ResumableEventHandler interface {
+	EventHandler
+	EmitWithEventID(eventType EventType, id string, eventID string, data []byte, err error)
+}
+
+// TriggerKeyFunc returns the key of the trigger an operation subscribes to.
+// All subscriptions with the same key share one upstream operation and replay buffer.
+type TriggerKeyFunc func(ctx context.Context, payload []byte) (string, error)
+
+// ReplayOptions configures the ReplayEngine.
+type ReplayOptions struct {
+	// MaxEvents is the maximum number of events buffered per trigger.
+	MaxEvents int
+	// MaxAge is the maximum age of buffered events. A trigger keeps running for MaxAge after its last
+	// subscriber left, so that reconnecting clients don't miss events.
+	MaxAge time.Duration
+	// SubscriberBufferSize is the number of events which are queued for a subscriber. Subscribers with a full
+	// queue are slow consumers, they receive EventTypeOnLimitExceeded with ErrSlowConsumer and are unsubscribed.
+	SubscriberBufferSize int
+	// TriggerKey defaults to the Authorization and Cookie headers of the initial request of the client
+	// and the operation name, query and variables of the payload.
+	// Add other parts of the client identity to the key if the upstream depends on them.
+	TriggerKey TriggerKeyFunc
+}
+
+// ReplayEngine is an Engine which shares subscriptions with the same trigger and buffers their events.
+// Every event is stamped with an ID, which clients can provide on resubscribe to receive the events they
+// missed while they were gone. Non-subscription operations are passed through.
+//
+// A ReplayEngine is meant to be shared between connections, e.g. as UniversalProtocolHandlerOptions.CustomEngine.
+// Subscriptions are identified by their EventHandler and id, so the EventHandler of a connection must be comparable.
+type ReplayEngine struct {
+	engine  Engine
+	options ReplayOptions
+	now     func() time.Time
+
+	mu          sync.Mutex
+	triggers    map[string]*replayTrigger
+	operations  map[replayOperationKey]*replayOperation
+	nextID      uint64
+	lastEventID uint64
+}
+
+type replayOperationKey struct {
+	eventHandler EventHandler
+	id           string
+}
+
+type replayOperation struct {
+	// trigger is nil for non-subscription operations.
+	trigger    *replayTrigger
+	internalID string
+}
+
+type replayTrigger struct {
+	key         string
+	internalID  string
+	subscribers map[replayOperationKey]*replaySubscriber
+	events      []replayEvent
+	// replayableAfter is the event id after which all events of the trigger are buffered.
+	// Clients resuming after an older event missed events.
+	replayableAfter uint64
+	expireTimer     *time.Timer
+	// sendMu serializes queueing the events of the trigger, so that every subscriber receives them in order.
+	// It's acquired before ReplayEngine.mu.
+	sendMu sync.Mutex
+}
+
+type replayEvent struct {
+	id   uint64
+	data []byte
+	time time.Time
+}
+
+// replaySubscriber queues the events of a trigger for one subscriber, so that a slow client doesn't block
+// the other subscribers of the trigger.
+type replaySubscriber struct {
+	key      replayOperationKey
+	events   chan replayDelivery
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type replayDelivery struct {
+	eventType EventType
+	// event has no id for events which are not buffered, e.g. ReplayGapPayload.
+	event replayEvent
+	err   error
+}
+
+func newReplaySubscriber(key replayOperationKey, size int) *replaySubscriber {
+	return &replaySubscriber{
+		key:    key,
+		events: make(chan replayDelivery, size),
+		done:   make(chan struct{}),
+	}
+}
+
+// send queues delivery without blocking. It returns false if the queue of the subscriber is full.
+func (s *replaySubscriber) send(delivery replayDelivery) bool {
+	select {
+	case s.events <- delivery:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *replaySubscriber) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// NewReplayEngine wraps engine with a replay buffer. The wrapped engine is used with internal operation ids,
+// so it can be shared between all connections.
+func NewReplayEngine(engine Engine, options ReplayOptions) *ReplayEngine {
+	if options.MaxEvents <= 0 {
+		options.MaxEvents = DefaultReplayMaxEvents
+	}
+	if options.MaxAge <= 0 {
+		options.MaxAge = DefaultReplayMaxAge
+	}
+	if options.SubscriberBufferSize <= 0 {
+		options.SubscriberBufferSize = DefaultReplaySubscriberBufferSize
+	}
+	if options.TriggerKey == nil {
+		options.TriggerKey = defaultTriggerKey
+	}
+	return &ReplayEngine{
+		engine:     engine,
+		options:    options,
+		now:        time.Now,
+		triggers:   map[string]*replayTrigger{},
+		operations: map[replayOperationKey]*replayOperation{},
+		// event ids of a restarted process should be greater than the ones clients received before
+		lastEventID: uint64(time.Now().UnixNano()),
+	}
+}
+
+func defaultTriggerKey(ctx context.Context, payload []byte) (string, error) {
+	var request graphql.Request
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return "", err
+	}
+	return clientIdentity(ctx) + "\x00" + request.OperationName + "\x00" + request.Query + "\x00" + string(request.Variables), nil
+}
+
+// clientIdentity returns the credentials of the initial request of the client,
+// so that clients with different credentials don't share an upstream operation.
+func clientIdentity(ctx context.Context) string {
+	requestContext, ok := InitialHttpRequestContextFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	header := requestContext.Request.Header
+	return strings.Join(header.Values("Authorization"), ",") + "\x00" + strings.Join(header.Values("Cookie"), ",")
+}
+
+// StartOperation is an implementation of Engine.
+func (e *ReplayEngine) StartOperation(ctx context.Context, id string, payload []byte, eventHandler EventHandler) error {
+	key := replayOperationKey{eventHandler: eventHandler, id: id}
+
+	e.mu.Lock()
+	_, exists := e.operations[key]
+	e.mu.Unlock()
+	if exists {
+		err := fmt.Errorf("%w: %s", ErrSubscriberIDAlreadyExists, id)
+		eventHandler.Emit(EventTypeOnDuplicatedSubscriberID, id, nil, err)
+		return err
+	}
+
+	var request graphql.Request
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return err
+	}
+	operationType, err := request.OperationType()
+	if err != nil {
+		eventHandler.Emit(EventTypeOnError, id, nil, err)
+		return err
+	}
+	if operationType != graphql.OperationTypeSubscription {
+		return e.startOperation(ctx, key, payload)
+	}
+
+	lastEventID, err := e.lastEventIDFromRequest(ctx, payload)
+	if err != nil {
+		eventHandler.Emit(EventTypeOnError, id, nil, err)
+		return err
+	}
+	triggerKey, err := e.options.TriggerKey(ctx, payload)
+	if err != nil {
+		eventHandler.Emit(EventTypeOnError, id, nil, err)
+		return err
+	}
+
+	trigger, isNew := e.subscribe(key, triggerKey, lastEventID)
+	if !isNew {
+		return nil
+	}
+
+	// the trigger outlives the connection of its first subscriber, but keeps the values of its context, e.g. the request
+	err = e.engine.StartOperation(context.WithoutCancel(ctx), trigger.internalID, payload, &replayTriggerEventHandler{engine: e, trigger: trigger})
+	if err != nil {
+		e.mu.Lock()
+		for _, subscriber := range trigger.subscribers {
+			subscriber.stop()
+		}
+		e.removeTrigger(trigger)
+		e.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// subscribe adds a subscriber to the trigger and replays all events after lastEventID.
+// If events after lastEventID are no longer buffered, the subscriber receives ReplayGapPayload first.
+func (e *ReplayEngine) subscribe(key replayOperationKey, triggerKey string, lastEventID uint64) (trigger *replayTrigger, isNew bool) {
+	for {
+		e.mu.Lock()
+		trigger, exists := e.triggers[triggerKey]
+		if !exists {
+			e.nextID++
+			trigger = &replayTrigger{
+				key:             triggerKey,
+				internalID:      "replay:" + strconv.FormatUint(e.nextID, 10),
+				subscribers:     map[replayOperationKey]*replaySubscriber{},
+				replayableAfter: e.lastEventID,
+			}
+			e.triggers[triggerKey] = trigger
+		}
+		e.mu.Unlock()
+
+		trigger.sendMu.Lock()
+		e.mu.Lock()
+		if e.triggers[triggerKey] != trigger {
+			// the trigger completed in the meantime
+			e.mu.Unlock()
+			trigger.sendMu.Unlock()
+			continue
+		}
+		if trigger.expireTimer != nil {
+			trigger.expireTimer.Stop()
+			trigger.expireTimer = nil
+		}
+
+		subscriber := newReplaySubscriber(key, e.options.SubscriberBufferSize)
+		trigger.subscribers[key] = subscriber
+		e.operations[key] = &replayOperation{trigger: trigger}
+
+		var replay []replayDelivery
+		if lastEventID != 0 {
+			e.evictEvents(trigger)
+			if lastEventID < trigger.replayableAfter {
+				replay = append(replay, replayDelivery{eventType: EventTypeOnSubscriptionData, event: replayEvent{data: []byte(ReplayGapPayload)}})
+			}
+			for _, event := range trigger.events {
+				if event.id > lastEventID {
+					replay = append(replay, replayDelivery{eventType: EventTypeOnSubscriptionData, event: event})
+				}
+			}
+		}
+		e.mu.Unlock()
+
+		// the subscriber sends the replayed events before the events which are queued by the trigger
+		go e.runSubscriber(subscriber, replay)
+		trigger.sendMu.Unlock()
+
+		return trigger, !exists
+	}
+}
+
+func (e *ReplayEngine) startOperation(ctx context.Context, key replayOperationKey, payload []byte) error {
+	e.mu.Lock()
+	e.nextID++
+	operation := &replayOperation{internalID: "replay:" + strconv.FormatUint(e.nextID, 10)}
+	e.operations[key] = operation
+	e.mu.Unlock()
+
+	err := e.engine.StartOperation(ctx, operation.internalID, payload, &replayOperationEventHandler{engine: e, key: key})
+	if err != nil {
+		e.removeOperation(key, operation)
+	}
+	return err
+}
+
+// StopSubscription is an implementation of Engine.
+func (e *ReplayEngine) StopSubscription(id string, eventHandler EventHandler) error {
+	key := replayOperationKey{eventHandler: eventHandler, id: id}
+
+	e.mu.Lock()
+	operation, exists := e.operations[key]
+	if exists {
+		delete(e.operations, key)
+		if operation.trigger != nil {
+			e.unsubscribe(operation.trigger, key)
+		}
+	}
+	e.mu.Unlock()
+
+	if exists && operation.trigger == nil {
+		return e.engine.StopSubscription(operation.internalID, &replayOperationEventHandler{engine: e, key: key})
+	}
+	eventHandler.Emit(EventTypeOnSubscriptionCompleted, id, nil, nil)
+	return nil
+}
+
+// TerminateAllSubscriptions is an implementation of Engine.
+func (e *ReplayEngine) TerminateAllSubscriptions(eventHandler EventHandler) error {
+	var internalIDs []string
+
+	e.mu.Lock()
+	terminated := false
+	for key, operation := range e.operations {
+		if key.eventHandler != eventHandler {
+			continue
+		}
+		terminated = true
+		delete(e.operations, key)
+		if operation.trigger != nil {
+			e.unsubscribe(operation.trigger, key)
+			continue
+		}
+		internalIDs = append(internalIDs, operation.internalID)
+	}
+	e.mu.Unlock()
+
+	for _, internalID := range internalIDs {
+		if err := e.engine.StopSubscription(internalID, discardEventHandler{}); err != nil {
+			return err
+		}
+	}
+
+	if terminated {
+		eventHandler.Emit(EventTypeOnConnectionTerminatedByServer, "", []byte("connection terminated by server"), nil)
+	}
+	return nil
+}
+
+// unsubscribe removes a subscriber and keeps the trigger running for MaxAge if it was the last one.
+func (e *ReplayEngine) unsubscribe(trigger *replayTrigger, key replayOperationKey) {
+	if subscriber, ok := trigger.subscribers[key]; ok {
+		subscriber.stop()
+		delete(trigger.subscribers, key)
+	}
+	if len(trigger.subscribers) > 0 || e.triggers[trigger.key] != trigger {
+		return
+	}
+	trigger.expireTimer = time.AfterFunc(e.options.MaxAge, func() {
+		e.expireTrigger(trigger)
+	})
+}
+
+func (e *ReplayEngine) expireTrigger(trigger *replayTrigger) {
+	e.mu.Lock()
+	if e.triggers[trigger.key] != trigger || len(trigger.subscribers) > 0 {
+		e.mu.Unlock()
+		return
+	}
+	delete(e.triggers, trigger.key)
+	e.mu.Unlock()
+
+	_ = e.engine.StopSubscription(trigger.internalID, discardEventHandler{})
+}
+
+// removeTrigger must be called with e.mu held.
+func (e *ReplayEngine) removeTrigger(trigger *replayTrigger) {
+	if e.triggers[trigger.key] == trigger {
+		delete(e.triggers, trigger.key)
+	}
+	if trigger.expireTimer != nil {
+		trigger.expireTimer.Stop()
+	}
+	for key := range trigger.subscribers {
+		delete(e.operations, key)
+	}
+	trigger.subscribers = map[replayOperationKey]*replaySubscriber{}
+}
+
+func (e *ReplayEngine) removeOperation(key replayOperationKey, operation *replayOperation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.operations[key] == operation {
+		delete(e.operations, key)
+	}
+}
+
+// handleTriggerEvent queues an event of the upstream operation for all subscribers of the trigger.
+func (e *ReplayEngine) handleTriggerEvent(trigger *replayTrigger, eventType EventType, data []byte, err error) {
+	trigger.sendMu.Lock()
+	defer trigger.sendMu.Unlock()
+
+	e.mu.Lock()
+	if e.triggers[trigger.key] != trigger {
+		e.mu.Unlock()
+		return
+	}
+	subscribers := make([]*replaySubscriber, 0, len(trigger.subscribers))
+	for _, subscriber := range trigger.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+
+	// the upstream engine re-uses its buffers after emitting
+	delivery := replayDelivery{eventType: eventType, event: replayEvent{data: append([]byte(nil), data...)}, err: err}
+	if eventType == EventTypeOnSubscriptionData {
+		e.lastEventID++
+		delivery.event.id = e.lastEventID
+		delivery.event.time = e.now()
+		trigger.events = append(trigger.events, delivery.event)
+		e.evictEvents(trigger)
+	}
+	if isReplayTerminalEvent(eventType) {
+		e.removeTrigger(trigger)
+	}
+	e.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		if !subscriber.send(delivery) {
+			e.removeSlowSubscriber(trigger, subscriber)
+		}
+	}
+
+	if eventType == EventTypeOnError {
+		// the subscription is completed for the clients, so the upstream operation has to be stopped as well
+		_ = e.engine.StopSubscription(trigger.internalID, discardEventHandler{})
+	}
+}
+
+// removeSlowSubscriber unsubscribes a subscriber whose queue is full and notifies it with ErrSlowConsumer,
+// which closes the connection of the client.
+func (e *ReplayEngine) removeSlowSubscriber(trigger *replayTrigger, subscriber *replaySubscriber) {
+	e.mu.Lock()
+	if operation, ok := e.operations[subscriber.key]; ok && operation.trigger == trigger {
+		delete(e.operations, subscriber.key)
+		e.unsubscribe(trigger, subscriber.key)
+	}
+	e.mu.Unlock()
+	subscriber.stop()
+
+	// the goroutine of the subscriber might be blocked by the client
+	go subscriber.key.eventHandler.Emit(EventTypeOnLimitExceeded, subscriber.key.id, nil, ErrSlowConsumer)
+}
+
+// runSubscriber sends the replayed and queued events to a subscriber until it's stopped or its subscription ends.
+func (e *ReplayEngine) runSubscriber(subscriber *replaySubscriber, replay []replayDelivery) {
+	for {
+		var delivery replayDelivery
+		if len(replay) > 0 {
+			select {
+			case <-subscriber.done:
+				return
+			default:
+			}
+			delivery, replay = replay[0], replay[1:]
+		} else {
+			select {
+			case <-subscriber.done:
+				return
+			case delivery = <-subscriber.events:
+			}
+		}
+
+		if delivery.eventType == EventTypeOnSubscriptionData && delivery.event.id != 0 {
+			e.emitEvent(subscriber.key, delivery.event)
+		} else {
+			subscriber.key.eventHandler.Emit(delivery.eventType, subscriber.key.id, delivery.event.data, delivery.err)
+		}
+		if isReplayTerminalEvent(delivery.eventType) {
+			return
+		}
+	}
+}
+
+func isReplayTerminalEvent(eventType EventType) bool {
+	return eventType == EventTypeOnSubscriptionCompleted || eventType == EventTypeOnError
+}
+
+// evictEvents must be called with e.mu held.
+func (e *ReplayEngine) evictEvents(trigger *replayTrigger) {
+	evict := 0
+	if len(trigger.events) > e.options.MaxEvents {
+		evict = len(trigger.events) - e.options.MaxEvents
+	}
+	oldest := e.now().Add(-e.options.MaxAge)
+	for evict < len(trigger.events) && trigger.events[evict].time.Before(oldest) {
+		evict++
+	}
+	if evict > 0 {
+		trigger.replayableAfter = trigger.events[evict-1].id
+		trigger.events = append(trigger.events[:0], trigger.events[evict:]...)
+	}
+}
+
+func (e *ReplayEngine) emitEvent(key replayOperationKey, event replayEvent) {
+	if resumable, ok := key.eventHandler.(ResumableEventHandler); ok {
+		resumable.EmitWithEventID(EventTypeOnSubscriptionData, key.id, strconv.FormatUint(event.id, 10), event.data, nil)
+		return
+	}
+	key.eventHandler.Emit(EventTypeOnSubscriptionData, key.id, event.data, nil)
+}
+
+func (e *ReplayEngine) lastEventIDFromRequest(ctx context.Context, payload []byte) (uint64, error) {
+	lastEventID, ok := LastEventIDFromContext(ctx)
+	if !ok {
+		value, err := jsonparser.GetString(payload, "extensions", "lastEventId")
+		if err != nil || value == "" {
+			return 0, nil
+		}
+		lastEventID = value
+	}
+	id, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidLastEventID, lastEventID)
+	}
+	return id, nil
+}
+
+// replayTriggerEventHandler receives the events of the upstream operation of a trigger.
+type replayTriggerEventHandler struct {
+	engine  *ReplayEngine
+	trigger *replayTrigger
+}
+
+func (r *replayTriggerEventHandler) Emit(eventType EventType, _ string, data []byte, err error) {
+	r.engine.handleTriggerEvent(r.trigger, eventType, data, err)
+}
+
+// replayOperationEventHandler forwards the events of a non-subscription operation with the client id.
+type replayOperationEventHandler struct {
+	engine *ReplayEngine
+	key    replayOperationKey
+}
+
+func (r *replayOperationEventHandler) Emit(eventType EventType, _ string, data []byte, err error) {
+	switch eventType {
+	case EventTypeOnNonSubscriptionExecutionResult, EventTypeOnError, EventTypeOnSubscriptionCompleted:
+		r.engine.mu.Lock()
+		if operation, ok := r.engine.operations[r.key]; ok && operation.trigger == nil {
+			delete(r.engine.operations, r.key)
+		}
+		r.engine.mu.Unlock()
+	}
+	r.key.eventHandler.Emit(eventType, r.key.id, data, err)
+}
+
+type discardEventHandler struct{}
+
+func (discardEventHandler) Emit(_ EventType, _ string, _ []byte, _ error) {}
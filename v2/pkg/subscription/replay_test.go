@@ -0,0 +1,382 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTriggerEngine records started operations, so that tests can emit upstream events.
+type fakeTriggerEngine struct {
+	mu         sync.Mutex
+	operations map[string]EventHandler
+	contexts   []context.Context
+	started    int
+	stopped    []string
+}
+
+func (f *fakeTriggerEngine) StartOperation(ctx context.Context, id string, _ []byte, eventHandler EventHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.operations == nil {
+		f.operations = map[string]EventHandler{}
+	}
+	f.operations[id] = eventHandler
+	f.contexts = append(f.contexts, ctx)
+	f.started++
+	return nil
+}
+
+func (f *fakeTriggerEngine) StopSubscription(id string, eventHandler EventHandler) error {
+	f.mu.Lock()
+	delete(f.operations, id)
+	f.stopped = append(f.stopped, id)
+	f.mu.Unlock()
+	eventHandler.Emit(EventTypeOnSubscriptionCompleted, id, nil, nil)
+	return nil
+}
+
+func (f *fakeTriggerEngine) TerminateAllSubscriptions(_ EventHandler) error {
+	return nil
+}
+
+func (f *fakeTriggerEngine) emit(eventType EventType, data string) {
+	f.mu.Lock()
+	handlers := make([]EventHandler, 0, len(f.operations))
+	for _, handler := range f.operations {
+		handlers = append(handlers, handler)
+	}
+	f.mu.Unlock()
+	for _, handler := range handlers {
+		handler.Emit(eventType, "", []byte(data), nil)
+	}
+}
+
+func (f *fakeTriggerEngine) running() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.operations)
+}
+
+type recordedEvent struct {
+	eventType EventType
+	id        string
+	eventID   string
+	data      string
+}
+
+type recordingEventHandler struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+func (r *recordingEventHandler) Emit(eventType EventType, id string, data []byte, _ error) {
+	r.EmitWithEventID(eventType, id, "", data, nil)
+}
+
+func (r *recordingEventHandler) EmitWithEventID(eventType EventType, id string, eventID string, data []byte, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, recordedEvent{eventType: eventType, id: id, eventID: eventID, data: string(data)})
+}
+
+func (r *recordingEventHandler) recorded() []recordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]recordedEvent(nil), r.events...)
+}
+
+func (r *recordingEventHandler) data() []string {
+	var data []string
+	for _, event := range r.recorded() {
+		if event.eventType == EventTypeOnSubscriptionData {
+			data = append(data, event.data)
+		}
+	}
+	return data
+}
+
+// waitForEvents waits until the subscriber goroutines of the ReplayEngine sent count events.
+func (r *recordingEventHandler) waitForEvents(t *testing.T, count int) []recordedEvent {
+	require.Eventually(t, func() bool {
+		return len(r.recorded()) >= count
+	}, time.Second, time.Millisecond)
+	return r.recorded()
+}
+
+// waitForData waits until the subscriber goroutines of the ReplayEngine sent count data events.
+func (r *recordingEventHandler) waitForData(t *testing.T, count int) []string {
+	require.Eventually(t, func() bool {
+		return len(r.data()) >= count
+	}, time.Second, time.Millisecond)
+	return r.data()
+}
+
+// reentrantEventHandler stops its subscription when it receives data.
+type reentrantEventHandler struct {
+	engine *ReplayEngine
+}
+
+func (r *reentrantEventHandler) Emit(eventType EventType, id string, _ []byte, _ error) {
+	if eventType == EventTypeOnSubscriptionData {
+		_ = r.engine.StopSubscription(id, r)
+	}
+}
+
+const replaySubscriptionPayload = `{"query":"subscription { counter }"}`
+
+func TestReplayEngine(t *testing.T) {
+	t.Run("should share triggers and stamp events with ids", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		first, second := &recordingEventHandler{}, &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), first))
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), second))
+		assert.Equal(t, 1, inner.started)
+
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":1}}`)
+
+		firstEvents, secondEvents := first.waitForEvents(t, 1), second.waitForEvents(t, 1)
+		require.Len(t, firstEvents, 1)
+		require.Len(t, secondEvents, 1)
+		assert.Equal(t, "1", firstEvents[0].id)
+		assert.NotEmpty(t, firstEvents[0].eventID)
+		assert.Equal(t, firstEvents[0], secondEvents[0])
+	})
+
+	t.Run("should replay missed events after last event id", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		first, other := &recordingEventHandler{}, &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), first))
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), other))
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":1}}`)
+		lastEventID := first.waitForEvents(t, 1)[0].eventID
+
+		require.NoError(t, engine.TerminateAllSubscriptions(first))
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":2}}`)
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":3}}`)
+
+		resumed := &recordingEventHandler{}
+		ctx := WithLastEventID(context.Background(), lastEventID)
+		require.NoError(t, engine.StartOperation(ctx, "1", []byte(replaySubscriptionPayload), resumed))
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":4}}`)
+
+		assert.Equal(t, []string{`{"data":{"counter":2}}`, `{"data":{"counter":3}}`, `{"data":{"counter":4}}`}, resumed.waitForData(t, 3))
+		assert.Equal(t, 1, inner.started)
+	})
+
+	t.Run("should read last event id from payload extensions", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		first := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), first))
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":1}}`)
+		inner.emit(EventTypeOnSubscriptionData, `{"data":{"counter":2}}`)
+		lastEventID := first.waitForEvents(t, 2)[0].eventID
+
+		resumed := &recordingEventHandler{}
+		payload := `{"query":"subscription { counter }","extensions":{"lastEventId":"` + lastEventID + `"}}`
+		require.NoError(t, engine.StartOperation(context.Background(), "2", []byte(payload), resumed))
+		assert.Equal(t, []string{`{"data":{"counter":2}}`}, resumed.waitForData(t, 1))
+	})
+
+	t.Run("should bound replay buffer by count and age", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{MaxEvents: 2, MaxAge: time.Minute})
+		now := time.Now()
+		engine.now = func() time.Time { return now }
+
+		first := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), first))
+		inner.emit(EventTypeOnSubscriptionData, `1`)
+		inner.emit(EventTypeOnSubscriptionData, `2`)
+		inner.emit(EventTypeOnSubscriptionData, `3`)
+		now = now.Add(30 * time.Second)
+		inner.emit(EventTypeOnSubscriptionData, `4`)
+		lastEventID := first.waitForEvents(t, 4)[0].eventID
+
+		resumed := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(WithLastEventID(context.Background(), lastEventID), "2", []byte(replaySubscriptionPayload), resumed))
+		// event 2 was evicted
+		assert.Equal(t, []string{ReplayGapPayload, `3`, `4`}, resumed.waitForData(t, 3))
+
+		now = now.Add(45 * time.Second)
+		expired := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(WithLastEventID(context.Background(), lastEventID), "3", []byte(replaySubscriptionPayload), expired))
+		assert.Equal(t, []string{ReplayGapPayload, `4`}, expired.waitForData(t, 2))
+	})
+
+	t.Run("should keep trigger running for max age after last subscriber left", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{MaxAge: 50 * time.Millisecond})
+
+		handler := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), handler))
+		require.NoError(t, engine.StopSubscription("1", handler))
+		assert.Equal(t, EventTypeOnSubscriptionCompleted, handler.recorded()[0].eventType)
+		assert.Equal(t, 1, inner.running())
+
+		assert.Eventually(t, func() bool {
+			return inner.running() == 0
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should complete all subscribers when upstream completes", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		handler := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), handler))
+		inner.emit(EventTypeOnSubscriptionCompleted, "")
+
+		events := handler.waitForEvents(t, 1)
+		require.Len(t, events, 1)
+		assert.Equal(t, EventTypeOnSubscriptionCompleted, events[0].eventType)
+
+		// a new subscriber starts a new trigger
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), handler))
+		assert.Equal(t, 2, inner.started)
+	})
+
+	t.Run("should pass through non-subscription operations with internal ids", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		first, second := &recordingEventHandler{}, &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(`{"query":"{ hello }"}`), first))
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(`{"query":"{ hello }"}`), second))
+		assert.Equal(t, 2, inner.running())
+
+		inner.emit(EventTypeOnNonSubscriptionExecutionResult, `{"data":{"hello":"world"}}`)
+		assert.Equal(t, []recordedEvent{{eventType: EventTypeOnNonSubscriptionExecutionResult, id: "1", data: `{"data":{"hello":"world"}}`}}, first.recorded())
+		assert.Equal(t, first.recorded(), second.recorded())
+	})
+
+	t.Run("should signal missed events of expired triggers", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		first := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), first))
+		inner.emit(EventTypeOnSubscriptionData, `1`)
+		inner.emit(EventTypeOnSubscriptionCompleted, "")
+		lastEventID := first.waitForEvents(t, 2)[0].eventID
+
+		// another trigger produces events while the first one doesn't exist
+		other := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(`{"query":"subscription { other }"}`), other))
+		inner.emit(EventTypeOnSubscriptionData, `2`)
+
+		resumed := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(WithLastEventID(context.Background(), lastEventID), "2", []byte(replaySubscriptionPayload), resumed))
+		assert.Equal(t, []string{ReplayGapPayload}, resumed.waitForData(t, 1))
+
+		upToDate := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(WithLastEventID(context.Background(), other.waitForEvents(t, 1)[0].eventID), "3", []byte(replaySubscriptionPayload), upToDate))
+		assert.Len(t, upToDate.data(), 0)
+	})
+
+	t.Run("should not share triggers between clients with different credentials", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		withAuthorization := func(authorization string) context.Context {
+			request, _ := http.NewRequest(http.MethodGet, "http://localhost/graphql", nil)
+			request.Header.Set("Authorization", authorization)
+			return WithInitialHttpRequestContext(context.Background(), NewInitialHttpRequestContext(request))
+		}
+
+		require.NoError(t, engine.StartOperation(withAuthorization("a"), "1", []byte(replaySubscriptionPayload), &recordingEventHandler{}))
+		require.NoError(t, engine.StartOperation(withAuthorization("a"), "1", []byte(replaySubscriptionPayload), &recordingEventHandler{}))
+		require.NoError(t, engine.StartOperation(withAuthorization("b"), "1", []byte(replaySubscriptionPayload), &recordingEventHandler{}))
+		assert.Equal(t, 2, inner.started)
+
+		inner.mu.Lock()
+		defer inner.mu.Unlock()
+		for _, ctx := range inner.contexts {
+			requestContext, ok := InitialHttpRequestContextFromContext(ctx)
+			require.True(t, ok)
+			assert.NotEmpty(t, requestContext.Request.Header.Get("Authorization"))
+		}
+	})
+
+	t.Run("should not hold the engine lock while sending events", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{})
+
+		blocking := &blockingEventHandler{unblock: make(chan struct{})}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), blocking))
+		other := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(`{"query":"subscription { other }"}`), other))
+
+		inner.mu.Lock()
+		blockedTrigger := inner.operations["replay:1"]
+		otherTrigger := inner.operations["replay:2"]
+		inner.mu.Unlock()
+
+		go blockedTrigger.Emit(EventTypeOnSubscriptionData, "", []byte(`1`), nil)
+		time.Sleep(10 * time.Millisecond)
+		otherTrigger.Emit(EventTypeOnSubscriptionData, "", []byte(`2`), nil)
+		assert.Equal(t, []string{`2`}, other.waitForData(t, 1))
+
+		// unsubscribing from within the event handler must not deadlock
+		reentrant := &reentrantEventHandler{engine: engine}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(`{"query":"subscription { other }"}`), reentrant))
+		otherTrigger.Emit(EventTypeOnSubscriptionData, "", []byte(`3`), nil)
+		assert.Equal(t, []string{`2`, `3`}, other.waitForData(t, 2))
+
+		close(blocking.unblock)
+	})
+
+	t.Run("should not delay subscribers by a slow subscriber of the same trigger", func(t *testing.T) {
+		inner := &fakeTriggerEngine{}
+		engine := NewReplayEngine(inner, ReplayOptions{SubscriberBufferSize: 2})
+
+		blocking := &blockingEventHandler{unblock: make(chan struct{})}
+		defer close(blocking.unblock)
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), blocking))
+		other := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), other))
+		assert.Equal(t, 1, inner.started)
+
+		for i := 1; i <= 5; i++ {
+			emitted := make(chan struct{})
+			go func() {
+				defer close(emitted)
+				inner.emit(EventTypeOnSubscriptionData, strconv.Itoa(i))
+			}()
+			select {
+			case <-emitted:
+			case <-time.After(time.Second):
+				t.Fatal("events were blocked by a slow subscriber")
+			}
+			require.Len(t, other.waitForData(t, i), i)
+		}
+		assert.Equal(t, []string{`1`, `2`, `3`, `4`, `5`}, other.data())
+
+		// the queue of the blocked subscriber overflowed
+		assert.Eventually(t, func() bool {
+			events, _ := blocking.recorded()
+			return len(events) == 1 && events[0] == EventTypeOnLimitExceeded
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should reject duplicated subscriber ids and invalid event ids", func(t *testing.T) {
+		engine := NewReplayEngine(&fakeTriggerEngine{}, ReplayOptions{})
+
+		handler := &recordingEventHandler{}
+		require.NoError(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), handler))
+		assert.ErrorIs(t, engine.StartOperation(context.Background(), "1", []byte(replaySubscriptionPayload), handler), ErrSubscriberIDAlreadyExists)
+		assert.ErrorIs(t, engine.StartOperation(WithLastEventID(context.Background(), "abc"), "2", []byte(replaySubscriptionPayload), handler), ErrInvalidLastEventID)
+	})
+}
@@ -0,0 +1,286 @@
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/jensneuse/abstractlogger"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+)
+
+const (
+	DefaultKeepAliveInterval = 15 * time.Second
+
+	HeaderLastEventID = "Last-Event-ID"
+)
+
+var ErrStreamingUnsupported = errors.New("response writer does not support streaming")
+
+// operationCounter makes operation ids unique, so that engines can be shared between requests.
+var operationCounter uint64
+
+// HandleOptions can be used to pass options to the SSE handler.
+type HandleOptions struct {
+	Logger                  abstractlogger.Logger
+	CustomKeepAliveInterval time.Duration
+}
+
+// HandleOptionFunc can be used to define option functions.
+type HandleOptionFunc func(opts *HandleOptions)
+
+// WithLogger is a function that sets a logger for the SSE handler.
+func WithLogger(logger abstractlogger.Logger) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.Logger = logger
+	}
+}
+
+// WithCustomKeepAliveInterval is a function that sets the interval in which comments are sent to keep the
+// connection open.
+func WithCustomKeepAliveInterval(keepAliveInterval time.Duration) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.CustomKeepAliveInterval = keepAliveInterval
+	}
+}
+
+// Handle streams the operation of the request as server-sent events using the 'distinct connections mode' of
+// the GraphQL over SSE protocol. Execution results are sent as 'next' events and the stream ends with a
+// 'complete' event. It blocks until the operation is completed or the client disconnects.
+//
+// The operation is read from the query parameters of GET requests or the JSON body of POST requests.
+// If the engine stamps events with ids, e.g. subscription.ReplayEngine, clients can resume a subscription
+// with the Last-Event-ID header or the 'lastEventId' extension.
+func Handle(w http.ResponseWriter, r *http.Request, engine subscription.Engine, options ...HandleOptionFunc) {
+	definedOptions := HandleOptions{
+		Logger: abstractlogger.Noop{},
+	}
+
+	for _, optionFunc := range options {
+		optionFunc(&definedOptions)
+	}
+
+	HandleWithOptions(w, r, engine, definedOptions)
+}
+
+// HandleWithOptions streams the operation of the request as server-sent events. It requires an option struct
+// to define the behavior.
+func HandleWithOptions(w http.ResponseWriter, r *http.Request, engine subscription.Engine, options HandleOptions) {
+	if options.Logger == nil {
+		options.Logger = abstractlogger.Noop{}
+	}
+	keepAliveInterval := options.CustomKeepAliveInterval
+	if keepAliveInterval == 0 {
+		keepAliveInterval = DefaultKeepAliveInterval
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		options.Logger.Error("sse.HandleWithOptions: on streaming check",
+			abstractlogger.Error(ErrStreamingUnsupported),
+		)
+		http.Error(w, ErrStreamingUnsupported.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	payload, extensions, err := readOperation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := subscription.WithInitialHttpRequestContext(r.Context(), subscription.NewInitialHttpRequestContext(r))
+	if lastEventID := r.Header.Get(HeaderLastEventID); lastEventID != "" {
+		ctx = subscription.WithLastEventID(ctx, lastEventID)
+	} else if lastEventID, err := jsonparser.GetString(extensions, "lastEventId"); err == nil {
+		ctx = subscription.WithLastEventID(ctx, lastEventID)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	eventHandler := &EventHandler{
+		logger:  options.Logger,
+		writer:  w,
+		flusher: flusher,
+		done:    make(chan struct{}),
+	}
+
+	id := "sse:" + strconv.FormatUint(atomic.AddUint64(&operationCounter, 1), 10)
+	defer func() {
+		eventHandler.close()
+		if err := engine.StopSubscription(id, eventHandler); err != nil {
+			options.Logger.Error("sse.HandleWithOptions: on stopping operation",
+				abstractlogger.Error(err),
+			)
+		}
+	}()
+
+	if err := engine.StartOperation(ctx, id, payload, eventHandler); err != nil {
+		options.Logger.Debug("sse.HandleWithOptions: on starting operation",
+			abstractlogger.Error(err),
+		)
+		// engines might return an error without emitting it
+		eventHandler.Emit(subscription.EventTypeOnError, id, nil, err)
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-eventHandler.done:
+			return
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			eventHandler.writeKeepAlive()
+		}
+	}
+}
+
+// readOperation returns the operation as engine payload and the extensions of the request.
+func readOperation(r *http.Request) (payload []byte, extensions []byte, err error) {
+	var request struct {
+		graphql.Request
+		Extensions json.RawMessage `json:"extensions,omitempty"`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			request.Variables = json.RawMessage(variables)
+		}
+		if extensions := query.Get("extensions"); extensions != "" {
+			request.Extensions = json.RawMessage(extensions)
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, errors.New("method not allowed")
+	}
+
+	if request.Query == "" {
+		return nil, nil, graphql.ErrEmptyRequest
+	}
+
+	payload, err = json.Marshal(request.Request)
+	return payload, request.Extensions, err
+}
+
+// EventHandler writes subscription events as server-sent events. It is an implementation of
+// subscription.ResumableEventHandler.
+type EventHandler struct {
+	logger  abstractlogger.Logger
+	mu      sync.Mutex
+	writer  http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+	done    chan struct{}
+}
+
+// Emit is an implementation of subscription.EventHandler.
+func (e *EventHandler) Emit(eventType subscription.EventType, id string, data []byte, err error) {
+	e.EmitWithEventID(eventType, id, "", data, err)
+}
+
+// EmitWithEventID is an implementation of subscription.ResumableEventHandler.
+func (e *EventHandler) EmitWithEventID(eventType subscription.EventType, _ string, eventID string, data []byte, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+
+	switch eventType {
+	case subscription.EventTypeOnSubscriptionData:
+		e.writeEvent("next", eventID, data)
+	case subscription.EventTypeOnNonSubscriptionExecutionResult:
+		e.writeEvent("next", eventID, data)
+		e.complete()
+	case subscription.EventTypeOnError, subscription.EventTypeOnLimitExceeded, subscription.EventTypeOnDuplicatedSubscriberID:
+		errorsPayload, marshalErr := json.Marshal(graphql.RequestErrorsFromError(err))
+		if marshalErr != nil {
+			e.logger.Error("sse.EventHandler.Emit: on marshalling errors",
+				abstractlogger.Error(marshalErr),
+			)
+			errorsPayload = []byte(`[]`)
+		}
+		e.writeEvent("next", "", append(append([]byte(`{"errors":`), errorsPayload...), '}'))
+		e.complete()
+	case subscription.EventTypeOnSubscriptionCompleted, subscription.EventTypeOnConnectionTerminatedByServer:
+		e.complete()
+	default:
+		return
+	}
+}
+
+func (e *EventHandler) complete() {
+	e.writeEvent("complete", "", nil)
+	e.closed = true
+	close(e.done)
+}
+
+func (e *EventHandler) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	e.closed = true
+	close(e.done)
+}
+
+func (e *EventHandler) writeKeepAlive() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	e.write([]byte(":\n\n"))
+}
+
+// writeEvent must be called with e.mu held.
+func (e *EventHandler) writeEvent(event string, eventID string, data []byte) {
+	message := make([]byte, 0, len(data)+len(event)+len(eventID)+24)
+	message = append(message, "event: "...)
+	message = append(message, event...)
+	message = append(message, '\n')
+	if eventID != "" {
+		message = append(message, "id: "...)
+		message = append(message, eventID...)
+		message = append(message, '\n')
+	}
+	message = append(message, "data: "...)
+	// every line of the data has to be sent as a separate data field
+	message = append(message, bytes.ReplaceAll(data, []byte("\n"), []byte("\ndata: "))...)
+	message = append(message, "\n\n"...)
+	e.write(message)
+}
+
+func (e *EventHandler) write(message []byte) {
+	if _, err := e.writer.Write(message); err != nil {
+		e.logger.Error("sse.EventHandler.write: on writing event",
+			abstractlogger.Error(err),
+		)
+		return
+	}
+	e.flusher.Flush()
+}
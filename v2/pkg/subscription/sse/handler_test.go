@@ -0,0 +1,125 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
+)
+
+// fakeEngine emits its events synchronously and records the operation it was started with.
+type fakeEngine struct {
+	events      []string
+	err         error
+	payload     string
+	lastEventID string
+	stopped     bool
+}
+
+func (f *fakeEngine) StartOperation(ctx context.Context, id string, payload []byte, eventHandler subscription.EventHandler) error {
+	f.payload = string(payload)
+	f.lastEventID, _ = subscription.LastEventIDFromContext(ctx)
+	if f.err != nil {
+		return f.err
+	}
+	resumable := eventHandler.(subscription.ResumableEventHandler)
+	for i, event := range f.events {
+		resumable.EmitWithEventID(subscription.EventTypeOnSubscriptionData, id, string(rune('1'+i)), []byte(event), nil)
+	}
+	eventHandler.Emit(subscription.EventTypeOnSubscriptionCompleted, id, nil, nil)
+	return nil
+}
+
+func (f *fakeEngine) StopSubscription(_ string, _ subscription.EventHandler) error {
+	f.stopped = true
+	return nil
+}
+
+func (f *fakeEngine) TerminateAllSubscriptions(_ subscription.EventHandler) error {
+	return nil
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("should stream events with ids", func(t *testing.T) {
+		engine := &fakeEngine{events: []string{`{"data":{"counter":1}}`, "{\"data\":\n{\"counter\":2}}"}}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Handle(w, r, engine)
+		}))
+		defer server.Close()
+
+		query := url.Values{}
+		query.Set("query", "subscription { counter }")
+		query.Set("variables", `{"a":1}`)
+		req, err := http.NewRequest(http.MethodGet, server.URL+"?"+query.Encode(), nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderLastEventID, "42")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		expected := "event: next\nid: 1\ndata: {\"data\":{\"counter\":1}}\n\n" +
+			"event: next\nid: 2\ndata: {\"data\":\ndata: {\"counter\":2}}\n\n" +
+			"event: complete\ndata: \n\n"
+		assert.Equal(t, expected, string(body))
+		assert.Equal(t, `{"operationName":"","variables":{"a":1},"query":"subscription { counter }"}`, engine.payload)
+		assert.Equal(t, "42", engine.lastEventID)
+		assert.True(t, engine.stopped)
+	})
+
+	t.Run("should read operation and last event id extension from post body", func(t *testing.T) {
+		engine := &fakeEngine{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Handle(w, r, engine)
+		}))
+		defer server.Close()
+
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"query":"subscription { counter }","extensions":{"lastEventId":"7"}}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "7", engine.lastEventID)
+	})
+
+	t.Run("should send errors and complete", func(t *testing.T) {
+		engine := &fakeEngine{err: errors.New("upstream failed")}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Handle(w, r, engine)
+		}))
+		defer server.Close()
+
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"query":"subscription { counter }"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "event: next\ndata: {\"errors\":[{\"message\":\"upstream failed\"}]}\n\nevent: complete\ndata: \n\n", string(body))
+	})
+
+	t.Run("should reject requests without query", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Handle(w, r, &fakeEngine{})
+		}))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -32,6 +32,9 @@ var (
 	)
 )
 
+// CloseCodeTooManySubscriptions is used to close connections which exceed subscription.Limits.MaxSubscriptionsPerConnection.
+const CloseCodeTooManySubscriptions = 4430
+
 // NewCloseReason is used to compose a close frame with code and reason message.
 func NewCloseReason(code uint16, reason string) CloseReason {
 	wsCloseFrame := ws.NewCloseFrame(ws.NewCloseFrameBody(
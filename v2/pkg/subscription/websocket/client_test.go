@@ -337,6 +337,7 @@ type TestClient struct {
 	messageToClient   chan []byte
 	isConnected       bool
 	shouldFail        bool
+	closeReason       interface{}
 }
 
 func NewTestClient(shouldFail bool) *TestClient {
@@ -381,6 +382,7 @@ func (t *TestClient) DisconnectWithReason(reason interface{}) error {
 	t.connectionMutex.Lock()
 	defer t.connectionMutex.Unlock()
 	t.isConnected = false
+	t.closeReason = reason
 	return nil
 }
 
@@ -39,6 +39,8 @@ type HandleOptions struct {
 	CustomConnectionInitTimeOut      time.Duration
 	CustomReadErrorTimeOut           time.Duration
 	CustomSubscriptionEngine         subscription.Engine
+	Limits                           subscription.Limits
+	InitialHttpRequestContext        *subscription.InitialHttpRequestContext
 }
 
 // HandleOptionFunc can be used to define option functions.
@@ -102,6 +104,21 @@ func WithCustomSubscriptionEngine(subscriptionEngine subscription.Engine) Handle
 	}
 }
 
+// WithLimits is a function that sets the connection, subscription and rate limits for the websocket handler.
+func WithLimits(limits subscription.Limits) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.Limits = limits
+	}
+}
+
+// WithInitialHttpRequestContext is a function that sets the context of the upgrade request. It is used to
+// identify clients for the connection limit.
+func WithInitialHttpRequestContext(ctx *subscription.InitialHttpRequestContext) HandleOptionFunc {
+	return func(opts *HandleOptions) {
+		opts.InitialHttpRequestContext = ctx
+	}
+}
+
 // WithProtocol is a function that sets the protocol.
 func WithProtocol(protocol Protocol) HandleOptionFunc {
 	return func(opts *HandleOptions) {
@@ -186,6 +203,8 @@ func HandleWithOptions(done chan bool, errChan chan error, conn net.Conn, execut
 		CustomSubscriptionUpdateInterval: options.CustomSubscriptionUpdateInterval,
 		CustomReadErrorTimeOut:           options.CustomReadErrorTimeOut,
 		CustomEngine:                     options.CustomSubscriptionEngine,
+		Limits:                           options.Limits,
+		InitialHttpRequestContext:        options.InitialHttpRequestContext,
 	})
 	if err != nil {
 		options.Logger.Error("websocket.HandleWithOptions: on subscription handler creation",
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/buger/jsonparser"
 	"github.com/jensneuse/abstractlogger"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
@@ -125,6 +127,18 @@ func (g *GraphQLTransportWSMessageWriter) WriteNext(id string, executionResult [
 	return g.write(message)
 }
 
+// WriteNextWithEventID writes a message of type 'next' and adds the event id to the extensions of the payload.
+// Clients can resume a subscription by sending the id as 'lastEventId' extension of a 'subscribe' message.
+func (g *GraphQLTransportWSMessageWriter) WriteNextWithEventID(id string, eventID string, executionResult []byte) error {
+	// the execution result might be shared with other subscribers, so it must not be modified
+	payload := append([]byte(nil), executionResult...)
+	payload, err := jsonparser.Set(payload, []byte(strconv.Quote(eventID)), "extensions", "eventId")
+	if err != nil {
+		return err
+	}
+	return g.WriteNext(id, payload)
+}
+
 // WriteError writes a message of type 'error' to the transport client including the graphql errors as payload.
 func (g *GraphQLTransportWSMessageWriter) WriteError(id string, graphqlErrors graphql.RequestErrors) error {
 	payloadBytes, err := json.Marshal(graphqlErrors)
@@ -204,12 +218,50 @@ func (g *GraphQLTransportWSEventHandler) Emit(eventType subscription.EventType,
 			)
 		}
 		return
+	case subscription.EventTypeOnLimitExceeded:
+		code, reason := uint16(4429), "Limit exceeded"
+		if err != nil {
+			reason = err.Error()
+		}
+		switch {
+		case errors.Is(err, subscription.ErrSlowConsumer):
+			// the client is not sending too much, it is just not reading fast enough
+			code = 1008
+		case errors.Is(err, subscription.ErrTooManySubscriptions):
+			// 4429 is reserved for rate limiting
+			code = CloseCodeTooManySubscriptions
+		}
+		err = g.Writer.Client.DisconnectWithReason(NewCloseReason(code, reason))
+		if err != nil {
+			g.logger.Error("websocket.GraphQLTransportWSEventHandler.Emit: on limit exceeded handling",
+				abstractlogger.Error(err),
+				abstractlogger.String("id", id),
+			)
+		}
+		return
 	default:
 		return
 	}
 	g.HandleWriteEvent(messageType, id, data, err)
 }
 
+// EmitWithEventID is an implementation of subscription.ResumableEventHandler.
+func (g *GraphQLTransportWSEventHandler) EmitWithEventID(eventType subscription.EventType, id string, eventID string, data []byte, err error) {
+	if eventType != subscription.EventTypeOnSubscriptionData || eventID == "" {
+		g.Emit(eventType, id, data, err)
+		return
+	}
+	err = g.Writer.WriteNextWithEventID(id, eventID, data)
+	if err != nil {
+		g.logger.Error("websocket.GraphQLTransportWSEventHandler.EmitWithEventID: on write next",
+			abstractlogger.Error(err),
+			abstractlogger.String("id", id),
+			abstractlogger.String("event_id", eventID),
+			abstractlogger.ByteString("payload", data),
+		)
+	}
+}
+
 // HandleWriteEvent forwards messages to the underlying writer.
 func (g *GraphQLTransportWSEventHandler) HandleWriteEvent(messageType GraphQLTransportWSMessageType, id string, data []byte, providedErr error) {
 	var err error
@@ -501,6 +553,10 @@ func (p *ProtocolGraphQLTransportWSHandler) handleSubscribe(ctx context.Context,
 		return err
 	}
 
+	if lastEventID, err := jsonparser.GetString(subscribePayload.Extensions, "lastEventId"); err == nil {
+		ctx = subscription.WithLastEventID(ctx, lastEventID)
+	}
+
 	return engine.StartOperation(ctx, message.Id, enginePayloadBytes, &p.eventHandler)
 }
 
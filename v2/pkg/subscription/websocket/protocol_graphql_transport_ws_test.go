@@ -11,6 +11,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/jensneuse/abstractlogger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/graphql"
 	"github.com/wundergraph/graphql-go-tools/v2/pkg/subscription"
@@ -210,6 +211,18 @@ func TestGraphQLTransportWSMessageWriter_WriteNext(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
 	})
+	t.Run("should add event id to payload extensions", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		writer := GraphQLTransportWSMessageWriter{
+			logger: abstractlogger.Noop{},
+			Client: testClient,
+			mu:     &sync.Mutex{},
+		}
+		expectedMessage := []byte(`{"id":"1","type":"next","payload":{"data":{"hello":"world"},"extensions":{"eventId":"42"}}}`)
+		err := writer.WriteNextWithEventID("1", "42", []byte(`{"data":{"hello":"world"}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
+	})
 }
 
 func TestGraphQLTransportWSMessageWriter_WriteError(t *testing.T) {
@@ -318,6 +331,27 @@ func TestGraphQLTransportWSEventHandler_Emit(t *testing.T) {
 		eventHandler.Emit(subscription.EventTypeOnDuplicatedSubscriberID, "1", nil, errors.New("subscriber already exists"))
 		assert.False(t, testClient.IsConnected())
 	})
+	t.Run("should write next with event id", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
+		eventHandler.EmitWithEventID(subscription.EventTypeOnSubscriptionData, "1", "42", []byte(`{"data":{"counter":1}}`), nil)
+		expectedMessage := []byte(`{"id":"1","type":"next","payload":{"data":{"counter":1},"extensions":{"eventId":"42"}}}`)
+		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
+	})
+	t.Run("should disconnect on limit exceeded", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
+		eventHandler.Emit(subscription.EventTypeOnLimitExceeded, "", nil, subscription.ErrTooManyMessages)
+		assert.False(t, testClient.IsConnected())
+		assert.Equal(t, NewCloseReason(4429, "too many messages"), testClient.closeReason)
+	})
+	t.Run("should disconnect with its own code on too many subscriptions", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		eventHandler := NewTestGraphQLTransportWSEventHandler(testClient)
+		eventHandler.Emit(subscription.EventTypeOnLimitExceeded, "1", nil, subscription.ErrTooManySubscriptions)
+		assert.False(t, testClient.IsConnected())
+		assert.Equal(t, NewCloseReason(CloseCodeTooManySubscriptions, "too many subscriptions"), testClient.closeReason)
+	})
 }
 
 func TestGraphQLTransportWSWriteEventHandler_HandleWriteEvent(t *testing.T) {
@@ -466,6 +500,31 @@ func TestProtocolGraphQLTransportWSHandler_Handle(t *testing.T) {
 		}, 1*time.Second, 2*time.Millisecond)
 	})
 
+	t.Run("should pass last event id of subscribe extensions to the engine", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		protocol := NewTestProtocolGraphQLTransportWSHandler(testClient)
+
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		operation := []byte(`{"operationName":"","query":"subscription { counter }"}`)
+		ctrl := gomock.NewController(t)
+		mockEngine := NewMockEngine(ctrl)
+		mockEngine.EXPECT().StartOperation(gomock.Any(), gomock.Eq("2"), gomock.Eq(operation), gomock.Eq(&protocol.eventHandler)).
+			DoAndReturn(func(ctx context.Context, _ string, _ []byte, _ subscription.EventHandler) error {
+				lastEventID, ok := subscription.LastEventIDFromContext(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, "42", lastEventID)
+				return nil
+			})
+
+		err := protocol.Handle(ctx, mockEngine, []byte(`{"id":"1","type":"connection_init"}`))
+		require.NoError(t, err)
+		subscribeMessage := []byte(`{"id":"2","type":"subscribe","payload":{"query":"subscription { counter }","extensions":{"lastEventId":"42"}}}`)
+		err = protocol.Handle(ctx, mockEngine, subscribeMessage)
+		require.NoError(t, err)
+	})
+
 	t.Run("should handle subscribe", func(t *testing.T) {
 		testClient := NewTestClient(false)
 		protocol := NewTestProtocolGraphQLTransportWSHandler(testClient)
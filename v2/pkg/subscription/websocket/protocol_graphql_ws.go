@@ -183,6 +183,15 @@ func (g *GraphQLWSWriteEventHandler) Emit(eventType subscription.EventType, id s
 		messageType = GraphQLWSMessageTypeError
 	case subscription.EventTypeOnConnectionError:
 		messageType = GraphQLWSMessageTypeConnectionError
+	case subscription.EventTypeOnLimitExceeded:
+		g.HandleWriteEvent(GraphQLWSMessageTypeConnectionError, id, data, err)
+		if err := g.Writer.Client.Disconnect(); err != nil {
+			g.logger.Error("websocket.GraphQLWSWriteEventHandler.Emit: on limit exceeded handling",
+				abstractlogger.Error(err),
+				abstractlogger.String("id", id),
+			)
+		}
+		return
 	default:
 		return
 	}
@@ -245,6 +245,14 @@ func TestGraphQLWSWriteEventHandler_Emit(t *testing.T) {
 		expectedMessage := []byte(`{"type":"connection_error","payload":"connection error occurred"}`)
 		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
 	})
+	t.Run("should write connection_error and disconnect on limit exceeded", func(t *testing.T) {
+		testClient := NewTestClient(false)
+		writeEventHandler := NewTestGraphQLWSWriteEventHandler(testClient)
+		writeEventHandler.Emit(subscription.EventTypeOnLimitExceeded, "", nil, subscription.ErrTooManySubscriptions)
+		expectedMessage := []byte(`{"type":"connection_error","payload":"too many subscriptions"}`)
+		assert.Equal(t, expectedMessage, testClient.readMessageToClient())
+		assert.False(t, testClient.IsConnected())
+	})
 	t.Run("should write on non-subscription execution result", func(t *testing.T) {
 		testClient := NewTestClient(false)
 		writeEventHandler := NewTestGraphQLWSWriteEventHandler(testClient)